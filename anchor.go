@@ -0,0 +1,70 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Best-fit anchoring
+// -----------------------------
+
+// AnchorMode picks how a face is positioned against its parent in the net.
+type AnchorMode int
+
+const (
+    // AnchorEdge hinges the face on the two vertices of the shared edge.
+    AnchorEdge AnchorMode = iota
+    // AnchorBestFit fits the face over every vertex it shares with its parent
+    // (least squares, rotation and translation only). Faces that touch their
+    // parent in more than one edge, e.g. after coplanar faces were merged,
+    // then spread the mismatch over all contacts instead of leaving it as a
+    // visible crack at the far end.
+    AnchorBestFit
+)
+
+// bestFitAnchor moves the already hinged pts of faceIdx rigidly so they best
+// match the parent's 2D copy of every vertex the two faces share. With only
+// the hinge in common it returns pts unchanged.
+func bestFitAnchor(poly Polyhedron, parentIdx, faceIdx int, parent2D, pts []Point2) []Point2 {
+    inParent := make(map[int]int, len(poly.Faces[parentIdx].Vertices))
+    for i, v := range poly.Faces[parentIdx].Vertices {
+        inParent[v] = i
+    }
+    var src, dst []Point2
+    for i, v := range poly.Faces[faceIdx].Vertices {
+        if j, ok := inParent[v]; ok {
+            src = append(src, pts[i])
+            dst = append(dst, parent2D[j])
+        }
+    }
+    if len(src) <= 2 {
+        return pts
+    }
+
+    // 2D Kabsch: centre both sets, the best rotation angle comes straight
+    // out of the summed dot and cross products
+    var cs, cd Point2
+    for i := range src {
+        cs.X += src[i].X
+        cs.Y += src[i].Y
+        cd.X += dst[i].X
+        cd.Y += dst[i].Y
+    }
+    k := float64(len(src))
+    cs = Point2{X: cs.X / k, Y: cs.Y / k}
+    cd = Point2{X: cd.X / k, Y: cd.Y / k}
+    var sdot, scross float64
+    for i := range src {
+        sx, sy := src[i].X-cs.X, src[i].Y-cs.Y
+        dx, dy := dst[i].X-cd.X, dst[i].Y-cd.Y
+        sdot += sx*dx + sy*dy
+        scross += sx*dy - sy*dx
+    }
+    theta := math.Atan2(scross, sdot)
+    c, s := math.Cos(theta), math.Sin(theta)
+
+    out := make([]Point2, len(pts))
+    for i, p := range pts {
+        x, y := p.X-cs.X, p.Y-cs.Y
+        out[i] = Point2{X: cd.X + x*c - y*s, Y: cd.Y + x*s + y*c}
+    }
+    return out
+}
@@ -0,0 +1,76 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+)
+
+// -----------------------------
+//   Fold animation
+// -----------------------------
+
+// FrameMesh is one frame of a fold animation: the net folded Progress of
+// the way from flat (0) to the finished model (1). Faces and Vertices are
+// laid out as in FoldNet, one face per placed face in face order, each with
+// its own copies of its corners; Faces is the same slice in every frame.
+type FrameMesh struct {
+    Progress float64
+    Faces    []Face
+    Vertices []Vector3
+}
+
+// GenerateFoldAnimation folds result up in steps equal steps, turning every
+// fold at once from flat to the mesh's dihedral angle, and returns the
+// steps+1 frames from the flat net to the folded model. The net lies on a
+// table, the z = 0 plane, with the inside of the model facing up: the root
+// face of each piece stays put and the rest folds up (towards +z) around
+// it. So an exterior net (see NetSide) lies face down and, seen from above,
+// mirrored (y is flipped); an interior net lies face up as it is.
+func GenerateFoldAnimation(poly Polyhedron, result *UnfoldResult, steps int) ([]FrameMesh, error) {
+    if result == nil {
+        return nil, errors.New("nil result")
+    }
+    if steps < 1 {
+        return nil, fmt.Errorf("need at least 1 step, got %d", steps)
+    }
+    sim, err := newFoldSim(poly, result)
+    if err != nil {
+        return nil, err
+    }
+
+    var faces []Face
+    n := 0
+    for _, f2 := range result.Face2D {
+        if len(f2.Vertices) < 3 {
+            continue
+        }
+        face := Face{Vertices: make([]int, len(f2.Vertices))}
+        for i := range face.Vertices {
+            face.Vertices[i] = n
+            n++
+        }
+        faces = append(faces, face)
+    }
+
+    frames := make([]FrameMesh, steps+1)
+    progress := make([]float64, len(result.FoldEdges))
+    for k := range frames {
+        t := float64(k) / float64(steps)
+        for i := range progress {
+            progress[i] = t
+        }
+        verts := make([]Vector3, 0, n)
+        for _, pts := range sim.pose(progress) {
+            for _, p := range pts {
+                if result.Side == SideExterior {
+                    // turned over about the x axis: the faces point down at
+                    // the table and fold up off it
+                    p = Vector3{p.X, -p.Y, -p.Z}
+                }
+                verts = append(verts, p)
+            }
+        }
+        frames[k] = FrameMesh{Progress: t, Faces: faces, Vertices: verts}
+    }
+    return frames, nil
+}
@@ -0,0 +1,144 @@
+package unfolder
+
+import (
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Anonymizing meshes
+// -----------------------------
+
+// AnonymizeOptions picks how much Anonymize takes away besides the name and
+// the order of vertices and faces.
+type AnonymizeOptions struct {
+    // Normalize moves the model's bounding box centre to the origin and
+    // scales it to fit a unit cube, so its size and position in the
+    // assembly it came from are gone too. Units are dropped with it.
+    Normalize bool
+    // KeepUVs keeps texture coordinates; by default they're dropped, they
+    // say how the model was textured.
+    KeepUVs bool
+}
+
+// Anonymized is a mesh from Anonymize, with the way back to the original.
+type Anonymized struct {
+    Mesh Polyhedron
+    // Vertices is the new index of every original vertex, -1 for vertices
+    // no face used (they're dropped); Faces the new index of every face.
+    Vertices []int
+    Faces    []int
+}
+
+// Anonymize makes a copy of poly safe to share in a problem report about
+// proprietary geometry: no name, no vertices no face uses, and vertices and
+// faces renumbered in an order that only depends on the geometry (vertices
+// by position, faces by their vertices), so nothing of the original file's
+// structure (its parts, groups, export order) is left. Every face keeps its
+// winding, so the copy unfolds the same; the maps translate face and
+// vertex numbers between the two.
+//
+// The same shape always comes out the same however its file was ordered,
+// unless vertices share a position.
+func Anonymize(poly Polyhedron, opts AnonymizeOptions) (anon *Anonymized, err error) {
+    defer (&progress{stage: "anonymize", face: -1}).recover(&err)
+    for f, face := range poly.Faces {
+        if !faceInRange(poly, face) {
+            return nil, &ErrDegenerateFace{Face: f, Reason: "vertex out of range"}
+        }
+    }
+    out := &Anonymized{Mesh: Polyhedron{Units: poly.Units}}
+
+    // the vertices still in use, by position
+    used := make([]bool, len(poly.Vertices))
+    for _, face := range poly.Faces {
+        for _, v := range face.Vertices {
+            used[v] = true
+        }
+        for _, hole := range face.Holes {
+            for _, v := range hole {
+                used[v] = true
+            }
+        }
+    }
+    var keep []int
+    for v, ok := range used {
+        if ok {
+            keep = append(keep, v)
+        }
+    }
+    sort.SliceStable(keep, func(i, j int) bool {
+        a, b := poly.Vertices[keep[i]], poly.Vertices[keep[j]]
+        if a.X != b.X {
+            return a.X < b.X
+        }
+        if a.Y != b.Y {
+            return a.Y < b.Y
+        }
+        return a.Z < b.Z
+    })
+    out.Vertices = make([]int, len(poly.Vertices))
+    for v := range out.Vertices {
+        out.Vertices[v] = -1
+    }
+    out.Mesh.Vertices = make([]Vector3, len(keep))
+    for i, v := range keep {
+        out.Vertices[v] = i
+        out.Mesh.Vertices[i] = poly.Vertices[v]
+    }
+
+    if opts.Normalize && len(keep) > 0 {
+        lo, hi := boundingBox(out.Mesh.Vertices)
+        c := scale(add(lo, hi), 0.5)
+        s := math.Max(hi.X-lo.X, math.Max(hi.Y-lo.Y, hi.Z-lo.Z))
+        if s == 0 {
+            s = 1
+        }
+        for i, p := range out.Mesh.Vertices {
+            out.Mesh.Vertices[i] = scale(sub(p, c), 1/s)
+        }
+        out.Mesh.Units = ""
+    }
+
+    // faces renumbered, each starting at its lowest vertex, then sorted
+    faces := make([]Face, len(poly.Faces))
+    for f, face := range poly.Faces {
+        n := len(face.Vertices)
+        start := 0
+        for i, v := range face.Vertices {
+            if out.Vertices[v] < out.Vertices[face.Vertices[start]] {
+                start = i
+            }
+        }
+        nf := Face{Vertices: make([]int, n), Holes: copyHoles(face.Holes, func(v int) int { return out.Vertices[v] })}
+        textured := opts.KeepUVs && len(face.UVs) == n
+        for i := range face.Vertices {
+            k := (start + i) % n
+            nf.Vertices[i] = out.Vertices[face.Vertices[k]]
+            if textured {
+                nf.UVs = append(nf.UVs, face.UVs[k])
+            }
+        }
+        faces[f] = nf
+    }
+    order := make([]int, len(faces))
+    for i := range order {
+        order[i] = i
+    }
+    sort.SliceStable(order, func(i, j int) bool {
+        a, b := faces[order[i]].Vertices, faces[order[j]].Vertices
+        for k := 0; k < len(a) && k < len(b); k++ {
+            if a[k] != b[k] {
+                return a[k] < b[k]
+            }
+        }
+        return len(a) < len(b)
+    })
+    out.Faces = make([]int, len(faces))
+    out.Mesh.Faces = make([]Face, len(faces))
+    for i, f := range order {
+        out.Faces[f] = i
+        out.Mesh.Faces[i] = faces[f]
+    }
+    return out, nil
+}
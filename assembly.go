@@ -0,0 +1,180 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strconv"
+)
+
+// -----------------------------
+//   Assembly order
+// -----------------------------
+
+// AssemblyStep is one step of building the model from its net: fold face
+// Face onto face To along the fold edge between them, or (Glue) glue the
+// cut edge between them shut. The first face of every piece has To -1 and
+// no edge. Faces are mesh indices; AssemblyPlan.Numbers has the numbers
+// printed on the net.
+type AssemblyStep struct {
+    Step  int    `json:"step"` // 1 upwards
+    Face  int    `json:"face"`
+    To    int    `json:"to"`
+    Edge  [2]int `json:"edge"` // mesh vertices of the edge, smaller first
+    Glue  bool   `json:"glue,omitempty"`
+    Label int    `json:"label,omitempty"` // edge label of a glued edge, as AssignEdgeLabels gives it
+}
+
+// AssemblyPlan is the order to build a net in, see Assembly.
+type AssemblyPlan struct {
+    // Numbers is every face's number in assembly order, 1 upwards, indexed
+    // by face; 0 for faces not in the net.
+    Numbers []int          `json:"numbers"`
+    Steps   []AssemblyStep `json:"steps"`
+}
+
+// Assembly orders the faces of result for building: every piece of the net
+// starts at its root (largest pieces first) and its faces follow their
+// parents in the spanning tree, breadth first, so each face is folded to a
+// face already in place. A seam (a cut edge between two faces of the net)
+// is glued as soon as both its faces are. Faces are numbered in that order;
+// print the numbers on the net with Overlay and the steps with
+// Instructions.
+func Assembly(result *UnfoldResult) *AssemblyPlan {
+    plan := &AssemblyPlan{}
+    if result == nil {
+        return plan
+    }
+    nFaces := len(result.Face2D)
+    plan.Numbers = make([]int, nFaces)
+    placed := func(f int) bool { return f >= 0 && f < nFaces && len(result.Face2D[f].Vertices) >= 3 }
+    parentOf := func(f int) int {
+        if f < len(result.SpanningTree) && placed(result.SpanningTree[f]) {
+            return result.SpanningTree[f]
+        }
+        return -1
+    }
+
+    // the tree's children, and a fold edge for every face and its parent
+    children := make([][]int, nFaces)
+    var roots []int
+    for f := 0; f < nFaces; f++ {
+        if !placed(f) {
+            continue
+        }
+        if p := parentOf(f); p >= 0 {
+            children[p] = append(children[p], f)
+        } else {
+            roots = append(roots, f)
+        }
+    }
+    hinge := make(map[[2]int][2]int)
+    for _, e := range result.FoldEdges {
+        hinge[sortPair(e.FaceA, e.FaceB)] = sortPair(e.Vertices[0], e.Vertices[1])
+    }
+
+    // largest piece first
+    size := make(map[int]float64, len(roots))
+    for _, r := range roots {
+        stack := []int{r}
+        for len(stack) > 0 {
+            f := stack[len(stack)-1]
+            stack = stack[:len(stack)-1]
+            size[r] += math.Abs(polygonArea(result.Face2D[f].Vertices))
+            stack = append(stack, children[f]...)
+        }
+    }
+    sort.SliceStable(roots, func(i, j int) bool { return size[roots[i]] > size[roots[j]] })
+
+    // seams, by the faces they join
+    labels := AssignEdgeLabels(result)
+    seams := make([][]NetEdge, nFaces)
+    cuts := append([]NetEdge(nil), result.CutEdges...)
+    sortNetEdges(cuts)
+    for _, e := range cuts {
+        if placed(e.FaceA) && placed(e.FaceB) {
+            seams[e.FaceA] = append(seams[e.FaceA], e)
+            seams[e.FaceB] = append(seams[e.FaceB], e)
+        }
+    }
+
+    n := 0
+    add := func(f, to int, edge [2]int) {
+        n++
+        plan.Numbers[f] = n
+        plan.Steps = append(plan.Steps, AssemblyStep{Step: len(plan.Steps) + 1, Face: f, To: to, Edge: edge})
+        for _, e := range seams[f] {
+            other := e.FaceA
+            if other == f {
+                other = e.FaceB
+            }
+            if plan.Numbers[other] == 0 || other == f {
+                continue
+            }
+            plan.Steps = append(plan.Steps, AssemblyStep{
+                Step:  len(plan.Steps) + 1,
+                Face:  f,
+                To:    other,
+                Edge:  sortPair(e.Vertices[0], e.Vertices[1]),
+                Glue:  true,
+                Label: labels[EdgeInstance{e.FaceA, e.EdgeA}],
+            })
+        }
+    }
+    for _, r := range roots {
+        add(r, -1, [2]int{-1, -1})
+        queue := []int{r}
+        for len(queue) > 0 {
+            f := queue[0]
+            queue = queue[1:]
+            for _, c := range children[f] {
+                add(c, f, hinge[sortPair(f, c)])
+                queue = append(queue, c)
+            }
+        }
+    }
+    return plan
+}
+
+// Instructions words the steps, calling faces by their numbers:
+// "3. fold face 3 to face 1", "7. glue face 6 to face 2 (edge 4)".
+func (p *AssemblyPlan) Instructions() []string {
+    out := make([]string, len(p.Steps))
+    num := func(f int) int {
+        if f >= 0 && f < len(p.Numbers) {
+            return p.Numbers[f]
+        }
+        return 0
+    }
+    for i, s := range p.Steps {
+        switch {
+        case s.To < 0:
+            out[i] = fmt.Sprintf("%d. start with face %d", s.Step, num(s.Face))
+        case s.Glue && s.Label > 0:
+            out[i] = fmt.Sprintf("%d. glue face %d to face %d (edge %d)", s.Step, num(s.Face), num(s.To), s.Label)
+        case s.Glue:
+            out[i] = fmt.Sprintf("%d. glue face %d to face %d", s.Step, num(s.Face), num(s.To))
+        default:
+            out[i] = fmt.Sprintf("%d. fold face %d to face %d", s.Step, num(s.Face), num(s.To))
+        }
+    }
+    return out
+}
+
+// Overlay writes every face's number in the middle of it. size is the text
+// height in net units; 0 picks a quarter of the median edge length.
+func (p *AssemblyPlan) Overlay(result *UnfoldResult, size float64) *Overlay {
+    o := &Overlay{Name: "assembly"}
+    if result == nil {
+        return o
+    }
+    if size <= 0 {
+        size = edgeTextSize(result) * 6 / 4
+    }
+    for f, n := range p.Numbers {
+        if n > 0 && f < len(result.Face2D) && len(result.Face2D[f].Vertices) >= 3 {
+            o.Texts = append(o.Texts, OverlayText{At: interiorPoint(result.Face2D[f].Vertices), Text: strconv.Itoa(n), Size: size})
+        }
+    }
+    return o
+}
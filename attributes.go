@@ -0,0 +1,57 @@
+package unfolder
+
+import (
+    "fmt"
+    "sort"
+)
+
+// -----------------------------
+//   Vertex attributes
+// -----------------------------
+
+// VertexAttributes holds extra per-vertex values that came with a mesh, such
+// as colours ("red", "green", "blue") or texture coordinates ("s", "t"), by
+// property name. Every slice has one value per mesh vertex. meshio.LoadPLY
+// reads them and meshio.WritePLY writes them back.
+type VertexAttributes map[string][]float64
+
+// Names returns the attribute names, sorted.
+func (a VertexAttributes) Names() []string {
+    names := make([]string, 0, len(a))
+    for name := range a {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    return names
+}
+
+// Check reports an attribute that doesn't have one value per vertex of poly.
+func (a VertexAttributes) Check(poly Polyhedron) error {
+    for _, name := range a.Names() {
+        if len(a[name]) != len(poly.Vertices) {
+            return fmt.Errorf("attribute %q has %d values for %d vertices", name, len(a[name]), len(poly.Vertices))
+        }
+    }
+    return nil
+}
+
+// ForInstances carries the attributes over to the net: the result has one
+// value per entry of result.VertexInstances, taken from the instance's mesh
+// vertex, so every corner of every placed face gets its vertex's colour (or
+// whatever the attribute is). Instances without a known vertex get 0.
+func (a VertexAttributes) ForInstances(result *UnfoldResult) VertexAttributes {
+    out := make(VertexAttributes, len(a))
+    if result == nil {
+        return out
+    }
+    for name, vals := range a {
+        per := make([]float64, len(result.VertexInstances))
+        for i, in := range result.VertexInstances {
+            if in.Vertex >= 0 && in.Vertex < len(vals) {
+                per[i] = vals[in.Vertex]
+            }
+        }
+        out[name] = per
+    }
+    return out
+}
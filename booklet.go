@@ -0,0 +1,577 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "sort"
+    "strings"
+)
+
+// -----------------------------
+//   PDF instruction booklet
+// -----------------------------
+
+// BookletOptions controls ExportBooklet.
+type BookletOptions struct {
+    // Title printed on the cover. Default: the mesh's name, else "Paper model".
+    Title string
+    // Net prints the net pages as ExportPDF would; its Page and Landscape
+    // set the paper of the whole booklet. Mesh defaults to the booklet's mesh
+    // and the glue codes of every seam are added to the overlays.
+    Net PDFOptions
+    // Camera the cover picture is taken with (see RenderPreview).
+    Camera Camera
+    // StepFaces is about how many faces each assembly step brings together.
+    // Default 8.
+    StepFaces int
+    // Regions names faces in the step instructions (see LoadOBJGroups);
+    // faces without a region are called by their index.
+    Regions FaceRegions
+}
+
+// ExportBooklet writes a whole kit as one PDF: a cover with a picture of the
+// model, a difficulty rating and the line legend, then the net (tiled as in
+// ExportPDF, with glue codes on every seam and, if there are several pieces,
+// their names, see PieceNames), then the assembly steps, two to a page.
+// Each step is a piece of the spanning tree: its faces are highlighted on a
+// small copy of the net with the folds to make and the seams to glue. Every
+// step builds on the ones before, and the seams are glued from the far side
+// of the model towards the largest face, which closes it up last, so each
+// one can still be reached.
+func ExportBooklet(poly Polyhedron, result *UnfoldResult, w io.Writer, opts BookletOptions) error {
+    if result == nil {
+        return errors.New("nil unfold result")
+    }
+    if len(result.Face2D) != len(poly.Faces) {
+        return fmt.Errorf("net has %d faces, mesh %d", len(result.Face2D), len(poly.Faces))
+    }
+    if opts.Title == "" {
+        opts.Title = poly.Name
+    }
+    if opts.Title == "" {
+        opts.Title = "Paper model"
+    }
+    if opts.StepFaces <= 0 {
+        opts.StepFaces = 8
+    }
+    g, err := NetGluingGraph(poly, result)
+    if err != nil {
+        return err
+    }
+    g.namePieces(poly, opts.Regions)
+
+    net := opts.Net
+    if net.Mesh == nil {
+        net.Mesh = &poly
+    }
+    n := len(net.Overlays)
+    net.Overlays = append(net.Overlays[:n:n], EdgeCodeOverlay(result, g, 0))
+    if len(g.Pieces) > 1 {
+        // the piece names the instructions use
+        names := make(FaceRegions, len(result.Face2D))
+        for _, p := range g.Pieces {
+            for _, f := range p.Faces {
+                names[f] = p.Name
+            }
+        }
+        net.Overlays = append(net.Overlays, RegionOverlay(result, names, 0))
+    }
+    netPages, page, err := pdfNetPages(result, net)
+    if err != nil {
+        return err
+    }
+    margin := net.Margin
+    if margin <= 0 {
+        margin = 10
+    }
+
+    steps := bookletSteps(result, g, opts.StepFaces)
+    stepPages := (len(steps) + 1) / 2
+    cover := bookletCover(poly, result, g, opts, page, margin, len(netPages), len(steps), stepPages)
+    pages := append([][]byte{cover}, netPages...)
+    for i := 0; i < len(steps); i += 2 {
+        pages = append(pages, bookletStepPage(poly, result, g, steps, i, opts.Regions, page, margin, len(pages)+1))
+    }
+    return writePDF(w, pages, page.Width*mmToPt, page.Height*mmToPt)
+}
+
+// bookletStep is one assembly step.
+type bookletStep struct {
+    faces []int      // faces that come together in this step
+    folds []NetEdge  // folds to make: every fold hanging off those faces
+    mates []EdgeMate // seams whose faces are both done by the end of the step
+}
+
+// bookletSteps cuts the spanning tree into steps of about size faces,
+// working up from the leaves: a face collects the faces below it that
+// aren't in a step yet, and once it has size of them they become a step.
+//
+// The steps are then built from the roots out, each folding its faces and
+// the fold onto the step above it, in an order that keeps every seam within
+// reach. The model closes up last around its largest face: its seams stay
+// open to the end, leaving an opening to reach in through, and are then
+// pressed shut from outside. The rest are glued as soon as both their faces
+// are in place, deepest first by how many faces they lie from the opening,
+// and of the steps that can be built next, the one reaching deepest goes
+// first, so the far side of the model is closed while the most of it is
+// still open.
+func bookletSteps(result *UnfoldResult, g *GluingGraph, size int) []bookletStep {
+    nFaces := len(result.Face2D)
+    placed := func(f int) bool { return f >= 0 && f < nFaces && len(result.Face2D[f].Vertices) > 0 }
+    parentOf := func(f int) int {
+        if f < len(result.SpanningTree) && placed(result.SpanningTree[f]) {
+            return result.SpanningTree[f]
+        }
+        return -1
+    }
+    // folds by the faces they join, and the tree's children
+    folds := make(map[[2]int]NetEdge, len(result.FoldEdges))
+    for _, e := range result.FoldEdges {
+        folds[sortPair(e.FaceA, e.FaceB)] = e
+    }
+    children := make([][]int, nFaces)
+    var order []int // breadth first from the roots
+    for f := 0; f < nFaces; f++ {
+        if !placed(f) {
+            continue
+        }
+        if p := parentOf(f); p >= 0 {
+            children[p] = append(children[p], f)
+        } else {
+            order = append(order, f)
+        }
+    }
+    for i := 0; i < len(order); i++ {
+        order = append(order, children[order[i]]...)
+    }
+
+    // cut the tree into groups, leaves first
+    var groups [][]int
+    stepOf := make([]int, nFaces)
+    pending := make([][]int, nFaces)
+    for i := len(order) - 1; i >= 0; i-- {
+        f := order[i]
+        pending[f] = append(pending[f], f)
+        for _, c := range children[f] {
+            pending[f] = append(pending[f], pending[c]...)
+            pending[c] = nil
+        }
+        if len(pending[f]) >= size || parentOf(f) < 0 {
+            for _, h := range pending[f] {
+                stepOf[h] = len(groups)
+            }
+            groups = append(groups, pending[f])
+            pending[f] = nil
+        }
+    }
+    above := make([]int, len(groups)) // the group each one hangs off, -1 at a root
+    for k, faces := range groups {
+        above[k] = -1
+        if p := parentOf(faces[0]); p >= 0 {
+            above[k] = stepOf[p]
+        }
+    }
+
+    // reach: the deepest any group's faces lie from the opening
+    depth := seamDepths(result)
+    reach := make([]int, len(groups))
+    for k, faces := range groups {
+        for _, f := range faces {
+            if depth[f] > reach[k] {
+                reach[k] = depth[f]
+            }
+        }
+    }
+    var steps []bookletStep
+    position := make([]int, len(groups))
+    done := make([]bool, len(groups))
+    for len(steps) < len(groups) {
+        next := -1
+        for k := range groups {
+            ready := above[k] < 0 || done[above[k]]
+            if !done[k] && ready && (next < 0 || reach[k] > reach[next]) {
+                next = k
+            }
+        }
+        done[next] = true
+        position[next] = len(steps)
+        faces := groups[next]
+        sort.Ints(faces)
+        st := bookletStep{faces: faces}
+        for _, f := range faces {
+            if e, ok := folds[sortPair(f, parentOf(f))]; ok {
+                st.folds = append(st.folds, e)
+            }
+        }
+        sortNetEdges(st.folds)
+        steps = append(steps, st)
+    }
+
+    // seams in the first step that has both their faces, deepest first;
+    // the opening's in the last one
+    type seam struct{ mate, depth, ready int }
+    var seams []seam
+    for i, m := range g.Mates {
+        if !placed(m.FaceA) || !placed(m.FaceB) {
+            continue
+        }
+        d := depth[m.FaceA]
+        if depth[m.FaceB] < d {
+            d = depth[m.FaceB]
+        }
+        ready := position[stepOf[m.FaceA]]
+        if r := position[stepOf[m.FaceB]]; r > ready {
+            ready = r
+        }
+        if d == 0 {
+            ready = len(steps) - 1
+        }
+        seams = append(seams, seam{i, d, ready})
+    }
+    sort.SliceStable(seams, func(i, j int) bool {
+        if seams[i].ready != seams[j].ready {
+            return seams[i].ready < seams[j].ready
+        }
+        return seams[i].depth > seams[j].depth
+    })
+    for _, s := range seams {
+        steps[s.ready].mates = append(steps[s.ready].mates, g.Mates[s.mate])
+    }
+    return steps
+}
+
+// seamDepths returns how many edges away from the largest placed face every
+// face is, crossing folds and seams alike (-1 for faces out of reach).
+func seamDepths(result *UnfoldResult) []int {
+    nFaces := len(result.Face2D)
+    depth := make([]int, nFaces)
+    for i := range depth {
+        depth[i] = -1
+    }
+    open, best := -1, 0.0
+    for f, f2 := range result.Face2D {
+        if len(f2.Vertices) < 3 {
+            continue
+        }
+        if a := math.Abs(polygonArea(f2.Vertices)); open < 0 || a > best {
+            open, best = f, a
+        }
+    }
+    if open < 0 {
+        return depth
+    }
+    nbrs := make([][]int, nFaces)
+    for _, edges := range [][]NetEdge{result.FoldEdges, result.CutEdges} {
+        for _, e := range edges {
+            if e.FaceB >= 0 {
+                nbrs[e.FaceA] = append(nbrs[e.FaceA], e.FaceB)
+                nbrs[e.FaceB] = append(nbrs[e.FaceB], e.FaceA)
+            }
+        }
+    }
+    depth[open] = 0
+    queue := []int{open}
+    for len(queue) > 0 {
+        f := queue[0]
+        queue = queue[1:]
+        for _, g := range nbrs[f] {
+            if depth[g] < 0 {
+                depth[g] = depth[f] + 1
+                queue = append(queue, g)
+            }
+        }
+    }
+    return depth
+}
+
+// bookletCover draws the cover page.
+func bookletCover(poly Polyhedron, result *UnfoldResult, g *GluingGraph, opts BookletOptions, page PageSize, margin float64, netPages, steps, stepPages int) []byte {
+    scale := opts.Net.Scale
+    if scale <= 0 {
+        scale = exportScale(&poly, "mm")
+    }
+    // the numbers behind the rating
+    diff, err := ScoreDifficulty(poly, result, DifficultyOptions{Scale: scale})
+    if err != nil {
+        diff = &Difficulty{}
+    }
+    mountains, valleys := 0, 0
+    for _, e := range result.FoldEdges {
+        switch foldClass(&poly, result.Side, e) {
+        case lineMountain:
+            mountains++
+        case lineValley:
+            valleys++
+        }
+    }
+
+    var cs pdfContent
+    cs.op("q %s 0 0 %s 0 0 cm", pdfNum(mmToPt), pdfNum(mmToPt))
+    cs.op("0 0 0 rg")
+    y := page.Height - margin - 8
+    cs.text(margin, y, 9, opts.Title)
+    y -= 8
+    cs.text(margin, y, 4, fmt.Sprintf("%d faces, %d %s, %d %s to cut out", diff.Faces, len(g.Pieces), plural(len(g.Pieces), "piece"), netPages, plural(netPages, "page")))
+
+    // the picture takes the upper half of what's left
+    top := y - 6
+    h := (top - margin) / 2
+    bookletPreview(&cs, poly, opts.Camera, margin, top-h, page.Width-2*margin, h)
+
+    y = top - h - 12
+    cs.op("0 0 0 rg")
+    cs.text(margin, y, 5, fmt.Sprintf("Difficulty: %d of 5 (%s)", diff.Score, diff.Level))
+    y -= 4
+    facts := []string{
+        fmt.Sprintf("Folds: %d (%d mountain, %d valley)", len(result.FoldEdges), mountains, valleys),
+        fmt.Sprintf("Seams to glue: %d", len(g.Mates)),
+        fmt.Sprintf("Shortest edge: %s mm", pdfNum(math.Round(diff.ShortestEdge*10)/10)),
+        fmt.Sprintf("Sharpest fold: %s degrees", pdfNum(math.Round(diff.SharpestFold))),
+    }
+    if diff.Faces == 0 {
+        facts = facts[:2]
+    }
+    if diff.CurvedRegions > 0 {
+        facts = append(facts, fmt.Sprintf("Curved areas to shape: %d", diff.CurvedRegions))
+    }
+    for _, s := range facts {
+        y -= 5.5
+        cs.text(margin, y, 3.5, s)
+    }
+
+    // line legend
+    y -= 10
+    cs.text(margin, y, 4, "Lines")
+    for _, l := range []struct{ class, text string }{
+        {lineCut, "cut"},
+        {lineMountain, "mountain fold: the crease points at you"},
+        {lineValley, "valley fold: the crease points away from you"},
+    } {
+        y -= 6
+        cs.op("%s 0.4 w %s %s m %s %s l S", pdfLineStyle(l.class, 0.4), pdfNum(margin), pdfNum(y+1), pdfNum(margin+15), pdfNum(y+1))
+        cs.text(margin+20, y, 3.5, l.text)
+    }
+
+    // what's where
+    y -= 10
+    cs.text(margin, y, 4, "Contents")
+    y -= 6
+    cs.text(margin, y, 3.5, fmt.Sprintf("%s: the net. Cut along the solid lines; letters mark the edges glued together.", pageRange(2, netPages)))
+    if steps > 0 {
+        y -= 5.5
+        cs.text(margin, y, 3.5, fmt.Sprintf("%s: assembly in %d %s.", pageRange(2+netPages, stepPages), steps, plural(steps, "step")))
+    }
+    cs.op("Q")
+    return cs.Bytes()
+}
+
+// bookletPreview draws poly flat shaded as the camera sees it, far faces
+// first, fitted into the box at (x, y), w by h mm.
+func bookletPreview(cs *pdfContent, poly Polyhedron, cam Camera, x, y, w, h float64) {
+    faces, lo, hi := cam.project(poly)
+    if len(faces) == 0 || hi.X <= lo.X && hi.Y <= lo.Y {
+        return
+    }
+    to := fitBox(lo, hi, x, y, w, h)
+    cs.op("0.2 0.2 0.2 RG 0.1 w [] 0 d 1 j")
+    for _, f := range faces {
+        for i, p := range f.pts {
+            px, py := to(p)
+            if i == 0 {
+                cs.op("%s %s m", pdfNum(px), pdfNum(py))
+            } else {
+                cs.op("%s %s l", pdfNum(px), pdfNum(py))
+            }
+        }
+        cs.op("h %s g b", pdfNum(0.87*f.shade))
+    }
+}
+
+// bookletStepPage draws steps[first] and the one after it, if any, one above
+// the other. number is the page's number in the booklet.
+func bookletStepPage(poly Polyhedron, result *UnfoldResult, g *GluingGraph, steps []bookletStep, first int, regions FaceRegions, page PageSize, margin float64, number int) []byte {
+    var cs pdfContent
+    cs.op("q %s 0 0 %s 0 0 cm", pdfNum(mmToPt), pdfNum(mmToPt))
+    half := (page.Height - 2*margin) / 2
+    for k := 0; k < 2 && first+k < len(steps); k++ {
+        top := page.Height - margin - float64(k)*half
+        bookletDrawStep(&cs, poly, result, g, steps, first+k, regions, margin, top, page.Width-2*margin, half-4)
+    }
+    cs.op("0 0 0 rg")
+    cs.text(margin, margin/2, 3, fmt.Sprintf("page %d", number))
+    cs.op("Q")
+    return cs.Bytes()
+}
+
+// bookletDrawStep draws step i in the box of width w whose top left corner is
+// (x, top): heading, the net with the step marked, and what to do.
+func bookletDrawStep(cs *pdfContent, poly Polyhedron, result *UnfoldResult, g *GluingGraph, steps []bookletStep, i int, regions FaceRegions, x, top, w, h float64) {
+    st := steps[i]
+    cs.op("0 0 0 rg")
+    cs.text(x, top-5, 5, fmt.Sprintf("Step %d of %d", i+1, len(steps)))
+
+    // the words first, so the picture gets whatever room is left
+    names := make([]string, len(st.faces))
+    for k, f := range st.faces {
+        names[k] = regions.Name(f)
+    }
+    mountains, valleys := 0, 0
+    for _, e := range st.folds {
+        switch foldClass(&poly, result.Side, e) {
+        case lineMountain:
+            mountains++
+        case lineValley:
+            valleys++
+        }
+    }
+    var text []string
+    text = append(text, wrapText("Take "+strings.Join(names, ", ")+" (shaded).", w, 3.5)...)
+    if len(st.folds) > 0 {
+        text = append(text, fmt.Sprintf("Fold along the %d thick %s: %d mountain, %d valley.", len(st.folds), plural(len(st.folds), "line"), mountains, valleys))
+    }
+    glue := GlueInstructions(&GluingGraph{Pieces: g.Pieces, Mates: st.mates}, regions)
+    for k, s := range glue {
+        glue[k] = strings.ToUpper(s[:1]) + s[1:] + "."
+    }
+    text = append(text, glue...)
+    lines := int((h - 8) / 2 / 5) // the text gets at most half the box
+    if lines < 2 {
+        lines = 2
+    }
+    if len(text) > lines {
+        more := len(text) - lines + 1
+        text = append(text[:lines-1], fmt.Sprintf("... and %d more.", more))
+    }
+    textTop := top - h + float64(len(text))*5
+    for k, s := range text {
+        cs.text(x, textTop-float64(k+1)*5+1, 3.5, s)
+    }
+
+    // the net, with earlier steps grey and this one shaded
+    lo, hi, ok := sheetBounds(result, nil)
+    if !ok {
+        return
+    }
+    to := fitBox(lo, hi, x, textTop+3, w, top-9-(textTop+3))
+    here := make(map[int]bool, len(st.faces))
+    for _, f := range st.faces {
+        here[f] = true
+    }
+    earlier := make(map[int]bool)
+    for _, s := range steps[:i] {
+        for _, f := range s.faces {
+            earlier[f] = true
+        }
+    }
+    path := func(pts []Point2) {
+        for k, p := range pts {
+            px, py := to(p)
+            if k == 0 {
+                cs.op("%s %s m", pdfNum(px), pdfNum(py))
+            } else {
+                cs.op("%s %s l", pdfNum(px), pdfNum(py))
+            }
+        }
+        cs.op("h")
+    }
+    for f, face := range result.Face2D {
+        if len(face.Vertices) < 3 {
+            continue
+        }
+        switch {
+        case here[f]:
+            cs.op("1 0.87 0.5 rg")
+        case earlier[f]:
+            cs.op("0.85 0.85 0.85 rg")
+        default:
+            continue
+        }
+        path(face.Vertices)
+        cs.op("f")
+    }
+    cs.op("0 0 0 RG 0.15 w [] 0 d 1 J 1 j")
+    for _, l := range sheetLines(result, nil, nil, 0) {
+        if l.class != lineCut {
+            continue
+        }
+        ax, ay := to(l.a)
+        bx, by := to(l.b)
+        cs.op("%s %s m %s %s l", pdfNum(ax), pdfNum(ay), pdfNum(bx), pdfNum(by))
+    }
+    cs.op("S")
+    for _, e := range st.folds {
+        pts := result.Face2D[e.FaceA].Vertices
+        ax, ay := to(pts[e.EdgeA])
+        bx, by := to(pts[(e.EdgeA+1)%len(pts)])
+        cs.op("%s", pdfLineStyle(foldClass(&poly, result.Side, e), 0.5))
+        cs.op("0.5 w %s %s m %s %s l S", pdfNum(ax), pdfNum(ay), pdfNum(bx), pdfNum(by))
+    }
+    // glued seams: both edges in green, with their code
+    cs.op("0 0.55 0 RG 0 0.55 0 rg 0.6 w [] 0 d")
+    for _, m := range st.mates {
+        for _, side := range [2]EdgeInstance{{m.FaceA, m.EdgeA}, {m.FaceB, m.EdgeB}} {
+            pts := result.Face2D[side.Face].Vertices
+            a, b := pts[side.Edge], pts[(side.Edge+1)%len(pts)]
+            ax, ay := to(a)
+            bx, by := to(b)
+            cs.op("%s %s m %s %s l S", pdfNum(ax), pdfNum(ay), pdfNum(bx), pdfNum(by))
+            cs.text((ax+bx)/2-pdfTextWidth(m.Code, 2.5)/2, (ay+by)/2+0.8, 2.5, m.Code)
+        }
+    }
+}
+
+// fitBox returns the mapping that scales the box lo..hi (Y up) to fit
+// centred in the w by h box at (x, y).
+func fitBox(lo, hi Point2, x, y, w, h float64) func(Point2) (float64, float64) {
+    s := math.Inf(1)
+    if hi.X > lo.X {
+        s = w / (hi.X - lo.X)
+    }
+    if hi.Y > lo.Y {
+        s = math.Min(s, h/(hi.Y-lo.Y))
+    }
+    if math.IsInf(s, 1) || s <= 0 {
+        s = 1
+    }
+    ox := x + (w-(hi.X-lo.X)*s)/2
+    oy := y + (h-(hi.Y-lo.Y)*s)/2
+    return func(p Point2) (float64, float64) {
+        return ox + (p.X-lo.X)*s, oy + (p.Y-lo.Y)*s
+    }
+}
+
+// wrapText breaks s into lines no wider than width at the given font size.
+func wrapText(s string, width, size float64) []string {
+    var lines []string
+    line := ""
+    for _, word := range strings.Fields(s) {
+        if line != "" && pdfTextWidth(line+" "+word, size) > width {
+            lines = append(lines, line)
+            line = word
+            continue
+        }
+        if line != "" {
+            line += " "
+        }
+        line += word
+    }
+    return append(lines, line)
+}
+
+// plural adds an s to word unless n is 1.
+func plural(n int, word string) string {
+    if n == 1 {
+        return word
+    }
+    return word + "s"
+}
+
+// pageRange writes the n pages from first as "Page 3" or "Pages 3-5".
+func pageRange(first, n int) string {
+    if n <= 1 {
+        return fmt.Sprintf("Page %d", first)
+    }
+    return fmt.Sprintf("Pages %d-%d", first, first+n-1)
+}
@@ -0,0 +1,391 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+    "runtime"
+    "sort"
+    "sync"
+)
+
+// -----------------------------
+//   Bounding volume hierarchy
+// -----------------------------
+
+// BVH is a bounding volume hierarchy over the faces of a mesh, for geometric
+// queries that would otherwise have to look at every face: nearest point,
+// ray casts, and faces near a box or a plane. Faces are split into triangles
+// (see Triangulate); queries answer with the original face index.
+//
+// A BVH doesn't change once built, except through Refit, so any number of
+// goroutines can query it at once.
+type BVH struct {
+    verts []Vector3
+    tris  []bvhTri
+    root  *bvhNode
+}
+
+type bvhTri struct {
+    v    [3]int
+    face int
+}
+
+// bvhNode is a box around its children, or around tris[start:end] for a leaf.
+type bvhNode struct {
+    lo, hi      Vector3
+    left, right *bvhNode
+    start, end  int
+}
+
+// bvhLeafSize is how many triangles a leaf holds at most.
+const bvhLeafSize = 4
+
+// bvhParallelMin is the fewest triangles worth handing to another goroutine.
+const bvhParallelMin = 4096
+
+// NewBVH builds a BVH over the faces of poly, splitting the top of the tree
+// across workers goroutines (<= 0 means GOMAXPROCS).
+func NewBVH(poly Polyhedron, workers int) (*BVH, error) {
+    tri, origin, err := Triangulate(poly, 0)
+    if err != nil {
+        return nil, err
+    }
+    b := &BVH{verts: append([]Vector3(nil), poly.Vertices...)}
+    for i, f := range tri.Faces {
+        for k := 1; k+1 < len(f.Vertices); k++ {
+            b.tris = append(b.tris, bvhTri{v: [3]int{f.Vertices[0], f.Vertices[k], f.Vertices[k+1]}, face: origin[i]})
+        }
+    }
+    if len(b.tris) == 0 {
+        return b, nil
+    }
+    if workers <= 0 {
+        workers = runtime.GOMAXPROCS(0)
+    }
+    depth := 0 // levels that still fork
+    for 1<<depth < workers {
+        depth++
+    }
+    b.root = b.build(0, len(b.tris), depth)
+    return b, nil
+}
+
+// build makes the node for tris[start:end], splitting at the median centroid
+// along the longest axis of the centroids' box.
+func (b *BVH) build(start, end, fork int) *bvhNode {
+    n := &bvhNode{start: start, end: end}
+    b.fitLeaf(n)
+    if end-start <= bvhLeafSize {
+        return n
+    }
+    clo, chi := Vector3{math.Inf(1), math.Inf(1), math.Inf(1)}, Vector3{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+    for _, t := range b.tris[start:end] {
+        c := b.centroid(t)
+        clo, chi = minVec(clo, c), maxVec(chi, c)
+    }
+    axis := func(v Vector3) float64 { return v.X }
+    if d := sub(chi, clo); d.Y > d.X && d.Y >= d.Z {
+        axis = func(v Vector3) float64 { return v.Y }
+    } else if d.Z > d.X && d.Z > d.Y {
+        axis = func(v Vector3) float64 { return v.Z }
+    }
+    part := b.tris[start:end]
+    keys := make([]float64, len(part))
+    for i, t := range part {
+        keys[i] = axis(b.centroid(t))
+    }
+    sort.Sort(bvhSorter{part, keys})
+
+    mid := (start + end) / 2
+    if fork > 0 && end-start >= bvhParallelMin {
+        var wg sync.WaitGroup
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            n.left = b.build(start, mid, fork-1)
+        }()
+        n.right = b.build(mid, end, fork-1)
+        wg.Wait()
+    } else {
+        n.left = b.build(start, mid, 0)
+        n.right = b.build(mid, end, 0)
+    }
+    return n
+}
+
+type bvhSorter struct {
+    tris []bvhTri
+    keys []float64
+}
+
+func (s bvhSorter) Len() int           { return len(s.tris) }
+func (s bvhSorter) Less(i, j int) bool { return s.keys[i] < s.keys[j] }
+func (s bvhSorter) Swap(i, j int) {
+    s.tris[i], s.tris[j] = s.tris[j], s.tris[i]
+    s.keys[i], s.keys[j] = s.keys[j], s.keys[i]
+}
+
+func (b *BVH) centroid(t bvhTri) Vector3 {
+    return scale(add(add(b.verts[t.v[0]], b.verts[t.v[1]]), b.verts[t.v[2]]), 1.0/3)
+}
+
+// corners returns the triangle's points.
+func (b *BVH) corners(t bvhTri) (Vector3, Vector3, Vector3) {
+    return b.verts[t.v[0]], b.verts[t.v[1]], b.verts[t.v[2]]
+}
+
+// fitLeaf sets n's box around tris[n.start:n.end].
+func (b *BVH) fitLeaf(n *bvhNode) {
+    n.lo = Vector3{math.Inf(1), math.Inf(1), math.Inf(1)}
+    n.hi = Vector3{math.Inf(-1), math.Inf(-1), math.Inf(-1)}
+    for _, t := range b.tris[n.start:n.end] {
+        for _, v := range t.v {
+            n.lo, n.hi = minVec(n.lo, b.verts[v]), maxVec(n.hi, b.verts[v])
+        }
+    }
+}
+
+// Refit moves the BVH to new vertex positions, e.g. after a transform or a
+// fold animation step, without rebuilding it: the tree stays the same and
+// only the boxes are recomputed. vertices must match the mesh it was built
+// from one for one. Queries get slower if the faces move far relative to
+// each other; rebuild then.
+func (b *BVH) Refit(vertices []Vector3) error {
+    if len(vertices) != len(b.verts) {
+        return fmt.Errorf("refit with %d vertices, BVH has %d", len(vertices), len(b.verts))
+    }
+    copy(b.verts, vertices)
+    var refit func(n *bvhNode)
+    refit = func(n *bvhNode) {
+        if n.left == nil {
+            b.fitLeaf(n)
+            return
+        }
+        refit(n.left)
+        refit(n.right)
+        n.lo, n.hi = minVec(n.left.lo, n.right.lo), maxVec(n.left.hi, n.right.hi)
+    }
+    if b.root != nil {
+        refit(b.root)
+    }
+    return nil
+}
+
+// Bounds returns the box around every face (zero for an empty BVH).
+func (b *BVH) Bounds() (lo, hi Vector3) {
+    if b.root == nil {
+        return
+    }
+    return b.root.lo, b.root.hi
+}
+
+// Nearest returns the face closest to p and its distance, or -1 and +Inf if
+// the BVH is empty.
+func (b *BVH) Nearest(p Vector3) (face int, dist float64) {
+    face, dist = -1, math.Inf(1)
+    if b.root == nil {
+        return
+    }
+    stack := []*bvhNode{b.root}
+    for len(stack) > 0 {
+        n := stack[len(stack)-1]
+        stack = stack[:len(stack)-1]
+        if boxDistance(p, n.lo, n.hi) >= dist {
+            continue
+        }
+        if n.left == nil {
+            for _, t := range b.tris[n.start:n.end] {
+                x, y, z := b.corners(t)
+                if d := pointTriangleDistance(p, x, y, z); d < dist {
+                    face, dist = t.face, d
+                }
+            }
+            continue
+        }
+        // visit the nearer child first: it's pushed last
+        if boxDistance(p, n.left.lo, n.left.hi) < boxDistance(p, n.right.lo, n.right.hi) {
+            stack = append(stack, n.right, n.left)
+        } else {
+            stack = append(stack, n.left, n.right)
+        }
+    }
+    return face, dist
+}
+
+// Raycast returns the first face the ray from origin along dir hits, and how
+// far along it is in units of dir (t > 0). ok is false if it hits nothing.
+// Faces are hit from either side.
+func (b *BVH) Raycast(origin, dir Vector3) (face int, t float64, ok bool) {
+    face, t = -1, math.Inf(1)
+    if b.root == nil {
+        return
+    }
+    inv := Vector3{1 / dir.X, 1 / dir.Y, 1 / dir.Z}
+    stack := []*bvhNode{b.root}
+    for len(stack) > 0 {
+        n := stack[len(stack)-1]
+        stack = stack[:len(stack)-1]
+        if !rayHitsBox(origin, inv, n.lo, n.hi, t) {
+            continue
+        }
+        if n.left == nil {
+            for _, tr := range b.tris[n.start:n.end] {
+                x, y, z := b.corners(tr)
+                if d, hit := rayTriangle(origin, dir, x, y, z); hit && d < t {
+                    face, t, ok = tr.face, d, true
+                }
+            }
+            continue
+        }
+        stack = append(stack, n.left, n.right)
+    }
+    return face, t, ok
+}
+
+// FacesInBox returns the faces with a triangle whose bounding box meets the
+// box [lo, hi], sorted. It's a broad phase: the faces themselves may miss.
+func (b *BVH) FacesInBox(lo, hi Vector3) []int {
+    return b.collect(func(nlo, nhi Vector3) bool {
+        return nlo.X <= hi.X && lo.X <= nhi.X && nlo.Y <= hi.Y && lo.Y <= nhi.Y && nlo.Z <= hi.Z && lo.Z <= nhi.Z
+    }, func(t bvhTri) bool {
+        x, y, z := b.corners(t)
+        tlo, thi := minVec(minVec(x, y), z), maxVec(maxVec(x, y), z)
+        return tlo.X <= hi.X && lo.X <= thi.X && tlo.Y <= hi.Y && lo.Y <= thi.Y && tlo.Z <= hi.Z && lo.Z <= thi.Z
+    })
+}
+
+// FacesNearPlane returns the faces that cross pl or come within eps of it,
+// sorted.
+func (b *BVH) FacesNearPlane(pl Plane, eps float64) []int {
+    n := normalize(pl.Normal)
+    d0 := dot(pl.Point, n)
+    // the distance range of a box is its centre's distance, give or take
+    // its half-size projected on the normal
+    straddles := func(lo, hi Vector3) bool {
+        c := scale(add(lo, hi), 0.5)
+        h := scale(sub(hi, lo), 0.5)
+        r := h.X*math.Abs(n.X) + h.Y*math.Abs(n.Y) + h.Z*math.Abs(n.Z)
+        return math.Abs(dot(c, n)-d0) <= r+eps
+    }
+    return b.collect(straddles, func(t bvhTri) bool {
+        x, y, z := b.corners(t)
+        dx, dy, dz := dot(x, n)-d0, dot(y, n)-d0, dot(z, n)-d0
+        return math.Min(dx, math.Min(dy, dz)) <= eps && math.Max(dx, math.Max(dy, dz)) >= -eps
+    })
+}
+
+// collect returns the sorted faces of the triangles that pass tri, in nodes
+// that pass box.
+func (b *BVH) collect(box func(lo, hi Vector3) bool, tri func(bvhTri) bool) []int {
+    var out []int
+    if b.root == nil {
+        return out
+    }
+    stack := []*bvhNode{b.root}
+    for len(stack) > 0 {
+        n := stack[len(stack)-1]
+        stack = stack[:len(stack)-1]
+        if !box(n.lo, n.hi) {
+            continue
+        }
+        if n.left == nil {
+            for _, t := range b.tris[n.start:n.end] {
+                if tri(t) {
+                    out = append(out, t.face)
+                }
+            }
+            continue
+        }
+        stack = append(stack, n.left, n.right)
+    }
+    sort.Ints(out)
+    k := 0
+    for i, f := range out {
+        if i == 0 || f != out[k-1] {
+            out[k] = f
+            k++
+        }
+    }
+    return out[:k]
+}
+
+// SelfIntersections returns the pairs of faces of poly that cut through
+// each other, smaller face first, sorted. Faces that share a vertex are
+// never reported: neighbours meet along their edges by design. Coplanar
+// overlaps aren't found either.
+func SelfIntersections(poly Polyhedron) ([][2]int, error) {
+    b, err := NewBVH(poly, 0)
+    if err != nil {
+        return nil, err
+    }
+    lo, hi := b.Bounds()
+    eps := 1e-9 * length(sub(hi, lo))
+    corners := func(f int) []Vector3 {
+        pts := make([]Vector3, len(poly.Faces[f].Vertices))
+        for i, v := range poly.Faces[f].Vertices {
+            pts[i] = poly.Vertices[v]
+        }
+        return pts
+    }
+    var out [][2]int
+    for f, face := range poly.Faces {
+        if len(face.Vertices) < 3 {
+            continue
+        }
+        pf := corners(f)
+        flo, fhi := boundingBox(pf)
+        for _, g := range b.FacesInBox(flo, fhi) {
+            if g <= f || shareVertex(face, poly.Faces[g]) {
+                continue
+            }
+            if polygonsPierce(pf, corners(g), eps) {
+                out = append(out, [2]int{f, g})
+            }
+        }
+    }
+    return out, nil
+}
+
+// rayHitsBox is the slab test: does the ray reach the box before tMax?
+func rayHitsBox(o, inv, lo, hi Vector3, tMax float64) bool {
+    t0, t1 := 0.0, tMax
+    for _, s := range [3][4]float64{{o.X, inv.X, lo.X, hi.X}, {o.Y, inv.Y, lo.Y, hi.Y}, {o.Z, inv.Z, lo.Z, hi.Z}} {
+        a, c := (s[2]-s[0])*s[1], (s[3]-s[0])*s[1]
+        if a > c {
+            a, c = c, a
+        }
+        if math.IsNaN(a) || math.IsNaN(c) {
+            // ray parallel to the slab and starting on its face
+            continue
+        }
+        t0, t1 = math.Max(t0, a), math.Min(t1, c)
+        if t0 > t1 {
+            return false
+        }
+    }
+    return true
+}
+
+// rayTriangle is Möller–Trumbore: where along dir the ray from o meets
+// triangle abc, if it does (t > 0).
+func rayTriangle(o, dir, a, b, c Vector3) (float64, bool) {
+    e1, e2 := sub(b, a), sub(c, a)
+    p := cross(dir, e2)
+    det := dot(e1, p)
+    if det == 0 {
+        return 0, false
+    }
+    inv := 1 / det
+    s := sub(o, a)
+    u := dot(s, p) * inv
+    if u < 0 || u > 1 {
+        return 0, false
+    }
+    q := cross(s, e1)
+    v := dot(dir, q) * inv
+    if v < 0 || u+v > 1 {
+        return 0, false
+    }
+    t := dot(e2, q) * inv
+    return t, t > 0
+}
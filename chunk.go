@@ -0,0 +1,293 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "sync"
+)
+
+// -----------------------------
+//   Chunked unfolding
+// -----------------------------
+
+// ChunkOptions controls UnfoldMeshChunked.
+type ChunkOptions struct {
+    // MaxFacesPerChunk is the largest number of faces a spatial chunk may hold.
+    // Chunks are split along their longest axis until they fit. Default 10000.
+    MaxFacesPerChunk int
+
+    // Workers is how many pieces are unfolded at the same time. Together with
+    // MaxFacesPerChunk this bounds peak memory. Default 1.
+    Workers int
+
+    // Unfold is passed to UnfoldMeshWithOptions for every piece, so e.g.
+    // Unfold.MemoryLimit acts as a per-piece budget.
+    Unfold UnfoldOptions
+}
+
+// ChunkPiece is one connected group of faces inside a chunk, unfolded on its own.
+type ChunkPiece struct {
+    Chunk    int   // index of the spatial chunk this piece came from
+    Faces    []int // global face indices; Faces[i] is local face i of Result
+    Vertices []int // global vertex indices; Vertices[i] is local vertex i of Result
+    Result   *UnfoldResult
+}
+
+// ChunkSeam records a mesh edge that was split between two pieces, so that
+// the pieces can be matched up again (edge labels, assembly, ...).
+type ChunkSeam struct {
+    Edge   [2]int // global vertex indices, smaller first
+    PieceA int
+    FaceA  int // global face index in PieceA
+    PieceB int
+    FaceB  int // global face index in PieceB
+}
+
+// ChunkedResult is the output of UnfoldMeshChunked.
+type ChunkedResult struct {
+    Chunks [][]int // global face indices per spatial chunk
+    Pieces []ChunkPiece
+    Seams  []ChunkSeam
+}
+
+// UnfoldMeshChunked unfolds a mesh that is too big to handle in one go: it
+// splits the faces spatially into chunks, unfolds every connected piece of
+// every chunk independently (in parallel, opts.Workers at a time), and stitches
+// a global map of the edges cut between pieces.
+func UnfoldMeshChunked(poly Polyhedron, opts ChunkOptions) (result *ChunkedResult, err error) {
+    at := &progress{stage: "spatial split", face: -1}
+    defer at.recover(&err)
+    if len(poly.Faces) == 0 {
+        return nil, errors.New("polyhedron has no faces")
+    }
+    if opts.MaxFacesPerChunk <= 0 {
+        opts.MaxFacesPerChunk = 10000
+    }
+    if opts.Workers <= 0 {
+        opts.Workers = 1
+    }
+
+    // 1) spatial split
+    all := make([]int, len(poly.Faces))
+    for i := range all {
+        all[i] = i
+    }
+    centroids := make([]Vector3, len(poly.Faces))
+    for i, f := range poly.Faces {
+        centroids[i] = faceCentroid(poly, f)
+    }
+    chunks := splitFacesSpatially(all, centroids, opts.MaxFacesPerChunk)
+
+    // 2) connected pieces per chunk
+    at.stage = "chunk adjacency"
+    var pieces []ChunkPiece
+    pieceOfFace := make([]int, len(poly.Faces))
+    open := make(map[[2]int][]int) // edges on a chunk's boundary -> their faces
+    for cIdx, faces := range chunks {
+        openEdges(poly, faces, open)
+        part, _ := subMesh(poly, faces)
+        adj, err := BuildFaceAdjacency(part)
+        if err != nil {
+            return nil, fmt.Errorf("error building adjacency for chunk %d: %v", cIdx, err)
+        }
+        for _, comp := range faceComponents(adj, len(part.Faces)) {
+            global := make([]int, len(comp))
+            for i, local := range comp {
+                global[i] = faces[local]
+                pieceOfFace[faces[local]] = len(pieces)
+            }
+            pieces = append(pieces, ChunkPiece{Chunk: cIdx, Faces: global})
+        }
+    }
+
+    // 3) unfold pieces with a bounded worker pool; a panic in a worker is
+    // its piece's error
+    var wg sync.WaitGroup
+    jobs := make(chan int)
+    errs := make([]error, len(pieces))
+    unfoldPiece := func(p *ChunkPiece) (err error) {
+        at := &progress{stage: "piece extraction", face: p.Faces[0]}
+        defer at.recover(&err)
+        part, vmap := subMesh(poly, p.Faces)
+        p.Vertices = vmap
+        p.Result, err = UnfoldMeshWithOptions(part, 0, opts.Unfold)
+        return err
+    }
+    for w := 0; w < opts.Workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for pIdx := range jobs {
+                errs[pIdx] = unfoldPiece(&pieces[pIdx])
+            }
+        }()
+    }
+    for pIdx := range pieces {
+        jobs <- pIdx
+    }
+    close(jobs)
+    wg.Wait()
+    for pIdx, err := range errs {
+        if err != nil {
+            return nil, fmt.Errorf("failed to unfold piece %d: %w", pIdx, err)
+        }
+    }
+
+    // 4) stitch: a chunk boundary edge with one face on either side is a
+    // seam (pieces of one chunk don't share edges, or they'd be one piece)
+    at.stage = "stitching"
+    var seams []ChunkSeam
+    for edge, faces := range open {
+        if len(faces) != 2 {
+            continue // the mesh's own boundary, or non-manifold
+        }
+        fa, fb := faces[0], faces[1]
+        if fa > fb {
+            fa, fb = fb, fa
+        }
+        seams = append(seams, ChunkSeam{
+            Edge:   edge,
+            PieceA: pieceOfFace[fa],
+            FaceA:  fa,
+            PieceB: pieceOfFace[fb],
+            FaceB:  fb,
+        })
+    }
+    sort.Slice(seams, func(i, j int) bool {
+        if seams[i].Edge[0] != seams[j].Edge[0] {
+            return seams[i].Edge[0] < seams[j].Edge[0]
+        }
+        return seams[i].Edge[1] < seams[j].Edge[1]
+    })
+
+    return &ChunkedResult{Chunks: chunks, Pieces: pieces, Seams: seams}, nil
+}
+
+// openEdges adds the edges that only one face of faces (a chunk) runs along
+// to open, with that face. An edge two of them share joins them, so it can't
+// be a seam; three or more make it non-manifold, which BuildFaceAdjacency
+// doesn't join either, so they go in too.
+func openEdges(poly Polyhedron, faces []int, open map[[2]int][]int) {
+    in := make(map[[2]int][]int)
+    for _, f := range faces {
+        vs := poly.Faces[f].Vertices
+        for i := range vs {
+            e := sortPair(vs[i], vs[(i+1)%len(vs)])
+            in[e] = append(in[e], f)
+        }
+    }
+    for e, fs := range in {
+        if len(fs) != 2 {
+            open[e] = append(open[e], fs...)
+        }
+    }
+}
+
+// splitFacesSpatially recursively halves the face set along the longest axis
+// of its centroid bounding box until every part has at most maxFaces faces.
+func splitFacesSpatially(faces []int, centroids []Vector3, maxFaces int) [][]int {
+    if len(faces) <= maxFaces {
+        return [][]int{faces}
+    }
+
+    lo, hi := centroids[faces[0]], centroids[faces[0]]
+    for _, f := range faces[1:] {
+        c := centroids[f]
+        lo = Vector3{minf(lo.X, c.X), minf(lo.Y, c.Y), minf(lo.Z, c.Z)}
+        hi = Vector3{maxf(hi.X, c.X), maxf(hi.Y, c.Y), maxf(hi.Z, c.Z)}
+    }
+    ext := sub(hi, lo)
+    axis := func(v Vector3) float64 { return v.X }
+    if ext.Y >= ext.X && ext.Y >= ext.Z {
+        axis = func(v Vector3) float64 { return v.Y }
+    } else if ext.Z >= ext.X && ext.Z >= ext.Y {
+        axis = func(v Vector3) float64 { return v.Z }
+    }
+
+    sorted := append([]int(nil), faces...)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        return axis(centroids[sorted[i]]) < axis(centroids[sorted[j]])
+    })
+    mid := len(sorted) / 2
+    left := splitFacesSpatially(sorted[:mid], centroids, maxFaces)
+    right := splitFacesSpatially(sorted[mid:], centroids, maxFaces)
+    return append(left, right...)
+}
+
+// subMesh copies the given faces (and only the vertices they use) into a new
+// Polyhedron. The returned slice maps local vertex index -> global vertex index.
+func subMesh(poly Polyhedron, faces []int) (Polyhedron, []int) {
+    local := make(map[int]int)
+    var vmap []int
+    out := Polyhedron{Name: poly.Name, Units: poly.Units, Faces: make([]Face, len(faces))}
+    localVertex := func(v int) int {
+        lv, ok := local[v]
+        if !ok {
+            lv = len(vmap)
+            local[v] = lv
+            vmap = append(vmap, v)
+            out.Vertices = append(out.Vertices, poly.Vertices[v])
+        }
+        return lv
+    }
+    for i, fIdx := range faces {
+        src := poly.Faces[fIdx]
+        loop := make([]int, len(src.Vertices))
+        for j, v := range src.Vertices {
+            loop[j] = localVertex(v)
+        }
+        out.Faces[i] = Face{Vertices: loop, UVs: src.UVs, Holes: copyHoles(src.Holes, localVertex)}
+    }
+    return out, vmap
+}
+
+// faceComponents groups faces into connected components of the face graph.
+// Each component lists its faces in BFS order from its smallest face index.
+func faceComponents(adj *FaceAdjacency, nFaces int) [][]int {
+    seen := make([]bool, nFaces)
+    var comps [][]int
+    for start := 0; start < nFaces; start++ {
+        if seen[start] {
+            continue
+        }
+        seen[start] = true
+        comp := []int{start}
+        for i := 0; i < len(comp); i++ {
+            for _, nbr := range adj.Neighbors[comp[i]] {
+                if !seen[nbr.FaceIndex] {
+                    seen[nbr.FaceIndex] = true
+                    comp = append(comp, nbr.FaceIndex)
+                }
+            }
+        }
+        comps = append(comps, comp)
+    }
+    return comps
+}
+
+// faceCentroid returns the average of a face's vertices.
+func faceCentroid(poly Polyhedron, face Face) Vector3 {
+    var c Vector3
+    for _, v := range face.Vertices {
+        c = add(c, poly.Vertices[v])
+    }
+    if len(face.Vertices) == 0 {
+        return c
+    }
+    return scale(c, 1/float64(len(face.Vertices)))
+}
+
+func minf(a, b float64) float64 {
+    if a < b {
+        return a
+    }
+    return b
+}
+
+func maxf(a, b float64) float64 {
+    if a > b {
+        return a
+    }
+    return b
+}
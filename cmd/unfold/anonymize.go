@@ -0,0 +1,73 @@
+package main
+
+import (
+    "bufio"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+
+    "github.com/yourusername/unfolder"
+    "github.com/yourusername/unfolder/meshio"
+)
+
+// runAnonymize implements "unfold anonymize": write a copy of a model that
+// can go into a problem report, as PLY. It exits 0, or 2 if the model
+// couldn't be read or written.
+func runAnonymize(args []string) int {
+    fs := flag.NewFlagSet("anonymize", flag.ContinueOnError)
+    normalize := fs.Bool("normalize", false, "also move the model to the origin and scale it to a unit cube")
+    keepUVs := fs.Bool("keep-uvs", false, "keep texture coordinates")
+    root := fs.Int("root", -1, "print the number face n of the model gets in the copy, to unfold it from the same face")
+    out := fs.String("o", "", "output file (default stdout)")
+    if err := parseInterspersed(fs, args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 1 {
+        fmt.Fprintln(os.Stderr, "usage: unfold anonymize [-normalize] [-keep-uvs] [-root n] [-o file.ply] model")
+        return 2
+    }
+
+    poly, err := loadMesh(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold anonymize: %v\n", err)
+        return 2
+    }
+    anon, err := unfolder.Anonymize(poly, unfolder.AnonymizeOptions{Normalize: *normalize, KeepUVs: *keepUVs})
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold anonymize: %v\n", err)
+        return 2
+    }
+    if *root >= 0 {
+        if *root >= len(anon.Faces) {
+            fmt.Fprintf(os.Stderr, "unfold anonymize: face %d out of range [0, %d)\n", *root, len(anon.Faces))
+            return 2
+        }
+        fmt.Fprintf(os.Stderr, "face %d is face %d in the copy\n", *root, anon.Faces[*root])
+    }
+
+    var w io.Writer = os.Stdout
+    var f *os.File
+    if *out != "" && *out != "-" {
+        if f, err = os.Create(*out); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold anonymize: %v\n", err)
+            return 2
+        }
+        w = f
+    }
+    bw := bufio.NewWriter(w)
+    err = meshio.WritePLY(bw, anon.Mesh, nil, meshio.PLYOptions{})
+    if err == nil {
+        err = bw.Flush()
+    }
+    if f != nil {
+        if cerr := f.Close(); err == nil {
+            err = cerr
+        }
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold anonymize: %v\n", err)
+        return 2
+    }
+    return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "os"
+
+    "github.com/yourusername/unfolder"
+)
+
+// runDiff implements "unfold diff". Like diff(1) it exits 0 when the nets are
+// the same, 1 when they differ and 2 on errors.
+func runDiff(args []string) int {
+    fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+    tol := fs.Float64("tol", 1e-9, "tolerance for comparing 2D coordinates")
+    asJSON := fs.Bool("json", false, "print the diff as JSON")
+    if err := fs.Parse(args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 2 {
+        fmt.Fprintln(os.Stderr, "usage: unfold diff [-tol t] [-json] old.unfold new.unfold")
+        return 2
+    }
+
+    oldNet, err := unfolder.LoadNetFile(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold diff: %v\n", err)
+        return 2
+    }
+    newNet, err := unfolder.LoadNetFile(fs.Arg(1))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold diff: %v\n", err)
+        return 2
+    }
+
+    d, err := unfolder.DiffNets(oldNet, newNet, *tol)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold diff: %v\n", err)
+        return 2
+    }
+
+    if *asJSON {
+        enc := json.NewEncoder(os.Stdout)
+        enc.SetIndent("", "  ")
+        err = enc.Encode(d)
+    } else {
+        err = d.WriteText(os.Stdout)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold diff: %v\n", err)
+        return 2
+    }
+    if d.Empty() {
+        return 0
+    }
+    return 1
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "math"
+    "os"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// runInfo implements "unfold info": a quick look at a model before
+// unfolding it. It exits 0, or 2 if the model couldn't be read.
+func runInfo(args []string) int {
+    fs := flag.NewFlagSet("info", flag.ContinueOnError)
+    modelUnits := fs.String("model-units", "", "units the model is in: mm, cm, m or in (default: from the file, else guessed)")
+    asJSON := fs.Bool("json", false, "print the summary as JSON")
+    if err := parseInterspersed(fs, args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 1 {
+        fmt.Fprintln(os.Stderr, "usage: unfold info [-model-units u] [-json] model")
+        return 2
+    }
+    if *modelUnits != "" {
+        if _, err := unfolder.UnitFactor(*modelUnits, "mm"); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold info: %v\n", err)
+            return 2
+        }
+    }
+
+    poly, err := loadMesh(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold info: %v\n", err)
+        return 2
+    }
+    if *modelUnits != "" {
+        poly.Units = *modelUnits
+    }
+    info, err := unfolder.Inspect(poly)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold info: %v\n", err)
+        return 2
+    }
+    if *asJSON {
+        enc := json.NewEncoder(os.Stdout)
+        enc.SetIndent("", "  ")
+        if err := enc.Encode(info); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold info: %v\n", err)
+            return 2
+        }
+        return 0
+    }
+
+    if info.Name != "" {
+        fmt.Printf("name: %s\n", info.Name)
+    }
+    fmt.Printf("%d vertices, %d faces, %d edges\n", info.Vertices, info.Faces, info.Edges)
+    fmt.Printf("topology: %s (Euler characteristic %d)\n", strings.Join(info.Shells, " + "), info.Euler)
+    switch {
+    case info.NonManifoldEdges > 0:
+        fmt.Printf("manifold: no, %d edges shared by 3 or more faces\n", info.NonManifoldEdges)
+    case !info.Orientable:
+        fmt.Println("manifold: yes, but not orientable")
+    case info.BoundaryEdges > 0:
+        fmt.Printf("manifold: yes, open (%d boundary edges)\n", info.BoundaryEdges)
+    default:
+        fmt.Println("manifold: yes, closed")
+    }
+    if info.DegenerateFaces > 0 {
+        fmt.Printf("degenerate faces: %d (these can't be unfolded)\n", info.DegenerateFaces)
+    }
+
+    fmt.Printf("size: %.4g x %.4g x %.4g\n", info.Size.X, info.Size.Y, info.Size.Z)
+    fmt.Printf("bounds: (%.4g, %.4g, %.4g) to (%.4g, %.4g, %.4g)\n", info.Min.X, info.Min.Y, info.Min.Z, info.Max.X, info.Max.Y, info.Max.Z)
+    units := info.Units
+    switch {
+    case *modelUnits != "":
+        fmt.Printf("units: %s (given)\n", units)
+    case units != "":
+        fmt.Printf("units: %s (from the file)\n", units)
+    default:
+        // the exporters take a model without units to be in mm
+        units = "mm"
+        fmt.Println("units: not given, taken as mm")
+    }
+    if w := info.UnitGuess.Warning; w != "" && *modelUnits == "" {
+        fmt.Printf("  warning: %s (set them with -model-units)\n", w)
+    }
+    if info.NonPlanarFaces > 0 {
+        fmt.Printf("planarity: %d non-planar faces, up to %.3g off flat\n", info.NonPlanarFaces, info.MaxWarp)
+    } else {
+        fmt.Println("planarity: all faces flat")
+    }
+
+    // the net at 1:1, as paper
+    mm, _ := unfolder.UnitFactor(units, "mm")
+    area := info.SurfaceArea * mm * mm
+    sheets := int(math.Ceil(area / (unfolder.PageA4.Width * unfolder.PageA4.Height)))
+    word := "sheets"
+    if sheets == 1 {
+        word = "sheet"
+    }
+    fmt.Printf("net at 1:1: %.4g cm² of paper, at least %d A4 %s\n", area/100, sheets, word)
+    return 0
+}
@@ -0,0 +1,65 @@
+// Command unfold works with unfolded polyhedron nets from the command line.
+//
+// Usage:
+//
+//	unfold anonymize [-normalize] [-keep-uvs] [-root n] [-o file.ply] model
+//	unfold net [-root n] [-strategy s] [-optimize objective] [-suggest n] [-gsm g] [-difficulty] [-scale f] [-format svg|pdf|dxf|json|png|booklet|gltf|glb] [-texture img] [-o file] model
+//	unfold diff [-tol t] [-json] old.unfold new.unfold
+//	unfold info [-model-units u] [-json] model
+//	unfold preview [-view x,y,z] [-up x,y,z] [-size px] [-explode f] [-texture img] [-format png|svg] [-o file] model
+//	unfold roundtrip [-root n] [-strategy s] [-tol t] [-o folded.stl] [-json] model
+//	unfold validate [-root n] [-mem-limit bytes] [-json] model
+//
+// Models are read from .obj, .stl, .ply, .off, .gltf/.glb or .unfold (with
+// embedded mesh) files.
+package main
+
+import (
+    "fmt"
+    "os"
+    "sort"
+)
+
+// command is one "unfold <name>" subcommand. run gets the arguments after the
+// subcommand name and returns the process exit code.
+type command struct {
+    summary string
+    run     func(args []string) int
+}
+
+var commands = map[string]command{
+    "anonymize": {"write a copy of a model with no name and its vertices and faces renumbered, for sharing (ply)", runAnonymize},
+    "diff":      {"compare two .unfold files", runDiff},
+    "info":      {"summarise a model: counts, topology, size, units, flatness and paper needed", runInfo},
+    "net":       {"unfold a mesh and write the net (svg, pdf, dxf, json, png, booklet or a gltf fold animation)", runNet},
+    "preview":   {"draw a shaded picture of the model (png or svg)", runPreview},
+    "roundtrip": {"fold the net back up via STL and measure it against the mesh", runRoundTrip},
+    "validate":  {"check a mesh can be unfolded, without unfolding it", runValidate},
+}
+
+func main() {
+    if len(os.Args) < 2 {
+        usage()
+        os.Exit(2)
+    }
+    cmd, ok := commands[os.Args[1]]
+    if !ok {
+        fmt.Fprintf(os.Stderr, "unfold: unknown command %q\n", os.Args[1])
+        usage()
+        os.Exit(2)
+    }
+    os.Exit(cmd.run(os.Args[2:]))
+}
+
+func usage() {
+    fmt.Fprintln(os.Stderr, "usage: unfold <command> [flags] [args]")
+    fmt.Fprintln(os.Stderr, "commands:")
+    names := make([]string, 0, len(commands))
+    for name := range commands {
+        names = append(names, name)
+    }
+    sort.Strings(names)
+    for _, name := range names {
+        fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+    }
+}
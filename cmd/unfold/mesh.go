@@ -0,0 +1,111 @@
+package main
+
+import (
+    "fmt"
+    "image"
+    _ "image/jpeg"
+    _ "image/png"
+    "os"
+    "path/filepath"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+    "github.com/yourusername/unfolder/meshio"
+)
+
+// loadMesh reads a mesh from path, picking the reader from the file extension.
+func loadMesh(path string) (unfolder.Polyhedron, error) {
+    switch ext := strings.ToLower(filepath.Ext(path)); ext {
+    case ".obj":
+        f, err := os.Open(path)
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        defer f.Close()
+        return meshio.LoadOBJ(f)
+    case ".stl":
+        f, err := os.Open(path)
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        defer f.Close()
+        return meshio.LoadSTL(f, meshio.STLOptions{})
+    case ".ply":
+        f, err := os.Open(path)
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        defer f.Close()
+        poly, _, err := meshio.LoadPLY(f)
+        return poly, err
+    case ".off":
+        f, err := os.Open(path)
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        defer f.Close()
+        poly, _, err := meshio.LoadOFF(f)
+        return poly, err
+    case ".gltf", ".glb":
+        f, err := os.Open(path)
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        defer f.Close()
+        polys, err := meshio.LoadGLTF(f, meshio.GLTFOptions{MergePrimitives: true, Dir: filepath.Dir(path)})
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        return joinMeshes(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), polys)
+    case ".unfold":
+        nf, err := unfolder.LoadNetFile(path)
+        if err != nil {
+            return unfolder.Polyhedron{}, err
+        }
+        poly, ok := nf.EmbeddedMesh()
+        if !ok {
+            return unfolder.Polyhedron{}, fmt.Errorf("%s does not embed its mesh", path)
+        }
+        return poly, nil
+    default:
+        return unfolder.Polyhedron{}, fmt.Errorf("unsupported mesh format %q", ext)
+    }
+}
+
+// joinMeshes puts several meshes into one (the pieces stay separate, they
+// just share a vertex list). A single mesh is returned as it is.
+func joinMeshes(name string, polys []unfolder.Polyhedron) (unfolder.Polyhedron, error) {
+    switch len(polys) {
+    case 0:
+        return unfolder.Polyhedron{}, fmt.Errorf("%s has no triangle meshes", name)
+    case 1:
+        return polys[0], nil
+    }
+    out := unfolder.Polyhedron{Name: name, Units: polys[0].Units}
+    for _, p := range polys {
+        base := len(out.Vertices)
+        out.Vertices = append(out.Vertices, p.Vertices...)
+        for _, f := range p.Faces {
+            vs := make([]int, len(f.Vertices))
+            for i, v := range f.Vertices {
+                vs[i] = v + base
+            }
+            out.Faces = append(out.Faces, unfolder.Face{Vertices: vs, UVs: f.UVs})
+        }
+    }
+    return out, nil
+}
+
+// loadTexture reads a PNG or JPEG image.
+func loadTexture(path string) (image.Image, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    img, _, err := image.Decode(f)
+    if err != nil {
+        return nil, fmt.Errorf("%s: %v", path, err)
+    }
+    return img, nil
+}
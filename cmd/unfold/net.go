@@ -0,0 +1,472 @@
+package main
+
+import (
+    "bufio"
+    "flag"
+    "fmt"
+    "image"
+    "image/color"
+    "image/png"
+    "io"
+    "os"
+    "path/filepath"
+    "runtime"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/yourusername/unfolder"
+)
+
+// strategies maps -strategy names to spanning strategies.
+var strategies = map[string]unfolder.SpanningStrategy{
+    "bfs":      unfolder.BreadthFirst{},
+    "steepest": unfolder.SteepestEdge{},
+    "dihedral": unfolder.DihedralMST{},
+    "bbox":     unfolder.MinBoundingBox{},
+    "hidden":   unfolder.HiddenSeams{},
+}
+
+// objectives maps -optimize names to objectives.
+var objectives = map[string]unfolder.Objective{
+    "overlaps":     unfolder.MinOverlaps,
+    "overlap-area": unfolder.MinOverlapArea,
+    "box":          unfolder.MinBoxArea,
+    "cuts":         unfolder.MinCutLength,
+}
+
+// runNet implements "unfold net": unfold a mesh and write the net. It exits 0
+// on success, 1 if the mesh couldn't be unfolded and 2 on usage or I/O errors.
+func runNet(args []string) int {
+    fs := flag.NewFlagSet("net", flag.ContinueOnError)
+    root := fs.Int("root", 0, "face to start unfolding from")
+    largest := fs.Bool("largest-root", false, "start from the largest face instead of -root")
+    strategy := fs.String("strategy", "bfs", "spanning tree: bfs, steepest, dihedral, bbox, hidden or random")
+    seed := fs.Int64("seed", 0, "seed for -strategy random")
+    optimize := fs.String("optimize", "", "search roots and strategies for the best net: overlaps, overlap-area, box or cuts")
+    budget := fs.Int("budget", 64, "candidate nets -optimize tries")
+    nonOverlap := fs.Bool("non-overlapping", false, "search for a net without overlapping faces")
+    fallback := fs.String("fallback", "", "try these in turn until a net has no overlaps, each with an optional time limit, e.g. steepest:2s,optimize:30s,bfs (strategies, non-overlapping or optimize); overrides -strategy, -optimize and -non-overlapping")
+    mustCut := fs.String("cut", "", "edges the net must cut, as vertex pairs: 3-7,8-12")
+    mustFold := fs.String("fold", "", "edges the net must fold, as vertex pairs")
+    planarity := fs.Float64("planarity", 0, "fail on faces with a corner further than this (mesh units) from the face's plane, which would come out distorted")
+    splitWarped := fs.Bool("split-warped", false, "with -planarity, split such faces into triangles instead of failing")
+    simplify := fs.Int("simplify", 0, "first reduce the mesh to about this many triangles (-root, -cut and -fold then refer to the reduced mesh)")
+    mergeFlat := fs.Bool("merge-coplanar", false, "first merge neighbouring faces in one plane into one face, dropping the folds between them (-root then refers to the merged mesh)")
+    scale := fs.Float64("scale", 0, "output units (mm for pdf) per mesh unit (default: from -model-units, else 1)")
+    modelUnits := fs.String("model-units", "", "units the model is in: mm, cm, m or in, for nets at real size; auto to go by the file and the size (see unfold info)")
+    format := fs.String("format", "", "output format: svg, pdf, dxf, json, png, booklet (a pdf with cover and assembly steps) or gltf/glb (the net folding itself up) (default: from -o, else svg)")
+    out := fs.String("o", "", "output file (default stdout)")
+    labels := fs.Bool("labels", false, "print face numbers on the net")
+    assembly := fs.Bool("assembly", false, "number the faces in assembly order on the net (svg, pdf, dxf) and print the assembly steps to stderr")
+    edgeLabels := fs.Bool("edge-labels", false, "print matching numbers on the two sides of every cut edge (svg, pdf)")
+    heatmap := fs.Bool("heatmap", false, "colour folds by how sharply they bend")
+    smooth := fs.Float64("smooth", 0, "treat edges bending less than this many degrees as curved surface, not folds: keep such faces together (with -strategy dihedral unless given) and draw no fold lines between them (svg, pdf, dxf)")
+    tabs := fs.Bool("tabs", false, "add glue tabs")
+    side := fs.String("side", "exterior", "side of the model the net shows, to print on: exterior or interior (mirrored, mountain and valley swapped)")
+    suggest := fs.Int("suggest", 0, "if the net overlaps, print up to this many edges worth cutting to stderr")
+    gsm := fs.Float64("gsm", 0, "paper weight in g/m²: print the estimated weight of the model to stderr")
+    difficulty := fs.Bool("difficulty", false, "print a difficulty rating of the net to stderr")
+    cutList := fs.String("cut-list", "", "also write every edge of the net (length, cut or fold, fold angle, matching number) to this file, as csv, or json if it ends in .json")
+    units := fs.String("units", "", "svg or dxf units (default mm)")
+    page := fs.String("page", "A4", "pdf paper: A4, A3, Letter or Legal")
+    landscape := fs.Bool("landscape", false, "pdf pages in landscape")
+    duplex := fs.Bool("duplex", false, "pdf: follow every page with a mirrored back page, for printing on both sides")
+    shortEdge := fs.Bool("duplex-short-edge", false, "pdf: the printer flips on the short edge (default long edge)")
+    texture := fs.String("texture", "", "png or jpeg image to paint the faces with, by their UVs (svg, png)")
+    dpi := fs.Float64("dpi", 300, "png resolution")
+    if err := parseInterspersed(fs, args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 1 {
+        fmt.Fprintln(os.Stderr, "usage: unfold net [-root n] [-strategy s] [-optimize objective] [-suggest n] [-gsm g] [-difficulty] [-scale f] [-format svg|pdf|dxf|json|png|booklet|gltf|glb] [-texture img] [-o file] model")
+        return 2
+    }
+
+    if *format == "" {
+        switch ext := strings.ToLower(filepath.Ext(*out)); ext {
+        case "":
+            *format = "svg"
+        case ".unfold":
+            *format = "json"
+        default:
+            *format = ext[1:]
+        }
+    }
+    switch *format {
+    case "svg", "pdf", "dxf", "json", "booklet", "gltf", "glb":
+    case "png":
+        if *texture == "" {
+            fmt.Fprintln(os.Stderr, "unfold net: png output needs -texture")
+            return 2
+        }
+    default:
+        fmt.Fprintf(os.Stderr, "unfold net: unknown format %q\n", *format)
+        return 2
+    }
+    s, ok := strategies[*strategy]
+    if *strategy == "random" {
+        s, ok = unfolder.RandomTree{Seed: *seed}, true
+    }
+    if !ok {
+        fmt.Fprintf(os.Stderr, "unfold net: unknown strategy %q\n", *strategy)
+        return 2
+    }
+    if *smooth > 0 {
+        explicit := false
+        fs.Visit(func(f *flag.Flag) { explicit = explicit || f.Name == "strategy" })
+        if !explicit {
+            s = unfolder.DihedralMST{}
+        }
+    }
+    if *largest {
+        s = unfolder.LargestFaceRoot{Strategy: s}
+    }
+    chain, err := parseFallback(*fallback, *seed, *budget)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+        return 2
+    }
+    var netSide unfolder.NetSide
+    switch *side {
+    case "exterior":
+    case "interior":
+        netSide = unfolder.SideInterior
+    default:
+        fmt.Fprintf(os.Stderr, "unfold net: unknown side %q\n", *side)
+        return 2
+    }
+    var paper unfolder.PageSize
+    for _, p := range []unfolder.PageSize{unfolder.PageA4, unfolder.PageA3, unfolder.PageLetter, unfolder.PageLegal} {
+        if strings.EqualFold(p.Name, *page) {
+            paper = p
+        }
+    }
+    if paper.Name == "" {
+        fmt.Fprintf(os.Stderr, "unfold net: unknown page size %q\n", *page)
+        return 2
+    }
+
+    cuts, err := parseEdges(*mustCut)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+        return 2
+    }
+    folds, err := parseEdges(*mustFold)
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+        return 2
+    }
+    if *modelUnits != "" && *modelUnits != "auto" {
+        if _, err := unfolder.UnitFactor(*modelUnits, "mm"); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 2
+        }
+    }
+
+    poly, err := loadMesh(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+        return 2
+    }
+    if *simplify > 0 {
+        n := len(poly.Faces)
+        if poly, err = unfolder.Simplify(poly, *simplify); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 2
+        }
+        fmt.Fprintf(os.Stderr, "unfold net: simplified %d faces to %d\n", n, len(poly.Faces))
+    }
+    if *mergeFlat {
+        n := len(poly.Faces)
+        if poly, _, err = unfolder.MergeCoplanarFaces(poly, 0); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 2
+        }
+        fmt.Fprintf(os.Stderr, "unfold net: merged %d faces into %d\n", n, len(poly.Faces))
+    }
+    var tex image.Image
+    if *texture != "" {
+        if tex, err = loadTexture(*texture); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 2
+        }
+    }
+    switch g := unfolder.InferUnits(poly); {
+    case *modelUnits == "auto":
+        if g.Units != poly.Units {
+            fmt.Fprintf(os.Stderr, "unfold net: taking the model to be in %s\n", g.Units)
+        }
+        poly.Units = g.Units
+    case *modelUnits != "":
+        poly.Units = *modelUnits
+    case g.Warning != "" && *scale == 0:
+        fmt.Fprintf(os.Stderr, "unfold net: warning: %s (set them with -model-units, or -model-units auto)\n", g.Warning)
+    }
+    var groups []int
+    if *smooth > 0 {
+        if groups, err = unfolder.SmoothRegions(poly, *smooth); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 2
+        }
+    }
+    opts := unfolder.UnfoldOptions{Strategy: s, Side: netSide, PlanarityTolerance: *planarity}
+    if *splitWarped {
+        opts.NonPlanar = unfolder.NonPlanarTriangulate
+    }
+    if len(cuts)+len(folds) > 0 {
+        opts.Constraints = &unfolder.Constraints{MustCut: cuts, MustFold: folds}
+    }
+    var result *unfolder.UnfoldResult
+    if len(chain) > 0 {
+        var fb *unfolder.FallbackResult
+        if fb, err = unfolder.UnfoldWithFallback(poly, *root, opts, chain); err == nil {
+            result, *root = fb.Result, fb.Root
+            for i, r := range fb.Reports {
+                switch {
+                case r.Err != nil:
+                    fmt.Fprintf(os.Stderr, "%s: %v\n", r.Name, r.Err)
+                case i == fb.Attempt:
+                    fmt.Fprintf(os.Stderr, "%s: %d overlaps in %v, using it\n", r.Name, r.Overlaps, r.Duration.Round(time.Millisecond))
+                default:
+                    fmt.Fprintf(os.Stderr, "%s: %d overlaps in %v\n", r.Name, r.Overlaps, r.Duration.Round(time.Millisecond))
+                }
+            }
+        }
+    } else if *optimize != "" {
+        objective, ok := objectives[*optimize]
+        if !ok {
+            fmt.Fprintf(os.Stderr, "unfold net: unknown objective %q\n", *optimize)
+            return 2
+        }
+        var best *unfolder.Optimized
+        best, err = unfolder.OptimizeUnfold(poly, objective, unfolder.OptimizeBudget{Candidates: *budget, Workers: runtime.NumCPU()})
+        if err == nil {
+            result, *root = best.Result, best.Root
+            opts.Strategy = best.Strategy
+            if netSide != unfolder.SideExterior || opts.Constraints != nil || *planarity > 0 {
+                // the search unfolds exterior nets without constraints or
+                // planarity checks; mirroring scores the same, the rest may not
+                result, err = unfolder.UnfoldMeshWithOptions(poly, *root, opts)
+            }
+        }
+    } else if *nonOverlap {
+        result, err = unfolder.UnfoldMeshNonOverlapping(poly, *root, opts)
+    } else {
+        result, err = unfolder.UnfoldMeshWithOptions(poly, *root, opts)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+        return 1
+    }
+    if result.Mesh != nil {
+        // warped faces were split: the net is of the split mesh
+        warped := make(map[int]bool)
+        for _, f := range result.FaceOrigin[len(poly.Faces):] {
+            warped[f] = true
+        }
+        fmt.Fprintf(os.Stderr, "unfold net: split %d warped faces into triangles\n", len(warped))
+        poly = *result.Mesh
+        if groups == nil {
+            groups = result.FaceOrigin
+        } else {
+            for f := len(groups); f < len(result.FaceOrigin); f++ {
+                groups = append(groups, groups[result.FaceOrigin[f]])
+            }
+        }
+    }
+    if *suggest > 0 {
+        suggestions, err := unfolder.SuggestCuts(poly, result, *suggest)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 1
+        }
+        for i, s := range suggestions {
+            fmt.Fprintf(os.Stderr, "%d. %v\n", i+1, s)
+        }
+    }
+    if *tabs {
+        result.Tabs = unfolder.GenerateTabs(result, unfolder.TabOptions{})
+    }
+    if *gsm > 0 {
+        mm := *scale
+        if mm <= 0 {
+            mm, _ = unfolder.UnitFactor(poly.Units, "mm") // 0 (unknown) counts as mm
+        }
+        est, err := unfolder.EstimateWeight(result, unfolder.Material{GSM: *gsm, Scale: mm})
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 1
+        }
+        faces := make([][]int, len(est.Pieces))
+        for i, p := range est.Pieces {
+            faces[i] = p.Faces
+        }
+        names := unfolder.PieceNames(poly, faces, nil)
+        for i, p := range est.Pieces {
+            fmt.Fprintf(os.Stderr, "piece %s: %d faces, %.0f mm², %.2f g\n", names[i], len(p.Faces), p.Area, p.Weight)
+        }
+        fmt.Fprintf(os.Stderr, "total: %.0f mm² (%.0f in tabs), %.2f g\n", est.Area, est.TabArea, est.Weight)
+    }
+    if *difficulty {
+        d, err := unfolder.ScoreDifficulty(poly, result, unfolder.DifficultyOptions{Scale: *scale})
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 1
+        }
+        fmt.Fprintf(os.Stderr, "difficulty: %d of 5 (%s)\n", d.Score, d.Level)
+        fmt.Fprintf(os.Stderr, "  faces %d, pieces %d, folds %d, seams %d, curved regions %d\n", d.Faces, d.Pieces, d.Folds, d.Seams, d.CurvedRegions)
+        fmt.Fprintf(os.Stderr, "  shortest edge %.1f mm, smallest tab %.1f mm, sharpest fold %.0f°\n", d.ShortestEdge, d.SmallestTab, d.SharpestFold)
+    }
+    if *cutList != "" {
+        if err := writeCutList(*cutList, poly, result, unfolder.CutListOptions{Scale: *scale, Units: *units}); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 1
+        }
+    }
+    var overlays []*unfolder.Overlay
+    if *assembly {
+        plan := unfolder.Assembly(result)
+        for _, s := range plan.Instructions() {
+            fmt.Fprintln(os.Stderr, s)
+        }
+        overlays = append(overlays, plan.Overlay(result, 0))
+    }
+
+    var w io.Writer = os.Stdout
+    var f *os.File
+    if *out != "" && *out != "-" {
+        if f, err = os.Create(*out); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+            return 2
+        }
+        w = f
+    }
+    bw := bufio.NewWriter(w)
+    switch *format {
+    case "svg":
+        err = unfolder.ExportSVG(result, bw, unfolder.SVGOptions{Scale: *scale, Units: *units, Mesh: &poly, FaceLabels: *labels, EdgeLabels: *edgeLabels, FoldHeatmap: *heatmap, FaceGroups: groups, MinFoldAngle: *smooth, Texture: tex, Overlays: overlays})
+    case "pdf":
+        err = unfolder.ExportPDF(result, bw, unfolder.PDFOptions{Page: paper, Landscape: *landscape, Scale: *scale, Mesh: &poly, FaceLabels: *labels, EdgeLabels: *edgeLabels, FoldHeatmap: *heatmap, FaceGroups: groups, MinFoldAngle: *smooth, Overlays: overlays, Duplex: *duplex, DuplexShortEdge: *shortEdge})
+    case "booklet":
+        err = unfolder.ExportBooklet(poly, result, bw, unfolder.BookletOptions{Net: unfolder.PDFOptions{Page: paper, Landscape: *landscape, Scale: *scale, FaceLabels: *labels, FoldHeatmap: *heatmap, Duplex: *duplex, DuplexShortEdge: *shortEdge}})
+    case "gltf", "glb":
+        m := 0.0
+        if *scale > 0 {
+            m = *scale / 1000 // -scale is in mm
+        }
+        err = unfolder.ExportFoldGLTF(poly, result, bw, unfolder.FoldGLTFOptions{Scale: m, Binary: *format == "glb"})
+    case "dxf":
+        err = unfolder.ExportDXF(result, bw, unfolder.DXFOptions{Units: *units, Scale: *scale, Mesh: &poly, FaceLabels: *labels, FoldHeatmap: *heatmap, FaceGroups: groups, MinFoldAngle: *smooth, Overlays: overlays})
+    case "png":
+        mm := *scale
+        if mm <= 0 {
+            if mm, _ = unfolder.UnitFactor(poly.Units, "mm"); mm <= 0 {
+                mm = 1
+            }
+        }
+        var img *image.RGBA
+        img, err = unfolder.RenderTexture(result, tex, unfolder.TextureOptions{Scale: mm * *dpi / 25.4, Margin: 10, Background: color.White, Lines: true, Mesh: &poly})
+        if err == nil {
+            err = png.Encode(bw, img)
+        }
+    case "json":
+        var nf *unfolder.NetFile
+        if nf, err = unfolder.NewNetFile(poly, *root, opts, result, true); err == nil {
+            err = unfolder.WriteNetFile(bw, nf)
+        }
+    }
+    if err == nil {
+        err = bw.Flush()
+    }
+    if f != nil {
+        if cerr := f.Close(); err == nil {
+            err = cerr
+        }
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold net: %v\n", err)
+        return 2
+    }
+    return 0
+}
+
+// writeCutList writes the cut list of result to path, as JSON if the name
+// ends in .json, else CSV.
+func writeCutList(path string, poly unfolder.Polyhedron, result *unfolder.UnfoldResult, opts unfolder.CutListOptions) error {
+    list, err := unfolder.NetCutList(poly, result, opts)
+    if err != nil {
+        return err
+    }
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if strings.EqualFold(filepath.Ext(path), ".json") {
+        err = unfolder.WriteCutListJSON(f, list)
+    } else {
+        err = unfolder.WriteCutListCSV(f, list)
+    }
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    return err
+}
+
+// parseFallback reads a -fallback chain: comma-separated strategy names,
+// non-overlapping or optimize, each optionally followed by :duration.
+func parseFallback(s string, seed int64, budget int) ([]unfolder.Attempt, error) {
+    var chain []unfolder.Attempt
+    for _, part := range strings.Split(s, ",") {
+        if part = strings.TrimSpace(part); part == "" {
+            continue
+        }
+        name, limit := part, time.Duration(0)
+        if i := strings.IndexByte(part, ':'); i >= 0 {
+            d, err := time.ParseDuration(part[i+1:])
+            if err != nil || d < 0 {
+                return nil, fmt.Errorf("bad time limit in %q, want e.g. 2s", part)
+            }
+            name, limit = part[:i], d
+        }
+        switch name {
+        case "non-overlapping":
+            chain = append(chain, unfolder.NonOverlappingAttempt(limit))
+        case "optimize":
+            chain = append(chain, unfolder.OptimizeAttempt(unfolder.MinOverlaps, unfolder.OptimizeBudget{Candidates: budget, Workers: runtime.NumCPU()}, limit))
+        case "random":
+            chain = append(chain, unfolder.StrategyAttempt(name, unfolder.RandomTree{Seed: seed}, limit))
+        default:
+            st, ok := strategies[name]
+            if !ok {
+                return nil, fmt.Errorf("unknown fallback step %q", name)
+            }
+            chain = append(chain, unfolder.StrategyAttempt(name, st, limit))
+        }
+    }
+    return chain, nil
+}
+
+// parseEdges reads a comma-separated list of vertex pairs, "3-7,8-12".
+func parseEdges(s string) ([]unfolder.Edge, error) {
+    var edges []unfolder.Edge
+    for _, part := range strings.Split(s, ",") {
+        if part = strings.TrimSpace(part); part == "" {
+            continue
+        }
+        ends := strings.Split(part, "-")
+        if len(ends) != 2 {
+            return nil, fmt.Errorf("bad edge %q, want a-b", part)
+        }
+        var e unfolder.Edge
+        for i, v := range ends {
+            n, err := strconv.Atoi(strings.TrimSpace(v))
+            if err != nil {
+                return nil, fmt.Errorf("bad edge %q, want a-b", part)
+            }
+            e[i] = n
+        }
+        edges = append(edges, e)
+    }
+    return edges, nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+    "bufio"
+    "flag"
+    "fmt"
+    "image"
+    "image/color"
+    "image/png"
+    "io"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// runPreview implements "unfold preview": a shaded picture of the model.
+func runPreview(args []string) int {
+    fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+    view := fs.String("view", "1,1,1", "direction from the model to the viewer, x,y,z")
+    up := fs.String("up", "0,1,0", "up in the picture, x,y,z")
+    size := fs.Int("size", 512, "width and height in pixels")
+    edges := fs.Bool("edges", true, "outline the faces")
+    explode := fs.Float64("explode", 0, "draw the overlap-free pieces of a segmented unfold pulled apart by this fraction of the model size (0 = off)")
+    texture := fs.String("texture", "", "png or jpeg image to paint the faces with, by their UVs (png only)")
+    format := fs.String("format", "", "output format: png or svg (default: from -o, else png)")
+    out := fs.String("o", "", "output file (default stdout)")
+    if err := parseInterspersed(fs, args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 1 {
+        fmt.Fprintln(os.Stderr, "usage: unfold preview [-view x,y,z] [-up x,y,z] [-size px] [-explode f] [-texture img] [-format png|svg] [-o file] model")
+        return 2
+    }
+    if *format == "" {
+        *format = "png"
+        if strings.EqualFold(filepath.Ext(*out), ".svg") {
+            *format = "svg"
+        }
+    }
+    if *format != "png" && *format != "svg" {
+        fmt.Fprintf(os.Stderr, "unfold preview: unknown format %q\n", *format)
+        return 2
+    }
+    var cam unfolder.Camera
+    var err error
+    if cam.View, err = parseVector(*view); err == nil {
+        cam.Up, err = parseVector(*up)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold preview: %v\n", err)
+        return 2
+    }
+
+    poly, err := loadMesh(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold preview: %v\n", err)
+        return 2
+    }
+    opts := unfolder.PreviewOptions{Camera: cam, Width: *size, Height: *size, Edges: *edges, Background: color.White}
+    if *texture != "" {
+        if opts.Texture, err = loadTexture(*texture); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold preview: %v\n", err)
+            return 2
+        }
+    }
+
+    var w io.Writer = os.Stdout
+    var f *os.File
+    if *out != "" && *out != "-" {
+        if f, err = os.Create(*out); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold preview: %v\n", err)
+            return 2
+        }
+        w = f
+    }
+    var pieces [][]int
+    if *explode > 0 {
+        patches, _, err := unfolder.UnfoldMeshSegmented(poly, unfolder.UnfoldOptions{})
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "unfold preview: %v\n", err)
+            return 2
+        }
+        for _, res := range patches {
+            var faces []int
+            for f, f2 := range res.Face2D {
+                if len(f2.Vertices) > 0 {
+                    faces = append(faces, f)
+                }
+            }
+            pieces = append(pieces, faces)
+        }
+    }
+
+    bw := bufio.NewWriter(w)
+    ex := unfolder.ExplodeOptions{Distance: *explode, Preview: opts}
+    switch {
+    case *format == "svg" && pieces != nil:
+        err = unfolder.ExportExplodedSVG(poly, pieces, bw, ex)
+    case *format == "svg":
+        err = unfolder.ExportPreviewSVG(poly, bw, opts)
+    default:
+        var img *image.RGBA
+        if pieces != nil {
+            img, err = unfolder.RenderExploded(poly, pieces, ex)
+        } else {
+            img, err = unfolder.RenderPreview(poly, opts)
+        }
+        if err == nil {
+            err = png.Encode(bw, img)
+        }
+    }
+    if err == nil {
+        err = bw.Flush()
+    }
+    if f != nil {
+        if cerr := f.Close(); err == nil {
+            err = cerr
+        }
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold preview: %v\n", err)
+        return 2
+    }
+    return 0
+}
+
+// parseVector reads "x,y,z".
+func parseVector(s string) (unfolder.Vector3, error) {
+    parts := strings.Split(s, ",")
+    if len(parts) != 3 {
+        return unfolder.Vector3{}, fmt.Errorf("bad vector %q, want x,y,z", s)
+    }
+    var c [3]float64
+    for i, p := range parts {
+        v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+        if err != nil {
+            return unfolder.Vector3{}, fmt.Errorf("bad vector %q, want x,y,z", s)
+        }
+        c[i] = v
+    }
+    return unfolder.Vector3{X: c[0], Y: c[1], Z: c[2]}, nil
+}
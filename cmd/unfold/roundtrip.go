@@ -0,0 +1,84 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "os"
+
+    "github.com/yourusername/unfolder"
+    "github.com/yourusername/unfolder/meshio"
+)
+
+// runRoundTrip implements "unfold roundtrip": unfold a mesh, fold the net back
+// up through STL and report how far it is from the original. It exits 0 if
+// the distance is within -tol, 1 if not (or the mesh couldn't be unfolded)
+// and 2 on usage or I/O errors.
+func runRoundTrip(args []string) int {
+    fs := flag.NewFlagSet("roundtrip", flag.ContinueOnError)
+    root := fs.Int("root", 0, "face to start unfolding from")
+    strategy := fs.String("strategy", "bfs", "spanning tree: bfs, steepest, dihedral, bbox or hidden")
+    tol := fs.Float64("tol", 1e-5, "largest acceptable Hausdorff distance, relative to the mesh size")
+    out := fs.String("o", "", "also write the folded model to this STL file")
+    asJSON := fs.Bool("json", false, "print the report as JSON")
+    if err := parseInterspersed(fs, args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 1 {
+        fmt.Fprintln(os.Stderr, "usage: unfold roundtrip [-root n] [-strategy s] [-tol t] [-o folded.stl] [-json] model")
+        return 2
+    }
+    s, ok := strategies[*strategy]
+    if !ok {
+        fmt.Fprintf(os.Stderr, "unfold roundtrip: unknown strategy %q\n", *strategy)
+        return 2
+    }
+
+    poly, err := loadMesh(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold roundtrip: %v\n", err)
+        return 2
+    }
+    result, err := unfolder.UnfoldMeshWithOptions(poly, *root, unfolder.UnfoldOptions{Strategy: s})
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold roundtrip: %v\n", err)
+        return 1
+    }
+
+    var f *os.File
+    if *out != "" {
+        if f, err = os.Create(*out); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold roundtrip: %v\n", err)
+            return 2
+        }
+    }
+    var rt meshio.RoundTrip
+    if f != nil {
+        rt, err = meshio.VerifyRoundTrip(poly, result, f)
+        if cerr := f.Close(); err == nil {
+            err = cerr
+        }
+    } else {
+        rt, err = meshio.VerifyRoundTrip(poly, result, nil)
+    }
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold roundtrip: %v\n", err)
+        return 2
+    }
+
+    if *asJSON {
+        enc := json.NewEncoder(os.Stdout)
+        enc.SetIndent("", "  ")
+        if err := enc.Encode(rt); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold roundtrip: %v\n", err)
+            return 2
+        }
+    } else {
+        fmt.Printf("%d faces folded back, mesh size %g\n", rt.Faces, rt.Size)
+        fmt.Printf("hausdorff %.3g (mesh to folded %.3g, folded to mesh %.3g), mean %.3g\n", rt.Hausdorff, rt.Forward, rt.Backward, rt.Mean)
+    }
+    if rt.Hausdorff > *tol*rt.Size {
+        return 1
+    }
+    return 0
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+    "encoding/json"
+    "flag"
+    "fmt"
+    "os"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// runValidate implements "unfold validate". It exits 0 if the mesh can be
+// unfolded, 1 if validation found errors and 2 if the mesh couldn't be read.
+func runValidate(args []string) int {
+    fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+    root := fs.Int("root", 0, "root face the unfold would start from")
+    memLimit := fs.Int64("mem-limit", 0, "fail if the estimated memory exceeds this many bytes (0 = no limit)")
+    asJSON := fs.Bool("json", false, "print the report as JSON")
+    if err := parseInterspersed(fs, args); err != nil {
+        return 2
+    }
+    if fs.NArg() != 1 {
+        fmt.Fprintln(os.Stderr, "usage: unfold validate [-root n] [-mem-limit bytes] [-json] model")
+        return 2
+    }
+
+    poly, err := loadMesh(fs.Arg(0))
+    if err != nil {
+        fmt.Fprintf(os.Stderr, "unfold validate: %v\n", err)
+        return 2
+    }
+
+    report := unfolder.Preflight(poly, *root, unfolder.UnfoldOptions{MemoryLimit: *memLimit})
+    // add the geometry checks Preflight doesn't already cover
+    if mesh, _ := unfolder.ValidateMesh(poly); mesh != nil {
+        seen := make(map[string]bool)
+        for _, is := range report.Issues {
+            seen[is.Code] = true
+        }
+        for _, is := range mesh.Issues {
+            if !seen[is.Code] {
+                report.Issues = append(report.Issues, is)
+            }
+        }
+    }
+    if *asJSON {
+        enc := json.NewEncoder(os.Stdout)
+        enc.SetIndent("", "  ")
+        if err := enc.Encode(report); err != nil {
+            fmt.Fprintf(os.Stderr, "unfold validate: %v\n", err)
+            return 2
+        }
+    } else {
+        fmt.Printf("%d vertices, %d faces, %d edges, ~%d bytes to unfold\n",
+            report.Vertices, report.Faces, report.Edges, report.EstimatedMemory)
+        if topo, err := unfolder.Topology(poly); err == nil {
+            kinds := make([]string, len(topo.Shells))
+            for i, s := range topo.Shells {
+                kinds[i] = s.Kind()
+            }
+            fmt.Printf("topology: %s (Euler characteristic %d)\n", strings.Join(kinds, " + "), topo.Euler)
+        }
+        for _, is := range report.Issues {
+            fmt.Printf("%s: %s (%s)\n", is.Severity, is.Message, is.Code)
+        }
+    }
+    if !report.OK() {
+        return 1
+    }
+    return 0
+}
+
+// parseInterspersed parses flags that may come before or after positional
+// arguments ("unfold validate model.stl --json").
+func parseInterspersed(fs *flag.FlagSet, args []string) error {
+    var positional []string
+    for {
+        if err := fs.Parse(args); err != nil {
+            return err
+        }
+        if fs.NArg() == 0 {
+            break
+        }
+        positional = append(positional, fs.Arg(0))
+        args = fs.Args()[1:]
+    }
+    return fs.Parse(positional)
+}
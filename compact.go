@@ -0,0 +1,304 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Piece compaction
+// -----------------------------
+
+// CompactOptions controls CompactPieces.
+type CompactOptions struct {
+    // Tries is how many faces of each piece are tried as its root. Default 16.
+    Tries int
+}
+
+// CompactPieces re-lays each piece of a split net (from UnfoldMeshSegmented
+// or similar: one UnfoldResult per piece, unplaced faces belonging to other
+// pieces) into a tighter shape before nesting. For every piece it rebuilds
+// the spanning tree inside the piece from several roots and with the
+// dihedral and steepest-edge strategies, drops candidates that overlap
+// themselves, turns each to its smallest bounding rectangle and keeps the
+// smallest. The piece's original layout is always a candidate, so no piece
+// gets bigger.
+//
+// Pieces come back in the same order and with the same faces, each moved to
+// start at (0, 0); a piece nothing beats is returned as it was. Tabs and
+// double walls are dropped since they no longer fit; add them after
+// compacting.
+func CompactPieces(poly Polyhedron, pieces []UnfoldResult, opts CompactOptions) ([]UnfoldResult, error) {
+    if opts.Tries <= 0 {
+        opts.Tries = 16
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+
+    out := make([]UnfoldResult, len(pieces))
+    for i := range pieces {
+        res, err := compactPiece(poly, adj, &pieces[i], opts.Tries)
+        if err != nil {
+            return nil, fmt.Errorf("piece %d: %v", i, err)
+        }
+        out[i] = *res
+    }
+    return out, nil
+}
+
+func compactPiece(poly Polyhedron, adj *FaceAdjacency, piece *UnfoldResult, tries int) (*UnfoldResult, error) {
+    if len(piece.Face2D) != len(poly.Faces) {
+        return nil, fmt.Errorf("piece has %d faces, mesh %d", len(piece.Face2D), len(poly.Faces))
+    }
+    in := make([]bool, len(poly.Faces))
+    var faces []int
+    for f, f2 := range piece.Face2D {
+        if len(f2.Vertices) >= 3 {
+            in[f] = true
+            faces = append(faces, f)
+        }
+    }
+    if len(faces) == 0 {
+        return nil, errors.New("no placed faces")
+    }
+    inAdj := restrictAdjacency(adj, in)
+    inPiece := func(f int) bool { return in[f] }
+
+    // the original layout is the fallback: it may be turned but never grows
+    orig := &UnfoldResult{
+        Face2D:          piece.Face2D,
+        VertexInstances: piece.VertexInstances,
+        SpanningTree:    piece.SpanningTree,
+        FoldEdges:       piece.FoldEdges,
+        CutEdges:        piece.CutEdges,
+        Side:            piece.Side,
+    }
+    var best *UnfoldResult
+    bestArea := boundsArea(orig)
+    consider := func(res *UnfoldResult) {
+        area, angle := minAreaRect(res)
+        if area >= bestArea*(1-1e-9) {
+            return
+        }
+        // moving the net shuffles rounding on faces that only just touch, so
+        // check the moved layout and fall back to the unturned one
+        if moved := placeAtOrigin(res, -angle); len(DetectOverlaps(moved)) == 0 {
+            best, bestArea = moved, area
+            return
+        }
+        if area = boundsArea(res); area >= bestArea*(1-1e-9) {
+            return
+        }
+        if moved := placeAtOrigin(res, 0); len(DetectOverlaps(moved)) == 0 {
+            best, bestArea = moved, area
+        }
+    }
+    consider(orig)
+
+    try := func(parent []int, root int) {
+        res, err := unfoldAlongTree(poly, inAdj, root, parent, UnfoldOptions{Side: piece.Side}, nil)
+        if err != nil {
+            return
+        }
+        for _, f := range faces {
+            if len(res.Face2D[f].Vertices) == 0 {
+                return // tree didn't reach the whole piece
+            }
+        }
+        res.CutEdges = pieceCutEdges(res.CutEdges, inPiece)
+        consider(res)
+    }
+    step := 1
+    if len(faces) > tries {
+        step = (len(faces) + tries - 1) / tries
+    }
+    for k := 0; k < len(faces); k += step {
+        root := faces[k]
+        if parent, _, err := (BreadthFirst{}).SpanningTree(poly, inAdj, root); err == nil {
+            try(parent, root)
+        }
+    }
+    others := []SpanningStrategy{DihedralMST{}}
+    for _, d := range sphereDirections(4) {
+        others = append(others, SteepestEdge{Direction: d})
+    }
+    for _, s := range others {
+        if parent, root, err := s.SpanningTree(poly, inAdj, faces[0]); err == nil {
+            try(parent, root)
+        }
+    }
+
+    if best == nil {
+        best = transformResult(orig, 0, Point2{}) // a copy, left where it was
+    }
+    return best, nil
+}
+
+// placeAtOrigin turns res by angle and moves its lower left corner to (0, 0).
+func placeAtOrigin(res *UnfoldResult, angle float64) *UnfoldResult {
+    out := transformResult(res, angle, Point2{})
+    lo, _, ok := sheetBounds(out, nil)
+    if !ok {
+        return out
+    }
+    return transformResult(out, 0, Point2{X: -lo.X, Y: -lo.Y})
+}
+
+// boundsArea is the area of the axis-aligned box around the placed faces.
+func boundsArea(res *UnfoldResult) float64 {
+    lo, hi, ok := sheetBounds(res, nil)
+    if !ok {
+        return 0
+    }
+    return (hi.X - lo.X) * (hi.Y - lo.Y)
+}
+
+// restrictAdjacency keeps only the neighbours between faces in the set.
+func restrictAdjacency(adj *FaceAdjacency, in []bool) *FaceAdjacency {
+    out := &FaceAdjacency{Neighbors: make([][]FaceNeighbor, len(adj.Neighbors))}
+    for f, nbrs := range adj.Neighbors {
+        if !in[f] {
+            continue
+        }
+        for _, n := range nbrs {
+            if in[n.FaceIndex] {
+                out.Neighbors[f] = append(out.Neighbors[f], n)
+            }
+        }
+    }
+    return out
+}
+
+// pieceCutEdges keeps the cut edges of one piece: edges to faces outside it
+// become boundary edges (FaceB = -1) on the piece's side.
+func pieceCutEdges(cuts []NetEdge, in func(int) bool) []NetEdge {
+    var out []NetEdge
+    for _, e := range cuts {
+        inA := in(e.FaceA)
+        inB := e.FaceB >= 0 && in(e.FaceB)
+        switch {
+        case inA && inB:
+            out = append(out, e)
+        case inA:
+            out = append(out, NetEdge{Vertices: e.Vertices, FaceA: e.FaceA, EdgeA: e.EdgeA, FaceB: -1, EdgeB: -1})
+        case inB:
+            out = append(out, NetEdge{Vertices: e.Vertices, FaceA: e.FaceB, EdgeA: e.EdgeB, FaceB: -1, EdgeB: -1})
+        }
+    }
+    sortNetEdges(out)
+    return out
+}
+
+// minAreaRect returns the area of the smallest rectangle around the placed
+// faces and the angle of one of its sides; turning the net by -angle makes
+// the rectangle axis aligned.
+func minAreaRect(res *UnfoldResult) (area, angle float64) {
+    var pts []Point2
+    for _, f := range res.Face2D {
+        pts = append(pts, f.Vertices...)
+    }
+    return minAreaRectOf(pts)
+}
+
+// minAreaRectOf is minAreaRect for a set of points.
+func minAreaRectOf(pts []Point2) (area, angle float64) {
+    hull := convexHull2D(pts)
+    if len(hull) < 3 {
+        return 0, 0
+    }
+    area = math.Inf(1)
+    for i := range hull {
+        a, b := hull[i], hull[(i+1)%len(hull)]
+        theta := math.Atan2(b.Y-a.Y, b.X-a.X)
+        c, s := math.Cos(theta), math.Sin(theta)
+        minU, maxU, minV, maxV := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+        for _, p := range hull {
+            u, v := p.X*c+p.Y*s, -p.X*s+p.Y*c
+            minU, maxU = math.Min(minU, u), math.Max(maxU, u)
+            minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+        }
+        if a := (maxU - minU) * (maxV - minV); a < area {
+            area, angle = a, theta
+        }
+    }
+    return area, angle
+}
+
+// convexHull2D returns the convex hull of pts, CCW (monotone chain).
+func convexHull2D(pts []Point2) []Point2 {
+    ps := append([]Point2(nil), pts...)
+    sort.Slice(ps, func(i, j int) bool {
+        if ps[i].X != ps[j].X {
+            return ps[i].X < ps[j].X
+        }
+        return ps[i].Y < ps[j].Y
+    })
+    if len(ps) < 3 {
+        return ps
+    }
+    var hull []Point2
+    for pass := 0; pass < 2; pass++ {
+        start := len(hull)
+        for _, p := range ps {
+            for len(hull) >= start+2 && cross2(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+                hull = hull[:len(hull)-1]
+            }
+            hull = append(hull, p)
+        }
+        hull = hull[:len(hull)-1] // the last point starts the other chain
+        for i, j := 0, len(ps)-1; i < j; i, j = i+1, j-1 {
+            ps[i], ps[j] = ps[j], ps[i]
+        }
+    }
+    return hull
+}
+
+// transformResult returns a copy of res turned by angle (radians, CCW) about
+// the origin and then moved by d. Tabs and double walls are left out.
+func transformResult(res *UnfoldResult, angle float64, d Point2) *UnfoldResult {
+    c, s := math.Cos(angle), math.Sin(angle)
+    tf := func(p Point2) Point2 {
+        return Point2{X: p.X*c - p.Y*s + d.X, Y: p.X*s + p.Y*c + d.Y}
+    }
+    out := &UnfoldResult{
+        Face2D:       make([]Face2D, len(res.Face2D)),
+        SpanningTree: append([]int(nil), res.SpanningTree...),
+        FoldEdges:    append([]NetEdge(nil), res.FoldEdges...),
+        CutEdges:     append([]NetEdge(nil), res.CutEdges...),
+        Side:         res.Side,
+        Mesh:         res.Mesh,
+        FaceOrigin:   append([]int(nil), res.FaceOrigin...),
+    }
+    for i, f := range res.Face2D {
+        if f.Vertices == nil {
+            continue
+        }
+        pts := make([]Point2, len(f.Vertices))
+        for j, p := range f.Vertices {
+            pts[j] = tf(p)
+        }
+        out.Face2D[i] = Face2D{Vertices: pts, UVs: f.UVs}
+        if f.Holes != nil {
+            holes := make([][]Point2, len(f.Holes))
+            for h, loop := range f.Holes {
+                holes[h] = make([]Point2, len(loop))
+                for j, p := range loop {
+                    holes[h][j] = tf(p)
+                }
+            }
+            out.Face2D[i].Holes = holes
+        }
+    }
+    if res.VertexInstances != nil {
+        out.VertexInstances = make([]VertexInstance, len(res.VertexInstances))
+        for i, in := range res.VertexInstances {
+            in.Pos = tf(in.Pos)
+            out.VertexInstances[i] = in
+        }
+    }
+    return out
+}
@@ -0,0 +1,190 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// -----------------------------
+//   Disconnected components
+// -----------------------------
+
+// ErrDisconnected is returned (wrapped) when a mesh falls apart into
+// several components and UnfoldOptions.Components asks not to ignore them.
+var ErrDisconnected = errors.New("mesh has disconnected components")
+
+// ComponentMode says what to do with a mesh made of several components,
+// groups of faces that no edge joins to each other (two separate shells, or
+// a loose face).
+type ComponentMode int
+
+const (
+    // ComponentsRootOnly unfolds the root face's component and leaves the
+    // faces of every other one unplaced (Preflight warns about them).
+    ComponentsRootOnly ComponentMode = iota
+    // ComponentsError fails with ErrDisconnected.
+    ComponentsError
+    // ComponentsSeparate gives every component a net of its own, see
+    // UnfoldComponents. UnfoldMeshWithOptions, which returns one net, fails
+    // with ErrDisconnected instead.
+    ComponentsSeparate
+)
+
+// FaceComponents splits poly's faces into components, faces joined by
+// shared edges. Each component lists its faces in ascending order; the
+// components are ordered by their lowest face. Unlike the shells of
+// Topology, faces that only touch at a vertex are in different components:
+// a net can't hinge on a vertex.
+func FaceComponents(poly Polyhedron) ([][]int, error) {
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+    return sortedComponents(adj, len(poly.Faces)), nil
+}
+
+// sortedComponents is faceComponents with every component sorted.
+func sortedComponents(adj *FaceAdjacency, nFaces int) [][]int {
+    comps := faceComponents(adj, nFaces)
+    for _, c := range comps {
+        sort.Ints(c)
+    }
+    return comps
+}
+
+// disconnectedError describes comps for ErrDisconnected.
+func disconnectedError(comps [][]int) error {
+    sizes := make([]string, len(comps))
+    for i, c := range comps {
+        sizes[i] = strconv.Itoa(len(c))
+    }
+    if len(sizes) > 6 {
+        sizes = append(sizes[:5], "...")
+    }
+    return fmt.Errorf("%w: %d components of %s faces (first faces %d and %d)",
+        ErrDisconnected, len(comps), strings.Join(sizes, ", "), comps[0][0], comps[1][0])
+}
+
+// UnfoldComponents unfolds every component of poly into a net of its own,
+// in the order of FaceComponents. The component holding rootFace starts
+// there, the others at their lowest face (opts.Strategy may pick another
+// root inside the component). Like the patches of UnfoldMeshSegmented, each
+// result uses the mesh's global face and vertex indices and leaves the
+// faces of other components unplaced.
+//
+// opts.Components still applies: ComponentsRootOnly returns just the root
+// face's net, ComponentsError fails if there is more than one component.
+func UnfoldComponents(poly Polyhedron, rootFace int, opts UnfoldOptions) (results []UnfoldResult, err error) {
+    at := &progress{stage: "adjacency", face: -1}
+    defer at.recover(&err)
+    if opts.ValidateOnly {
+        res, err := UnfoldMeshWithOptions(poly, rootFace, opts)
+        if res == nil {
+            return nil, err
+        }
+        return []UnfoldResult{*res}, err
+    }
+    if len(poly.Faces) == 0 {
+        return nil, errors.New("polyhedron has no faces")
+    }
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, err
+    }
+    estimate := EstimateMemory(poly)
+    if opts.MemoryLimit > 0 && estimate > opts.MemoryLimit {
+        return nil, fmt.Errorf("%w: estimated %d bytes, limit %d", ErrMemoryLimit, estimate, opts.MemoryLimit)
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+
+    comps := sortedComponents(adj, len(poly.Faces))
+    switch opts.Components {
+    case ComponentsRootOnly:
+        for _, c := range comps {
+            if i := sort.SearchInts(c, rootFace); i < len(c) && c[i] == rootFace {
+                comps = [][]int{c}
+                break
+            }
+        }
+    case ComponentsError:
+        if len(comps) > 1 {
+            return nil, disconnectedError(comps)
+        }
+    }
+
+    results = make([]UnfoldResult, 0, len(comps))
+    for _, comp := range comps {
+        root := comp[0]
+        if i := sort.SearchInts(comp, rootFace); i < len(comp) && comp[i] == rootFace {
+            root = rootFace
+        }
+        var mem *memoryTracker
+        if opts.ReportMemory {
+            mem = newMemoryTracker(estimate)
+        }
+        at.stage, at.face = "component", comp[0]
+        res, err := unfoldComponent(poly, adj, comp, root, opts, mem)
+        if err != nil {
+            return nil, fmt.Errorf("component of face %d: %w", comp[0], err)
+        }
+        results = append(results, *res)
+    }
+    return results, nil
+}
+
+// unfoldComponent unfolds the faces of comp on their own.
+func unfoldComponent(poly Polyhedron, adj *FaceAdjacency, comp []int, root int, opts UnfoldOptions, mem *memoryTracker) (*UnfoldResult, error) {
+    in := make([]bool, len(poly.Faces))
+    for _, f := range comp {
+        in[f] = true
+    }
+    inAdj := restrictAdjacency(adj, in)
+
+    var parent []int
+    if opts.Strategy != nil {
+        // the strategy only sees this component's faces, so nothing it does
+        // (e.g. LargestFaceRoot) can wander off into another one
+        view := Polyhedron{Vertices: poly.Vertices, Faces: make([]Face, len(poly.Faces))}
+        for _, f := range comp {
+            view.Faces[f] = poly.Faces[f]
+        }
+        var err error
+        parent, root, err = opts.Strategy.SpanningTree(view, inAdj, root)
+        if err != nil {
+            return nil, fmt.Errorf("error building spanning tree: %v", err)
+        }
+        if root < 0 || root >= len(in) || !in[root] {
+            return nil, fmt.Errorf("strategy picked root face %d outside the component", root)
+        }
+    } else {
+        parent = BuildFaceSpanningTree(inAdj, root, len(poly.Faces))
+    }
+    if !opts.Constraints.empty() {
+        var err error
+        if parent, err = constrainTree(poly, inAdj, root, parent, opts.Constraints); err != nil {
+            return nil, err
+        }
+    }
+    mem.mark("spanning-tree")
+
+    // the passes need the cut edges of this component only
+    passes := opts
+    opts.DetectOverlaps, opts.DoubleWalled = false, false
+    res, err := unfoldAlongTree(poly, inAdj, root, parent, opts, mem)
+    if err != nil {
+        return nil, err
+    }
+    res.CutEdges = pieceCutEdges(res.CutEdges, func(f int) bool { return in[f] })
+    if passes.DetectOverlaps {
+        res.Overlaps = DetectOverlaps(res)
+    }
+    if passes.DoubleWalled {
+        AddDoubleWalls(res)
+    }
+    return res, nil
+}
@@ -0,0 +1,220 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Numerical conditioning of placement chains
+// -----------------------------
+
+// FaceConditioning describes how trustworthy one face's placement is.
+type FaceConditioning struct {
+    Face  int `json:"face"`
+    Depth int `json:"depth"` // hinges between the root and this face
+
+    // Amplification is how much the rounding error of the root has grown by
+    // the time it reaches this face. Every hinge adds rounding to the
+    // orientation (more for short hinges) and the orientation error moves
+    // vertices in proportion to their reach, so long chains of short hinges
+    // with wide faces hanging off them are the worst.
+    Amplification float64 `json:"amplification"`
+    // ErrorBound is the resulting worst-case position error, in net units.
+    ErrorBound float64 `json:"errorBound"`
+    // Residual is the measured error: the largest difference between a 2D
+    // edge length of the placed face and its 3D length.
+    Residual float64 `json:"residual"`
+}
+
+// ConditioningReport is the result of AnalyzeConditioning.
+type ConditioningReport struct {
+    Root  int                `json:"root"`
+    Faces []FaceConditioning `json:"faces"` // indexed by face; unplaced faces have Depth -1
+
+    // Worst lists up to 10 faces with the largest ErrorBound, worst first.
+    Worst []int `json:"worst"`
+    // MaxDepth is the longest placement chain.
+    MaxDepth int `json:"maxDepth"`
+    // SuggestedRoot is a face near the middle of the face graph; rooting the
+    // unfold there roughly halves the longest chain.
+    SuggestedRoot int `json:"suggestedRoot"`
+}
+
+// AnalyzeConditioning walks the placement chains of result (root outwards)
+// and estimates how much floating point error each face has accumulated.
+func AnalyzeConditioning(poly Polyhedron, result *UnfoldResult) (*ConditioningReport, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    n := len(result.Face2D)
+    if n != len(poly.Faces) || len(result.SpanningTree) != n {
+        return nil, fmt.Errorf("result has %d faces, mesh has %d", n, len(poly.Faces))
+    }
+
+    rep := &ConditioningReport{Root: -1, Faces: make([]FaceConditioning, n)}
+    children := make([][]NetEdge, n)
+    for _, e := range result.FoldEdges {
+        children[e.FaceA] = append(children[e.FaceA], e)
+    }
+    for f := range rep.Faces {
+        rep.Faces[f] = FaceConditioning{Face: f, Depth: -1}
+        if result.SpanningTree[f] < 0 && len(result.Face2D[f].Vertices) > 0 {
+            if rep.Root >= 0 {
+                return nil, fmt.Errorf("result has more than one root (%d and %d)", rep.Root, f)
+            }
+            rep.Root = f
+        }
+    }
+    if rep.Root < 0 {
+        return nil, errors.New("result has no root face")
+    }
+
+    const ulp = 0x1p-52
+    // rounding error of a freshly flattened face: a few ulps of its extent
+    fresh := func(pts []Point2) float64 {
+        m := 0.0
+        for _, p := range pts {
+            m = math.Max(m, math.Max(math.Abs(p.X), math.Abs(p.Y)))
+        }
+        return 4 * ulp * math.Max(m, 1)
+    }
+
+    root := &rep.Faces[rep.Root]
+    root.Depth = 0
+    root.Amplification = 1
+    root.ErrorBound = fresh(result.Face2D[rep.Root].Vertices)
+    root.Residual = placementResidual(poly, rep.Root, result.Face2D[rep.Root].Vertices)
+    base := root.ErrorBound
+    angleErr := make([]float64, n) // orientation error per face, radians
+
+    queue := []int{rep.Root}
+    for len(queue) > 0 {
+        f := queue[0]
+        queue = queue[1:]
+        for _, e := range children[f] {
+            c := e.FaceB
+            pts := result.Face2D[c].Vertices
+            if len(pts) < 3 {
+                continue
+            }
+            k := len(pts)
+            a, b := pts[e.EdgeB], pts[(e.EdgeB+1)%k]
+            hinge := math.Hypot(b.X-a.X, b.Y-a.Y)
+            reach := 0.0
+            for _, p := range pts {
+                reach = math.Max(reach, math.Hypot(p.X-a.X, p.Y-a.Y))
+            }
+            // first-order model: the hinge carries the parent's orientation
+            // error over unchanged plus rounding of the hinge direction, and
+            // positions pick up that angle error times the reach
+            angle := math.Inf(1)
+            if hinge > 0 {
+                angle = angleErr[f] + fresh(pts)/hinge
+            }
+            angleErr[c] = angle
+            rep.Faces[c] = FaceConditioning{
+                Face:       c,
+                Depth:      rep.Faces[f].Depth + 1,
+                ErrorBound: rep.Faces[f].ErrorBound + reach*angle + fresh(pts),
+                Residual:   placementResidual(poly, c, pts),
+            }
+            rep.Faces[c].Amplification = rep.Faces[c].ErrorBound / base
+            queue = append(queue, c)
+        }
+    }
+
+    order := make([]int, 0, n)
+    for f, fc := range rep.Faces {
+        if fc.Depth >= 0 {
+            order = append(order, f)
+            if fc.Depth > rep.MaxDepth {
+                rep.MaxDepth = fc.Depth
+            }
+        }
+    }
+    sort.SliceStable(order, func(i, j int) bool {
+        return rep.Faces[order[i]].ErrorBound > rep.Faces[order[j]].ErrorBound
+    })
+    if len(order) > 10 {
+        order = order[:10]
+    }
+    rep.Worst = order
+
+    rep.SuggestedRoot = rep.Root
+    if adj, err := BuildFaceAdjacency(poly); err == nil {
+        rep.SuggestedRoot = centralFace(adj, rep.Root, n)
+    }
+    return rep, nil
+}
+
+// placementResidual compares the placed edge lengths of a face with the 3D
+// ones.
+func placementResidual(poly Polyhedron, face int, pts []Point2) float64 {
+    vs := poly.Faces[face].Vertices
+    worst := 0.0
+    for i := range vs {
+        j := (i + 1) % len(vs)
+        l3 := length(sub(poly.Vertices[vs[j]], poly.Vertices[vs[i]]))
+        l2 := math.Hypot(pts[j].X-pts[i].X, pts[j].Y-pts[i].Y)
+        worst = math.Max(worst, math.Abs(l3-l2))
+    }
+    return worst
+}
+
+// centralFace approximates the centre of the face graph with a double BFS:
+// the middle of a longest shortest path is close to the face with the
+// smallest eccentricity.
+func centralFace(adj *FaceAdjacency, start, n int) int {
+    bfs := func(from int) (last int, prev []int) {
+        prev = make([]int, n)
+        for i := range prev {
+            prev[i] = -2
+        }
+        prev[from] = -1
+        queue := []int{from}
+        last = from
+        for len(queue) > 0 {
+            f := queue[0]
+            queue = queue[1:]
+            last = f
+            for _, nbr := range adj.Neighbors[f] {
+                if prev[nbr.FaceIndex] == -2 {
+                    prev[nbr.FaceIndex] = f
+                    queue = append(queue, nbr.FaceIndex)
+                }
+            }
+        }
+        return last, prev
+    }
+    far, _ := bfs(start)
+    other, prev := bfs(far)
+    var path []int
+    for f := other; f >= 0; f = prev[f] {
+        path = append(path, f)
+    }
+    return path[len(path)/2]
+}
+
+// WriteText prints the report with the least trustworthy faces first.
+func (r *ConditioningReport) WriteText(w io.Writer) error {
+    var err error
+    p := func(format string, args ...interface{}) {
+        if err == nil {
+            _, err = fmt.Fprintf(w, format, args...)
+        }
+    }
+    p("root face %d, longest chain %d hinges\n", r.Root, r.MaxDepth)
+    if r.SuggestedRoot != r.Root {
+        p("suggested root: face %d\n", r.SuggestedRoot)
+    }
+    p("%6s %6s %14s %12s %12s\n", "face", "depth", "amplification", "error bound", "residual")
+    for _, f := range r.Worst {
+        fc := r.Faces[f]
+        p("%6d %6d %14.4g %12.3g %12.3g\n", fc.Face, fc.Depth, fc.Amplification, fc.ErrorBound, fc.Residual)
+    }
+    return err
+}
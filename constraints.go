@@ -0,0 +1,186 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+)
+
+// -----------------------------
+//   Cut and fold constraints
+// -----------------------------
+
+// ErrConstraintConflict is returned (wrapped) when UnfoldOptions.Constraints
+// can't all be met, with the edges that clash.
+var ErrConstraintConflict = errors.New("unfold constraints conflict")
+
+// Edge is a mesh edge given by its two vertex indices, in either order.
+type Edge [2]int
+
+// Constraints are edges the net has to cut or fold along whatever the
+// spanning strategy prefers, e.g. to keep a texture seam on a hidden edge or
+// to cut a symmetric model symmetrically. The strategy's tree is kept as far
+// as it agrees with them.
+type Constraints struct {
+    // MustCut edges are cut. They must not cut a face off from the rest.
+    MustCut []Edge `json:"mustCut,omitempty"`
+    // MustFold edges are folded. They must join two faces and must not go
+    // all the way round a vertex or a handle, as a net can't fold a loop.
+    MustFold []Edge `json:"mustFold,omitempty"`
+}
+
+func (c *Constraints) empty() bool { return c == nil || len(c.MustCut) == 0 && len(c.MustFold) == 0 }
+
+// constraintEdges maps the constrained edges (smaller vertex first) to
+// whether they must be folded, checking that each is an edge of poly and
+// not in both lists.
+func constraintEdges(poly Polyhedron, adj *FaceAdjacency, c *Constraints) (map[[2]int]bool, error) {
+    meshEdge := make(map[[2]int]bool)
+    for _, face := range poly.Faces {
+        for i, v := range face.Vertices {
+            meshEdge[sortPair(v, face.Vertices[(i+1)%len(face.Vertices)])] = true
+        }
+    }
+    shared := make(map[[2]int]bool)
+    for _, nbrs := range adj.Neighbors {
+        for _, nbr := range nbrs {
+            shared[nbr.SharedEdge] = true
+        }
+    }
+    fold := make(map[[2]int]bool, len(c.MustCut)+len(c.MustFold))
+    for _, e := range c.MustCut {
+        k := sortPair(e[0], e[1])
+        if !meshEdge[k] {
+            return nil, fmt.Errorf("must-cut edge %d-%d is not an edge of the mesh", e[0], e[1])
+        }
+        fold[k] = false
+    }
+    for _, e := range c.MustFold {
+        k := sortPair(e[0], e[1])
+        if cut, ok := fold[k]; ok && !cut {
+            return nil, fmt.Errorf("%w: edge %d-%d is both a must-cut and a must-fold edge", ErrConstraintConflict, k[0], k[1])
+        }
+        if !shared[k] {
+            if meshEdge[k] {
+                return nil, fmt.Errorf("%w: must-fold edge %d-%d has only one face, there's nothing to fold it to", ErrConstraintConflict, k[0], k[1])
+            }
+            return nil, fmt.Errorf("must-fold edge %d-%d is not an edge of the mesh", e[0], e[1])
+        }
+        fold[k] = true
+    }
+    return fold, nil
+}
+
+// constrainTree rebuilds the spanning tree parent (rooted at root) so that
+// it folds every must-fold edge and no must-cut one, keeping as many of its
+// own edges as it can: the must-fold edges go in first, then parent's
+// edges, then whatever else it takes to reach the faces parent reached.
+func constrainTree(poly Polyhedron, adj *FaceAdjacency, root int, parent []int, c *Constraints) ([]int, error) {
+    fold, err := constraintEdges(poly, adj, c)
+    if err != nil {
+        return nil, err
+    }
+    nFaces := len(poly.Faces)
+    set := make([]int, nFaces)
+    for i := range set {
+        set[i] = i
+    }
+    var find func(int) int
+    find = func(x int) int {
+        if set[x] != x {
+            set[x] = find(set[x])
+        }
+        return set[x]
+    }
+    tree := make([][]int, nFaces)
+    join := func(f, g int) bool {
+        a, b := find(f), find(g)
+        if a == b {
+            return false
+        }
+        set[a] = b
+        tree[f] = append(tree[f], g)
+        tree[g] = append(tree[g], f)
+        return true
+    }
+
+    // 1) the must-fold edges, in edge order, which must not close a loop
+    var folds []NetEdge
+    for f, nbrs := range adj.Neighbors {
+        for _, nbr := range nbrs {
+            if fold[nbr.SharedEdge] && nbr.FaceIndex > f {
+                folds = append(folds, NetEdge{Vertices: nbr.SharedEdge, FaceA: f, FaceB: nbr.FaceIndex})
+            }
+        }
+    }
+    sortNetEdges(folds)
+    for _, e := range folds {
+        if !join(e.FaceA, e.FaceB) {
+            return nil, fmt.Errorf("%w: must-fold edge %d-%d closes a loop of must-fold edges (through faces %d and %d); one of them has to be cut",
+                ErrConstraintConflict, e.Vertices[0], e.Vertices[1], e.FaceA, e.FaceB)
+        }
+    }
+
+    // 2) the tree's own edges, 3) any other allowed edge
+    allowed := func(nbr FaceNeighbor) bool {
+        f, ok := fold[nbr.SharedEdge]
+        return !ok || f
+    }
+    reached := make([]bool, nFaces)
+    for f := range parent {
+        if f == root || parent[f] >= 0 {
+            reached[f] = true
+        }
+    }
+    for f := range parent {
+        if parent[f] < 0 {
+            continue
+        }
+        for _, nbr := range adj.Neighbors[f] {
+            if nbr.FaceIndex == parent[f] && allowed(nbr) {
+                join(f, nbr.FaceIndex)
+                break
+            }
+        }
+    }
+    for f, nbrs := range adj.Neighbors {
+        for _, nbr := range nbrs {
+            if reached[f] && reached[nbr.FaceIndex] && allowed(nbr) {
+                join(f, nbr.FaceIndex)
+            }
+        }
+    }
+
+    // orient it from the root
+    out := make([]int, nFaces)
+    for i := range out {
+        out[i] = -1
+    }
+    seen := make([]bool, nFaces)
+    seen[root] = true
+    queue := []int{root}
+    for len(queue) > 0 {
+        f := queue[0]
+        queue = queue[1:]
+        for _, g := range tree[f] {
+            if !seen[g] {
+                seen[g] = true
+                out[g] = f
+                queue = append(queue, g)
+            }
+        }
+    }
+    var lost []int
+    for f := range reached {
+        if reached[f] && !seen[f] {
+            lost = append(lost, f)
+        }
+    }
+    if len(lost) > 0 {
+        more := ""
+        if len(lost) > 8 {
+            lost, more = lost[:8], " ..."
+        }
+        return nil, fmt.Errorf("%w: the must-cut edges cut faces %v%s off from root face %d", ErrConstraintConflict, lost, more, root)
+    }
+    return out, nil
+}
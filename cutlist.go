@@ -0,0 +1,158 @@
+package unfolder
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "errors"
+    "io"
+    "math"
+    "sort"
+    "strconv"
+)
+
+// -----------------------------
+//   Cut list
+// -----------------------------
+
+// CutList is a table of every edge of a net with its real length: a
+// shopping and cutting list for building the model from strips (wire, wood,
+// card strips), and a check sheet for QA.
+type CutList struct {
+    Units string        `json:"units"` // of Length
+    Edges []CutListEdge `json:"edges"`
+}
+
+// CutListEdge is one mesh edge of a net. Kind is "fold", "cut", "boundary"
+// (the mesh's own edge, one face of a non-manifold edge, or an edge to a face
+// the net left out) or "hole". A cut edge joining two faces is one entry; both
+// copies of it in the net carry its Label.
+type CutListEdge struct {
+    ID       int     `json:"id"`              // 1 upwards, in vertex-pair order
+    Label    int     `json:"label,omitempty"` // matching number of a cut edge, as AssignEdgeLabels prints it
+    Kind     string  `json:"kind"`
+    Vertices [2]int  `json:"vertices"` // mesh vertices, smaller first
+    FaceA    int     `json:"faceA"`
+    FaceB    int     `json:"faceB"`            // -1 for boundary and hole edges
+    Length   float64 `json:"length"`           // in Units
+    Dihedral float64 `json:"dihedral"`         // interior angle between the faces, degrees; 180 for flat and boundary edges
+    Crease   string  `json:"crease,omitempty"` // folds: "mountain" or "valley" as seen from the printed side
+}
+
+// CutListOptions controls NetCutList.
+type CutListOptions struct {
+    // Scale converts mesh units to Units. Default: from the mesh's Units
+    // if set, else 1.
+    Scale float64
+    // Units of the lengths, default "mm".
+    Units string
+}
+
+// NetCutList lists the edges of result: every fold, every cut (once, with the
+// matching label of its two sides), the mesh boundary and the edges of face
+// holes. Edges of faces the net left out aren't listed. poly is the mesh the
+// net was unfolded from (result.Mesh if that is set).
+func NetCutList(poly Polyhedron, result *UnfoldResult, opts CutListOptions) (*CutList, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    if len(result.Face2D) != len(poly.Faces) {
+        return nil, errors.New("the net is of another mesh")
+    }
+    if opts.Units == "" {
+        opts.Units = "mm"
+    }
+    if opts.Scale <= 0 {
+        opts.Scale = exportScale(&poly, opts.Units)
+    }
+    placed := func(f int) bool {
+        return f >= 0 && f < len(result.Face2D) && len(result.Face2D[f].Vertices) >= 3
+    }
+    edgeLength := func(e [2]int) float64 {
+        return length(sub(poly.Vertices[e[1]], poly.Vertices[e[0]])) * opts.Scale
+    }
+    degrees := func(e NetEdge) float64 {
+        return DihedralAngle(poly, e) * 180 / math.Pi
+    }
+
+    out := &CutList{Units: opts.Units}
+    for _, e := range result.FoldEdges {
+        c := CutListEdge{Kind: "fold", Vertices: e.Vertices, FaceA: e.FaceA, FaceB: e.FaceB, Length: edgeLength(e.Vertices), Dihedral: degrees(e)}
+        if class := foldClass(&poly, result.Side, e); class != lineFold {
+            c.Crease = class
+        }
+        out.Edges = append(out.Edges, c)
+    }
+    labels := AssignEdgeLabels(result)
+    for _, e := range result.CutEdges {
+        if !placed(e.FaceA) && !placed(e.FaceB) {
+            continue
+        }
+        c := CutListEdge{Kind: "cut", Vertices: e.Vertices, FaceA: e.FaceA, FaceB: e.FaceB, Length: edgeLength(e.Vertices), Dihedral: degrees(e)}
+        // an edge to a face the net left out is as good as the boundary
+        switch {
+        case e.FaceB < 0:
+            c.Kind = "boundary"
+        case !placed(e.FaceB):
+            c.Kind, c.FaceB = "boundary", -1
+        case !placed(e.FaceA):
+            c.Kind, c.FaceA, c.FaceB = "boundary", e.FaceB, -1
+        }
+        c.Label = labels[EdgeInstance{e.FaceA, e.EdgeA}]
+        out.Edges = append(out.Edges, c)
+    }
+    for f, face := range poly.Faces {
+        if !placed(f) {
+            continue
+        }
+        for _, hole := range face.Holes {
+            for i := range hole {
+                e := sortPair(hole[i], hole[(i+1)%len(hole)])
+                out.Edges = append(out.Edges, CutListEdge{Kind: "hole", Vertices: e, FaceA: f, FaceB: -1, Length: edgeLength(e), Dihedral: 180})
+            }
+        }
+    }
+
+    sort.SliceStable(out.Edges, func(i, j int) bool {
+        a, b := out.Edges[i], out.Edges[j]
+        if a.Vertices != b.Vertices {
+            if a.Vertices[0] != b.Vertices[0] {
+                return a.Vertices[0] < b.Vertices[0]
+            }
+            return a.Vertices[1] < b.Vertices[1]
+        }
+        return a.FaceA < b.FaceA
+    })
+    for i := range out.Edges {
+        out.Edges[i].ID = i + 1
+    }
+    return out, nil
+}
+
+// WriteCutListCSV writes c as CSV with a header row; the length column is
+// named after the units, e.g. length_mm.
+func WriteCutListCSV(w io.Writer, c *CutList) error {
+    cw := csv.NewWriter(w)
+    cw.Write([]string{"id", "label", "kind", "v1", "v2", "face_a", "face_b", "length_" + c.Units, "dihedral_deg", "crease"})
+    num := func(v float64) string { return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64) }
+    for _, e := range c.Edges {
+        label := ""
+        if e.Label > 0 {
+            label = strconv.Itoa(e.Label)
+        }
+        cw.Write([]string{
+            strconv.Itoa(e.ID), label, e.Kind,
+            strconv.Itoa(e.Vertices[0]), strconv.Itoa(e.Vertices[1]),
+            strconv.Itoa(e.FaceA), strconv.Itoa(e.FaceB),
+            num(e.Length), num(e.Dihedral), e.Crease,
+        })
+    }
+    cw.Flush()
+    return cw.Error()
+}
+
+// WriteCutListJSON writes c as indented JSON.
+func WriteCutListJSON(w io.Writer, c *CutList) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(c)
+}
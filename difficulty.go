@@ -0,0 +1,220 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Difficulty rating
+// -----------------------------
+
+// DifficultyOptions controls ScoreDifficulty.
+type DifficultyOptions struct {
+    // Scale is mm per net unit, for the sizes. 0 = from the mesh's Units,
+    // else 1 (the net is taken to be in mm).
+    Scale float64
+    // CurvedAngle is how far from flat (degrees) a fold may bend and still
+    // be part of a curved region: a run of faces approximating a smooth
+    // surface, which takes patience to shape evenly. Default 20.
+    CurvedAngle float64
+}
+
+// Difficulty rates how hard a net is to build, from the numbers behind it.
+// Sizes are in mm, angles in degrees.
+type Difficulty struct {
+    Faces  int `json:"faces"` // placed faces
+    Pieces int `json:"pieces"`
+    Folds  int `json:"folds"`
+    Seams  int `json:"seams"` // cut edges glued to another face
+    // ShortestEdge is the shortest edge of any placed face.
+    ShortestEdge float64 `json:"shortestEdge"`
+    // SmallestTab is the narrowest glue tab (the shorter of its length and
+    // depth), or without tabs the shortest seam, which still has to be
+    // glued somehow. 0 if there's nothing to glue.
+    SmallestTab float64 `json:"smallestTab"`
+    // SharpestFold is the interior angle of the fold or seam furthest from
+    // flat, measured on its sharper side: 90 for a cube, 180 if nothing
+    // bends.
+    SharpestFold float64 `json:"sharpestFold"`
+    // CurvedRegions counts runs of 3 or more faces joined by gentle bends
+    // (see DifficultyOptions.CurvedAngle).
+    CurvedRegions int `json:"curvedRegions"`
+    // Score runs from 1 (easy) to 5.
+    Score int `json:"score"`
+    // Level is "beginner" (score 1-2), "intermediate" (3) or "advanced"
+    // (4-5), for labelling kits the same way every time.
+    Level string `json:"level"`
+}
+
+// ScoreDifficulty measures result and rates it. The rating only looks at
+// the numbers in Difficulty, so equal nets always get the same label: many
+// faces, small edges and tabs, sharp folds, several pieces and curved
+// regions all make a net harder.
+func ScoreDifficulty(poly Polyhedron, result *UnfoldResult, opts DifficultyOptions) (*Difficulty, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    if len(result.Face2D) != len(poly.Faces) {
+        return nil, fmt.Errorf("net has %d faces, mesh %d", len(result.Face2D), len(poly.Faces))
+    }
+    scale := opts.Scale
+    if scale <= 0 {
+        scale = exportScale(&poly, "mm")
+    }
+    curved := opts.CurvedAngle
+    if curved <= 0 {
+        curved = 20
+    }
+    edgeLen := func(face, edge int) float64 {
+        pts := result.Face2D[face].Vertices
+        a, b := pts[edge], pts[(edge+1)%len(pts)]
+        return math.Hypot(b.X-a.X, b.Y-a.Y) * scale
+    }
+
+    d := &Difficulty{Folds: len(result.FoldEdges), ShortestEdge: math.Inf(1), SharpestFold: 180}
+    _, pieces := netPieces(result)
+    d.Pieces = len(pieces)
+    for f, f2 := range result.Face2D {
+        if len(f2.Vertices) < 3 {
+            continue
+        }
+        d.Faces++
+        for i := range f2.Vertices {
+            if l := edgeLen(f, i); l > 0 {
+                d.ShortestEdge = math.Min(d.ShortestEdge, l)
+            }
+        }
+    }
+    if d.Faces == 0 {
+        d.ShortestEdge = 0
+    }
+
+    // folds and seams: the sharpest bend, and the gentle ones that make up
+    // curved regions
+    region := make([]int, len(poly.Faces))
+    for i := range region {
+        region[i] = i
+    }
+    var find func(int) int
+    find = func(x int) int {
+        if region[x] != x {
+            region[x] = find(region[x])
+        }
+        return region[x]
+    }
+    var bentFaces []int // a face on every gentle bend that isn't flat
+    shortestSeam := math.Inf(1)
+    for k, edges := range [][]NetEdge{result.FoldEdges, result.CutEdges} {
+        for _, e := range edges {
+            if e.FaceB < 0 || len(result.Face2D[e.FaceA].Vertices) < 3 {
+                continue
+            }
+            if k == 1 {
+                d.Seams++
+                shortestSeam = math.Min(shortestSeam, edgeLen(e.FaceA, e.EdgeA))
+            }
+            theta := DihedralAngle(poly, e) * 180 / math.Pi
+            d.SharpestFold = math.Min(d.SharpestFold, math.Min(theta, 360-theta))
+            if bend := math.Abs(theta - 180); bend < curved {
+                if a, b := find(e.FaceA), find(e.FaceB); a != b {
+                    region[a] = b
+                }
+                if bend >= 1 {
+                    bentFaces = append(bentFaces, e.FaceA)
+                }
+            }
+        }
+    }
+    bent := make(map[int]bool)
+    for _, f := range bentFaces {
+        bent[find(f)] = true
+    }
+    size := make(map[int]int)
+    for f, f2 := range result.Face2D {
+        if len(f2.Vertices) >= 3 {
+            size[find(f)]++
+        }
+    }
+    for root, n := range size {
+        if n >= 3 && bent[root] {
+            d.CurvedRegions++
+        }
+    }
+
+    // tabs: the narrower of each tab's length and depth
+    d.SmallestTab = math.Inf(1)
+    for _, t := range result.Tabs {
+        if len(t.Vertices) < 3 {
+            continue
+        }
+        a, b := t.Vertices[0], t.Vertices[1]
+        l := math.Hypot(b.X-a.X, b.Y-a.Y)
+        if l == 0 {
+            continue
+        }
+        depth := 0.0
+        for _, p := range t.Vertices[2:] {
+            depth = math.Max(depth, math.Abs((b.X-a.X)*(p.Y-a.Y)-(b.Y-a.Y)*(p.X-a.X))/l)
+        }
+        d.SmallestTab = math.Min(d.SmallestTab, math.Min(l, depth)*scale)
+    }
+    if math.IsInf(d.SmallestTab, 1) {
+        d.SmallestTab = shortestSeam
+    }
+    if math.IsInf(d.SmallestTab, 1) {
+        d.SmallestTab = 0
+    }
+
+    d.Score, d.Level = difficultyScore(d)
+    return d, nil
+}
+
+// difficultyScore turns the numbers of d into a 1-5 rating.
+func difficultyScore(d *Difficulty) (int, string) {
+    score := 0
+    switch {
+    case d.Faces > 120:
+        score += 3
+    case d.Faces > 40:
+        score += 2
+    case d.Faces > 12:
+        score++
+    }
+    switch {
+    case d.Faces == 0:
+    case d.ShortestEdge < 4:
+        score += 3
+    case d.ShortestEdge < 8:
+        score += 2
+    case d.ShortestEdge < 15:
+        score++
+    }
+    if d.SmallestTab > 0 && d.SmallestTab < 5 {
+        score++
+    }
+    switch {
+    case d.SharpestFold < 30:
+        score += 2
+    case d.SharpestFold < 60:
+        score++
+    }
+    if d.Pieces > 1 {
+        score++
+    }
+    switch {
+    case d.CurvedRegions > 3:
+        score += 2
+    case d.CurvedRegions > 0:
+        score++
+    }
+    rating := 1 + int(math.Round(float64(score)*4/12))
+    switch {
+    case rating <= 2:
+        return rating, "beginner"
+    case rating == 3:
+        return rating, "intermediate"
+    }
+    return rating, "advanced"
+}
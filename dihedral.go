@@ -0,0 +1,29 @@
+package unfolder
+
+import "math"
+
+// DihedralAngle returns the interior angle (radians) between the two faces of
+// an edge, measured inside the solid: pi for a flat edge, less than pi for a
+// convex edge, more than pi for a concave one. Edges with only one face give pi.
+// Assumes consistent outward (CCW) winding.
+func DihedralAngle(poly Polyhedron, e NetEdge) float64 {
+    if e.FaceB < 0 {
+        return math.Pi
+    }
+    fa, fb := poly.Faces[e.FaceA], poly.Faces[e.FaceB]
+    na := normalize(faceNormal(poly, fa))
+    nb := normalize(faceNormal(poly, fb))
+    c := math.Max(-1, math.Min(1, dot(na, nb)))
+    bend := math.Acos(c) // 0 for flat, grows as the edge gets sharper
+    if bend < 1e-12 {
+        return math.Pi
+    }
+
+    // convex if face B bends away behind face A's plane
+    pa := poly.Vertices[e.Vertices[0]]
+    cb := faceCentroid(poly, fb)
+    if dot(sub(cb, pa), na) < 0 {
+        return math.Pi - bend
+    }
+    return math.Pi + bend
+}
@@ -0,0 +1,169 @@
+package unfolder
+
+import (
+    "errors"
+    "math"
+    "math/rand"
+    "sort"
+)
+
+// -----------------------------
+//   Mesh-to-mesh distance
+// -----------------------------
+
+// DistanceOptions controls MeshDistance.
+type DistanceOptions struct {
+    // Samples adds this many random points per surface, spread by area, to
+    // the corners, edge midpoints and face centroids that are always
+    // measured. 0 = none.
+    Samples int
+    // Seed seeds the random samples.
+    Seed int64
+}
+
+// DistanceReport is how far apart two surfaces are, in model units.
+type DistanceReport struct {
+    Forward   float64 `json:"forward"`   // furthest a is from b
+    Backward  float64 `json:"backward"`  // furthest b is from a
+    Hausdorff float64 `json:"hausdorff"` // the larger of the two
+    Mean      float64 `json:"mean"`      // mean over all samples, both ways
+    Samples   int     `json:"samples"`   // points measured, both ways
+    Size      float64 `json:"size"`      // bounding box diagonal of a, to judge the rest by
+}
+
+// MeshDistance measures the sampled Hausdorff and mean distance between the
+// surfaces of a and b: points on each surface are taken to the nearest point
+// of the other. Faces don't need to be shared-vertex or consistently wound,
+// so triangle soups (STL) work too. The nearest-point queries go through a
+// BVH, so big meshes are fine.
+func MeshDistance(a, b Polyhedron, opts DistanceOptions) (DistanceReport, error) {
+    var rep DistanceReport
+    ba, err := NewBVH(a, 0)
+    if err != nil {
+        return rep, err
+    }
+    bb, err := NewBVH(b, 0)
+    if err != nil {
+        return rep, err
+    }
+    if len(ba.tris) == 0 || len(bb.tris) == 0 {
+        return rep, errors.New("both meshes need at least one face")
+    }
+    r := rand.New(rand.NewSource(opts.Seed))
+    fwd, sumF, nF := oneWayDistance(surfaceSamples(a, ba, opts.Samples, r), bb)
+    bwd, sumB, nB := oneWayDistance(surfaceSamples(b, bb, opts.Samples, r), ba)
+    rep.Forward, rep.Backward = fwd, bwd
+    rep.Hausdorff = math.Max(fwd, bwd)
+    rep.Samples = nF + nB
+    rep.Mean = (sumF + sumB) / float64(rep.Samples)
+    lo, hi := boundingBox(a.Vertices)
+    rep.Size = length(sub(hi, lo))
+    return rep, nil
+}
+
+// oneWayDistance returns the largest and the summed distance from pts to the
+// faces in t, and the number of points.
+func oneWayDistance(pts []Vector3, t *BVH) (max, sum float64, n int) {
+    for _, p := range pts {
+        _, d := t.Nearest(p)
+        max = math.Max(max, d)
+        sum += d
+    }
+    return max, sum, len(pts)
+}
+
+// surfaceSamples returns the corners, edge midpoints and centroids of the
+// faces of poly, plus extra random points on the triangles of t picked by
+// area.
+func surfaceSamples(poly Polyhedron, t *BVH, extra int, r *rand.Rand) []Vector3 {
+    var out []Vector3
+    for _, f := range poly.Faces {
+        var c Vector3
+        for i, v := range f.Vertices {
+            p, q := poly.Vertices[v], poly.Vertices[f.Vertices[(i+1)%len(f.Vertices)]]
+            out = append(out, p, scale(add(p, q), 0.5))
+            c = add(c, p)
+        }
+        if len(f.Vertices) > 0 {
+            out = append(out, scale(c, 1/float64(len(f.Vertices))))
+        }
+    }
+    if extra <= 0 {
+        return out
+    }
+    cum := make([]float64, len(t.tris))
+    total := 0.0
+    for i, tri := range t.tris {
+        a, b, c := t.corners(tri)
+        total += length(cross(sub(b, a), sub(c, a)))
+        cum[i] = total
+    }
+    if total == 0 {
+        return out
+    }
+    for k := 0; k < extra; k++ {
+        a, b, c := t.corners(t.tris[sort.SearchFloat64s(cum, r.Float64()*total)%len(t.tris)])
+        u, v := r.Float64(), r.Float64()
+        if u+v > 1 {
+            u, v = 1-u, 1-v
+        }
+        out = append(out, add(a, add(scale(sub(b, a), u), scale(sub(c, a), v))))
+    }
+    return out
+}
+
+// boxDistance returns the distance from p to the box [lo, hi], 0 inside.
+func boxDistance(p, lo, hi Vector3) float64 {
+    d := Vector3{
+        math.Max(0, math.Max(lo.X-p.X, p.X-hi.X)),
+        math.Max(0, math.Max(lo.Y-p.Y, p.Y-hi.Y)),
+        math.Max(0, math.Max(lo.Z-p.Z, p.Z-hi.Z)),
+    }
+    return length(d)
+}
+
+func minVec(a, b Vector3) Vector3 {
+    return Vector3{math.Min(a.X, b.X), math.Min(a.Y, b.Y), math.Min(a.Z, b.Z)}
+}
+
+func maxVec(a, b Vector3) Vector3 {
+    return Vector3{math.Max(a.X, b.X), math.Max(a.Y, b.Y), math.Max(a.Z, b.Z)}
+}
+
+// pointTriangleDistance returns the distance from p to the nearest point of
+// triangle abc (Ericson, Real-Time Collision Detection 5.1.5).
+func pointTriangleDistance(p, a, b, c Vector3) float64 {
+    dist := func(q Vector3) float64 { return length(sub(p, q)) }
+    ab, ac, ap := sub(b, a), sub(c, a), sub(p, a)
+    d1, d2 := dot(ab, ap), dot(ac, ap)
+    if d1 <= 0 && d2 <= 0 {
+        return dist(a)
+    }
+    bp := sub(p, b)
+    d3, d4 := dot(ab, bp), dot(ac, bp)
+    if d3 >= 0 && d4 <= d3 {
+        return dist(b)
+    }
+    vc := d1*d4 - d3*d2
+    if vc <= 0 && d1 >= 0 && d3 <= 0 {
+        return dist(add(a, scale(ab, d1/(d1-d3))))
+    }
+    cp := sub(p, c)
+    d5, d6 := dot(ab, cp), dot(ac, cp)
+    if d6 >= 0 && d5 <= d6 {
+        return dist(c)
+    }
+    vb := d5*d2 - d1*d6
+    if vb <= 0 && d2 >= 0 && d6 <= 0 {
+        return dist(add(a, scale(ac, d2/(d2-d6))))
+    }
+    va := d3*d6 - d5*d4
+    if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+        return dist(add(b, scale(sub(c, b), (d4-d3)/((d4-d3)+(d5-d6)))))
+    }
+    denom := va + vb + vc
+    if denom == 0 {
+        return dist(a) // degenerate triangle
+    }
+    return dist(add(a, add(scale(ab, vb/denom), scale(ac, vc/denom))))
+}
@@ -0,0 +1,102 @@
+package unfolder
+
+import (
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Double-walled faces
+// -----------------------------
+
+// DoubleWall is a mirrored copy of a face, hinged on one of the face's cut
+// edges. Folding the copy over (180 degrees, valley) puts it behind the face,
+// so the finished model has a clean inside surface.
+type DoubleWall struct {
+    Face     int      `json:"face"`     // face being doubled
+    Edge     int      `json:"edge"`     // local edge index of the hinge in Face
+    Vertices []Point2 `json:"vertices"` // the mirrored polygon, same vertex order as Face
+}
+
+// AddDoubleWalls computes a DoubleWall for every placed face that has at least
+// one cut edge, trying the longest hinges first and skipping hinges whose copy
+// would overlap the net or an earlier copy. It stores the walls in
+// result.DoubleWalls and returns the faces that couldn't be doubled (all edges
+// folded, or every hinge collides).
+func AddDoubleWalls(result *UnfoldResult) []int {
+    if result == nil {
+        return nil
+    }
+
+    // cut edges per face
+    hinges := make(map[int][]int)
+    for _, e := range result.CutEdges {
+        hinges[e.FaceA] = append(hinges[e.FaceA], e.EdgeA)
+        if e.FaceB >= 0 {
+            hinges[e.FaceB] = append(hinges[e.FaceB], e.EdgeB)
+        }
+    }
+
+    _, eps := faceBoxes(result.Face2D)
+    var placedPolys [][]Point2
+    for _, f := range result.Face2D {
+        if len(f.Vertices) >= 3 {
+            placedPolys = append(placedPolys, f.Vertices)
+        }
+    }
+
+    result.DoubleWalls = nil
+    var skipped []int
+    for fIdx, face := range result.Face2D {
+        pts := face.Vertices
+        if len(pts) < 3 {
+            continue
+        }
+        cands := append([]int(nil), hinges[fIdx]...)
+        edgeLen := func(i int) float64 {
+            a, b := pts[i], pts[(i+1)%len(pts)]
+            return math.Hypot(b.X-a.X, b.Y-a.Y)
+        }
+        sort.SliceStable(cands, func(i, j int) bool { return edgeLen(cands[i]) > edgeLen(cands[j]) })
+
+        done := false
+        for _, e := range cands {
+            mirrored := mirrorPolygon(pts, pts[e], pts[(e+1)%len(pts)])
+            clash := false
+            for _, other := range placedPolys {
+                if polygonsOverlap(mirrored, other, eps) {
+                    clash = true
+                    break
+                }
+            }
+            if clash {
+                continue
+            }
+            result.DoubleWalls = append(result.DoubleWalls, DoubleWall{Face: fIdx, Edge: e, Vertices: mirrored})
+            placedPolys = append(placedPolys, mirrored)
+            done = true
+            break
+        }
+        if !done {
+            skipped = append(skipped, fIdx)
+        }
+    }
+    return skipped
+}
+
+// mirrorPolygon reflects pts across the line through a and b.
+func mirrorPolygon(pts []Point2, a, b Point2) []Point2 {
+    dx, dy := b.X-a.X, b.Y-a.Y
+    l2 := dx*dx + dy*dy
+    out := make([]Point2, len(pts))
+    for i, p := range pts {
+        if l2 == 0 {
+            out[i] = p
+            continue
+        }
+        t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / l2
+        fx, fy := a.X+t*dx, a.Y+t*dy // foot of the perpendicular
+        out[i] = Point2{X: 2*fx - p.X, Y: 2*fy - p.Y}
+    }
+    return out
+}
@@ -0,0 +1,234 @@
+package unfolder
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+)
+
+// -----------------------------
+//   DXF export (laser cutters, plotters)
+// -----------------------------
+
+// DXF layer names used by ExportDXF.
+const (
+    DXFLayerCut     = "CUT"
+    DXFLayerFold    = "FOLD"
+    DXFLayerEngrave = "ENGRAVE"
+)
+
+// DXFOptions controls ExportDXF. The zero value writes millimetres at
+// 1 net unit = 1 mm, or at the mesh's real size if Mesh has Units.
+type DXFOptions struct {
+    // Units of the drawing: "mm" (default) or "in".
+    Units string
+    // Scale converts net units to Units. Default: from Mesh.Units if set,
+    // else 1.
+    Scale float64
+
+    // Mesh, when set, colours folds by kind: red mountain, blue valley.
+    Mesh *Polyhedron
+    // FaceLabels engraves each face's index inside it.
+    FaceLabels bool
+    // LabelSize is the text height of face labels, in Units. Default 3 (mm)
+    // or 0.12 (in).
+    LabelSize float64
+    // Overlays are engraved along with the labels, except for cut overlays,
+    // which go on the CUT layer.
+    Overlays []*Overlay
+    // FaceGroups and MinFoldAngle work as in SVGOptions.
+    FaceGroups   []int
+    MinFoldAngle float64
+    // FoldHeatmap colours folds by how sharply they bend, as in SVGOptions,
+    // using the nearest of the standard colours blue, cyan, green, yellow,
+    // orange and red.
+    FoldHeatmap bool
+}
+
+// ExportDXF writes the net as an ASCII DXF (R2000) with LWPOLYLINE entities:
+// cut lines chained into as few polylines as possible on layer CUT, folds on
+// FOLD, and face labels and overlays as engraving on ENGRAVE.
+func ExportDXF(result *UnfoldResult, w io.Writer, opts DXFOptions) error {
+    if result == nil {
+        return errors.New("nil unfold result")
+    }
+    mesh, err := netMesh(result, opts.Mesh)
+    if err != nil {
+        return err
+    }
+    opts.Mesh = mesh
+    insunits := 4
+    switch opts.Units {
+    case "", "mm":
+        opts.Units = "mm"
+        if opts.LabelSize <= 0 {
+            opts.LabelSize = 3
+        }
+    case "in":
+        insunits = 1
+        if opts.LabelSize <= 0 {
+            opts.LabelSize = 0.12
+        }
+    default:
+        return fmt.Errorf("unsupported DXF units %q", opts.Units)
+    }
+    if opts.Scale <= 0 {
+        opts.Scale = exportScale(opts.Mesh, opts.Units)
+    }
+    lo, hi, ok := sheetBounds(result, opts.Overlays)
+    if !ok {
+        return errors.New("nothing to draw: no face was placed")
+    }
+    eps := 1e-9 * math.Max(1, math.Hypot(hi.X-lo.X, hi.Y-lo.Y))
+
+    d := &dxfWriter{w: bufio.NewWriter(w), scale: opts.Scale}
+    d.pair(0, "SECTION")
+    d.pair(2, "HEADER")
+    d.pair(9, "$ACADVER")
+    d.pair(1, "AC1015")
+    d.pair(9, "$INSUNITS")
+    d.pair(70, strconv.Itoa(insunits))
+    d.pair(0, "ENDSEC")
+
+    d.pair(0, "SECTION")
+    d.pair(2, "TABLES")
+    d.pair(0, "TABLE")
+    d.pair(2, "LAYER")
+    d.pair(70, "3")
+    for _, layer := range []struct {
+        name  string
+        color int
+    }{{DXFLayerCut, 7}, {DXFLayerFold, 8}, {DXFLayerEngrave, 3}} {
+        d.pair(0, "LAYER")
+        d.pair(2, layer.name)
+        d.pair(70, "0")
+        d.pair(62, strconv.Itoa(layer.color))
+        d.pair(6, "CONTINUOUS")
+    }
+    d.pair(0, "ENDTAB")
+    d.pair(0, "ENDSEC")
+
+    d.pair(0, "SECTION")
+    d.pair(2, "ENTITIES")
+    lines := sheetLines(result, opts.Mesh, opts.FaceGroups, opts.MinFoldAngle*math.Pi/180)
+    var cuts []sheetLine
+    for _, l := range lines {
+        if l.class == lineCut {
+            cuts = append(cuts, l)
+        }
+    }
+    for _, p := range chainSheetLines(cuts, eps) {
+        d.polyline(DXFLayerCut, 0, p.points, p.closed)
+    }
+    for _, l := range lines {
+        if opts.FoldHeatmap && l.bend >= 0 {
+            d.polyline(DXFLayerFold, dxfHeatColor(l.bend), []Point2{l.a, l.b}, false)
+            continue
+        }
+        switch l.class {
+        case lineMountain:
+            d.polyline(DXFLayerFold, 1, []Point2{l.a, l.b}, false)
+        case lineValley:
+            d.polyline(DXFLayerFold, 5, []Point2{l.a, l.b}, false)
+        case lineFold:
+            d.polyline(DXFLayerFold, 0, []Point2{l.a, l.b}, false)
+        }
+    }
+    if opts.FaceLabels {
+        for _, l := range sheetLabels(result, opts.FaceGroups) {
+            d.text(DXFLayerEngrave, l.at, opts.LabelSize, l.text, true)
+        }
+    }
+    for _, o := range opts.Overlays {
+        if o == nil {
+            continue
+        }
+        layer := DXFLayerEngrave
+        if o.Cut {
+            layer = DXFLayerCut
+        }
+        for _, l := range o.Polylines {
+            if len(l.Points) >= 2 {
+                d.polyline(layer, 0, l.Points, l.Closed)
+            }
+        }
+        for _, t := range o.Texts {
+            d.text(DXFLayerEngrave, t.At, t.Size*opts.Scale, t.Text, false)
+        }
+    }
+    d.pair(0, "ENDSEC")
+    d.pair(0, "EOF")
+    return d.w.Flush()
+}
+
+// dxfHeatColor picks the AutoCAD colour index closest to foldHeat's ramp;
+// R2000 files have no true colour.
+func dxfHeatColor(bend float64) int {
+    ramp := []int{5, 4, 3, 2, 30, 1} // blue, cyan, green, yellow, orange, red
+    t := math.Max(0, math.Min(1, bend/math.Pi))
+    return ramp[int(math.Round(t*float64(len(ramp)-1)))]
+}
+
+// dxfWriter writes DXF group code / value pairs.
+type dxfWriter struct {
+    w     *bufio.Writer
+    scale float64
+}
+
+func (d *dxfWriter) pair(code int, value string) {
+    fmt.Fprintf(d.w, "%3d\n%s\n", code, value)
+}
+
+func (d *dxfWriter) num(code int, v float64) {
+    d.pair(code, strconv.FormatFloat(math.Round(v*1e6)/1e6, 'f', -1, 64))
+}
+
+// polyline writes an LWPOLYLINE; color 0 means "by layer".
+func (d *dxfWriter) polyline(layer string, color int, pts []Point2, closed bool) {
+    d.pair(0, "LWPOLYLINE")
+    d.pair(100, "AcDbEntity")
+    d.pair(8, layer)
+    if color != 0 {
+        d.pair(62, strconv.Itoa(color))
+    }
+    d.pair(100, "AcDbPolyline")
+    d.pair(90, strconv.Itoa(len(pts)))
+    flags := 0
+    if closed {
+        flags = 1
+    }
+    d.pair(70, strconv.Itoa(flags))
+    for _, p := range pts {
+        d.num(10, p.X*d.scale)
+        d.num(20, p.Y*d.scale)
+    }
+}
+
+// text writes a TEXT entity of the given height (already in drawing units),
+// either starting at p or centred on it.
+func (d *dxfWriter) text(layer string, p Point2, height float64, s string, centred bool) {
+    s = strings.NewReplacer("\n", " ", "\r", " ").Replace(s)
+    d.pair(0, "TEXT")
+    d.pair(100, "AcDbEntity")
+    d.pair(8, layer)
+    d.pair(100, "AcDbText")
+    d.num(10, p.X*d.scale)
+    d.num(20, p.Y*d.scale)
+    d.num(30, 0)
+    d.num(40, height)
+    d.pair(1, s)
+    if centred {
+        d.pair(72, "1")
+        d.num(11, p.X*d.scale)
+        d.num(21, p.Y*d.scale)
+        d.num(31, 0)
+    }
+    d.pair(100, "AcDbText")
+    if centred {
+        d.pair(73, "2")
+    }
+}
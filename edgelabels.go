@@ -0,0 +1,63 @@
+package unfolder
+
+import "strconv"
+
+// -----------------------------
+//   Edge labels (matching numbers on cut edges)
+// -----------------------------
+
+// EdgeInstance is one copy of a mesh edge in the net: edge Edge (local
+// index) of face Face. A cut edge has two, one on each face.
+type EdgeInstance struct {
+    Face int `json:"face"`
+    Edge int `json:"edge"`
+}
+
+// AssignEdgeLabels numbers the cut edges of result that join two placed
+// faces, 1 upwards in vertex-pair order, and gives both copies of each edge
+// the same number, so the builder can find which edges glue together. Mesh
+// boundary edges don't get a label.
+func AssignEdgeLabels(result *UnfoldResult) map[EdgeInstance]int {
+    labels := make(map[EdgeInstance]int)
+    if result == nil {
+        return labels
+    }
+    placed := func(f int) bool {
+        return f >= 0 && f < len(result.Face2D) && len(result.Face2D[f].Vertices) >= 3
+    }
+    cuts := append([]NetEdge(nil), result.CutEdges...)
+    sortNetEdges(cuts)
+    next := 1
+    for _, e := range cuts {
+        if !placed(e.FaceA) || !placed(e.FaceB) {
+            continue
+        }
+        labels[EdgeInstance{e.FaceA, e.EdgeA}] = next
+        labels[EdgeInstance{e.FaceB, e.EdgeB}] = next
+        next++
+    }
+    return labels
+}
+
+// EdgeLabelOverlay prints each label just inside its edge. size is the text
+// height in net units; 0 picks a sixth of the median edge length.
+func EdgeLabelOverlay(result *UnfoldResult, labels map[EdgeInstance]int, size float64) *Overlay {
+    o := &Overlay{Name: "edge-labels"}
+    if result == nil {
+        return o
+    }
+    if size <= 0 {
+        size = edgeTextSize(result)
+    }
+    for f := range result.Face2D {
+        for i := range result.Face2D[f].Vertices {
+            e := EdgeInstance{f, i}
+            if n, ok := labels[e]; ok {
+                if t, ok := edgeText(result, e, strconv.Itoa(n), size); ok {
+                    o.Texts = append(o.Texts, t)
+                }
+            }
+        }
+    }
+    return o
+}
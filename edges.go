@@ -0,0 +1,82 @@
+package unfolder
+
+import "sort"
+
+// -----------------------------
+//   Cut and fold edges
+// -----------------------------
+
+// NetEdge is a mesh edge as it ends up in the net. Edges are referenced both
+// globally (Vertices) and per face: EdgeA is the local edge index in FaceA,
+// i.e. the edge runs from FaceA.Vertices[EdgeA] to FaceA.Vertices[EdgeA+1].
+// That's enough to look the edge up in Face2D without re-deriving anything.
+type NetEdge struct {
+    Vertices [2]int `json:"vertices"` // global vertex indices, smaller first
+    FaceA    int    `json:"faceA"`
+    EdgeA    int    `json:"edgeA"`
+    FaceB    int    `json:"faceB"` // -1 if the edge only belongs to FaceA in the net (boundary)
+    EdgeB    int    `json:"edgeB"` // -1 if FaceB is -1
+}
+
+// classifyEdges returns every face edge not covered by a fold as a cut edge.
+// Edges shared by two faces become one NetEdge with both sides; boundary and
+// non-manifold edges get one NetEdge per face with FaceB = -1.
+func classifyEdges(poly Polyhedron, folds []NetEdge) []NetEdge {
+    type side struct{ face, edge int }
+    folded := make(map[side]bool, 2*len(folds))
+    for _, f := range folds {
+        folded[side{f.FaceA, f.EdgeA}] = true
+        folded[side{f.FaceB, f.EdgeB}] = true
+    }
+
+    // group the unfolded face edges by mesh edge, remembering first-seen order
+    occ := make(map[[2]int][]side)
+    var order [][2]int
+    for fIdx, face := range poly.Faces {
+        n := len(face.Vertices)
+        for i := 0; i < n; i++ {
+            if folded[side{fIdx, i}] {
+                continue
+            }
+            e := sortPair(face.Vertices[i], face.Vertices[(i+1)%n])
+            if _, ok := occ[e]; !ok {
+                order = append(order, e)
+            }
+            occ[e] = append(occ[e], side{fIdx, i})
+        }
+    }
+
+    var cuts []NetEdge
+    for _, e := range order {
+        sides := occ[e]
+        if len(sides) == 2 {
+            cuts = append(cuts, NetEdge{
+                Vertices: e,
+                FaceA:    sides[0].face,
+                EdgeA:    sides[0].edge,
+                FaceB:    sides[1].face,
+                EdgeB:    sides[1].edge,
+            })
+            continue
+        }
+        for _, s := range sides {
+            cuts = append(cuts, NetEdge{Vertices: e, FaceA: s.face, EdgeA: s.edge, FaceB: -1, EdgeB: -1})
+        }
+    }
+    sortNetEdges(cuts)
+    return cuts
+}
+
+// sortNetEdges orders edges by vertex pair, then by face.
+func sortNetEdges(edges []NetEdge) {
+    sort.SliceStable(edges, func(i, j int) bool {
+        a, b := edges[i], edges[j]
+        if a.Vertices != b.Vertices {
+            if a.Vertices[0] != b.Vertices[0] {
+                return a.Vertices[0] < b.Vertices[0]
+            }
+            return a.Vertices[1] < b.Vertices[1]
+        }
+        return a.FaceA < b.FaceA
+    })
+}
@@ -0,0 +1,291 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+)
+
+// -----------------------------
+//   Incremental editing
+// -----------------------------
+
+// Unfolder keeps a net around for interactive editing: every change (cutting
+// a fold, hanging a piece on another edge, picking another root) lays out
+// again only the faces that moved, so an editor can follow the mouse. The
+// methods that move faces return them, sorted, so only those need redrawing.
+//
+// An Unfolder isn't safe for use by several goroutines at once.
+type Unfolder struct {
+    poly   Polyhedron
+    adj    *FaceAdjacency
+    opts   UnfoldOptions
+    root   int
+    parent []int
+    // via[f] is the entry of parent[f]'s neighbour list that f hangs on
+    via    []FaceNeighbor
+    face2D []Face2D
+    // split and origin are UnfoldResult.Mesh and FaceOrigin: when warped
+    // faces were split, poly is the split mesh and split points at it
+    split  *Polyhedron
+    origin []int
+}
+
+// NewUnfolder unfolds poly as UnfoldMeshWithOptions does and keeps the net
+// for editing. Overlaps and double walls (if opts asks for them) are worked
+// out by Result, not on every edit. If opts splits warped faces, the Unfolder
+// edits the split mesh: face indices given to and returned by its methods are
+// the split mesh's, and Result's Mesh and FaceOrigin say how they map back.
+func NewUnfolder(poly Polyhedron, rootFace int, opts UnfoldOptions) (*Unfolder, error) {
+    if opts.ValidateOnly {
+        return nil, errors.New("ValidateOnly produces no net to edit")
+    }
+    first := opts
+    first.DetectOverlaps, first.DoubleWalled, first.ReportMemory = false, false, false
+    first.Side = SideExterior // edits place faces unmirrored; Result mirrors
+    first.Material = nil      // and at their own sizes; Result makes room for the bends
+    if opts.Material != nil {
+        if err := opts.Material.check(); err != nil {
+            return nil, err
+        }
+    }
+    result, err := UnfoldMeshWithOptions(poly, rootFace, first)
+    if err != nil {
+        return nil, err
+    }
+    if result.Mesh != nil {
+        poly = *result.Mesh
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, err
+    }
+    u := &Unfolder{
+        poly:   poly,
+        adj:    adj,
+        opts:   opts,
+        root:   -1,
+        parent: append([]int(nil), result.SpanningTree...),
+        via:    make([]FaceNeighbor, len(poly.Faces)),
+        face2D: result.Face2D,
+        split:  result.Mesh,
+        origin: result.FaceOrigin,
+    }
+    for f := range u.parent {
+        if u.parent[f] < 0 && len(u.face2D[f].Vertices) > 0 {
+            u.root = f
+        }
+    }
+    for _, e := range result.FoldEdges {
+        nbr, ok := u.neighbor(e.FaceA, e.FaceB, e.Vertices)
+        if !ok {
+            return nil, fmt.Errorf("fold %v between faces %d and %d is not in the adjacency", e.Vertices, e.FaceA, e.FaceB)
+        }
+        u.via[e.FaceB] = nbr
+    }
+    return u, nil
+}
+
+// Root returns the face the net hangs from.
+func (u *Unfolder) Root() int {
+    return u.root
+}
+
+// Result returns the net as it is now, with cut edges, vertex instances and
+// whatever else the options ask for filled in. It's a snapshot: later edits
+// don't change it. It fails if opts.Material can't make room for the bends,
+// as UnfoldMeshWithOptions would.
+func (u *Unfolder) Result() (*UnfoldResult, error) {
+    var folds []NetEdge
+    for f, p := range u.parent {
+        if p < 0 || len(u.face2D[f].Vertices) == 0 {
+            continue
+        }
+        nbr := u.via[f]
+        childEdge, _ := findEdgeInFace(u.poly.Faces[f], nbr.SharedEdge)
+        folds = append(folds, NetEdge{
+            Vertices: nbr.SharedEdge,
+            FaceA:    p,
+            EdgeA:    nbr.ThisFaceEdge[0],
+            FaceB:    f,
+            EdgeB:    childEdge[0],
+        })
+    }
+    opts := u.opts
+    opts.ReportMemory = false
+    face2Ds := append([]Face2D(nil), u.face2D...)
+    if err := applyMaterial(u.poly, face2Ds, folds, opts.Material); err != nil {
+        return nil, err
+    }
+    result := finishResult(u.poly, face2Ds, append([]int(nil), u.parent...), folds, opts, nil)
+    if u.split != nil {
+        result.Mesh, result.FaceOrigin = u.split, append([]int(nil), u.origin...)
+    }
+    return result, nil
+}
+
+// Recut turns the fold along edge (a vertex pair, either order) into a cut.
+// The piece that hung on it is hung on the first other edge joining it to the
+// rest of the net instead (lowest face first, in each face's edge order);
+// use MoveSubtree to pick the edge. Fails if the fold is the only thing
+// holding the piece on.
+func (u *Unfolder) Recut(edge [2]int) ([]int, error) {
+    key := sortPair(edge[0], edge[1])
+    child := -1
+    for f, p := range u.parent {
+        if p >= 0 && u.via[f].SharedEdge == key {
+            child = f
+            break
+        }
+    }
+    if child < 0 {
+        return nil, fmt.Errorf("edge %v is not a fold", edge)
+    }
+    sub := u.subtree(child)
+    in := make(map[int]bool, len(sub))
+    for _, f := range sub {
+        in[f] = true
+    }
+    sorted := append([]int(nil), sub...)
+    sort.Ints(sorted)
+    for _, g := range sorted {
+        for _, nbr := range u.adj.Neighbors[g] {
+            if nbr.SharedEdge != key && !in[nbr.FaceIndex] {
+                return u.MoveSubtree(child, nbr.SharedEdge)
+            }
+        }
+    }
+    return nil, fmt.Errorf("cutting edge %v would split the net in two", edge)
+}
+
+// MoveSubtree takes face and everything hanging from it off its parent and
+// hangs it on edge instead, which has to join one of those faces to a face
+// outside them (a cut edge, so the piece swings over to it). The piece gets
+// laid out again from the face on edge; nothing else moves.
+func (u *Unfolder) MoveSubtree(face int, edge [2]int) ([]int, error) {
+    if face < 0 || face >= len(u.parent) || len(u.face2D[face].Vertices) == 0 {
+        return nil, fmt.Errorf("face %d is not in the net", face)
+    }
+    if face == u.root {
+        return nil, fmt.Errorf("face %d is the root: the whole net hangs from it", face)
+    }
+    key := sortPair(edge[0], edge[1])
+    sub := u.subtree(face)
+    in := make(map[int]bool, len(sub))
+    for _, f := range sub {
+        in[f] = true
+    }
+    g, h := -1, -1
+    for _, f := range sub {
+        for _, nbr := range u.adj.Neighbors[f] {
+            if nbr.SharedEdge == key && !in[nbr.FaceIndex] {
+                g, h = f, nbr.FaceIndex
+            }
+        }
+    }
+    if g < 0 {
+        return nil, fmt.Errorf("edge %v doesn't join face %d's piece to the rest of the net", edge, face)
+    }
+    if g == face && h == u.parent[face] && u.via[face].SharedEdge == key {
+        return nil, nil // already hangs there
+    }
+    hinge, ok := u.neighbor(h, g, key)
+    if !ok {
+        return nil, fmt.Errorf("edge %v is not in the adjacency", edge)
+    }
+
+    // hang the piece from g: turn the path g .. face around
+    u.reverseTo(g, face)
+    u.parent[g], u.via[g] = h, hinge
+    if err := u.place(g); err != nil {
+        return nil, err
+    }
+    sort.Ints(sub)
+    return sub, nil
+}
+
+// Reroot makes face the root of the net. The tree edges stay the same, only
+// their direction along the path to the old root changes, so no face moves:
+// the net keeps its shape and place (unlike UnfoldMesh from face, which would
+// put face at the origin).
+func (u *Unfolder) Reroot(face int) error {
+    if face < 0 || face >= len(u.parent) || len(u.face2D[face].Vertices) == 0 {
+        return fmt.Errorf("face %d is not in the net", face)
+    }
+    u.reverseTo(face, u.root)
+    u.parent[face] = -1
+    u.via[face] = FaceNeighbor{}
+    u.root = face
+    return nil
+}
+
+// reverseTo turns the tree path from g up to its ancestor top around, so
+// that each face on it hangs from the one below, through the same edges.
+// parent[g] is left for the caller to set.
+func (u *Unfolder) reverseTo(g, top int) {
+    prev := -1
+    var prevVia FaceNeighbor
+    for f := g; f != top; {
+        p, pv := u.parent[f], u.via[f]
+        if prev >= 0 {
+            u.parent[f], u.via[f] = prev, prevVia
+        }
+        // p now hangs from f, across the edge f hung on
+        nbr, _ := u.neighbor(f, p, pv.SharedEdge)
+        prev, prevVia = f, nbr
+        f = p
+    }
+    if prev >= 0 {
+        u.parent[top], u.via[top] = prev, prevVia
+    }
+}
+
+// place lays out f against its parent and then everything below it again.
+func (u *Unfolder) place(f int) error {
+    queue := []int{f}
+    for len(queue) > 0 {
+        c := queue[0]
+        queue = queue[1:]
+        p := u.parent[c]
+        nbr := u.via[c]
+        var pts Face2D
+        if err := placeAdjacentFace(u.poly, p, c, &u.face2D[p], &pts, &nbr, u.opts.Anchoring); err != nil {
+            return fmt.Errorf("failed to place face %d adjacent to %d: %w", c, p, err)
+        }
+        // a fresh Face2D, so results handed out earlier keep their copy
+        u.face2D[c] = Face2D{Vertices: pts.Vertices}
+        queue = append(queue, u.children(c)...)
+    }
+    return nil
+}
+
+// subtree returns f and every face below it, parents before children.
+func (u *Unfolder) subtree(f int) []int {
+    out := []int{f}
+    for i := 0; i < len(out); i++ {
+        out = append(out, u.children(out[i])...)
+    }
+    return out
+}
+
+// children returns the faces hanging from f, in f's edge order.
+func (u *Unfolder) children(f int) []int {
+    var out []int
+    for _, nbr := range u.adj.Neighbors[f] {
+        c := nbr.FaceIndex
+        if u.parent[c] == f && u.via[c].SharedEdge == nbr.SharedEdge {
+            out = append(out, c)
+        }
+    }
+    return out
+}
+
+// neighbor finds the entry of from's neighbour list for to across edge.
+func (u *Unfolder) neighbor(from, to int, edge [2]int) (FaceNeighbor, bool) {
+    for _, nbr := range u.adj.Neighbors[from] {
+        if nbr.FaceIndex == to && nbr.SharedEdge == edge {
+            return nbr, true
+        }
+    }
+    return FaceNeighbor{}, false
+}
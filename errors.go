@@ -0,0 +1,214 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "runtime/debug"
+)
+
+// -----------------------------
+//   Error types
+// -----------------------------
+
+// Failures callers may want to act on have errors of their own, to test
+// with errors.Is and errors.As instead of matching messages: the sentinels
+// ErrNonManifold, ErrDisconnected, ErrNonOrientable, ErrMemoryLimit, ... and
+// the types below, which say which face is at fault. The unfold functions
+// wrap them with %w, so they survive the "failed to place face ..." context.
+
+// ErrNonManifold means an edge is shared by three or more faces and the
+// caller asked for that to fail (UnfoldOptions.RequireManifold) rather than
+// have the edge cut.
+var ErrNonManifold = errors.New("mesh is not manifold")
+
+// nonManifoldError wraps ErrNonManifold with poly's first edge shared by
+// three or more faces; nil if there is none.
+func nonManifoldError(poly Polyhedron) error {
+    count := make(map[[2]int]int)
+    for _, face := range poly.Faces {
+        for i := range face.Vertices {
+            count[sortPair(face.Vertices[i], face.Vertices[(i+1)%len(face.Vertices)])]++
+        }
+    }
+    var bad [][2]int
+    for e, n := range count {
+        if n > 2 {
+            bad = append(bad, e)
+        }
+    }
+    if len(bad) == 0 {
+        return nil
+    }
+    sortPairs(bad)
+    return fmt.Errorf("%w: %d edges are shared by 3 or more faces, first is %d-%d (%d faces)",
+        ErrNonManifold, len(bad), bad[0][0], bad[0][1], count[bad[0]])
+}
+
+// ErrDegenerateFace is the error for a face that can't be laid flat: fewer
+// than three vertices, vertices out of range, or no area or a zero length
+// edge to hinge on. Reason says which.
+//
+//     var bad *unfolder.ErrDegenerateFace
+//     if errors.As(err, &bad) { ... remove face bad.Face and retry ... }
+type ErrDegenerateFace struct {
+    Face   int
+    Reason string
+}
+
+func (e *ErrDegenerateFace) Error() string {
+    if e.Reason == "" {
+        return fmt.Sprintf("face %d is degenerate", e.Face)
+    }
+    return fmt.Sprintf("face %d: %s", e.Face, e.Reason)
+}
+
+// Is matches a target ErrDegenerateFace with the same Face, or any face if
+// the target's Face is -1: errors.Is(err, &ErrDegenerateFace{Face: -1}).
+func (e *ErrDegenerateFace) Is(target error) bool {
+    t, ok := target.(*ErrDegenerateFace)
+    return ok && (t.Face == -1 || t.Face == e.Face)
+}
+
+// ErrNonPlanarFace is the error for a face whose corners aren't in one
+// plane: Deviation is the distance of the furthest corner from the face's
+// plane, in mesh units. Such a face can only be laid flat distorted; see
+// UnfoldOptions.PlanarityTolerance.
+type ErrNonPlanarFace struct {
+    Face      int
+    Deviation float64
+}
+
+func (e *ErrNonPlanarFace) Error() string {
+    return fmt.Sprintf("face %d is not planar (a corner is %.3g off its plane)", e.Face, e.Deviation)
+}
+
+// Is matches a target ErrNonPlanarFace with the same Face, or any face if
+// the target's Face is -1. Deviation isn't compared.
+func (e *ErrNonPlanarFace) Is(target error) bool {
+    t, ok := target.(*ErrNonPlanarFace)
+    return ok && (t.Face == -1 || t.Face == e.Face)
+}
+
+// ErrInvalidTree is the error for a parent array that isn't a spanning tree
+// of the face graph (see ValidateSpanningTree): Face is the face where it
+// goes wrong, or -1, and Reason says how.
+type ErrInvalidTree struct {
+    Face   int
+    Reason string
+}
+
+func (e *ErrInvalidTree) Error() string {
+    if e.Face < 0 {
+        return "invalid spanning tree: " + e.Reason
+    }
+    return fmt.Sprintf("invalid spanning tree at face %d: %s", e.Face, e.Reason)
+}
+
+// Is matches a target ErrInvalidTree with the same Face, or any face if the
+// target's Face is -1.
+func (e *ErrInvalidTree) Is(target error) bool {
+    t, ok := target.(*ErrInvalidTree)
+    return ok && (t.Face == -1 || t.Face == e.Face)
+}
+
+// ErrInternal is a bug caught on its way out of the package: a panic (index
+// out of range, nil map, ...) in one of the unfold, validation or mesh
+// repair functions, turned into an error so one bad mesh can't take down a
+// long-running server. Stage is where it happened ("adjacency",
+// "placement", ...), Face the face being worked on or -1, Value what was
+// panicked with and Stack the goroutine's stack at the time, for the bug
+// report.
+type ErrInternal struct {
+    Stage string
+    Face  int
+    Value interface{}
+    Stack []byte
+}
+
+func (e *ErrInternal) Error() string {
+    if e.Face >= 0 {
+        return fmt.Sprintf("internal error in %s at face %d: %v", e.Stage, e.Face, e.Value)
+    }
+    return fmt.Sprintf("internal error in %s: %v", e.Stage, e.Value)
+}
+
+// progress is how far an entry point has got, for the ErrInternal of a
+// panic. Keep stage and face up to date and defer recover:
+//
+//     at := &progress{stage: "adjacency", face: -1}
+//     defer at.recover(&err)
+type progress struct {
+    stage string
+    face  int
+}
+
+// recover turns a panic into an ErrInternal in *err. It must be deferred
+// itself, recover only works there.
+func (p *progress) recover(err *error) {
+    if r := recover(); r != nil {
+        *err = &ErrInternal{Stage: p.stage, Face: p.face, Value: r, Stack: debug.Stack()}
+    }
+}
+
+// ErrorInfo is an error in a form to send over the wire, for putting the
+// package behind an API: Code is stable and meant to be branched on, Message
+// is for people. Face is the face at fault, -1 if the error isn't about one
+// face, like Issue.Face. Deviation is set for "non-planar-face".
+//
+//     {"code": "degenerate-face", "message": "face 3: fewer than 3 vertices", "face": 3}
+//
+// The codes are "non-manifold", "disconnected", "non-orientable",
+// "degenerate-face", "non-planar-face", "invalid-tree", "memory-limit",
+// "constraint-conflict", "overlap-unavoidable", "search-budget",
+// "attempt-timeout", "internal" (a bug, see ErrInternal), and "error" for
+// anything else.
+type ErrorInfo struct {
+    Code      string  `json:"code"`
+    Message   string  `json:"message"`
+    Face      int     `json:"face"`
+    Deviation float64 `json:"deviation,omitempty"`
+}
+
+// DescribeError turns err into an ErrorInfo, going by the error types and
+// sentinels it wraps. nil gives nil.
+func DescribeError(err error) *ErrorInfo {
+    if err == nil {
+        return nil
+    }
+    info := &ErrorInfo{Code: "error", Message: err.Error(), Face: -1}
+    var degenerate *ErrDegenerateFace
+    var warped *ErrNonPlanarFace
+    var internal *ErrInternal
+    var tree *ErrInvalidTree
+    sentinels := []struct {
+        err  error
+        code string
+    }{
+        {ErrNonManifold, "non-manifold"},
+        {ErrDisconnected, "disconnected"},
+        {ErrNonOrientable, "non-orientable"},
+        {ErrMemoryLimit, "memory-limit"},
+        {ErrConstraintConflict, "constraint-conflict"},
+        {ErrOverlapUnavoidable, "overlap-unavoidable"},
+        {ErrSearchBudget, "search-budget"},
+        {ErrAttemptTimeout, "attempt-timeout"},
+    }
+    switch {
+    case errors.As(err, &degenerate):
+        info.Code, info.Face = "degenerate-face", degenerate.Face
+    case errors.As(err, &warped):
+        info.Code, info.Face, info.Deviation = "non-planar-face", warped.Face, warped.Deviation
+    case errors.As(err, &tree):
+        info.Code, info.Face = "invalid-tree", tree.Face
+    case errors.As(err, &internal):
+        info.Code, info.Face = "internal", internal.Face
+    default:
+        for _, s := range sentinels {
+            if errors.Is(err, s.err) {
+                info.Code = s.code
+                break
+            }
+        }
+    }
+    return info
+}
@@ -0,0 +1,159 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "image"
+    "image/color"
+    "io"
+    "math"
+)
+
+// -----------------------------
+//   Exploded views
+// -----------------------------
+
+// ExplodeOptions controls RenderExploded and ExportExplodedSVG.
+type ExplodeOptions struct {
+    // Distance each piece moves out, as a fraction of the model's bounding
+    // box diagonal. Default 0.25.
+    Distance float64
+    // Preview is how the exploded model is drawn. Unless Preview.FaceColors
+    // is set, every piece gets a colour of its own.
+    Preview PreviewOptions
+}
+
+// pieceColors are the piece colours of an exploded view, soft enough that
+// the shading still reads.
+var pieceColors = []color.RGBA{
+    {0x8d, 0xb6, 0xe8, 0xff}, // blue
+    {0xf2, 0xa6, 0x7a, 0xff}, // orange
+    {0x9c, 0xd6, 0x8f, 0xff}, // green
+    {0xe8, 0x9b, 0xb4, 0xff}, // pink
+    {0xf0, 0xd8, 0x74, 0xff}, // yellow
+    {0xb5, 0xa2, 0xe0, 0xff}, // purple
+    {0x83, 0xd3, 0xcc, 0xff}, // teal
+    {0xcf, 0xb4, 0x93, 0xff}, // tan
+}
+
+// Explode moves every piece of poly (a list of face indices, e.g. the
+// Faces of a GluingGraph's pieces or the placed faces of each
+// UnfoldMeshSegmented patch) away from the rest, by distance times the
+// model's bounding box diagonal. A piece moves along its average normal,
+// or, if that nearly cancels out (a closed piece), straight out from the
+// middle of the model. Pieces get vertices of their own; faces keep their
+// indices, and faces in no piece stay where they are.
+func Explode(poly Polyhedron, pieces [][]int, distance float64) (Polyhedron, error) {
+    pieceOf := make([]int, len(poly.Faces))
+    for i := range pieceOf {
+        pieceOf[i] = -1
+    }
+    for p, faces := range pieces {
+        for _, f := range faces {
+            if f < 0 || f >= len(poly.Faces) {
+                return Polyhedron{}, fmt.Errorf("piece %d: face %d out of range", p, f)
+            }
+            if pieceOf[f] >= 0 {
+                return Polyhedron{}, fmt.Errorf("face %d is in pieces %d and %d", f, pieceOf[f], p)
+            }
+            pieceOf[f] = p
+        }
+    }
+    if len(poly.Vertices) == 0 {
+        return Polyhedron{}, errors.New("polyhedron has no vertices")
+    }
+
+    lo, hi := poly.Vertices[0], poly.Vertices[0]
+    for _, v := range poly.Vertices[1:] {
+        lo = Vector3{math.Min(lo.X, v.X), math.Min(lo.Y, v.Y), math.Min(lo.Z, v.Z)}
+        hi = Vector3{math.Max(hi.X, v.X), math.Max(hi.Y, v.Y), math.Max(hi.Z, v.Z)}
+    }
+    middle := scale(add(lo, hi), 0.5)
+    step := distance * length(sub(hi, lo))
+
+    out := Polyhedron{Vertices: append([]Vector3(nil), poly.Vertices...), Faces: make([]Face, len(poly.Faces))}
+    copy(out.Faces, poly.Faces)
+    for _, faces := range pieces {
+        if len(faces) == 0 {
+            continue
+        }
+        // Newell normals are twice the face area long, so this is the
+        // area weighted average
+        var n, c Vector3
+        var area float64
+        for _, f := range faces {
+            fn := faceNormal(poly, poly.Faces[f])
+            n = add(n, fn)
+            area += length(fn)
+            c = add(c, scale(faceCentroid(poly, poly.Faces[f]), length(fn)))
+        }
+        var dir Vector3
+        if area > 0 {
+            c = scale(c, 1/area)
+            if length(n) > 0.1*area {
+                dir = normalize(n)
+            } else if d := sub(c, middle); length(d) > 0 {
+                dir = normalize(d)
+            }
+        }
+        offset := scale(dir, step)
+
+        moved := make(map[int]int)
+        for _, f := range faces {
+            face := poly.Faces[f]
+            vs := make([]int, len(face.Vertices))
+            for i, v := range face.Vertices {
+                nv, ok := moved[v]
+                if !ok {
+                    nv = len(out.Vertices)
+                    moved[v] = nv
+                    out.Vertices = append(out.Vertices, add(poly.Vertices[v], offset))
+                }
+                vs[i] = nv
+            }
+            face.Vertices = vs
+            out.Faces[f] = face
+        }
+    }
+    return out, nil
+}
+
+// explodedView explodes poly and colours its pieces for drawing.
+func explodedView(poly Polyhedron, pieces [][]int, opts ExplodeOptions) (Polyhedron, PreviewOptions, error) {
+    if opts.Distance == 0 {
+        opts.Distance = 0.25
+    }
+    out, err := Explode(poly, pieces, opts.Distance)
+    if err != nil {
+        return Polyhedron{}, PreviewOptions{}, err
+    }
+    prev := opts.Preview
+    if prev.FaceColors == nil {
+        prev.FaceColors = make([]color.Color, len(poly.Faces))
+        for p, faces := range pieces {
+            for _, f := range faces {
+                prev.FaceColors[f] = pieceColors[p%len(pieceColors)]
+            }
+        }
+    }
+    return out, prev, nil
+}
+
+// RenderExploded draws an exploded view of poly's pieces (see Explode), to
+// show how the pieces of a multi-piece kit fit together.
+func RenderExploded(poly Polyhedron, pieces [][]int, opts ExplodeOptions) (*image.RGBA, error) {
+    out, prev, err := explodedView(poly, pieces, opts)
+    if err != nil {
+        return nil, err
+    }
+    return RenderPreview(out, prev)
+}
+
+// ExportExplodedSVG writes the exploded view of RenderExploded as SVG.
+func ExportExplodedSVG(poly Polyhedron, pieces [][]int, w io.Writer, opts ExplodeOptions) error {
+    out, prev, err := explodedView(poly, pieces, opts)
+    if err != nil {
+        return err
+    }
+    return ExportPreviewSVG(out, w, prev)
+}
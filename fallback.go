@@ -0,0 +1,154 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "time"
+)
+
+// -----------------------------
+//   Fallback chains
+// -----------------------------
+
+// ErrAttemptTimeout is the error of an attempt that ran out of time.
+var ErrAttemptTimeout = errors.New("attempt timed out")
+
+// Attempt is one way of unfolding a mesh in a fallback chain, see
+// UnfoldWithFallback.
+type Attempt struct {
+    Name string
+    // Unfold makes the net. opts are the chain's options.
+    Unfold func(poly Polyhedron, rootFace int, opts UnfoldOptions) (*UnfoldResult, error)
+    // Timeout is how long Unfold may take; 0 = as long as it likes.
+    Timeout time.Duration
+}
+
+// StrategyAttempt unfolds along the tree of a spanning strategy, as
+// UnfoldMeshWithOptions with opts.Strategy = s.
+func StrategyAttempt(name string, s SpanningStrategy, timeout time.Duration) Attempt {
+    return Attempt{Name: name, Timeout: timeout, Unfold: func(poly Polyhedron, rootFace int, opts UnfoldOptions) (*UnfoldResult, error) {
+        opts.Strategy = s
+        return UnfoldMeshWithOptions(poly, rootFace, opts)
+    }}
+}
+
+// NonOverlappingAttempt searches for an overlap-free net with
+// UnfoldMeshNonOverlapping, within opts.SearchBudget.
+func NonOverlappingAttempt(timeout time.Duration) Attempt {
+    return Attempt{Name: "non-overlapping", Timeout: timeout, Unfold: UnfoldMeshNonOverlapping}
+}
+
+// OptimizeAttempt searches roots and strategies with OptimizeUnfold and
+// unfolds the winner again with the chain's options (the search itself
+// doesn't take any).
+func OptimizeAttempt(objective Objective, budget OptimizeBudget, timeout time.Duration) Attempt {
+    return Attempt{Name: "optimize", Timeout: timeout, Unfold: func(poly Polyhedron, _ int, opts UnfoldOptions) (*UnfoldResult, error) {
+        best, err := OptimizeUnfold(poly, objective, budget)
+        if err != nil {
+            return nil, err
+        }
+        opts.Strategy = FixedTree{Parent: best.Result.SpanningTree, Root: best.Root}
+        return UnfoldMeshWithOptions(poly, best.Root, opts)
+    }}
+}
+
+// AttemptReport is how one attempt of a chain went.
+type AttemptReport struct {
+    Name     string
+    Duration time.Duration
+    Err      error // ErrAttemptTimeout if it ran out of time
+    Overlaps int   // overlapping face pairs of its net, if it made one
+}
+
+// FallbackResult is the net UnfoldWithFallback settled on.
+type FallbackResult struct {
+    Result  *UnfoldResult
+    Root    int             // root face of Result, which the attempt may have picked
+    Attempt int             // index of the attempt that made Result
+    Reports []AttemptReport // one per attempt run, in order
+}
+
+// UnfoldWithFallback runs the attempts in order until one makes a net
+// without overlapping faces, e.g. steepest edge for 2 s, then a search for
+// 30 s, else breadth first: the best net to be had in a time budget from a
+// single call. If none gets there, the net with the fewest overlaps wins
+// (the earlier attempt on a tie), so end the chain with a quick strategy
+// that always unfolds. Only when no attempt made a net at all is it an
+// error, that of the last attempt.
+//
+// An attempt that runs out of time is given up on, not stopped: it runs on
+// in the background and its net is dropped. Searches stop by themselves
+// within their budgets (opts.SearchBudget, OptimizeBudget.Candidates), so
+// keep those to what the timeout allows.
+func UnfoldWithFallback(poly Polyhedron, rootFace int, opts UnfoldOptions, chain []Attempt) (*FallbackResult, error) {
+    if len(chain) == 0 {
+        return nil, errors.New("empty fallback chain")
+    }
+    out := &FallbackResult{Attempt: -1}
+    var lastErr error
+    for i, a := range chain {
+        if a.Unfold == nil {
+            return nil, fmt.Errorf("attempt %d (%s) has no Unfold function", i, a.Name)
+        }
+        start := time.Now()
+        res, err := runAttempt(a, poly, rootFace, opts)
+        report := AttemptReport{Name: a.Name, Duration: time.Since(start), Err: err}
+        if err == nil && res == nil {
+            report.Err = errors.New("no net")
+        }
+        if report.Err != nil {
+            out.Reports = append(out.Reports, report)
+            lastErr = fmt.Errorf("%s: %w", a.Name, report.Err)
+            continue
+        }
+        report.Overlaps = len(DetectOverlaps(res))
+        out.Reports = append(out.Reports, report)
+        if out.Result == nil || report.Overlaps < out.Reports[out.Attempt].Overlaps {
+            out.Result, out.Attempt = res, i
+        }
+        if report.Overlaps == 0 {
+            break
+        }
+    }
+    if out.Result == nil {
+        return nil, lastErr
+    }
+    out.Root = rootFace
+    for f, p := range out.Result.SpanningTree {
+        if p < 0 && f < len(out.Result.Face2D) && len(out.Result.Face2D[f].Vertices) > 0 {
+            out.Root = f
+            break
+        }
+    }
+    return out, nil
+}
+
+// runAttempt runs a, giving up on it after its timeout. A panic in a's Unfold
+// is its error, so the chain goes on to the next attempt.
+func runAttempt(a Attempt, poly Polyhedron, rootFace int, opts UnfoldOptions) (*UnfoldResult, error) {
+    unfold := func() (res *UnfoldResult, err error) {
+        at := &progress{stage: "attempt " + a.Name, face: -1}
+        defer at.recover(&err)
+        return a.Unfold(poly, rootFace, opts)
+    }
+    if a.Timeout <= 0 {
+        return unfold()
+    }
+    type outcome struct {
+        res *UnfoldResult
+        err error
+    }
+    done := make(chan outcome, 1) // buffered, so an abandoned attempt can still finish
+    go func() {
+        res, err := unfold()
+        done <- outcome{res, err}
+    }()
+    timer := time.NewTimer(a.Timeout)
+    defer timer.Stop()
+    select {
+    case o := <-done:
+        return o.res, o.err
+    case <-timer.C:
+        return nil, fmt.Errorf("%w after %v", ErrAttemptTimeout, a.Timeout)
+    }
+}
@@ -0,0 +1,86 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   EVA foam mode
+// -----------------------------
+
+// FoamOptions controls FoamAnnotations.
+type FoamOptions struct {
+    // SeamAllowance adds a band this wide outside every cut edge (0 = none).
+    SeamAllowance float64
+    // Mirrored puts the allowance on both sides of each seam instead of only
+    // on FaceA's side.
+    Mirrored bool
+}
+
+// BevelAnnotation tells a foam builder how to bevel one side of a cut edge.
+// Foam pieces are glued edge to edge instead of folded, so each side of the
+// seam is cut at half the dihedral angle.
+type BevelAnnotation struct {
+    Face      int      `json:"face"`
+    Edge      int      `json:"edge"`                // local edge index in Face
+    Dihedral  float64  `json:"dihedral"`            // interior angle of the seam, degrees
+    Bevel     float64  `json:"bevel"`               // blade angle from the foam surface, degrees (half the dihedral)
+    Convex    bool     `json:"convex"`              // seam is an outside corner
+    At        Point2   `json:"at"`                  // edge midpoint, for the label
+    Label     string   `json:"label"`               // e.g. "45.0°"
+    Allowance []Point2 `json:"allowance,omitempty"` // seam allowance band, if requested
+}
+
+// FoamAnnotations returns a bevel annotation for each side of every cut edge
+// that joins two faces (mesh boundary edges aren't glued, so they get none).
+func FoamAnnotations(poly Polyhedron, result *UnfoldResult, opts FoamOptions) []BevelAnnotation {
+    if result == nil {
+        return nil
+    }
+    var out []BevelAnnotation
+    for _, e := range result.CutEdges {
+        if e.FaceB < 0 {
+            continue
+        }
+        theta := DihedralAngle(poly, e) * 180 / math.Pi
+        sides := []struct{ face, edge int }{{e.FaceA, e.EdgeA}, {e.FaceB, e.EdgeB}}
+        for k, s := range sides {
+            pts := result.Face2D[s.face].Vertices
+            if len(pts) < 3 {
+                continue
+            }
+            a, b := pts[s.edge], pts[(s.edge+1)%len(pts)]
+            ann := BevelAnnotation{
+                Face:     s.face,
+                Edge:     s.edge,
+                Dihedral: theta,
+                Bevel:    theta / 2,
+                Convex:   theta < 180,
+                At:       Point2{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2},
+                Label:    fmt.Sprintf("%.1f°", theta/2),
+            }
+            if opts.SeamAllowance > 0 && (k == 0 || opts.Mirrored) {
+                ann.Allowance = edgeBand(pts, s.edge, opts.SeamAllowance)
+            }
+            out = append(out, ann)
+        }
+    }
+    return out
+}
+
+// edgeBand returns the rectangle of width w just outside edge i of a placed
+// face polygon.
+func edgeBand(pts []Point2, i int, w float64) []Point2 {
+    a, b := pts[i], pts[(i+1)%len(pts)]
+    n := leftNormal(a, b)
+    if polygonArea(pts) > 0 {
+        // CCW face: the outside is on the right
+        n = Point2{X: -n.X, Y: -n.Y}
+    }
+    return []Point2{
+        a, b,
+        {b.X + n.X*w, b.Y + n.Y*w},
+        {a.X + n.X*w, a.Y + n.Y*w},
+    }
+}
@@ -0,0 +1,298 @@
+package unfolder
+
+import (
+    "bytes"
+    "encoding/base64"
+    "encoding/binary"
+    "encoding/json"
+    "errors"
+    "image/color"
+    "io"
+    "math"
+    "strconv"
+)
+
+// -----------------------------
+//   glTF fold animation
+// -----------------------------
+
+// FoldGLTFOptions controls ExportFoldGLTF.
+type FoldGLTFOptions struct {
+    // Duration of the fold, flat to folded, in seconds. Default 4.
+    Duration float64
+    // Keyframes per fold, at least 2. Default 12; more keyframes make no
+    // difference to the motion, which is a steady turn either way, but
+    // keep viewers that lerp rotations from cutting corners.
+    Keyframes int
+    // Scale is metres (glTF's unit) per mesh unit. 0 = from the mesh's
+    // Units, else 1.
+    Scale float64
+    // Color of the paper, both sides. Default off-white.
+    Color color.Color
+    // Binary writes a .glb file instead of .gltf JSON with the data
+    // embedded.
+    Binary bool
+}
+
+// ExportFoldGLTF writes the net as an animated glTF 2.0 scene that folds
+// itself up, for any glTF viewer to play. The nodes follow the spanning
+// tree: every placed face is a node with its own mesh, hanging off its
+// parent face through a hinge node on the fold edge, and the animation
+// turns each hinge about its fold from flat to the mesh's dihedral angle,
+// all at once. As in GenerateFoldAnimation the net starts on the ground
+// (glTF's y = 0 plane) with the inside of the model up and folds up off it.
+func ExportFoldGLTF(poly Polyhedron, result *UnfoldResult, w io.Writer, opts FoldGLTFOptions) error {
+    if result == nil {
+        return errors.New("nil unfold result")
+    }
+    sim, err := newFoldSim(poly, result)
+    if err != nil {
+        return err
+    }
+    if len(sim.order) == 0 {
+        return errors.New("nothing to export: no placed faces")
+    }
+    if opts.Duration <= 0 {
+        opts.Duration = 4
+    }
+    if opts.Keyframes < 2 {
+        opts.Keyframes = 12
+    }
+    if opts.Scale <= 0 {
+        opts.Scale = exportScale(&poly, "m")
+    }
+    if opts.Color == nil {
+        opts.Color = color.RGBA{0xf4, 0xf1, 0xea, 0xff}
+    }
+
+    doc := gltfOut{Asset: gltfAsset{Version: "2.0", Generator: "unfolder"}}
+    var bin bytes.Buffer
+    // view adds a buffer view over data
+    view := func(data interface{}, target int) int {
+        off := bin.Len()
+        binary.Write(&bin, binary.LittleEndian, data)
+        doc.BufferViews = append(doc.BufferViews, gltfBufferView{Buffer: 0, ByteOffset: off, ByteLength: bin.Len() - off, Target: target})
+        return len(doc.BufferViews) - 1
+    }
+    accessor := func(a gltfAccessor) int {
+        doc.Accessors = append(doc.Accessors, a)
+        return len(doc.Accessors) - 1
+    }
+
+    r, g, b, a := opts.Color.RGBA()
+    doc.Materials = []gltfMaterial{{
+        Name: "paper",
+        PBR: gltfPBR{
+            BaseColorFactor: [4]float64{float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff, float64(a) / 0xffff},
+            MetallicFactor:  0,
+            RoughnessFactor: 0.9,
+        },
+        DoubleSided: true,
+    }}
+
+    // the scene root turns an exterior net over and goes from z up to
+    // glTF's y up: (x, y, z) -> (x, -z, y), a quarter turn about x; an
+    // interior net folds up as it is, (x, y, z) -> (x, z, -y)
+    s := math.Sqrt(0.5)
+    turn := []float64{s, 0, 0, s}
+    if result.Side == SideInterior {
+        turn[0] = -s
+    }
+    doc.Nodes = append(doc.Nodes, gltfNode{Name: "net", Rotation: turn, Scale: []float64{opts.Scale, opts.Scale, opts.Scale}})
+    doc.Scenes = []gltfScene{{Nodes: []int{0}}}
+
+    faceNode := make([]int, len(poly.Faces))
+    times := make([]float32, opts.Keyframes)
+    for k := range times {
+        times[k] = float32(opts.Duration * float64(k) / float64(opts.Keyframes-1))
+    }
+    var timeAcc = -1
+    anim := gltfAnimation{Name: "fold"}
+    for _, f := range sim.order {
+        pts := result.Face2D[f].Vertices
+        pos := make([]float32, 0, 3*len(pts))
+        lo := []float64{math.Inf(1), math.Inf(1), 0}
+        hi := []float64{math.Inf(-1), math.Inf(-1), 0}
+        for _, p := range pts {
+            pos = append(pos, float32(p.X), float32(p.Y), 0)
+            lo[0], lo[1] = math.Min(lo[0], float64(float32(p.X))), math.Min(lo[1], float64(float32(p.Y)))
+            hi[0], hi[1] = math.Max(hi[0], float64(float32(p.X))), math.Max(hi[1], float64(float32(p.Y)))
+        }
+        var idx []uint32
+        for _, t := range earClip(poly, poly.Faces[f]) {
+            idx = append(idx, uint32(t[0]), uint32(t[1]), uint32(t[2]))
+        }
+        posAcc := accessor(gltfAccessor{BufferView: view(pos, 34962), ComponentType: 5126, Count: len(pts), Type: "VEC3", Min: lo, Max: hi})
+        idxAcc := accessor(gltfAccessor{BufferView: view(idx, 34963), ComponentType: 5125, Count: len(idx), Type: "SCALAR"})
+        doc.Meshes = append(doc.Meshes, gltfMesh{
+            Name:       "face " + strconv.Itoa(f),
+            Primitives: []gltfPrimitive{{Attributes: map[string]int{"POSITION": posAcc}, Indices: idxAcc, Material: 0}},
+        })
+        mesh := len(doc.Meshes) - 1
+        node := gltfNode{Name: "face " + strconv.Itoa(f), Mesh: &mesh}
+
+        fi := sim.hinge[f]
+        if fi < 0 {
+            // a root: straight under the scene root
+            faceNode[f] = len(doc.Nodes)
+            doc.Nodes = append(doc.Nodes, node)
+            doc.Nodes[0].Children = append(doc.Nodes[0].Children, faceNode[f])
+            continue
+        }
+
+        // hinge node on the fold line, turning; the face hangs off it moved
+        // back so its local frame is the net's again
+        at, dir := sim.axisAt[fi], sim.axisDir[fi]
+        if timeAcc < 0 {
+            timeAcc = accessor(gltfAccessor{BufferView: view(times, 0), ComponentType: 5126, Count: len(times), Type: "SCALAR",
+                Min: []float64{0}, Max: []float64{float64(times[len(times)-1])}})
+        }
+        rot := make([]float32, 0, 4*len(times))
+        for k := range times {
+            half := sim.angle[fi] * float64(k) / float64(len(times)-1) / 2
+            sn := math.Sin(half)
+            rot = append(rot, float32(dir.X*sn), float32(dir.Y*sn), float32(dir.Z*sn), float32(math.Cos(half)))
+        }
+        rotAcc := accessor(gltfAccessor{BufferView: view(rot, 0), ComponentType: 5126, Count: len(times), Type: "VEC4"})
+
+        hinge := len(doc.Nodes)
+        doc.Nodes = append(doc.Nodes, gltfNode{Name: "fold " + strconv.Itoa(fi), Translation: []float64{at.X, at.Y, at.Z}})
+        faceNode[f] = len(doc.Nodes)
+        node.Translation = []float64{-at.X, -at.Y, -at.Z}
+        doc.Nodes = append(doc.Nodes, node)
+        doc.Nodes[hinge].Children = []int{faceNode[f]}
+        parent := faceNode[sim.parent[f]]
+        doc.Nodes[parent].Children = append(doc.Nodes[parent].Children, hinge)
+
+        anim.Samplers = append(anim.Samplers, gltfSampler{Input: timeAcc, Output: rotAcc, Interpolation: "LINEAR"})
+        anim.Channels = append(anim.Channels, gltfChannel{Sampler: len(anim.Samplers) - 1, Target: gltfTarget{Node: hinge, Path: "rotation"}})
+    }
+    if len(anim.Channels) > 0 {
+        doc.Animations = []gltfAnimation{anim}
+    }
+
+    doc.Buffers = []gltfBuffer{{ByteLength: bin.Len()}}
+    if !opts.Binary {
+        doc.Buffers[0].URI = "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(bin.Bytes())
+        enc := json.NewEncoder(w)
+        return enc.Encode(doc)
+    }
+
+    js, err := json.Marshal(doc)
+    if err != nil {
+        return err
+    }
+    for len(js)%4 != 0 {
+        js = append(js, ' ')
+    }
+    for bin.Len()%4 != 0 {
+        bin.WriteByte(0)
+    }
+    var out bytes.Buffer
+    out.WriteString("glTF")
+    binary.Write(&out, binary.LittleEndian, []uint32{2, uint32(12 + 8 + len(js) + 8 + bin.Len())})
+    binary.Write(&out, binary.LittleEndian, []uint32{uint32(len(js)), 0x4E4F534A}) // "JSON"
+    out.Write(js)
+    binary.Write(&out, binary.LittleEndian, []uint32{uint32(bin.Len()), 0x004E4942}) // "BIN\0"
+    out.Write(bin.Bytes())
+    _, err = w.Write(out.Bytes())
+    return err
+}
+
+// glTF document, only what ExportFoldGLTF writes.
+type gltfOut struct {
+    Asset       gltfAsset        `json:"asset"`
+    Scene       int              `json:"scene"`
+    Scenes      []gltfScene      `json:"scenes"`
+    Nodes       []gltfNode       `json:"nodes"`
+    Meshes      []gltfMesh       `json:"meshes"`
+    Materials   []gltfMaterial   `json:"materials"`
+    Animations  []gltfAnimation  `json:"animations,omitempty"`
+    Accessors   []gltfAccessor   `json:"accessors"`
+    BufferViews []gltfBufferView `json:"bufferViews"`
+    Buffers     []gltfBuffer     `json:"buffers"`
+}
+
+type gltfAsset struct {
+    Version   string `json:"version"`
+    Generator string `json:"generator,omitempty"`
+}
+
+type gltfScene struct {
+    Nodes []int `json:"nodes"`
+}
+
+type gltfNode struct {
+    Name        string    `json:"name,omitempty"`
+    Mesh        *int      `json:"mesh,omitempty"`
+    Children    []int     `json:"children,omitempty"`
+    Translation []float64 `json:"translation,omitempty"`
+    Rotation    []float64 `json:"rotation,omitempty"`
+    Scale       []float64 `json:"scale,omitempty"`
+}
+
+type gltfMesh struct {
+    Name       string          `json:"name,omitempty"`
+    Primitives []gltfPrimitive `json:"primitives"`
+}
+
+type gltfPrimitive struct {
+    Attributes map[string]int `json:"attributes"`
+    Indices    int            `json:"indices"`
+    Material   int            `json:"material"`
+}
+
+type gltfMaterial struct {
+    Name        string  `json:"name,omitempty"`
+    PBR         gltfPBR `json:"pbrMetallicRoughness"`
+    DoubleSided bool    `json:"doubleSided"`
+}
+
+type gltfPBR struct {
+    BaseColorFactor [4]float64 `json:"baseColorFactor"`
+    MetallicFactor  float64    `json:"metallicFactor"`
+    RoughnessFactor float64    `json:"roughnessFactor"`
+}
+
+type gltfAnimation struct {
+    Name     string        `json:"name,omitempty"`
+    Channels []gltfChannel `json:"channels"`
+    Samplers []gltfSampler `json:"samplers"`
+}
+
+type gltfChannel struct {
+    Sampler int        `json:"sampler"`
+    Target  gltfTarget `json:"target"`
+}
+
+type gltfTarget struct {
+    Node int    `json:"node"`
+    Path string `json:"path"`
+}
+
+type gltfSampler struct {
+    Input         int    `json:"input"`
+    Output        int    `json:"output"`
+    Interpolation string `json:"interpolation"`
+}
+
+type gltfAccessor struct {
+    BufferView    int       `json:"bufferView"`
+    ComponentType int       `json:"componentType"`
+    Count         int       `json:"count"`
+    Type          string    `json:"type"`
+    Min           []float64 `json:"min,omitempty"`
+    Max           []float64 `json:"max,omitempty"`
+}
+
+type gltfBufferView struct {
+    Buffer     int `json:"buffer"`
+    ByteOffset int `json:"byteOffset"`
+    ByteLength int `json:"byteLength"`
+    Target     int `json:"target,omitempty"`
+}
+
+type gltfBuffer struct {
+    URI        string `json:"uri,omitempty"`
+    ByteLength int    `json:"byteLength"`
+}
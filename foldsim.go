@@ -0,0 +1,424 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Fold-order simulation
+// -----------------------------
+
+// FoldCollision is a fold that can't be made in the given order: while fold
+// Fold (step Step of the order) swings Moving up, Moving runs into Hit,
+// which is already where earlier steps left it. At is how far along the
+// fold (0..1) they first meet.
+type FoldCollision struct {
+    Step   int     `json:"step"`
+    Fold   int     `json:"fold"` // index into UnfoldResult.FoldEdges
+    Moving int     `json:"moving"`
+    Hit    int     `json:"hit"`
+    At     float64 `json:"at"`
+}
+
+// FoldSimOptions controls SimulateFoldOrder.
+type FoldSimOptions struct {
+    // Samples is how many positions along each fold are checked. Default 8.
+    Samples int
+}
+
+// SimulateFoldOrder folds the net in 3D one fold at a time, in order
+// (indices into result.FoldEdges; nil means FoldEdges order), and reports
+// every face that would have to pass through another one to make its fold.
+// Each fold turns the part of the net away from the root about its hinge
+// from flat to the mesh's dihedral angle, checked at opts.Samples steps on
+// the way; faces sharing a mesh vertex are allowed to touch. Folds left out
+// of order stay flat. Collisions are sorted by step, moving face and hit
+// face, with one entry per pair and step.
+func SimulateFoldOrder(poly Polyhedron, result *UnfoldResult, order []int, opts FoldSimOptions) ([]FoldCollision, error) {
+    if result == nil {
+        return nil, errors.New("nil result")
+    }
+    if opts.Samples <= 0 {
+        opts.Samples = 8
+    }
+    sim, err := newFoldSim(poly, result)
+    if err != nil {
+        return nil, err
+    }
+    if order == nil {
+        order = make([]int, len(result.FoldEdges))
+        for i := range order {
+            order[i] = i
+        }
+    }
+    seen := make([]bool, len(result.FoldEdges))
+    for step, fi := range order {
+        if fi < 0 || fi >= len(result.FoldEdges) {
+            return nil, fmt.Errorf("step %d: fold %d out of range", step, fi)
+        }
+        if seen[fi] {
+            return nil, fmt.Errorf("step %d: fold %d is already folded", step, fi)
+        }
+        seen[fi] = true
+    }
+
+    progress := make([]float64, len(result.FoldEdges))
+    var out []FoldCollision
+    for step, fi := range order {
+        moving := sim.subtree(sim.child[fi])
+        hits := make(map[[2]int]float64)
+        for k := 1; k <= opts.Samples; k++ {
+            t := float64(k) / float64(opts.Samples)
+            progress[fi] = t
+            pts := sim.pose(progress)
+            for _, pair := range sim.collisions(pts, moving) {
+                if _, ok := hits[pair]; !ok {
+                    hits[pair] = t
+                }
+            }
+        }
+        progress[fi] = 1
+        start := len(out)
+        for pair, t := range hits {
+            out = append(out, FoldCollision{Step: step, Fold: fi, Moving: pair[0], Hit: pair[1], At: t})
+        }
+        batch := out[start:]
+        sort.Slice(batch, func(i, j int) bool {
+            if batch[i].Moving != batch[j].Moving {
+                return batch[i].Moving < batch[j].Moving
+            }
+            return batch[i].Hit < batch[j].Hit
+        })
+    }
+    return out, nil
+}
+
+// FoldNet folds result all the way back up and returns the folded faces as a
+// mesh: one face per placed face, each with its own copies of its corners,
+// in face order. Every piece of the net is moved so that its root face lands
+// on the same face of poly, so for a correct net the result is poly again up
+// to rounding; how far it is off measures the error of the whole unfold.
+// Faces that weren't placed are left out. Use WeldVertices to join the
+// corners up again.
+func FoldNet(poly Polyhedron, result *UnfoldResult) (Polyhedron, error) {
+    if result == nil {
+        return Polyhedron{}, errors.New("nil result")
+    }
+    sim, err := newFoldSim(poly, result)
+    if err != nil {
+        return Polyhedron{}, err
+    }
+    progress := make([]float64, len(result.FoldEdges))
+    for i := range progress {
+        progress[i] = 1
+    }
+    pts := sim.pose(progress)
+
+    // the roots are flat in the net; move each piece onto its root in 3D
+    place := make([]rigid3, len(pts))
+    for _, f := range sim.order {
+        if p := sim.parent[f]; p >= 0 {
+            place[f] = place[p]
+            continue
+        }
+        vs := poly.Faces[f].Vertices
+        from, okA := frameOf(pts[f][0], pts[f][1], pts[f][2])
+        to, okB := frameOf(poly.Vertices[vs[0]], poly.Vertices[vs[1]], poly.Vertices[vs[2]])
+        if !okA || !okB {
+            return Polyhedron{}, fmt.Errorf("root face %d is degenerate", f)
+        }
+        place[f] = to.then(from.inverse())
+    }
+
+    out := Polyhedron{Name: poly.Name, Units: poly.Units}
+    for f := range poly.Faces {
+        if len(pts[f]) < 3 {
+            continue
+        }
+        face := Face{Vertices: make([]int, len(pts[f]))}
+        for i, p := range pts[f] {
+            face.Vertices[i] = len(out.Vertices)
+            out.Vertices = append(out.Vertices, place[f].apply(p))
+        }
+        out.Faces = append(out.Faces, face)
+    }
+    return out, nil
+}
+
+// frameOf returns the rigid map from the standard axes to the frame of a
+// triangle: origin a, x towards b, z along its normal.
+func frameOf(a, b, c Vector3) (rigid3, bool) {
+    x := sub(b, a)
+    n := cross(x, sub(c, a))
+    if length(x) == 0 || length(n) == 0 {
+        return rigid3{}, false
+    }
+    x, n = normalize(x), normalize(n)
+    y := cross(n, x)
+    return rigid3{
+        R: [3][3]float64{{x.X, y.X, n.X}, {x.Y, y.Y, n.Y}, {x.Z, y.Z, n.Z}},
+        T: a,
+    }, true
+}
+
+// inverse returns the inverse map: Rᵀ and -Rᵀ T.
+func (m rigid3) inverse() rigid3 {
+    var out rigid3
+    for i := 0; i < 3; i++ {
+        for j := 0; j < 3; j++ {
+            out.R[i][j] = m.R[j][i]
+        }
+    }
+    out.T = scale(rigid3{R: out.R}.apply(m.T), -1)
+    return out
+}
+
+// foldSim holds what's needed to pose a net partly folded: every placed face
+// hangs off its parent by a hinge rotation.
+type foldSim struct {
+    poly     Polyhedron
+    result   *UnfoldResult
+    order    []int // placed faces, parents before children
+    parent   []int
+    hinge    []int     // fold index joining each face to its parent, -1 for roots
+    child    []int     // per fold: the face on the side away from the root
+    axisAt   []Vector3 // per fold: a point on the hinge in the flat net
+    axisDir  []Vector3 // per fold: unit hinge direction in the flat net
+    angle    []float64 // per fold: signed turn from flat to the mesh's dihedral
+    children [][]int
+    eps      float64
+}
+
+func newFoldSim(poly Polyhedron, result *UnfoldResult) (*foldSim, error) {
+    nFaces := len(poly.Faces)
+    if len(result.Face2D) != nFaces || len(result.SpanningTree) != nFaces {
+        return nil, fmt.Errorf("result has %d faces, mesh %d", len(result.Face2D), nFaces)
+    }
+    s := &foldSim{
+        poly:     poly,
+        result:   result,
+        parent:   result.SpanningTree,
+        hinge:    make([]int, nFaces),
+        child:    make([]int, len(result.FoldEdges)),
+        axisAt:   make([]Vector3, len(result.FoldEdges)),
+        axisDir:  make([]Vector3, len(result.FoldEdges)),
+        angle:    make([]float64, len(result.FoldEdges)),
+        children: make([][]int, nFaces),
+    }
+    for f := range s.hinge {
+        s.hinge[f] = -1
+    }
+    for fi, e := range result.FoldEdges {
+        c, ce := e.FaceB, e.EdgeB
+        p := e.FaceA
+        if s.parent[e.FaceA] == e.FaceB {
+            c, ce, p = e.FaceA, e.EdgeA, e.FaceB
+        } else if s.parent[e.FaceB] != e.FaceA {
+            return nil, fmt.Errorf("fold %d (faces %d/%d) is not a spanning tree edge", fi, e.FaceA, e.FaceB)
+        }
+        pts := result.Face2D[c].Vertices
+        if len(pts) < 3 || ce < 0 || ce >= len(pts) {
+            return nil, fmt.Errorf("fold %d: face %d is not placed", fi, c)
+        }
+        a, b := pts[ce], pts[(ce+1)%len(pts)]
+        s.child[fi] = c
+        s.hinge[c] = fi
+        s.axisAt[fi] = Vector3{a.X, a.Y, 0}
+        s.axisDir[fi] = normalize(Vector3{b.X - a.X, b.Y - a.Y, 0})
+
+        // the same edge in 3D, in the same direction
+        face := poly.Faces[c]
+        a3, b3 := poly.Vertices[face.Vertices[ce]], poly.Vertices[face.Vertices[(ce+1)%len(face.Vertices)]]
+        np, nc := normalize(faceNormal(poly, poly.Faces[p])), normalize(faceNormal(poly, face))
+        s.angle[fi] = math.Atan2(dot(cross(np, nc), normalize(sub(b3, a3))), dot(np, nc))
+    }
+    for f, p := range s.parent {
+        if p >= 0 {
+            s.children[p] = append(s.children[p], f)
+        }
+    }
+    for f, p := range s.parent {
+        if p < 0 && len(result.Face2D[f].Vertices) >= 3 {
+            s.order = append(s.order, s.subtree(f)...)
+        }
+    }
+    lo, hi := boundingBox(poly.Vertices)
+    s.eps = 1e-7 * length(sub(hi, lo))
+    if s.eps == 0 {
+        s.eps = 1e-9
+    }
+    return s, nil
+}
+
+// subtree returns f and everything below it, parents first.
+func (s *foldSim) subtree(f int) []int {
+    out := []int{f}
+    for q := 0; q < len(out); q++ {
+        out = append(out, s.children[out[q]]...)
+    }
+    return out
+}
+
+// rigid3 is x -> R x + T.
+type rigid3 struct {
+    R [3][3]float64
+    T Vector3
+}
+
+var identity3 = rigid3{R: [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}}
+
+func (m rigid3) apply(p Vector3) Vector3 {
+    return Vector3{
+        m.R[0][0]*p.X + m.R[0][1]*p.Y + m.R[0][2]*p.Z + m.T.X,
+        m.R[1][0]*p.X + m.R[1][1]*p.Y + m.R[1][2]*p.Z + m.T.Y,
+        m.R[2][0]*p.X + m.R[2][1]*p.Y + m.R[2][2]*p.Z + m.T.Z,
+    }
+}
+
+// then returns m applied after n (m ∘ n).
+func (m rigid3) then(n rigid3) rigid3 {
+    var out rigid3
+    for i := 0; i < 3; i++ {
+        for j := 0; j < 3; j++ {
+            for k := 0; k < 3; k++ {
+                out.R[i][j] += m.R[i][k] * n.R[k][j]
+            }
+        }
+    }
+    out.T = m.apply(n.T)
+    return out
+}
+
+// rotationAbout turns by angle (right hand rule) about the line through at
+// along the unit vector d.
+func rotationAbout(at, d Vector3, angle float64) rigid3 {
+    c, s := math.Cos(angle), math.Sin(angle)
+    t := 1 - c
+    var m rigid3
+    m.R = [3][3]float64{
+        {t*d.X*d.X + c, t*d.X*d.Y - s*d.Z, t*d.X*d.Z + s*d.Y},
+        {t*d.X*d.Y + s*d.Z, t*d.Y*d.Y + c, t*d.Y*d.Z - s*d.X},
+        {t*d.X*d.Z - s*d.Y, t*d.Y*d.Z + s*d.X, t*d.Z*d.Z + c},
+    }
+    m.T = sub(at, Vector3{
+        m.R[0][0]*at.X + m.R[0][1]*at.Y + m.R[0][2]*at.Z,
+        m.R[1][0]*at.X + m.R[1][1]*at.Y + m.R[1][2]*at.Z,
+        m.R[2][0]*at.X + m.R[2][1]*at.Y + m.R[2][2]*at.Z,
+    })
+    return m
+}
+
+// pose returns every placed face's corners in 3D with fold i turned
+// progress[i] of the way (0 = flat, 1 = as in the mesh). The roots stay in
+// the z = 0 plane.
+func (s *foldSim) pose(progress []float64) [][]Vector3 {
+    frames := make([]rigid3, len(s.parent))
+    pts := make([][]Vector3, len(s.parent))
+    for _, f := range s.order {
+        m := identity3
+        if fi := s.hinge[f]; fi >= 0 {
+            m = frames[s.parent[f]]
+            if progress[fi] != 0 {
+                m = m.then(rotationAbout(s.axisAt[fi], s.axisDir[fi], s.angle[fi]*progress[fi]))
+            }
+        }
+        frames[f] = m
+        flat := s.result.Face2D[f].Vertices
+        pts[f] = make([]Vector3, len(flat))
+        for i, p := range flat {
+            pts[f][i] = m.apply(Vector3{p.X, p.Y, 0})
+        }
+    }
+    return pts
+}
+
+// collisions returns the (moving, other) face pairs that cut into each other,
+// moving being a set of faces and other anything placed outside it.
+func (s *foldSim) collisions(pts [][]Vector3, moving []int) [][2]int {
+    in := make([]bool, len(pts))
+    for _, f := range moving {
+        in[f] = true
+    }
+    type box3 struct{ lo, hi Vector3 }
+    boxes := make([]box3, len(pts))
+    for f, p := range pts {
+        if len(p) > 0 {
+            lo, hi := boundingBox(p)
+            boxes[f] = box3{lo, hi}
+        }
+    }
+    apart := func(a, b box3) bool {
+        return a.lo.X > b.hi.X+s.eps || b.lo.X > a.hi.X+s.eps ||
+            a.lo.Y > b.hi.Y+s.eps || b.lo.Y > a.hi.Y+s.eps ||
+            a.lo.Z > b.hi.Z+s.eps || b.lo.Z > a.hi.Z+s.eps
+    }
+    var out [][2]int
+    for _, m := range moving {
+        for o := range pts {
+            if in[o] || len(pts[o]) == 0 || apart(boxes[m], boxes[o]) || shareVertex(s.poly.Faces[m], s.poly.Faces[o]) {
+                continue
+            }
+            if polygonsPierce(pts[m], pts[o], s.eps) {
+                out = append(out, [2]int{m, o})
+            }
+        }
+    }
+    return out
+}
+
+func shareVertex(a, b Face) bool {
+    for _, u := range a.Vertices {
+        for _, v := range b.Vertices {
+            if u == v {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// polygonsPierce reports whether two planar polygons in 3D cut through each
+// other: an edge of one crosses the plane of the other strictly inside it.
+// Polygons lying in the same plane don't count.
+func polygonsPierce(a, b []Vector3, eps float64) bool {
+    return edgesPierce(a, b, eps) || edgesPierce(b, a, eps)
+}
+
+func edgesPierce(a, b []Vector3, eps float64) bool {
+    for i := 1; i+1 < len(b); i++ {
+        t0, t1, t2 := b[0], b[i], b[i+1]
+        n := cross(sub(t1, t0), sub(t2, t0))
+        ln := length(n)
+        if ln == 0 {
+            continue
+        }
+        n = scale(n, 1/ln)
+        for j := range a {
+            p, q := a[j], a[(j+1)%len(a)]
+            dp, dq := dot(sub(p, t0), n), dot(sub(q, t0), n)
+            if !(dp > eps && dq < -eps) && !(dp < -eps && dq > eps) {
+                continue
+            }
+            x := add(p, scale(sub(q, p), dp/(dp-dq)))
+            if insideTriangle3(x, t0, t1, t2, n, eps) {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+// insideTriangle3 reports whether x (in the triangle's plane, unit normal n)
+// is inside the triangle by more than eps.
+func insideTriangle3(x, a, b, c, n Vector3, eps float64) bool {
+    for _, e := range [][2]Vector3{{a, b}, {b, c}, {c, a}} {
+        d := normalize(cross(n, sub(e[1], e[0]))) // points into the triangle
+        if dot(sub(x, e[0]), d) <= eps {
+            return false
+        }
+    }
+    return true
+}
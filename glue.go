@@ -0,0 +1,102 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Glue surface report
+// -----------------------------
+
+// GlueOptions controls GlueReport.
+type GlueOptions struct {
+    // MinRatio flags tabs whose area is less than MinRatio * length² of their
+    // seam, i.e. whose average depth is under MinRatio of the seam length.
+    // Default 0.05.
+    MinRatio float64
+}
+
+// SeamGlue is the glue on one seam (a cut edge joining two faces).
+type SeamGlue struct {
+    Edge     NetEdge `json:"edge"`
+    Length   float64 `json:"length"`   // seam length
+    Tabbed   bool    `json:"tabbed"`   // false if GenerateTabs had to leave it out
+    TabArea  float64 `json:"tabArea"`  // glue area of its tab
+    TabDepth float64 `json:"tabDepth"` // how far the tab reaches from the seam
+    Weak     bool    `json:"weak"`     // untabbed, or tab too small for the seam (see GlueOptions)
+}
+
+// GlueSummary adds up the glue in a net, in net units.
+type GlueSummary struct {
+    Seams          []SeamGlue `json:"seams"`
+    TabArea        float64    `json:"tabArea"`
+    DoubleWallArea float64    `json:"doubleWallArea"` // fold-over copies are glued flat on their face
+    TotalArea      float64    `json:"totalArea"`      // TabArea + DoubleWallArea
+    GlueLength     float64    `json:"glueLength"`     // length of the seams that have a tab
+    Weak           []int      `json:"weak"`           // indices into Seams
+}
+
+// GlueReport measures the glue surfaces of result: every seam with its tab
+// (from result.Tabs, so call GenerateTabs first) and the double walls. Kit
+// designers can use the totals for adhesive guidance and the Weak list to
+// find seams that won't hold.
+func GlueReport(result *UnfoldResult, opts GlueOptions) GlueSummary {
+    var sum GlueSummary
+    if result == nil {
+        return sum
+    }
+    if opts.MinRatio <= 0 {
+        opts.MinRatio = 0.05
+    }
+    type side struct{ face, edge int }
+    tabs := make(map[side]TabPolygon, len(result.Tabs))
+    for _, t := range result.Tabs {
+        tabs[side{t.Face, t.Edge}] = t
+    }
+    edgeOf := func(face, edge int) (Point2, Point2, bool) {
+        pts := result.Face2D[face].Vertices
+        if len(pts) < 3 || edge < 0 || edge >= len(pts) {
+            return Point2{}, Point2{}, false
+        }
+        return pts[edge], pts[(edge+1)%len(pts)], true
+    }
+
+    sum.Seams = []SeamGlue{}
+    sum.Weak = []int{}
+    for _, e := range result.CutEdges {
+        if e.FaceB < 0 {
+            continue
+        }
+        a, b, okA := edgeOf(e.FaceA, e.EdgeA)
+        _, _, okB := edgeOf(e.FaceB, e.EdgeB)
+        if !okA || !okB {
+            continue
+        }
+        s := SeamGlue{Edge: e, Length: math.Hypot(b.X-a.X, b.Y-a.Y)}
+        t, ok := tabs[side{e.FaceA, e.EdgeA}]
+        if !ok {
+            t, ok = tabs[side{e.FaceB, e.EdgeB}]
+        }
+        if ok && len(t.Vertices) >= 3 {
+            s.Tabbed = true
+            s.TabArea = math.Abs(polygonArea(t.Vertices))
+            p, q := t.Vertices[0], t.Vertices[1]
+            if l := math.Hypot(q.X-p.X, q.Y-p.Y); l > 0 {
+                for _, v := range t.Vertices[2:] {
+                    d := math.Abs((q.X-p.X)*(v.Y-p.Y)-(q.Y-p.Y)*(v.X-p.X)) / l
+                    s.TabDepth = math.Max(s.TabDepth, d)
+                }
+            }
+            sum.TabArea += s.TabArea
+            sum.GlueLength += s.Length
+        }
+        s.Weak = !s.Tabbed || s.TabArea < opts.MinRatio*s.Length*s.Length
+        if s.Weak {
+            sum.Weak = append(sum.Weak, len(sum.Seams))
+        }
+        sum.Seams = append(sum.Seams, s)
+    }
+    for _, dw := range result.DoubleWalls {
+        sum.DoubleWallArea += math.Abs(polygonArea(dw.Vertices))
+    }
+    sum.TotalArea = sum.TabArea + sum.DoubleWallArea
+    return sum
+}
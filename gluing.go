@@ -0,0 +1,240 @@
+package unfolder
+
+import (
+    "encoding/json"
+    "errors"
+    "io"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Edge gluing graph
+// -----------------------------
+
+// GluingGraph says which piece edge mates with which. Every mate has a short
+// code that is printed on both edges (see EdgeCodeOverlay).
+type GluingGraph struct {
+    Pieces []GluePiece `json:"pieces"`
+    Mates  []EdgeMate  `json:"mates"`
+}
+
+// GluePiece is one physical piece: a single face in stencil mode, a
+// connected group of folded faces for a net.
+type GluePiece struct {
+    ID    int    `json:"id"`
+    Name  string `json:"name"` // see PieceNames
+    Faces []int  `json:"faces"`
+}
+
+// EdgeMate is a pair of piece edges that are glued together.
+type EdgeMate struct {
+    Code     string  `json:"code"`
+    Vertices [2]int  `json:"vertices"` // mesh vertices of the edge, smaller first
+    PieceA   int     `json:"pieceA"`
+    FaceA    int     `json:"faceA"`
+    EdgeA    int     `json:"edgeA"` // local edge index in FaceA
+    PieceB   int     `json:"pieceB"`
+    FaceB    int     `json:"faceB"`
+    EdgeB    int     `json:"edgeB"`
+    Dihedral float64 `json:"dihedral"` // interior angle between the faces, degrees
+}
+
+// GluingGraph returns the mates between the stencil's pieces: every edge
+// shared by two selected faces.
+func (s *StencilSheet) GluingGraph(poly Polyhedron) *GluingGraph {
+    pieceOf := make(map[int]int, len(s.Pieces))
+    g := &GluingGraph{}
+    for i, p := range s.Pieces {
+        pieceOf[p.Face] = i
+        g.Pieces = append(g.Pieces, GluePiece{ID: i, Faces: []int{p.Face}})
+    }
+    g.namePieces(poly, nil)
+    var edges []NetEdge
+    for _, e := range classifyEdges(poly, nil) {
+        if e.FaceB < 0 {
+            continue
+        }
+        _, okA := pieceOf[e.FaceA]
+        _, okB := pieceOf[e.FaceB]
+        if okA && okB {
+            edges = append(edges, e)
+        }
+    }
+    g.Mates = buildMates(poly, edges, func(f int) int { return pieceOf[f] })
+    return g
+}
+
+// NetGluingGraph returns the mates of a net: its cut edges that join two
+// faces. Pieces are the groups of faces connected by folds (one for an
+// ordinary net).
+func NetGluingGraph(poly Polyhedron, result *UnfoldResult) (*GluingGraph, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    g := &GluingGraph{}
+    pieceOf, pieces := netPieces(result)
+    for id, faces := range pieces {
+        g.Pieces = append(g.Pieces, GluePiece{ID: id, Faces: faces})
+    }
+    g.namePieces(poly, nil)
+
+    var edges []NetEdge
+    for _, e := range result.CutEdges {
+        if e.FaceB >= 0 && pieceOf[e.FaceA] >= 0 && pieceOf[e.FaceB] >= 0 {
+            edges = append(edges, e)
+        }
+    }
+    g.Mates = buildMates(poly, edges, func(f int) int { return pieceOf[f] })
+    return g, nil
+}
+
+// namePieces (re)names g's pieces with PieceNames.
+func (g *GluingGraph) namePieces(poly Polyhedron, regions FaceRegions) {
+    faces := make([][]int, len(g.Pieces))
+    for i, p := range g.Pieces {
+        faces[i] = p.Faces
+    }
+    for i, n := range PieceNames(poly, faces, regions) {
+        g.Pieces[i].Name = n
+    }
+}
+
+// buildMates turns paired edges into mates, coded in vertex-pair order.
+func buildMates(poly Polyhedron, edges []NetEdge, pieceOf func(int) int) []EdgeMate {
+    sorted := append([]NetEdge(nil), edges...)
+    sortNetEdges(sorted)
+    mates := make([]EdgeMate, len(sorted))
+    for i, e := range sorted {
+        mates[i] = EdgeMate{
+            Code:     edgeCode(i),
+            Vertices: e.Vertices,
+            PieceA:   pieceOf(e.FaceA),
+            FaceA:    e.FaceA,
+            EdgeA:    e.EdgeA,
+            PieceB:   pieceOf(e.FaceB),
+            FaceB:    e.FaceB,
+            EdgeB:    e.EdgeB,
+            Dihedral: DihedralAngle(poly, e) * 180 / math.Pi,
+        }
+    }
+    return mates
+}
+
+// edgeCode returns the i-th code: A..Z, AA..AZ, BA.. (spreadsheet columns).
+func edgeCode(i int) string {
+    var b []byte
+    for i++; i > 0; i = (i - 1) / 26 {
+        b = append([]byte{byte('A' + (i-1)%26)}, b...)
+    }
+    return string(b)
+}
+
+// WriteGluingGraph writes g as indented JSON.
+func WriteGluingGraph(w io.Writer, g *GluingGraph) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(g)
+}
+
+// EdgeCodeOverlay prints every mate's code just inside both of its edges,
+// using the face positions of result (a net, or StencilSheet.Result). size
+// is the text height; 0 picks a sixth of the median edge length.
+func EdgeCodeOverlay(result *UnfoldResult, g *GluingGraph, size float64) *Overlay {
+    o := &Overlay{Name: "edge-codes"}
+    if result == nil || g == nil {
+        return o
+    }
+    if size <= 0 {
+        size = edgeTextSize(result)
+    }
+    for _, m := range g.Mates {
+        for _, side := range [2]EdgeInstance{{m.FaceA, m.EdgeA}, {m.FaceB, m.EdgeB}} {
+            if t, ok := edgeText(result, side, m.Code, size); ok {
+                o.Texts = append(o.Texts, t)
+            }
+        }
+    }
+    return o
+}
+
+// edgeTextSize is the default text height for edge codes and labels: a sixth
+// of the median edge length.
+func edgeTextSize(result *UnfoldResult) float64 {
+    var lengths []float64
+    for _, f := range result.Face2D {
+        for i, a := range f.Vertices {
+            b := f.Vertices[(i+1)%len(f.Vertices)]
+            lengths = append(lengths, math.Hypot(b.X-a.X, b.Y-a.Y))
+        }
+    }
+    if len(lengths) == 0 {
+        return 0
+    }
+    sort.Float64s(lengths)
+    return lengths[len(lengths)/2] / 6
+}
+
+// edgeText places text just inside edge e of its face, centred on the edge.
+func edgeText(result *UnfoldResult, e EdgeInstance, text string, size float64) (OverlayText, bool) {
+    if e.Face < 0 || e.Face >= len(result.Face2D) {
+        return OverlayText{}, false
+    }
+    pts := result.Face2D[e.Face].Vertices
+    if len(pts) < 3 || e.Edge < 0 || e.Edge >= len(pts) {
+        return OverlayText{}, false
+    }
+    a, b := pts[e.Edge], pts[(e.Edge+1)%len(pts)]
+    n := leftNormal(a, b)
+    if polygonArea(pts) < 0 {
+        n = Point2{X: -n.X, Y: -n.Y}
+    }
+    // centre of the text box sits 1.2 text heights inside the edge
+    c := Point2{X: (a.X+b.X)/2 + n.X*1.2*size, Y: (a.Y+b.Y)/2 + n.Y*1.2*size}
+    w := 0.6 * size * float64(len(text))
+    return OverlayText{At: Point2{X: c.X - w/2, Y: c.Y - size/2}, Text: text, Size: size}, true
+}
+
+// netPieces groups the placed faces of result into pieces, the faces
+// connected by folds, numbered in order of their lowest face. Unplaced faces
+// are in piece -1.
+func netPieces(result *UnfoldResult) ([]int, [][]int) {
+    // union the faces along the folds
+    parent := make([]int, len(result.Face2D))
+    for i := range parent {
+        parent[i] = i
+    }
+    var find func(int) int
+    find = func(x int) int {
+        if parent[x] != x {
+            parent[x] = find(parent[x])
+        }
+        return parent[x]
+    }
+    for _, e := range result.FoldEdges {
+        a, b := find(e.FaceA), find(e.FaceB)
+        if a != b {
+            parent[b] = a
+        }
+    }
+
+    pieceOf := make([]int, len(parent))
+    var pieces [][]int
+    ids := make(map[int]int)
+    for f := range parent {
+        if len(result.Face2D[f].Vertices) == 0 {
+            pieceOf[f] = -1
+            continue
+        }
+        root := find(f)
+        id, ok := ids[root]
+        if !ok {
+            id = len(pieces)
+            ids[root] = id
+            pieces = append(pieces, nil)
+        }
+        pieceOf[f] = id
+        pieces[id] = append(pieces[id], f)
+    }
+    return pieceOf, pieces
+}
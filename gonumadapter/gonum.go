@@ -0,0 +1,164 @@
+// Package gonumadapter converts unfolder meshes and nets to and from gonum
+// types. It lives in its own package so the core unfolder package does not
+// pull in gonum for users who don't need it.
+package gonumadapter
+
+import (
+    "errors"
+    "fmt"
+
+    "gonum.org/v1/gonum/graph/simple"
+    "gonum.org/v1/gonum/mat"
+    "gonum.org/v1/gonum/spatial/r3"
+
+    "github.com/yourusername/unfolder"
+)
+
+// -----------------------------
+//   Matrices
+// -----------------------------
+
+// VertexMatrix returns the polyhedron vertices as an n x 3 matrix,
+// one row per vertex (X, Y, Z).
+func VertexMatrix(poly unfolder.Polyhedron) *mat.Dense {
+    if len(poly.Vertices) == 0 {
+        return nil
+    }
+    m := mat.NewDense(len(poly.Vertices), 3, nil)
+    for i, v := range poly.Vertices {
+        m.SetRow(i, []float64{v.X, v.Y, v.Z})
+    }
+    return m
+}
+
+// NetMatrix returns the flattened vertex positions of an unfold result as
+// an n x 2 matrix, one row (X, Y) per vertex instance, in the order of
+// result.VertexInstances. Vertices on cuts have one row per copy.
+func NetMatrix(result *unfolder.UnfoldResult) *mat.Dense {
+    if result == nil || len(result.VertexInstances) == 0 {
+        return nil
+    }
+    m := mat.NewDense(len(result.VertexInstances), 2, nil)
+    for i, in := range result.VertexInstances {
+        m.SetRow(i, []float64{in.Pos.X, in.Pos.Y})
+    }
+    return m
+}
+
+// FaceMatrix returns the 2D coordinates of a single placed face as a k x 2
+// matrix, in the face's own vertex order.
+func FaceMatrix(result *unfolder.UnfoldResult, faceIdx int) (*mat.Dense, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    if faceIdx < 0 || faceIdx >= len(result.Face2D) {
+        return nil, fmt.Errorf("face index %d out of range", faceIdx)
+    }
+    verts := result.Face2D[faceIdx].Vertices
+    if len(verts) == 0 {
+        return nil, fmt.Errorf("face %d was not placed", faceIdx)
+    }
+    m := mat.NewDense(len(verts), 2, nil)
+    for i, p := range verts {
+        m.SetRow(i, []float64{p.X, p.Y})
+    }
+    return m, nil
+}
+
+// -----------------------------
+//   Graphs
+// -----------------------------
+
+// FaceGraph returns the dual graph of the polyhedron: one node per face
+// (node ID == face index) and an edge wherever two faces share an edge.
+func FaceGraph(poly unfolder.Polyhedron) (*simple.UndirectedGraph, error) {
+    adj, err := unfolder.BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+
+    g := simple.NewUndirectedGraph()
+    for fIdx := range poly.Faces {
+        g.AddNode(simple.Node(fIdx))
+    }
+    for fIdx, nbrs := range adj.Neighbors {
+        for _, nbr := range nbrs {
+            // each shared edge shows up once per side; add it once
+            if nbr.FaceIndex <= fIdx {
+                continue
+            }
+            g.SetEdge(simple.Edge{F: simple.Node(fIdx), T: simple.Node(nbr.FaceIndex)})
+        }
+    }
+    return g, nil
+}
+
+// SpanningTreeGraph returns the unfold spanning tree as a directed graph with
+// edges pointing from parent face to child face.
+func SpanningTreeGraph(result *unfolder.UnfoldResult) *simple.DirectedGraph {
+    g := simple.NewDirectedGraph()
+    if result == nil {
+        return g
+    }
+    for fIdx := range result.SpanningTree {
+        g.AddNode(simple.Node(fIdx))
+    }
+    for fIdx, p := range result.SpanningTree {
+        if p >= 0 {
+            g.SetEdge(simple.Edge{F: simple.Node(p), T: simple.Node(fIdx)})
+        }
+    }
+    return g
+}
+
+// -----------------------------
+//   Spatial types
+// -----------------------------
+
+// VerticesR3 returns the polyhedron vertices as gonum r3 vectors.
+func VerticesR3(poly unfolder.Polyhedron) []r3.Vec {
+    out := make([]r3.Vec, len(poly.Vertices))
+    for i, v := range poly.Vertices {
+        out[i] = r3.Vec{X: v.X, Y: v.Y, Z: v.Z}
+    }
+    return out
+}
+
+// PolyhedronFromR3 builds a Polyhedron from gonum r3 vectors and face index
+// loops (CCW order), validating that every index refers to a vertex.
+func PolyhedronFromR3(name string, verts []r3.Vec, faces [][]int) (unfolder.Polyhedron, error) {
+    poly := unfolder.Polyhedron{
+        Vertices: make([]unfolder.Vector3, len(verts)),
+        Faces:    make([]unfolder.Face, len(faces)),
+        Name:     name,
+    }
+    for i, v := range verts {
+        poly.Vertices[i] = unfolder.Vector3{X: v.X, Y: v.Y, Z: v.Z}
+    }
+    for fIdx, loop := range faces {
+        if len(loop) < 3 {
+            return unfolder.Polyhedron{}, fmt.Errorf("face %d has fewer than 3 vertices", fIdx)
+        }
+        for _, vi := range loop {
+            if vi < 0 || vi >= len(verts) {
+                return unfolder.Polyhedron{}, fmt.Errorf("face %d references vertex %d out of range", fIdx, vi)
+            }
+        }
+        poly.Faces[fIdx] = unfolder.Face{Vertices: append([]int(nil), loop...)}
+    }
+    return poly, nil
+}
+
+// PolyhedronFromMatrix builds a Polyhedron from an n x 3 vertex matrix and
+// face index loops.
+func PolyhedronFromMatrix(name string, verts mat.Matrix, faces [][]int) (unfolder.Polyhedron, error) {
+    r, c := verts.Dims()
+    if c != 3 {
+        return unfolder.Polyhedron{}, fmt.Errorf("vertex matrix must have 3 columns, got %d", c)
+    }
+    vecs := make([]r3.Vec, r)
+    for i := 0; i < r; i++ {
+        vecs[i] = r3.Vec{X: verts.At(i, 0), Y: verts.At(i, 1), Z: verts.At(i, 2)}
+    }
+    return PolyhedronFromR3(name, vecs, faces)
+}
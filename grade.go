@@ -0,0 +1,70 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "strconv"
+)
+
+// -----------------------------
+//   Pattern grading (multi-size output)
+// -----------------------------
+
+// GradeSize is one size of a graded family: a name for the label and the
+// scale factor relative to the base net.
+type GradeSize struct {
+    Name  string
+    Scale float64
+}
+
+// GradedNet is the base net at one size, with a label overlay naming it.
+type GradedNet struct {
+    Size   GradeSize
+    Result *UnfoldResult
+    Label  *Overlay
+}
+
+// ScaleModelSizes returns sizes for the usual model scales: for a mesh
+// modelled at real size, ScaleModelSizes(100, 87, 72) gives "1:100", "1:87"
+// and "1:72".
+func ScaleModelSizes(denominators ...float64) []GradeSize {
+    sizes := make([]GradeSize, len(denominators))
+    for i, d := range denominators {
+        sizes[i] = GradeSize{Name: "1:" + strconv.FormatFloat(d, 'f', -1, 64), Scale: 1 / d}
+    }
+    return sizes
+}
+
+// GradeNet produces the base net at every requested size. Scaling a mesh
+// scales its net by the same factor and keeps the spanning tree, so this only
+// scales coordinates instead of unfolding again.
+func GradeNet(result *UnfoldResult, sizes []GradeSize) ([]GradedNet, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    out := make([]GradedNet, len(sizes))
+    for i, size := range sizes {
+        if size.Scale <= 0 {
+            return nil, fmt.Errorf("size %q has non-positive scale %g", size.Name, size.Scale)
+        }
+        scaled := Scale(result, size.Scale)
+        out[i] = GradedNet{Size: size, Result: scaled, Label: sizeLabel(scaled, size.Name)}
+    }
+    return out, nil
+}
+
+// sizeLabel puts the size name just below the bottom left of the net, at a
+// twentieth of the net's height.
+func sizeLabel(result *UnfoldResult, name string) *Overlay {
+    o := &Overlay{Name: "size-label"}
+    lo, hi, ok := sheetBounds(result, nil)
+    if !ok {
+        return o
+    }
+    h := (hi.Y - lo.Y) / 20
+    if w := (hi.X - lo.X) / 20; w > h {
+        h = w
+    }
+    o.Texts = append(o.Texts, OverlayText{At: Point2{X: lo.X, Y: lo.Y - 1.5*h}, Text: name, Size: h})
+    return o
+}
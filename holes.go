@@ -0,0 +1,101 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Faces with holes
+// -----------------------------
+
+// Face.Holes are cutouts, like the bore in the flat face of a washer: loops of
+// vertices inside the outline, in its plane, wound the other way round. They
+// come along wherever the face goes, but are never hinged on: adjacency only
+// walks the outline, so a hole's edges are always cut and whatever lines the
+// hole (the washer's bore) hangs off its other neighbours, or is a piece of
+// its own (see UnfoldComponents). Overlap detection treats faces as solid, so
+// a piece laid out inside a hole counts as overlapping.
+
+// attachHoles lays out the holes of every placed face of poly: each face's
+// outline and holes are flattened together, then moved onto where the face
+// was placed. Like attachUVs it runs once the placement is final.
+func attachHoles(poly Polyhedron, face2D []Face2D) {
+    for f := range face2D {
+        if f >= len(poly.Faces) || len(poly.Faces[f].Holes) == 0 {
+            continue
+        }
+        face := poly.Faces[f]
+        placed := face2D[f].Vertices
+        if len(placed) < 3 || len(placed) != len(face.Vertices) || !faceInRange(poly, face) {
+            continue
+        }
+        face2D[f].Holes = placeHoles(poly, face, placed)
+    }
+}
+
+// placeHoles maps the holes of face to the net, given where its outline went.
+// The fit is a rotation and translation (and a flip if the outline was placed
+// mirrored) taken from the outline's longest edge.
+func placeHoles(poly Polyhedron, face Face, placed []Point2) [][]Point2 {
+    n := normalize(faceNormal(poly, face))
+    ref := Vector3{1, 0, 0}
+    if math.Abs(n.X) > 0.9 {
+        ref = Vector3{0, 1, 0}
+    }
+    u := normalize(cross(ref, n))
+    w := cross(n, u)
+    outline := make([]Point2, len(face.Vertices))
+    for i, v := range face.Vertices {
+        outline[i] = Point2{dot(poly.Vertices[v], u), dot(poly.Vertices[v], w)}
+    }
+    flip := 1.0
+    if polygonArea(outline)*polygonArea(placed) < 0 {
+        flip = -1
+        for i := range outline {
+            outline[i].Y = -outline[i].Y
+        }
+    }
+
+    k, best := 0, -1.0
+    for i := range outline {
+        j := (i + 1) % len(outline)
+        if d := math.Hypot(outline[j].X-outline[i].X, outline[j].Y-outline[i].Y); d > best {
+            k, best = i, d
+        }
+    }
+    j := (k + 1) % len(outline)
+    turn := math.Atan2(placed[j].Y-placed[k].Y, placed[j].X-placed[k].X) -
+        math.Atan2(outline[j].Y-outline[k].Y, outline[j].X-outline[k].X)
+    cosT, sinT := math.Cos(turn), math.Sin(turn)
+
+    holes := make([][]Point2, len(face.Holes))
+    for h, loop := range face.Holes {
+        holes[h] = make([]Point2, len(loop))
+        for i, v := range loop {
+            p := poly.Vertices[v]
+            dx, dy := dot(p, u)-outline[k].X, flip*dot(p, w)-outline[k].Y
+            holes[h][i] = Point2{
+                X: placed[k].X + dx*cosT - dy*sinT,
+                Y: placed[k].Y + dx*sinT + dy*cosT,
+            }
+        }
+    }
+    return holes
+}
+
+// copyHoles returns a deep copy of holes with every vertex index passed
+// through remap; nil stays nil.
+func copyHoles(holes [][]int, remap func(v int) int) [][]int {
+    if holes == nil {
+        return nil
+    }
+    out := make([][]int, len(holes))
+    for h, loop := range holes {
+        out[h] = make([]int, len(loop))
+        for i, v := range loop {
+            out[h][i] = remap(v)
+        }
+    }
+    return out
+}
+
+// sameVertex is the identity remap for copyHoles.
+func sameVertex(v int) int { return v }
@@ -0,0 +1,87 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Model summary
+// -----------------------------
+
+// ModelInfo sums up a mesh before unfolding it, see Inspect. Lengths are in
+// the mesh's units.
+type ModelInfo struct {
+    Name  string `json:"name,omitempty"`
+    Units string `json:"units,omitempty"` // as loaded; "" if the file doesn't say
+    // UnitGuess is what InferUnits makes of the units and the size.
+    UnitGuess UnitGuess `json:"unitGuess"`
+
+    Vertices int `json:"vertices"`
+    Faces    int `json:"faces"`
+    Edges    int `json:"edges"`
+    Euler    int `json:"euler"`
+    // Shells are the kinds of the connected pieces (see ShellTopology.Kind),
+    // largest first.
+    Shells           []string `json:"shells"`
+    Manifold         bool     `json:"manifold"`
+    Orientable       bool     `json:"orientable"`
+    BoundaryEdges    int      `json:"boundaryEdges"`
+    NonManifoldEdges int      `json:"nonManifoldEdges"`
+    DegenerateFaces  int      `json:"degenerateFaces"`
+
+    Min  Vector3 `json:"min"`
+    Max  Vector3 `json:"max"`
+    Size Vector3 `json:"size"` // Max - Min
+
+    // NonPlanarFaces counts faces with a corner further from the face's
+    // plane than 0.1% of the model size, enough to distort the net; MaxWarp
+    // is the furthest any corner is.
+    NonPlanarFaces int     `json:"nonPlanarFaces"`
+    MaxWarp        float64 `json:"maxWarp"`
+
+    // SurfaceArea is the area of all faces: the paper the net takes at
+    // 1:1, before tabs and the gaps between pieces.
+    SurfaceArea float64 `json:"surfaceArea"`
+}
+
+// Inspect gathers the numbers worth checking before unfolding poly: counts
+// and topology, bounding box, how flat the faces are and how much paper
+// the net needs. Only meshes Topology rejects (faces with fewer than three
+// or out of range vertices) are errors.
+func Inspect(poly Polyhedron) (mi *ModelInfo, err error) {
+    defer (&progress{stage: "inspection", face: -1}).recover(&err)
+    topo, err := Topology(poly)
+    if err != nil {
+        return nil, err
+    }
+    info := &ModelInfo{
+        Name:       poly.Name,
+        Units:      poly.Units,
+        UnitGuess:  InferUnits(poly),
+        Vertices:   len(poly.Vertices),
+        Faces:      len(poly.Faces),
+        Edges:      topo.Edges,
+        Euler:      topo.Euler,
+        Manifold:   topo.Manifold,
+        Orientable: topo.Orientable,
+    }
+    for _, s := range topo.Shells {
+        info.Shells = append(info.Shells, s.Kind())
+    }
+    if r, _ := ValidateMesh(poly); r != nil {
+        info.BoundaryEdges = len(r.BoundaryEdges)
+        info.NonManifoldEdges = len(r.NonManifoldEdges)
+        info.DegenerateFaces = len(r.DegenerateFaces)
+    }
+
+    info.Min, info.Max = boundingBox(poly.Vertices)
+    info.Size = sub(info.Max, info.Min)
+    tol := 1e-3 * length(info.Size)
+    for _, face := range poly.Faces {
+        w := faceWarp(poly, face)
+        info.MaxWarp = math.Max(info.MaxWarp, w)
+        if w > tol {
+            info.NonPlanarFaces++
+        }
+        info.SurfaceArea += length(faceNormal(poly, face)) / 2
+    }
+    return info, nil
+}
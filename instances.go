@@ -0,0 +1,83 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Vertex instances
+// -----------------------------
+
+// VertexInstance is one 2D copy of a mesh vertex: the corner Local of face
+// Face. A vertex on a cut has one instance per side of the cut, at different
+// places in the net; a vertex inside a folded region has instances that
+// coincide.
+type VertexInstance struct {
+    Face   int    `json:"face"`
+    Local  int    `json:"local"`  // corner index within the face
+    Vertex int    `json:"vertex"` // mesh vertex index, -1 if unknown (migrated v1 net files without a mesh)
+    Pos    Point2 `json:"pos"`
+}
+
+// vertexInstances lists the corners of every placed face, face by face.
+func vertexInstances(poly Polyhedron, face2D []Face2D) []VertexInstance {
+    var out []VertexInstance
+    for f, f2 := range face2D {
+        for i, p := range f2.Vertices {
+            v := -1
+            if f < len(poly.Faces) && i < len(poly.Faces[f].Vertices) {
+                v = poly.Faces[f].Vertices[i]
+            }
+            out = append(out, VertexInstance{Face: f, Local: i, Vertex: v, Pos: p})
+        }
+    }
+    return out
+}
+
+// Instances returns every 2D copy of mesh vertex v, in face order.
+func (r *UnfoldResult) Instances(v int) []VertexInstance {
+    var out []VertexInstance
+    for _, in := range r.VertexInstances {
+        if in.Vertex == v {
+            out = append(out, in)
+        }
+    }
+    return out
+}
+
+// CornerPosition returns where corner local of face f sits in the net; ok is
+// false if the face was not placed.
+func (r *UnfoldResult) CornerPosition(f, local int) (p Point2, ok bool) {
+    if f < 0 || f >= len(r.Face2D) || local < 0 || local >= len(r.Face2D[f].Vertices) {
+        return Point2{}, false
+    }
+    return r.Face2D[f].Vertices[local], true
+}
+
+// SplitVertices returns the mesh vertices that appear at more than one place
+// in the net (further apart than eps), i.e. the vertices cuts run through,
+// in increasing order.
+func (r *UnfoldResult) SplitVertices(eps float64) []int {
+    first := make(map[int]Point2)
+    split := make(map[int]bool)
+    maxV := -1
+    for _, in := range r.VertexInstances {
+        if in.Vertex < 0 {
+            continue
+        }
+        p, seen := first[in.Vertex]
+        if !seen {
+            first[in.Vertex] = in.Pos
+        } else if math.Hypot(p.X-in.Pos.X, p.Y-in.Pos.Y) > eps {
+            split[in.Vertex] = true
+        }
+        if in.Vertex > maxV {
+            maxV = in.Vertex
+        }
+    }
+    var out []int
+    for v := 0; v <= maxV; v++ {
+        if split[v] {
+            out = append(out, v)
+        }
+    }
+    return out
+}
@@ -0,0 +1,334 @@
+package unfolder
+
+import (
+    "encoding/json"
+    "fmt"
+)
+
+// -----------------------------
+//   JSON encoding
+// -----------------------------
+
+// Schema names and version of the JSON encodings of Polyhedron and
+// UnfoldResult. Every document carries both, so readers in other languages
+// can check what they've got. Bump JSONSchemaVersion on incompatible
+// changes; decoding refuses newer versions.
+const (
+    MeshSchema        = "go-unfold/mesh"
+    ResultSchema      = "go-unfold/result"
+    JSONSchemaVersion = 1
+)
+
+// polyhedronJSON is the wire form of a Polyhedron: points as [x, y, z]
+// arrays, faces as index arrays. Holes and UVs, if any face has them, have
+// the hole loops and texture coordinates of every face.
+type polyhedronJSON struct {
+    Schema   string         `json:"schema"`
+    Version  int            `json:"version"`
+    Name     string         `json:"name,omitempty"`
+    Units    string         `json:"units,omitempty"`
+    Vertices [][3]float64   `json:"vertices"`
+    Faces    [][]int        `json:"faces"`
+    Holes    [][][]int      `json:"holes,omitempty"`
+    UVs      [][][2]float64 `json:"uvs,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler:
+//
+//	{"schema": "go-unfold/mesh", "version": 1, "name": "cube", "units": "mm",
+//	 "vertices": [[0, 0, 0], ...], "faces": [[0, 3, 2, 1], ...]}
+func (p Polyhedron) MarshalJSON() ([]byte, error) {
+    doc := polyhedronJSON{
+        Schema:   MeshSchema,
+        Version:  JSONSchemaVersion,
+        Name:     p.Name,
+        Units:    p.Units,
+        Vertices: make([][3]float64, len(p.Vertices)),
+        Faces:    make([][]int, len(p.Faces)),
+    }
+    for i, v := range p.Vertices {
+        doc.Vertices[i] = [3]float64{v.X, v.Y, v.Z}
+    }
+    for i, f := range p.Faces {
+        doc.Faces[i] = f.Vertices
+        if doc.Faces[i] == nil {
+            doc.Faces[i] = []int{}
+        }
+        if len(f.Holes) > 0 && doc.Holes == nil {
+            doc.Holes = make([][][]int, len(p.Faces))
+        }
+        if len(f.UVs) > 0 && doc.UVs == nil {
+            doc.UVs = make([][][2]float64, len(p.Faces))
+        }
+    }
+    if doc.Holes != nil {
+        for i, f := range p.Faces {
+            doc.Holes[i] = f.Holes
+            if doc.Holes[i] == nil {
+                doc.Holes[i] = [][]int{}
+            }
+        }
+    }
+    if doc.UVs != nil {
+        for i, f := range p.Faces {
+            doc.UVs[i] = pointsToWire(f.UVs)
+        }
+    }
+    return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Documents without a schema are
+// read as the field-by-field encoding Polyhedron had before MarshalJSON.
+func (p *Polyhedron) UnmarshalJSON(data []byte) error {
+    // look at the schema first: the old field names fold onto the new ones
+    var probe struct {
+        Schema string `json:"schema"`
+    }
+    if err := json.Unmarshal(data, &probe); err != nil {
+        return err
+    }
+    if probe.Schema == "" {
+        var legacy struct {
+            Vertices []Vector3
+            Faces    []Face
+            Name     string
+            Units    string
+        }
+        if err := json.Unmarshal(data, &legacy); err != nil {
+            return err
+        }
+        *p = Polyhedron{Vertices: legacy.Vertices, Faces: legacy.Faces, Name: legacy.Name, Units: legacy.Units}
+        return nil
+    }
+    var doc polyhedronJSON
+    if err := json.Unmarshal(data, &doc); err != nil {
+        return err
+    }
+    if err := checkSchema(doc.Schema, doc.Version, MeshSchema); err != nil {
+        return err
+    }
+    out := Polyhedron{
+        Name:     doc.Name,
+        Units:    doc.Units,
+        Vertices: make([]Vector3, len(doc.Vertices)),
+        Faces:    make([]Face, len(doc.Faces)),
+    }
+    for i, v := range doc.Vertices {
+        out.Vertices[i] = Vector3{v[0], v[1], v[2]}
+    }
+    if doc.Holes != nil && len(doc.Holes) != len(doc.Faces) {
+        return fmt.Errorf("%d hole lists for %d faces", len(doc.Holes), len(doc.Faces))
+    }
+    if doc.UVs != nil && len(doc.UVs) != len(doc.Faces) {
+        return fmt.Errorf("%d texture coordinate lists for %d faces", len(doc.UVs), len(doc.Faces))
+    }
+    for i, f := range doc.Faces {
+        out.Faces[i] = Face{Vertices: f}
+        if doc.Holes != nil && len(doc.Holes[i]) > 0 {
+            out.Faces[i].Holes = doc.Holes[i]
+        }
+        if doc.UVs != nil && len(doc.UVs[i]) > 0 {
+            out.Faces[i].UVs = pointsFromWire(doc.UVs[i])
+        }
+    }
+    *p = out
+    return nil
+}
+
+// resultJSON is the wire form of an UnfoldResult. Memory is left out: it
+// describes one run on one machine, not the net.
+type resultJSON struct {
+    Schema          string            `json:"schema"`
+    Version         int               `json:"version"`
+    Face2D          [][][2]float64    `json:"face2D"`
+    Holes           [][][][2]float64  `json:"holes,omitempty"`
+    UVs             [][][2]float64    `json:"uvs,omitempty"`
+    VertexInstances []VertexInstance  `json:"vertexInstances,omitempty"`
+    SpanningTree    []int             `json:"spanningTree"`
+    FoldEdges       []NetEdge         `json:"foldEdges"`
+    CutEdges        []NetEdge         `json:"cutEdges"`
+    Overlaps        []OverlapPair     `json:"overlaps,omitempty"`
+    DoubleWalls     []DoubleWall      `json:"doubleWalls,omitempty"`
+    Tabs            []TabPolygon      `json:"tabs,omitempty"`
+    Validation      *ValidationReport `json:"validation,omitempty"`
+    Side            NetSide           `json:"side,omitempty"` // as in UnfoldOptions, 1 for interior
+    Mesh            *Polyhedron       `json:"mesh,omitempty"` // a mesh document, for nets of split meshes
+    FaceOrigin      []int             `json:"faceOrigin,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Face corners are [x, y] arrays, in
+// the order of the mesh face's vertices; unplaced faces are empty arrays.
+// Holes and UVs, if any face has them, have every face's placed hole loops
+// and texture coordinates.
+func (r UnfoldResult) MarshalJSON() ([]byte, error) {
+    doc := resultJSON{
+        Schema:          ResultSchema,
+        Version:         JSONSchemaVersion,
+        Face2D:          make([][][2]float64, len(r.Face2D)),
+        VertexInstances: r.VertexInstances,
+        SpanningTree:    r.SpanningTree,
+        FoldEdges:       r.FoldEdges,
+        CutEdges:        r.CutEdges,
+        Overlaps:        r.Overlaps,
+        DoubleWalls:     r.DoubleWalls,
+        Tabs:            r.Tabs,
+        Validation:      r.Validation,
+        Side:            r.Side,
+        Mesh:            r.Mesh,
+        FaceOrigin:      r.FaceOrigin,
+    }
+    for i, f := range r.Face2D {
+        doc.Face2D[i] = make([][2]float64, len(f.Vertices))
+        for j, p := range f.Vertices {
+            doc.Face2D[i][j] = [2]float64{p.X, p.Y}
+        }
+        if len(f.Holes) > 0 && doc.Holes == nil {
+            doc.Holes = make([][][][2]float64, len(r.Face2D))
+        }
+        if len(f.UVs) > 0 && doc.UVs == nil {
+            doc.UVs = make([][][2]float64, len(r.Face2D))
+        }
+    }
+    if doc.Holes != nil {
+        for i, f := range r.Face2D {
+            doc.Holes[i] = holesToWire(f.Holes)
+        }
+    }
+    if doc.UVs != nil {
+        for i, f := range r.Face2D {
+            doc.UVs[i] = pointsToWire(f.UVs)
+        }
+    }
+    // empty lists rather than null, easier on other languages
+    if doc.SpanningTree == nil {
+        doc.SpanningTree = []int{}
+    }
+    if doc.FoldEdges == nil {
+        doc.FoldEdges = []NetEdge{}
+    }
+    if doc.CutEdges == nil {
+        doc.CutEdges = []NetEdge{}
+    }
+    return json.Marshal(doc)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Documents without a schema are
+// read as the field-by-field encoding UnfoldResult had before MarshalJSON
+// (its Vertex2D is dropped, vertex instances can't be recovered from it).
+func (r *UnfoldResult) UnmarshalJSON(data []byte) error {
+    // look at the schema first: the old field names fold onto the new ones
+    var probe struct {
+        Schema string `json:"schema"`
+    }
+    if err := json.Unmarshal(data, &probe); err != nil {
+        return err
+    }
+    if probe.Schema == "" {
+        var legacy struct {
+            Face2D       []Face2D
+            SpanningTree []int
+            FoldEdges    []NetEdge
+            CutEdges     []NetEdge
+            Overlaps     []OverlapPair
+            DoubleWalls  []DoubleWall
+        }
+        if err := json.Unmarshal(data, &legacy); err != nil {
+            return err
+        }
+        *r = UnfoldResult{
+            Face2D:       legacy.Face2D,
+            SpanningTree: legacy.SpanningTree,
+            FoldEdges:    legacy.FoldEdges,
+            CutEdges:     legacy.CutEdges,
+            Overlaps:     legacy.Overlaps,
+            DoubleWalls:  legacy.DoubleWalls,
+        }
+        return nil
+    }
+    var doc resultJSON
+    if err := json.Unmarshal(data, &doc); err != nil {
+        return err
+    }
+    if err := checkSchema(doc.Schema, doc.Version, ResultSchema); err != nil {
+        return err
+    }
+    out := UnfoldResult{
+        Face2D:          make([]Face2D, len(doc.Face2D)),
+        VertexInstances: doc.VertexInstances,
+        SpanningTree:    doc.SpanningTree,
+        FoldEdges:       doc.FoldEdges,
+        CutEdges:        doc.CutEdges,
+        Overlaps:        doc.Overlaps,
+        DoubleWalls:     doc.DoubleWalls,
+        Tabs:            doc.Tabs,
+        Validation:      doc.Validation,
+        Side:            doc.Side,
+        Mesh:            doc.Mesh,
+        FaceOrigin:      doc.FaceOrigin,
+    }
+    for i, f := range doc.Face2D {
+        if len(f) == 0 {
+            continue
+        }
+        pts := make([]Point2, len(f))
+        for j, p := range f {
+            pts[j] = Point2{p[0], p[1]}
+        }
+        out.Face2D[i] = Face2D{Vertices: pts}
+        if i < len(doc.Holes) && len(doc.Holes[i]) > 0 {
+            out.Face2D[i].Holes = holesFromWire(doc.Holes[i])
+        }
+        if i < len(doc.UVs) && len(doc.UVs[i]) > 0 {
+            out.Face2D[i].UVs = pointsFromWire(doc.UVs[i])
+        }
+    }
+    *r = out
+    return nil
+}
+
+// pointsToWire turns points into [x, y] arrays, as resultJSON and NetFile
+// store them; nil gives an empty list.
+func pointsToWire(pts []Point2) [][2]float64 {
+    out := make([][2]float64, len(pts))
+    for i, p := range pts {
+        out[i] = [2]float64{p.X, p.Y}
+    }
+    return out
+}
+
+// pointsFromWire is the reverse of pointsToWire.
+func pointsFromWire(pts [][2]float64) []Point2 {
+    out := make([]Point2, len(pts))
+    for i, p := range pts {
+        out[i] = Point2{p[0], p[1]}
+    }
+    return out
+}
+
+// holesToWire is pointsToWire for every hole loop of a face.
+func holesToWire(holes [][]Point2) [][][2]float64 {
+    out := make([][][2]float64, len(holes))
+    for h, loop := range holes {
+        out[h] = pointsToWire(loop)
+    }
+    return out
+}
+
+// holesFromWire is the reverse of holesToWire.
+func holesFromWire(holes [][][2]float64) [][]Point2 {
+    out := make([][]Point2, len(holes))
+    for h, loop := range holes {
+        out[h] = pointsFromWire(loop)
+    }
+    return out
+}
+
+func checkSchema(schema string, version int, want string) error {
+    if schema != want {
+        return fmt.Errorf("expected a %s document, got %q", want, schema)
+    }
+    if version < 1 || version > JSONSchemaVersion {
+        return fmt.Errorf("%s version %d is not supported (max %d)", want, version, JSONSchemaVersion)
+    }
+    return nil
+}
@@ -0,0 +1,149 @@
+package unfolder
+
+import (
+    "bytes"
+    "encoding/json"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestPolyhedronJSONRoundTrip(t *testing.T) {
+    named := testCube()
+    named.Name, named.Units = "cube", "mm"
+    holed := testCube()
+    holed.Vertices = append(holed.Vertices, Vector3{0.25, 0.25, 1}, Vector3{0.75, 0.25, 1}, Vector3{0.75, 0.75, 1}, Vector3{0.25, 0.75, 1})
+    holed.Faces[1].Holes = [][]int{{8, 11, 10, 9}}
+    textured := testCube()
+    textured.Faces[0].UVs = []Point2{{0, 0}, {0, 1}, {1, 1}, {1, 0}}
+
+    tests := []struct {
+        name string
+        poly Polyhedron
+    }{
+        {"plain", testCube()},
+        {"name and units", named},
+        {"holes", holed},
+        {"texture coordinates", textured},
+        {"empty", Polyhedron{Vertices: []Vector3{}, Faces: []Face{}}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            data, err := json.Marshal(tt.poly)
+            if err != nil {
+                t.Fatal(err)
+            }
+            var got Polyhedron
+            if err := json.Unmarshal(data, &got); err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got, tt.poly) {
+                t.Errorf("got %+v\nwant %+v\nfrom %s", got, tt.poly, data)
+            }
+        })
+    }
+}
+
+func TestUnfoldResultJSONRoundTrip(t *testing.T) {
+    holed := testCube()
+    holed.Vertices = append(holed.Vertices, Vector3{0.25, 0.25, 1}, Vector3{0.75, 0.25, 1}, Vector3{0.75, 0.75, 1}, Vector3{0.25, 0.75, 1})
+    holed.Faces[1].Holes = [][]int{{8, 11, 10, 9}}
+    textured := testCube()
+    textured.Faces[2].UVs = []Point2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+    warped := testCube()
+    warped.Vertices[6].Z = 1.1
+
+    tests := []struct {
+        name string
+        poly Polyhedron
+        opts UnfoldOptions
+    }{
+        {"plain", testCube(), UnfoldOptions{}},
+        {"interior side", testCube(), UnfoldOptions{Side: SideInterior}},
+        {"overlaps and double walls", testCube(), UnfoldOptions{DetectOverlaps: true, DoubleWalled: true}},
+        {"holes", holed, UnfoldOptions{}},
+        {"texture coordinates", textured, UnfoldOptions{}},
+        {"split mesh", warped, UnfoldOptions{PlanarityTolerance: 0.01, NonPlanar: NonPlanarTriangulate}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            res, err := UnfoldMeshWithOptions(tt.poly, 0, tt.opts)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if (res.Mesh != nil) != (tt.poly.Vertices[6].Z != 1) {
+                t.Fatalf("split mesh %v", res.Mesh)
+            }
+            data, err := json.Marshal(res)
+            if err != nil {
+                t.Fatal(err)
+            }
+            var got UnfoldResult
+            if err := json.Unmarshal(data, &got); err != nil {
+                t.Fatal(err)
+            }
+            if !reflect.DeepEqual(got.Face2D, res.Face2D) {
+                t.Errorf("Face2D = %v, want %v", got.Face2D, res.Face2D)
+            }
+            if !reflect.DeepEqual(got.SpanningTree, res.SpanningTree) || !reflect.DeepEqual(got.VertexInstances, res.VertexInstances) {
+                t.Error("spanning tree or vertex instances changed")
+            }
+            if got.Side != res.Side || !reflect.DeepEqual(got.Mesh, res.Mesh) || !reflect.DeepEqual(got.FaceOrigin, res.FaceOrigin) {
+                t.Errorf("side %v, mesh %v, face origin %v; want %v, %v, %v", got.Side, got.Mesh, got.FaceOrigin, res.Side, res.Mesh, res.FaceOrigin)
+            }
+            again, err := json.Marshal(got)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if !bytes.Equal(again, data) {
+                t.Errorf("encoding again gives\n%s\nwant\n%s", again, data)
+            }
+        })
+    }
+}
+
+func TestJSONLegacyDocuments(t *testing.T) {
+    var poly Polyhedron
+    legacy := `{"Vertices": [{"X": 0, "Y": 0, "Z": 0}, {"X": 1, "Y": 0, "Z": 0}, {"X": 0, "Y": 1, "Z": 0}],
+        "Faces": [{"Vertices": [0, 1, 2]}], "Name": "tri"}`
+    if err := json.Unmarshal([]byte(legacy), &poly); err != nil {
+        t.Fatal(err)
+    }
+    if poly.Name != "tri" || len(poly.Vertices) != 3 || !reflect.DeepEqual(poly.Faces[0].Vertices, []int{0, 1, 2}) {
+        t.Errorf("legacy mesh read as %+v", poly)
+    }
+
+    var res UnfoldResult
+    legacy = `{"Face2D": [{"Vertices": [{"X": 0, "Y": 0}, {"X": 1, "Y": 0}, {"X": 0, "Y": 1}]}],
+        "Vertex2D": [{"X": 0, "Y": 0}], "SpanningTree": [-1]}`
+    if err := json.Unmarshal([]byte(legacy), &res); err != nil {
+        t.Fatal(err)
+    }
+    if len(res.Face2D) != 1 || len(res.Face2D[0].Vertices) != 3 || !reflect.DeepEqual(res.SpanningTree, []int{-1}) {
+        t.Errorf("legacy result read as %+v", res)
+    }
+}
+
+func TestJSONRejects(t *testing.T) {
+    tests := []struct {
+        name string
+        into interface{}
+        doc  string
+        want string
+    }{
+        {"mesh as result", &UnfoldResult{}, `{"schema": "go-unfold/mesh", "version": 1, "vertices": [], "faces": []}`, "expected a go-unfold/result document"},
+        {"result as mesh", &Polyhedron{}, `{"schema": "go-unfold/result", "version": 1, "face2D": []}`, "expected a go-unfold/mesh document"},
+        {"newer mesh", &Polyhedron{}, `{"schema": "go-unfold/mesh", "version": 99, "vertices": [], "faces": []}`, "not supported"},
+        {"newer result", &UnfoldResult{}, `{"schema": "go-unfold/result", "version": 99, "face2D": []}`, "not supported"},
+        {"hole lists", &Polyhedron{}, `{"schema": "go-unfold/mesh", "version": 1, "vertices": [], "faces": [[0, 1, 2]], "holes": []}`, "hole lists"},
+        {"uv lists", &Polyhedron{}, `{"schema": "go-unfold/mesh", "version": 1, "vertices": [], "faces": [[0, 1, 2]], "uvs": [[], []]}`, "texture coordinate lists"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := json.Unmarshal([]byte(tt.doc), tt.into)
+            if err == nil || !strings.Contains(err.Error(), tt.want) {
+                t.Errorf("err = %v, want one containing %q", err, tt.want)
+            }
+        })
+    }
+}
@@ -0,0 +1,211 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Thick material (bend allowance and kerf)
+// -----------------------------
+
+// MaterialSpec describes sheet material too thick to fold on a line, such
+// as sheet metal or corrugated card: every bend takes up a strip of the
+// sheet, so the flat pattern differs from the faces' own sizes. Lengths are
+// in mesh units.
+//
+// Where two faces folded to the same face meet at a corner, their bends
+// cross in the pattern and the faces overlap a little there; cut a corner
+// relief (DetectOverlaps finds the places).
+type MaterialSpec struct {
+    Thickness float64 `json:"thickness"`
+    // BendRadius is the inside radius of the bends.
+    BendRadius float64 `json:"bendRadius,omitempty"`
+    // KFactor is where the neutral layer (the one that neither stretches
+    // nor shrinks) lies, as a fraction of the thickness from the inside of
+    // the bend. 0 means 0.44, typical of air-bent sheet metal; card is
+    // nearer 0.3.
+    KFactor float64 `json:"kFactor,omitempty"`
+    // Kerf is the width the cutter takes away; the outline is moved out by
+    // half of it so pieces come out true to size.
+    Kerf float64 `json:"kerf,omitempty"`
+    // MeshInside says the mesh is the inside surface of the material (the
+    // sheet wraps round it, as round a foam core). By default the mesh is
+    // the outside, as for a box folded round its contents.
+    MeshInside bool `json:"meshInside,omitempty"`
+}
+
+func (m *MaterialSpec) check() error {
+    if m.Thickness < 0 || m.BendRadius < 0 || m.Kerf < 0 {
+        return fmt.Errorf("negative material size (thickness %g, bend radius %g, kerf %g)", m.Thickness, m.BendRadius, m.Kerf)
+    }
+    if m.KFactor < 0 || m.KFactor > 1 {
+        return fmt.Errorf("K-factor %g outside [0, 1]", m.KFactor)
+    }
+    return nil
+}
+
+// BendAdjustment is how much longer the flat pattern gets across a fold
+// bent bend radians away from flat (negative: shorter), with the mesh on
+// the outside of the bend or not: the bend allowance, the length of the
+// neutral layer round the bend, less the two setbacks from the mesh's
+// corner to where the bend starts. With the mesh on the outside this is
+// minus the usual bend deduction.
+func (m MaterialSpec) BendAdjustment(bend float64, outside bool) float64 {
+    k := m.KFactor
+    if k == 0 {
+        k = 0.44
+    }
+    allowance := bend * (m.BendRadius + k*m.Thickness)
+    setback := m.BendRadius * math.Tan(bend/2)
+    if outside {
+        setback = (m.BendRadius + m.Thickness) * math.Tan(bend/2)
+    }
+    return allowance - 2*setback
+}
+
+// applyMaterial adjusts a finished placement (folds from parent to child)
+// for thick material, in place: each child face and everything folded to it
+// moves away from its parent by the fold's BendAdjustment, the two faces of
+// a fold then meet halfway along it (the middle of the bend), and every
+// other edge moves out by half the kerf. Convex edges of the mesh have it on
+// the outside of the bend, concave ones on the inside, unless MeshInside.
+func applyMaterial(poly Polyhedron, face2D []Face2D, folds []NetEdge, m *MaterialSpec) error {
+    if m == nil {
+        return nil
+    }
+    if err := m.check(); err != nil {
+        return err
+    }
+    nFaces := len(face2D)
+    offset := make([][]float64, nFaces) // per local edge, outwards
+    for f := range face2D {
+        offset[f] = make([]float64, len(face2D[f].Vertices))
+        for i := range offset[f] {
+            offset[f][i] = m.Kerf / 2
+        }
+    }
+    children := make([][]int, nFaces) // indices into folds
+    isChild := make([]bool, nFaces)
+    adjust := make([]float64, len(folds))
+    for i, e := range folds {
+        if len(face2D[e.FaceA].Vertices) < 3 || len(face2D[e.FaceB].Vertices) < 3 {
+            continue
+        }
+        theta := DihedralAngle(poly, e)
+        d := m.BendAdjustment(math.Abs(math.Pi-theta), (theta < math.Pi) != m.MeshInside)
+        if math.IsNaN(d) || math.IsInf(d, 0) {
+            return fmt.Errorf("fold %d-%d is bent too far for thick material", e.Vertices[0], e.Vertices[1])
+        }
+        adjust[i] = d
+        offset[e.FaceA][e.EdgeA] = d / 2
+        offset[e.FaceB][e.EdgeB] = d / 2
+        children[e.FaceA] = append(children[e.FaceA], i)
+        isChild[e.FaceB] = true
+    }
+
+    // move every subtree by the folds above it, from the roots down
+    shift := make([]Point2, nFaces)
+    var queue []int
+    for f := range face2D {
+        if len(face2D[f].Vertices) >= 3 && !isChild[f] {
+            queue = append(queue, f)
+        }
+    }
+    for len(queue) > 0 {
+        f := queue[0]
+        queue = queue[1:]
+        for _, i := range children[f] {
+            e := folds[i]
+            n := outwardNormal(face2D[f].Vertices, e.EdgeA)
+            shift[e.FaceB] = Point2{X: shift[f].X + n.X*adjust[i], Y: shift[f].Y + n.Y*adjust[i]}
+            queue = append(queue, e.FaceB)
+        }
+    }
+
+    var bad []int
+    for f := range face2D {
+        pts := face2D[f].Vertices
+        if len(pts) < 3 {
+            continue
+        }
+        moved := make([]Point2, len(pts))
+        for i, p := range pts {
+            moved[i] = Point2{X: p.X + shift[f].X, Y: p.Y + shift[f].Y}
+        }
+        out := offsetPolygon(moved, offset[f])
+        if a, b := polygonArea(moved), polygonArea(out); a*b <= 0 || !polygonSimple(out) {
+            bad = append(bad, f)
+            continue
+        }
+        face2D[f].Vertices = out
+    }
+    if len(bad) > 0 {
+        more := ""
+        if len(bad) > 8 {
+            bad, more = bad[:8], " ..."
+        }
+        return fmt.Errorf("material too thick for faces %v%s: their bends take up more than the whole face", bad, more)
+    }
+    return nil
+}
+
+// outwardNormal is the unit normal of edge i of the polygon pts pointing
+// out of it.
+func outwardNormal(pts []Point2, i int) Point2 {
+    a, b := pts[i], pts[(i+1)%len(pts)]
+    dx, dy := b.X-a.X, b.Y-a.Y
+    l := math.Hypot(dx, dy)
+    if l == 0 {
+        return Point2{}
+    }
+    if polygonArea(pts) < 0 {
+        return Point2{X: -dy / l, Y: dx / l}
+    }
+    return Point2{X: dy / l, Y: -dx / l}
+}
+
+// offsetPolygon moves every edge i of pts out by off[i] (in by a negative
+// amount) and returns the corners where the moved edges meet.
+func offsetPolygon(pts []Point2, off []float64) []Point2 {
+    n := len(pts)
+    out := make([]Point2, n)
+    for i := range pts {
+        j := (i + n - 1) % n // the edge coming into corner i
+        ni, nj := outwardNormal(pts, i), outwardNormal(pts, j)
+        if off[i] == 0 && off[j] == 0 {
+            out[i] = pts[i]
+            continue
+        }
+        // corner i moved so it's off[j] from edge j and off[i] from edge i
+        pi := Point2{X: pts[i].X + ni.X*off[i], Y: pts[i].Y + ni.Y*off[i]}
+        pj := Point2{X: pts[i].X + nj.X*off[j], Y: pts[i].Y + nj.Y*off[j]}
+        di := Point2{X: pts[(i+1)%n].X - pts[i].X, Y: pts[(i+1)%n].Y - pts[i].Y}
+        dj := Point2{X: pts[i].X - pts[j].X, Y: pts[i].Y - pts[j].Y}
+        den := dj.X*di.Y - dj.Y*di.X
+        if math.Abs(den) <= 1e-12*math.Hypot(di.X, di.Y)*math.Hypot(dj.X, dj.Y) {
+            out[i] = pi // straight on: the edges are one line
+            continue
+        }
+        // pj + s*dj = pi + t*di
+        s := ((pi.X-pj.X)*di.Y - (pi.Y-pj.Y)*di.X) / den
+        out[i] = Point2{X: pj.X + s*dj.X, Y: pj.Y + s*dj.Y}
+    }
+    return out
+}
+
+// polygonSimple reports whether no two non-adjacent edges of pts cross.
+func polygonSimple(pts []Point2) bool {
+    n := len(pts)
+    for i := 0; i < n; i++ {
+        for j := i + 2; j < n; j++ {
+            if i == 0 && j == n-1 {
+                continue
+            }
+            if t, s, ok := segmentIntersection(pts[i], pts[(i+1)%n], pts[j], pts[(j+1)%n]); ok && t > 0 && t < 1 && s > 0 && s < 1 {
+                return false
+            }
+        }
+    }
+    return true
+}
@@ -0,0 +1,102 @@
+package unfolder
+
+import (
+    "errors"
+    "runtime"
+    "unsafe"
+)
+
+// ErrMemoryLimit is returned when a mesh's estimated memory use exceeds
+// UnfoldOptions.MemoryLimit. Servers can check for it with errors.Is and
+// reject the upload instead of running out of memory.
+var ErrMemoryLimit = errors.New("estimated memory exceeds limit")
+
+// StageMemory is the memory used by one stage of the unfold pipeline.
+type StageMemory struct {
+    Stage      string
+    AllocBytes uint64 // bytes allocated during this stage
+    HeapInUse  uint64 // heap in use at the end of this stage (whole process)
+}
+
+// MemoryReport compares the up-front estimate with what the stages actually used.
+type MemoryReport struct {
+    Estimated  int64
+    Stages     []StageMemory
+    TotalAlloc uint64 // sum of AllocBytes over all stages
+}
+
+// Rough per-item sizes used by EstimateMemory. Map entries are charged about
+// twice their key+value size to account for bucket overhead.
+const (
+    mapOverhead  = 2
+    sliceHeader  = int64(unsafe.Sizeof([]int(nil)))
+    intSize      = int64(unsafe.Sizeof(int(0)))
+    point2Size   = int64(unsafe.Sizeof(Point2{}))
+    instanceSize = int64(unsafe.Sizeof(VertexInstance{}))
+    neighborSize = int64(unsafe.Sizeof(FaceNeighbor{}))
+    edgeKeySize  = int64(unsafe.Sizeof([2]int{}))
+)
+
+// EstimateMemory returns an upper-bound-ish estimate (in bytes) of the peak
+// memory UnfoldMesh needs for poly, computed only from element counts so it's
+// cheap enough to call on untrusted input before doing any real work.
+func EstimateMemory(poly Polyhedron) int64 {
+    nFaces := int64(len(poly.Faces))
+    var corners int64 // sum of face sizes == number of directed edges
+    for _, f := range poly.Faces {
+        corners += int64(len(f.Vertices))
+    }
+    // each undirected edge is seen twice on a closed mesh
+    nEdges := corners/2 + 1
+
+    var total int64
+    // edge map: key + slice of face indices
+    total += mapOverhead * nEdges * (edgeKeySize + sliceHeader + 2*intSize)
+    // adjacency: map of face -> neighbor slice, one neighbor per directed edge
+    total += mapOverhead*nFaces*(intSize+sliceHeader) + corners*neighborSize
+    // spanning tree: parent, visited, queue
+    total += nFaces * (2*intSize + 1)
+    // placement: placed flags, Face2D headers + points, vertex instances
+    total += nFaces*(1+sliceHeader) + corners*point2Size + corners*instanceSize
+    return total
+}
+
+// memoryTracker snapshots runtime allocation counters between stages.
+// A nil tracker is valid and records nothing, so callers don't need to branch.
+type memoryTracker struct {
+    estimated int64
+    last      uint64
+    stages    []StageMemory
+}
+
+func newMemoryTracker(estimated int64) *memoryTracker {
+    var ms runtime.MemStats
+    runtime.ReadMemStats(&ms)
+    return &memoryTracker{estimated: estimated, last: ms.TotalAlloc}
+}
+
+// mark closes the current stage under the given name.
+func (t *memoryTracker) mark(stage string) {
+    if t == nil {
+        return
+    }
+    var ms runtime.MemStats
+    runtime.ReadMemStats(&ms)
+    t.stages = append(t.stages, StageMemory{
+        Stage:      stage,
+        AllocBytes: ms.TotalAlloc - t.last,
+        HeapInUse:  ms.HeapInuse,
+    })
+    t.last = ms.TotalAlloc
+}
+
+func (t *memoryTracker) report() *MemoryReport {
+    if t == nil {
+        return nil
+    }
+    r := &MemoryReport{Estimated: t.estimated, Stages: t.stages}
+    for _, s := range t.stages {
+        r.TotalAlloc += s.AllocBytes
+    }
+    return r
+}
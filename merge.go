@@ -0,0 +1,248 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Merging coplanar faces
+// -----------------------------
+
+// MergeCoplanarFaces joins neighbouring faces that lie in one plane into a
+// single polygon, so a cube exported as 12 triangles unfolds as 6 squares
+// without the diagonal folds. Faces are grown into a region from the
+// largest one out, across edges both faces run opposite ways, as long as
+// their normals are within angleTolerance degrees of the first face's; 0
+// still allows for rounding.
+//
+// A region only becomes one face if its outline is a single simple loop.
+// Regions with holes (the flat face of a washer) or that touch themselves
+// at a vertex keep their faces as they were, and faces that already have
+// Holes aren't merged. Outline vertices on a straight
+// run are dropped if no other face uses them.
+//
+// Vertices are shared with poly, not copied, so the ones inside merged
+// regions are left unused. The returned slice gives the new face of every
+// face of poly; merged faces take the place of their lowest face.
+func MergeCoplanarFaces(poly Polyhedron, angleTolerance float64) (merged Polyhedron, regionOf []int, err error) {
+    defer (&progress{stage: "coplanar merge", face: -1}).recover(&err)
+    if angleTolerance < 0 || angleTolerance >= 90 {
+        return Polyhedron{}, nil, fmt.Errorf("angle tolerance %v outside [0, 90)", angleTolerance)
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return Polyhedron{}, nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+    nFaces := len(poly.Faces)
+    cosLimit := math.Cos(math.Max(angleTolerance*math.Pi/180, 1e-6))
+    normals := make([]Vector3, nFaces)
+    area := make([]float64, nFaces)
+    for f, face := range poly.Faces {
+        n := faceNormal(poly, face)
+        area[f] = length(n) / 2
+        if area[f] > 0 {
+            normals[f] = normalize(n)
+        }
+    }
+
+    // regions, grown from the largest faces
+    seeds := make([]int, nFaces)
+    for i := range seeds {
+        seeds[i] = i
+    }
+    sort.SliceStable(seeds, func(i, j int) bool { return area[seeds[i]] > area[seeds[j]] })
+    region := make([]int, nFaces)
+    for i := range region {
+        region[i] = -1
+    }
+    var members [][]int
+    for _, seed := range seeds {
+        if region[seed] >= 0 {
+            continue
+        }
+        r := len(members)
+        region[seed] = r
+        group := []int{seed}
+        for i := 0; i < len(group) && area[seed] > 0 && len(poly.Faces[seed].Holes) == 0; i++ {
+            f := group[i]
+            for _, nbr := range adj.Neighbors[f] {
+                g := nbr.FaceIndex
+                if region[g] >= 0 || area[g] == 0 || len(poly.Faces[g].Holes) > 0 || dot(normals[g], normals[seed]) < cosLimit {
+                    continue
+                }
+                if !runsOpposite(poly.Faces[f], poly.Faces[g], nbr.SharedEdge) {
+                    continue
+                }
+                region[g] = r
+                group = append(group, g)
+            }
+        }
+        sort.Ints(group)
+        members = append(members, group)
+    }
+
+    // how many faces use every vertex, to tell which outline vertices only
+    // the region has
+    users := make([]int, len(poly.Vertices))
+    for _, face := range poly.Faces {
+        for _, v := range face.Vertices {
+            if v >= 0 && v < len(users) {
+                users[v]++
+            }
+        }
+    }
+
+    out := Polyhedron{Vertices: poly.Vertices, Name: poly.Name, Units: poly.Units}
+    newFace := make([]int, nFaces)
+    for f := range newFace {
+        newFace[f] = -1
+    }
+    unmerged := make([]bool, len(members)) // regions that keep their faces
+    for f := 0; f < nFaces; f++ {
+        if newFace[f] >= 0 {
+            continue
+        }
+        r := region[f]
+        if len(members[r]) > 1 && !unmerged[r] {
+            if merged, ok := mergeRegion(poly, members[r], normals[members[r][0]], users); ok {
+                for _, g := range members[r] {
+                    newFace[g] = len(out.Faces)
+                }
+                out.Faces = append(out.Faces, merged)
+                continue
+            }
+            unmerged[r] = true
+        }
+        newFace[f] = len(out.Faces)
+        src := poly.Faces[f]
+        out.Faces = append(out.Faces, Face{Vertices: append([]int(nil), src.Vertices...), UVs: append([]Point2(nil), src.UVs...), Holes: copyHoles(src.Holes, sameVertex)})
+    }
+    return out, newFace, nil
+}
+
+// runsOpposite reports whether faces f and g run their shared edge in
+// opposite directions, as neighbours with the same winding do.
+func runsOpposite(f, g Face, edge [2]int) bool {
+    dir := func(face Face) int {
+        n := len(face.Vertices)
+        for i, v := range face.Vertices {
+            w := face.Vertices[(i+1)%n]
+            if v == edge[0] && w == edge[1] {
+                return 1
+            }
+            if v == edge[1] && w == edge[0] {
+                return -1
+            }
+        }
+        return 0
+    }
+    a, b := dir(f), dir(g)
+    return a != 0 && a == -b
+}
+
+// mergeRegion traces the outline of a region of faces as one face; false if
+// it isn't a single simple loop. normal is the region's seed normal, users
+// how many faces of the mesh use each vertex.
+func mergeRegion(poly Polyhedron, group []int, normal Vector3, users []int) (Face, bool) {
+    // directed edges of the region; the outline is those whose reverse
+    // isn't there too
+    type corner struct{ face, i int }
+    edges := make(map[[2]int]corner)
+    for _, f := range group {
+        vs := poly.Faces[f].Vertices
+        for i := range vs {
+            edges[[2]int{vs[i], vs[(i+1)%len(vs)]}] = corner{f, i}
+        }
+    }
+    next := make(map[int]int)
+    var first int
+    for e := range edges {
+        if _, inner := edges[[2]int{e[1], e[0]}]; inner {
+            continue
+        }
+        if _, dup := next[e[0]]; dup {
+            return Face{}, false // the outline passes a vertex twice
+        }
+        next[e[0]] = e[1]
+        first = e[0]
+    }
+    if len(next) < 3 {
+        return Face{}, false
+    }
+    loop := []int{first}
+    for v := next[first]; v != first; v = next[v] {
+        if len(loop) > len(next) {
+            return Face{}, false
+        }
+        loop = append(loop, v)
+    }
+    if len(loop) != len(next) {
+        return Face{}, false // more than one loop: a hole
+    }
+
+    // in-region vertices on a straight run go
+    inRegion := make(map[int]int) // faces of the region using the vertex
+    for _, f := range group {
+        for _, v := range poly.Faces[f].Vertices {
+            inRegion[v]++
+        }
+    }
+    for changed := true; changed && len(loop) > 3; {
+        changed = false
+        for i := 0; i < len(loop) && len(loop) > 3; i++ {
+            n := len(loop)
+            v := loop[i]
+            if users[v] != inRegion[v] {
+                continue
+            }
+            a, b, c := poly.Vertices[loop[(i+n-1)%n]], poly.Vertices[v], poly.Vertices[loop[(i+1)%n]]
+            d1, d2 := sub(b, a), sub(c, b)
+            if length(cross(d1, d2)) <= 1e-9*length(d1)*length(d2) && dot(d1, d2) > 0 {
+                loop = append(loop[:i], loop[i+1:]...)
+                changed = true
+                i--
+            }
+        }
+    }
+
+    // the outline must not cross itself; looked at along the normal
+    pts := make([]Point2, len(loop))
+    ref := Vector3{1, 0, 0}
+    if math.Abs(normal.X) > 0.9 {
+        ref = Vector3{0, 1, 0}
+    }
+    u := normalize(cross(ref, normal))
+    w := cross(normal, u)
+    for i, v := range loop {
+        pts[i] = Point2{dot(poly.Vertices[v], u), dot(poly.Vertices[v], w)}
+    }
+    if polygonArea(pts) <= 0 || !polygonSimple(pts) {
+        return Face{}, false
+    }
+
+    // start at a clearly convex corner: placeRootFace takes the plane from
+    // the first three vertices
+    n := len(loop)
+    start, best := 0, 0.0
+    for i := range loop {
+        if c := cross2(pts[i], pts[(i+1)%n], pts[(i+2)%n]); c > best {
+            start, best = i, c
+        }
+    }
+    out := Face{Vertices: make([]int, n)}
+    textured := true
+    for _, f := range group {
+        textured = textured && len(poly.Faces[f].UVs) == len(poly.Faces[f].Vertices)
+    }
+    for k := range loop {
+        v := loop[(start+k)%n]
+        out.Vertices[k] = v
+        if textured {
+            c := edges[[2]int{v, next[v]}] // the face corner the outline leaves v from
+            out.UVs = append(out.UVs, poly.Faces[c.face].UVs[c.i])
+        }
+    }
+    return out, true
+}
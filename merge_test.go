@@ -0,0 +1,136 @@
+package unfolder
+
+import (
+    "reflect"
+    "testing"
+)
+
+// triangulated splits every face of poly into a fan of triangles.
+func triangulated(poly Polyhedron) Polyhedron {
+    out := Polyhedron{Vertices: poly.Vertices}
+    for _, face := range poly.Faces {
+        vs := face.Vertices
+        for i := 1; i+1 < len(vs); i++ {
+            tri := Face{Vertices: []int{vs[0], vs[i], vs[i+1]}}
+            if face.UVs != nil {
+                tri.UVs = []Point2{face.UVs[0], face.UVs[i], face.UVs[i+1]}
+            }
+            out.Faces = append(out.Faces, tri)
+        }
+    }
+    return out
+}
+
+// washer is a flat square with a square hole, in eight triangles.
+func washer() Polyhedron {
+    return Polyhedron{
+        Vertices: []Vector3{
+            {0, 0, 0}, {3, 0, 0}, {3, 3, 0}, {0, 3, 0},
+            {1, 1, 0}, {2, 1, 0}, {2, 2, 0}, {1, 2, 0},
+        },
+        Faces: []Face{
+            {Vertices: []int{0, 1, 5}}, {Vertices: []int{0, 5, 4}},
+            {Vertices: []int{1, 2, 6}}, {Vertices: []int{1, 6, 5}},
+            {Vertices: []int{2, 3, 7}}, {Vertices: []int{2, 7, 6}},
+            {Vertices: []int{3, 0, 4}}, {Vertices: []int{3, 4, 7}},
+        },
+    }
+}
+
+func TestMergeCoplanarFaces(t *testing.T) {
+    flatGrid := testGrid(3)
+    for i := range flatGrid.Vertices {
+        flatGrid.Vertices[i].Z = 0
+    }
+    bent := triangulated(testCube())
+    bent.Vertices = append([]Vector3(nil), bent.Vertices...)
+    bent.Vertices[6].Z = 1.01 // top's diagonal 4-6 bends by about half a degree
+    flipped := triangulated(testCube())
+    flipped.Faces[1].Vertices = reversed(flipped.Faces[1].Vertices)
+
+    tests := []struct {
+        name      string
+        poly      Polyhedron
+        tolerance float64
+        faces     int   // faces afterwards
+        sizes     []int // vertex counts of the faces afterwards, if checked
+    }{
+        {"quad cube", testCube(), 0, 6, []int{4, 4, 4, 4, 4, 4}},
+        {"triangulated cube", triangulated(testCube()), 0, 6, []int{4, 4, 4, 4, 4, 4}},
+        {"bent top, strict", bent, 0, 7, nil},
+        {"bent top, tolerant", bent, 2, 6, nil},
+        {"flat grid", flatGrid, 0, 1, []int{4}},
+        {"sphere", testSphere(6, 8), 0, 48, nil},
+        {"washer keeps its faces", washer(), 0, 8, nil},
+        {"wound the other way", flipped, 0, 7, nil},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            merged, regionOf, err := MergeCoplanarFaces(tt.poly, tt.tolerance)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(merged.Faces) != tt.faces {
+                t.Fatalf("%d faces, want %d", len(merged.Faces), tt.faces)
+            }
+            if tt.sizes != nil {
+                var sizes []int
+                for _, f := range merged.Faces {
+                    sizes = append(sizes, len(f.Vertices))
+                }
+                if !reflect.DeepEqual(sizes, tt.sizes) {
+                    t.Errorf("face sizes %v, want %v", sizes, tt.sizes)
+                }
+            }
+            if len(regionOf) != len(tt.poly.Faces) {
+                t.Fatalf("%d region entries for %d faces", len(regionOf), len(tt.poly.Faces))
+            }
+            // a merged face takes the place of its lowest face, and every
+            // new face is used
+            used := make([]bool, len(merged.Faces))
+            for f, r := range regionOf {
+                if r < 0 || r >= len(merged.Faces) {
+                    t.Fatalf("face %d went to %d", f, r)
+                }
+                used[r] = true
+            }
+            for r, ok := range used {
+                if !ok {
+                    t.Errorf("new face %d has no old faces", r)
+                }
+            }
+            before, _ := ValidateMesh(tt.poly)
+            if r, err := ValidateMesh(merged); err != nil || len(r.WindingConflicts) > len(before.WindingConflicts) {
+                t.Errorf("merged mesh: %v, %d winding conflicts (%d before)", err, len(r.WindingConflicts), len(before.WindingConflicts))
+            }
+        })
+    }
+}
+
+func TestMergeCoplanarFacesKeepsUVs(t *testing.T) {
+    square := Polyhedron{
+        Vertices: []Vector3{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}},
+        Faces:    []Face{{Vertices: []int{0, 1, 2, 3}, UVs: []Point2{{0, 0}, {0.5, 0}, {0.5, 0.5}, {0, 0.5}}}},
+    }
+    merged, _, err := MergeCoplanarFaces(triangulated(square), 0)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(merged.Faces) != 1 {
+        t.Fatalf("%d faces, want 1", len(merged.Faces))
+    }
+    f := merged.Faces[0]
+    for k, v := range f.Vertices {
+        if want := square.Faces[0].UVs[v]; f.UVs[k] != want {
+            t.Errorf("corner %d (vertex %d) has UV %v, want %v", k, v, f.UVs[k], want)
+        }
+    }
+}
+
+func TestMergeCoplanarFacesTolerance(t *testing.T) {
+    for _, tol := range []float64{-1, 90, 120} {
+        if _, _, err := MergeCoplanarFaces(testCube(), tol); err == nil {
+            t.Errorf("tolerance %v: no error", tol)
+        }
+    }
+}
@@ -0,0 +1,202 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// -----------------------------
+//   Mesh validation
+// -----------------------------
+
+// MeshReport is the result of ValidateMesh. The lists are sorted; Issues has
+// one summary entry per kind of problem found.
+type MeshReport struct {
+    Vertices int `json:"vertices"`
+    Faces    int `json:"faces"`
+    Edges    int `json:"edges"`
+
+    NonManifoldEdges     [][2]int `json:"nonManifoldEdges,omitempty"` // shared by 3+ faces
+    BoundaryEdges        [][2]int `json:"boundaryEdges,omitempty"`    // only one face
+    DuplicateFaces       [][2]int `json:"duplicateFaces,omitempty"`   // (first, duplicate) over the same vertices
+    DegenerateFaces      []int    `json:"degenerateFaces,omitempty"`  // zero area, repeated or out of range vertices
+    WindingConflicts     [][2]int `json:"windingConflicts,omitempty"` // neighbour faces that run their shared edge the same way
+    UnreferencedVertices []int    `json:"unreferencedVertices,omitempty"`
+    SelfIntersections    [][2]int `json:"selfIntersections,omitempty"` // faces cutting through each other
+
+    Issues []Issue `json:"issues"`
+}
+
+// OK reports whether the mesh can be unfolded (warnings are allowed).
+func (r *MeshReport) OK() bool {
+    for _, is := range r.Issues {
+        if is.Severity == SeverityError {
+            return false
+        }
+    }
+    return true
+}
+
+// ValidateMesh checks the geometry and topology BuildFaceAdjacency and the
+// placement assume. Non-manifold and boundary edges, duplicate faces,
+// inconsistent winding, unreferenced vertices and self-intersections (only
+// looked for if no face is degenerate) are warnings: the unfold still runs
+// but the net may come out cut up or mirrored. Degenerate faces are errors,
+// they can't be placed. The returned error lists the errors and wraps an
+// ErrDegenerateFace for the first degenerate face; the report is returned
+// either way, even if the checks broke off halfway (with what they had found,
+// and an "internal" error issue).
+func ValidateMesh(poly Polyhedron) (report *MeshReport, err error) {
+    r := &MeshReport{Vertices: len(poly.Vertices), Faces: len(poly.Faces), Issues: []Issue{}}
+    add := func(sev Severity, code string, face int, edge *[2]int, format string, args ...interface{}) {
+        r.Issues = append(r.Issues, Issue{Severity: sev, Code: code, Message: fmt.Sprintf(format, args...), Face: face, Edge: edge})
+    }
+    defer func() {
+        if report == nil && err != nil {
+            add(SeverityError, "internal", -1, nil, "%v", err)
+            report = r
+        }
+    }()
+    defer (&progress{stage: "mesh validation", face: -1}).recover(&err)
+    if len(poly.Faces) == 0 {
+        add(SeverityError, "no-faces", -1, nil, "polyhedron has no faces")
+        return r, errors.New("polyhedron has no faces")
+    }
+
+    // a tolerance for "zero area", relative to the model size
+    lo, hi := boundingBox(poly.Vertices)
+    diag := length(sub(hi, lo))
+    areaEps := 1e-12 * diag * diag
+
+    type use struct{ face, from int } // face and the directed edge's start vertex
+    edges := make(map[[2]int][]use)
+    used := make([]bool, len(poly.Vertices))
+    seenFace := make(map[string]int)
+    for fIdx, face := range poly.Faces {
+        vs := face.Vertices
+        degenerate := len(vs) < 3
+        distinct := make(map[int]bool, len(vs))
+        for _, v := range vs {
+            if v < 0 || v >= len(poly.Vertices) {
+                degenerate = true
+                continue
+            }
+            used[v] = true
+            if distinct[v] {
+                degenerate = true
+            }
+            distinct[v] = true
+        }
+        for _, hole := range face.Holes {
+            degenerate = degenerate || len(hole) < 3
+            for _, v := range hole {
+                if v < 0 || v >= len(poly.Vertices) {
+                    degenerate = true
+                    continue
+                }
+                used[v] = true
+            }
+        }
+        if !degenerate && length(faceNormal(poly, face))/2 <= areaEps {
+            degenerate = true
+        }
+        if degenerate {
+            r.DegenerateFaces = append(r.DegenerateFaces, fIdx)
+            continue
+        }
+
+        key := append([]int(nil), vs...)
+        sort.Ints(key)
+        k := fmt.Sprint(key)
+        if first, ok := seenFace[k]; ok {
+            // leave it out of the edge checks, every edge would look non-manifold
+            r.DuplicateFaces = append(r.DuplicateFaces, [2]int{first, fIdx})
+            continue
+        }
+        seenFace[k] = fIdx
+
+        for i := range vs {
+            a, b := vs[i], vs[(i+1)%len(vs)]
+            e := sortPair(a, b)
+            edges[e] = append(edges[e], use{fIdx, a})
+        }
+    }
+    r.Edges = len(edges)
+
+    for e, us := range edges {
+        switch {
+        case len(us) == 1:
+            r.BoundaryEdges = append(r.BoundaryEdges, e)
+        case len(us) > 2:
+            r.NonManifoldEdges = append(r.NonManifoldEdges, e)
+        case us[0].from == us[1].from:
+            // consistent neighbours run a shared edge in opposite directions
+            r.WindingConflicts = append(r.WindingConflicts, sortPair(us[0].face, us[1].face))
+        }
+    }
+    for v, ok := range used {
+        if !ok {
+            r.UnreferencedVertices = append(r.UnreferencedVertices, v)
+        }
+    }
+    if len(r.DegenerateFaces) == 0 {
+        r.SelfIntersections, _ = SelfIntersections(poly)
+    }
+    sortPairs(r.BoundaryEdges)
+    sortPairs(r.NonManifoldEdges)
+    sortPairs(r.WindingConflicts)
+
+    if n := len(r.DegenerateFaces); n > 0 {
+        add(SeverityError, "degenerate-face", r.DegenerateFaces[0], nil,
+            "%d degenerate faces (zero area, repeated or invalid vertices), first is face %d", n, r.DegenerateFaces[0])
+    }
+    if n := len(r.NonManifoldEdges); n > 0 {
+        e := r.NonManifoldEdges[0]
+        add(SeverityWarning, "non-manifold-edge", -1, &e,
+            "%d edges are shared by 3 or more faces and will always be cut, first is %d-%d", n, e[0], e[1])
+    }
+    if n := len(r.DuplicateFaces); n > 0 {
+        d := r.DuplicateFaces[0]
+        add(SeverityWarning, "duplicate-face", d[1], nil, "%d duplicate faces, first is face %d (same as %d)", n, d[1], d[0])
+    }
+    if n := len(r.WindingConflicts); n > 0 {
+        c := r.WindingConflicts[0]
+        add(SeverityWarning, "inconsistent-winding", c[0], nil,
+            "%d neighbouring face pairs disagree on winding, first is faces %d and %d; parts of the net will be mirrored (RepairWinding fixes this)", n, c[0], c[1])
+    }
+    if n := len(r.BoundaryEdges); n > 0 {
+        e := r.BoundaryEdges[0]
+        add(SeverityWarning, "boundary-edge", -1, &e, "mesh is open: %d boundary edges, first is %d-%d", n, e[0], e[1])
+    }
+    if n := len(r.SelfIntersections); n > 0 {
+        s := r.SelfIntersections[0]
+        add(SeverityWarning, "self-intersection", s[0], nil,
+            "%d face pairs cut through each other, first is faces %d and %d; the folded model can't be built as modelled", n, s[0], s[1])
+    }
+    if n := len(r.UnreferencedVertices); n > 0 {
+        add(SeverityWarning, "unreferenced-vertex", -1, nil, "%d vertices are not used by any face, first is vertex %d", n, r.UnreferencedVertices[0])
+    }
+
+    var msgs []string
+    for _, is := range r.Issues {
+        if is.Severity == SeverityError {
+            msgs = append(msgs, is.Message)
+        }
+    }
+    if len(msgs) > 0 {
+        // degenerate faces are the only errors past the no-faces check
+        return r, fmt.Errorf("%s: %w", strings.Join(msgs, "; "), &ErrDegenerateFace{Face: r.DegenerateFaces[0]})
+    }
+    return r, nil
+}
+
+func sortPairs(ps [][2]int) {
+    sort.Slice(ps, func(i, j int) bool {
+        if ps[i][0] != ps[j][0] {
+            return ps[i][0] < ps[j][0]
+        }
+        return ps[i][1] < ps[j][1]
+    })
+}
@@ -0,0 +1,148 @@
+package unfolder
+
+import (
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Mesh comparison
+// -----------------------------
+
+// CanonicalMesh returns a copy of poly in a canonical form: vertices sorted
+// by (X, Y, Z), each face rotated to start at its smallest vertex index (its
+// winding is kept), and faces sorted. Two meshes that differ only in vertex
+// and face order have identical canonical forms, which makes it usable as a
+// cache key. Coincident vertices should be welded first (see WeldVertices),
+// or their order depends on the input order.
+func CanonicalMesh(poly Polyhedron) Polyhedron {
+    order := make([]int, len(poly.Vertices))
+    for i := range order {
+        order[i] = i
+    }
+    sort.SliceStable(order, func(i, j int) bool {
+        return lessVector(poly.Vertices[order[i]], poly.Vertices[order[j]])
+    })
+    remap := make([]int, len(order))
+    out := Polyhedron{Name: poly.Name, Units: poly.Units, Vertices: make([]Vector3, len(order))}
+    for newIdx, oldIdx := range order {
+        remap[oldIdx] = newIdx
+        out.Vertices[newIdx] = poly.Vertices[oldIdx]
+    }
+    out.Faces = canonicalFaces(poly.Faces, func(v int) int {
+        if v < 0 || v >= len(remap) {
+            return v
+        }
+        return remap[v]
+    })
+    return out
+}
+
+// MeshesEqual reports whether a and b describe the same mesh up to vertex
+// and face order: every vertex of a has a vertex of b within tol (Euclidean
+// distance), and under that matching both have the same faces with the same
+// winding (a face may start at any of its vertices). Names are ignored. tol
+// should be well below half the shortest edge, or nearby vertices can be
+// matched the wrong way round; tol <= 0 compares exactly.
+func MeshesEqual(a, b Polyhedron, tol float64) bool {
+    if len(a.Vertices) != len(b.Vertices) || len(a.Faces) != len(b.Faces) {
+        return false
+    }
+    if tol < 0 {
+        tol = 0
+    }
+
+    // match each vertex of a to the nearest unmatched vertex of b, searching
+    // b sorted by X
+    byX := make([]int, len(b.Vertices))
+    for i := range byX {
+        byX[i] = i
+    }
+    sort.Slice(byX, func(i, j int) bool { return b.Vertices[byX[i]].X < b.Vertices[byX[j]].X })
+    used := make([]bool, len(b.Vertices))
+    match := make([]int, len(a.Vertices))
+    for i, va := range a.Vertices {
+        lo := sort.Search(len(byX), func(k int) bool { return b.Vertices[byX[k]].X >= va.X-tol })
+        best, bestD := -1, math.Inf(1)
+        for k := lo; k < len(byX) && b.Vertices[byX[k]].X <= va.X+tol; k++ {
+            j := byX[k]
+            if used[j] {
+                continue
+            }
+            if d := length(sub(va, b.Vertices[j])); d <= tol && d < bestD {
+                best, bestD = j, d
+            }
+        }
+        if best < 0 {
+            return false
+        }
+        used[best] = true
+        match[i] = best
+    }
+
+    fa := canonicalFaces(a.Faces, func(v int) int {
+        if v < 0 || v >= len(match) {
+            return -1 - v // out of range indices only equal themselves
+        }
+        return match[v]
+    })
+    fb := canonicalFaces(b.Faces, func(v int) int {
+        if v < 0 || v >= len(b.Vertices) {
+            return -1 - v
+        }
+        return v
+    })
+    for i := range fa {
+        if compareInts(fa[i].Vertices, fb[i].Vertices) != 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// canonicalFaces maps the face indices, rotates each face to start at its
+// smallest index and sorts the faces.
+func canonicalFaces(faces []Face, mapIdx func(int) int) []Face {
+    out := make([]Face, len(faces))
+    for fIdx, f := range faces {
+        n := len(f.Vertices)
+        vs := make([]int, n)
+        start := 0
+        for i, v := range f.Vertices {
+            vs[i] = mapIdx(v)
+            if vs[i] < vs[start] {
+                start = i
+            }
+        }
+        rot := make([]int, n)
+        for i := range vs {
+            rot[i] = vs[(start+i)%n]
+        }
+        out[fIdx] = Face{Vertices: rot, Holes: copyHoles(f.Holes, mapIdx)}
+    }
+    sort.Slice(out, func(i, j int) bool { return compareInts(out[i].Vertices, out[j].Vertices) < 0 })
+    return out
+}
+
+func lessVector(a, b Vector3) bool {
+    if a.X != b.X {
+        return a.X < b.X
+    }
+    if a.Y != b.Y {
+        return a.Y < b.Y
+    }
+    return a.Z < b.Z
+}
+
+// compareInts compares int slices lexicographically, shorter first on a tie.
+func compareInts(a, b []int) int {
+    for i := 0; i < len(a) && i < len(b); i++ {
+        if a[i] != b[i] {
+            if a[i] < b[i] {
+                return -1
+            }
+            return 1
+        }
+    }
+    return len(a) - len(b)
+}
@@ -0,0 +1,549 @@
+package meshio
+
+import (
+    "encoding/base64"
+    "encoding/binary"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "net/url"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// GLTFOptions controls LoadGLTF.
+type GLTFOptions struct {
+    // MergePrimitives puts all primitives of a mesh (one per material,
+    // usually) into one Polyhedron instead of one each.
+    MergePrimitives bool
+    // WeldEpsilon works as in STLOptions. Exporters split vertices wherever
+    // normals or UVs change, so they are merged back by position to get the
+    // shared edges the unfolder needs. Negative keeps them split.
+    WeldEpsilon float64
+    // Dir is where buffers stored in separate files (.bin) are read from.
+    // Empty means only embedded buffers (GLB, data: URIs) can be read.
+    Dir string
+}
+
+// LoadGLTF reads a glTF 2.0 file, JSON (.gltf) or binary (.glb), and returns
+// every triangle mesh placed in the default scene, with the node transforms
+// applied: one Polyhedron per primitive, or per mesh with MergePrimitives. A
+// mesh used by several nodes comes out once per node. Triangle strips and
+// fans are turned into triangle lists; points and lines are skipped. Faces
+// carry the TEXCOORD_0 texture coordinates if there are any. Units are
+// metres, as glTF specifies.
+func LoadGLTF(r io.Reader, opts GLTFOptions) ([]unfolder.Polyhedron, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return nil, fmt.Errorf("gltf: %v", err)
+    }
+    var jsonChunk, binChunk []byte
+    if len(data) >= 12 && string(data[:4]) == "glTF" {
+        jsonChunk, binChunk, err = splitGLB(data)
+        if err != nil {
+            return nil, err
+        }
+    } else {
+        jsonChunk = data
+    }
+    var doc gltfDoc
+    if err := json.Unmarshal(jsonChunk, &doc); err != nil {
+        return nil, fmt.Errorf("gltf: %v", err)
+    }
+    l := &gltfLoader{doc: &doc, bin: binChunk, dir: opts.Dir, buffers: make(map[int][]byte)}
+
+    // the scene's root nodes; without scenes, every node no one else owns
+    var roots []int
+    switch {
+    case len(doc.Scenes) > 0:
+        s := 0
+        if doc.Scene != nil {
+            s = *doc.Scene
+        }
+        if s < 0 || s >= len(doc.Scenes) {
+            return nil, fmt.Errorf("gltf: scene %d out of range", s)
+        }
+        roots = doc.Scenes[s].Nodes
+    case len(doc.Nodes) > 0:
+        child := make([]bool, len(doc.Nodes))
+        for _, n := range doc.Nodes {
+            for _, c := range n.Children {
+                if c >= 0 && c < len(child) {
+                    child[c] = true
+                }
+            }
+        }
+        for i := range doc.Nodes {
+            if !child[i] {
+                roots = append(roots, i)
+            }
+        }
+    default:
+        // bare meshes, no scene graph at all
+        for m := range doc.Meshes {
+            polys, err := l.mesh(m, identity4, "", opts)
+            if err != nil {
+                return nil, err
+            }
+            l.out = append(l.out, polys...)
+        }
+        return l.out, nil
+    }
+    for _, n := range roots {
+        if err := l.node(n, identity4, make(map[int]bool), opts); err != nil {
+            return nil, err
+        }
+    }
+    return l.out, nil
+}
+
+// splitGLB returns the JSON and BIN chunks of a GLB container.
+func splitGLB(data []byte) (jsonChunk, binChunk []byte, err error) {
+    if v := binary.LittleEndian.Uint32(data[4:8]); v != 2 {
+        return nil, nil, fmt.Errorf("gltf: GLB version %d not supported", v)
+    }
+    if n := int(binary.LittleEndian.Uint32(data[8:12])); n < len(data) {
+        data = data[:n]
+    }
+    for off := 12; off+8 <= len(data); {
+        n := int(binary.LittleEndian.Uint32(data[off : off+4]))
+        typ := binary.LittleEndian.Uint32(data[off+4 : off+8])
+        if n < 0 || off+8+n > len(data) {
+            return nil, nil, errors.New("gltf: GLB chunk runs past the end of the file")
+        }
+        chunk := data[off+8 : off+8+n]
+        switch typ {
+        case 0x4E4F534A: // "JSON"
+            jsonChunk = chunk
+        case 0x004E4942: // "BIN\0"
+            if binChunk == nil {
+                binChunk = chunk
+            }
+        }
+        off += 8 + n
+    }
+    if jsonChunk == nil {
+        return nil, nil, errors.New("gltf: GLB has no JSON chunk")
+    }
+    return jsonChunk, binChunk, nil
+}
+
+type gltfDoc struct {
+    Scene  *int `json:"scene"`
+    Scenes []struct {
+        Nodes []int `json:"nodes"`
+    } `json:"scenes"`
+    Nodes []struct {
+        Name        string    `json:"name"`
+        Mesh        *int      `json:"mesh"`
+        Children    []int     `json:"children"`
+        Matrix      []float64 `json:"matrix"`
+        Translation []float64 `json:"translation"`
+        Rotation    []float64 `json:"rotation"`
+        Scale       []float64 `json:"scale"`
+    } `json:"nodes"`
+    Meshes []struct {
+        Name       string `json:"name"`
+        Primitives []struct {
+            Attributes map[string]int `json:"attributes"`
+            Indices    *int           `json:"indices"`
+            Mode       *int           `json:"mode"`
+        } `json:"primitives"`
+    } `json:"meshes"`
+    Accessors []struct {
+        BufferView    *int            `json:"bufferView"`
+        ByteOffset    int             `json:"byteOffset"`
+        ComponentType int             `json:"componentType"`
+        Count         int             `json:"count"`
+        Type          string          `json:"type"`
+        Normalized    bool            `json:"normalized"`
+        Sparse        json.RawMessage `json:"sparse"`
+    } `json:"accessors"`
+    BufferViews []struct {
+        Buffer     int `json:"buffer"`
+        ByteOffset int `json:"byteOffset"`
+        ByteLength int `json:"byteLength"`
+        ByteStride int `json:"byteStride"`
+    } `json:"bufferViews"`
+    Buffers []struct {
+        URI        string `json:"uri"`
+        ByteLength int    `json:"byteLength"`
+    } `json:"buffers"`
+}
+
+// mat4 is a column-major 4x4 matrix, as glTF stores them.
+type mat4 [16]float64
+
+var identity4 = mat4{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+
+func (a mat4) mul(b mat4) mat4 {
+    var m mat4
+    for c := 0; c < 4; c++ {
+        for r := 0; r < 4; r++ {
+            for k := 0; k < 4; k++ {
+                m[c*4+r] += a[k*4+r] * b[c*4+k]
+            }
+        }
+    }
+    return m
+}
+
+func (a mat4) apply(p unfolder.Vector3) unfolder.Vector3 {
+    return unfolder.Vector3{
+        X: a[0]*p.X + a[4]*p.Y + a[8]*p.Z + a[12],
+        Y: a[1]*p.X + a[5]*p.Y + a[9]*p.Z + a[13],
+        Z: a[2]*p.X + a[6]*p.Y + a[10]*p.Z + a[14],
+    }
+}
+
+// det3 is the determinant of the linear part; negative means the transform
+// mirrors, which flips the winding.
+func (a mat4) det3() float64 {
+    return a[0]*(a[5]*a[10]-a[9]*a[6]) - a[4]*(a[1]*a[10]-a[9]*a[2]) + a[8]*(a[1]*a[6]-a[5]*a[2])
+}
+
+// trs builds translation * rotation (quaternion x, y, z, w) * scale.
+func trs(t, q, s []float64) mat4 {
+    tx, ty, tz := 0.0, 0.0, 0.0
+    if len(t) == 3 {
+        tx, ty, tz = t[0], t[1], t[2]
+    }
+    x, y, z, w := 0.0, 0.0, 0.0, 1.0
+    if len(q) == 4 {
+        x, y, z, w = q[0], q[1], q[2], q[3]
+    }
+    sx, sy, sz := 1.0, 1.0, 1.0
+    if len(s) == 3 {
+        sx, sy, sz = s[0], s[1], s[2]
+    }
+    return mat4{
+        (1 - 2*(y*y+z*z)) * sx, 2 * (x*y + z*w) * sx, 2 * (x*z - y*w) * sx, 0,
+        2 * (x*y - z*w) * sy, (1 - 2*(x*x+z*z)) * sy, 2 * (y*z + x*w) * sy, 0,
+        2 * (x*z + y*w) * sz, 2 * (y*z - x*w) * sz, (1 - 2*(x*x+y*y)) * sz, 0,
+        tx, ty, tz, 1,
+    }
+}
+
+type gltfLoader struct {
+    doc     *gltfDoc
+    bin     []byte
+    dir     string
+    buffers map[int][]byte
+    out     []unfolder.Polyhedron
+}
+
+// node adds the meshes under node n, placed by parent * the node's own
+// transform. onPath guards against malformed files with cycles.
+func (l *gltfLoader) node(n int, parent mat4, onPath map[int]bool, opts GLTFOptions) error {
+    if n < 0 || n >= len(l.doc.Nodes) {
+        return fmt.Errorf("gltf: node %d out of range", n)
+    }
+    if onPath[n] {
+        return fmt.Errorf("gltf: node %d is its own ancestor", n)
+    }
+    onPath[n] = true
+    defer delete(onPath, n)
+
+    nd := l.doc.Nodes[n]
+    local := trs(nd.Translation, nd.Rotation, nd.Scale)
+    if len(nd.Matrix) == 16 {
+        copy(local[:], nd.Matrix)
+    }
+    world := parent.mul(local)
+    if nd.Mesh != nil {
+        polys, err := l.mesh(*nd.Mesh, world, nd.Name, opts)
+        if err != nil {
+            return err
+        }
+        l.out = append(l.out, polys...)
+    }
+    for _, c := range nd.Children {
+        if err := l.node(c, world, onPath, opts); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// mesh converts the triangle primitives of mesh m, transformed by world.
+func (l *gltfLoader) mesh(m int, world mat4, nodeName string, opts GLTFOptions) ([]unfolder.Polyhedron, error) {
+    if m < 0 || m >= len(l.doc.Meshes) {
+        return nil, fmt.Errorf("gltf: mesh %d out of range", m)
+    }
+    mesh := l.doc.Meshes[m]
+    name := mesh.Name
+    if name == "" {
+        name = nodeName
+    }
+    if name == "" {
+        name = "mesh" + strconv.Itoa(m)
+    }
+    mirror := world.det3() < 0
+
+    var out []unfolder.Polyhedron
+    merged := unfolder.Polyhedron{Name: name, Units: "m"}
+    for pi, prim := range mesh.Primitives {
+        mode := 4
+        if prim.Mode != nil {
+            mode = *prim.Mode
+        }
+        if mode < 4 || mode > 6 {
+            continue // points and lines
+        }
+        posAcc, ok := prim.Attributes["POSITION"]
+        if !ok {
+            return nil, fmt.Errorf("gltf: mesh %q primitive %d has no POSITION", name, pi)
+        }
+        pos, comps, err := l.accessor(posAcc)
+        if err != nil {
+            return nil, err
+        }
+        if comps != 3 {
+            return nil, fmt.Errorf("gltf: mesh %q primitive %d: POSITION is not VEC3", name, pi)
+        }
+        nv := len(pos) / 3
+        var idx []int
+        if prim.Indices != nil {
+            vals, _, err := l.accessor(*prim.Indices)
+            if err != nil {
+                return nil, err
+            }
+            idx = make([]int, len(vals))
+            for i, v := range vals {
+                if idx[i] = int(v); idx[i] < 0 || idx[i] >= nv {
+                    return nil, fmt.Errorf("gltf: mesh %q primitive %d: index %d out of range", name, pi, idx[i])
+                }
+            }
+        } else {
+            idx = make([]int, nv)
+            for i := range idx {
+                idx[i] = i
+            }
+        }
+
+        // texture coordinates, V flipped: glTF's V runs down the image
+        var uv []float64
+        if a, ok := prim.Attributes["TEXCOORD_0"]; ok {
+            vals, comps, err := l.accessor(a)
+            if err != nil {
+                return nil, err
+            }
+            if comps == 2 && len(vals) == 2*nv {
+                uv = vals
+            }
+        }
+
+        poly := unfolder.Polyhedron{Name: name, Units: "m"}
+        if len(mesh.Primitives) > 1 && !opts.MergePrimitives {
+            poly.Name = name + "." + strconv.Itoa(pi)
+        }
+        for i := 0; i < nv; i++ {
+            p := unfolder.Vector3{X: pos[3*i], Y: pos[3*i+1], Z: pos[3*i+2]}
+            poly.Vertices = append(poly.Vertices, world.apply(p))
+        }
+        for _, t := range triangleList(idx, mode) {
+            if t[0] == t[1] || t[1] == t[2] || t[0] == t[2] {
+                continue
+            }
+            if mirror {
+                t[1], t[2] = t[2], t[1]
+            }
+            face := unfolder.Face{Vertices: []int{t[0], t[1], t[2]}}
+            if uv != nil {
+                face.UVs = make([]unfolder.Point2, 3)
+                for i, v := range t {
+                    face.UVs[i] = unfolder.Point2{X: uv[2*v], Y: 1 - uv[2*v+1]}
+                }
+            }
+            poly.Faces = append(poly.Faces, face)
+        }
+
+        if opts.MergePrimitives {
+            base := len(merged.Vertices)
+            merged.Vertices = append(merged.Vertices, poly.Vertices...)
+            for _, f := range poly.Faces {
+                for i := range f.Vertices {
+                    f.Vertices[i] += base
+                }
+                merged.Faces = append(merged.Faces, f)
+            }
+            continue
+        }
+        if opts.WeldEpsilon >= 0 {
+            poly = unfolder.WeldVertices(poly, opts.WeldEpsilon)
+        }
+        if len(poly.Faces) > 0 {
+            out = append(out, poly)
+        }
+    }
+    if opts.MergePrimitives && len(merged.Faces) > 0 {
+        if opts.WeldEpsilon >= 0 {
+            merged = unfolder.WeldVertices(merged, opts.WeldEpsilon)
+        }
+        out = append(out, merged)
+    }
+    return out, nil
+}
+
+// triangleList turns the indices of a TRIANGLES (4), TRIANGLE_STRIP (5) or
+// TRIANGLE_FAN (6) primitive into triangles.
+func triangleList(idx []int, mode int) [][3]int {
+    var tris [][3]int
+    switch mode {
+    case 4:
+        for i := 0; i+2 < len(idx); i += 3 {
+            tris = append(tris, [3]int{idx[i], idx[i+1], idx[i+2]})
+        }
+    case 5:
+        for i := 0; i+2 < len(idx); i++ {
+            if i%2 == 0 {
+                tris = append(tris, [3]int{idx[i], idx[i+1], idx[i+2]})
+            } else {
+                tris = append(tris, [3]int{idx[i+1], idx[i], idx[i+2]})
+            }
+        }
+    case 6:
+        for i := 1; i+1 < len(idx); i++ {
+            tris = append(tris, [3]int{idx[0], idx[i], idx[i+1]})
+        }
+    }
+    return tris
+}
+
+var gltfComponents = map[string]int{"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4}
+
+// accessor reads accessor a as floats, comps per element.
+func (l *gltfLoader) accessor(a int) ([]float64, int, error) {
+    if a < 0 || a >= len(l.doc.Accessors) {
+        return nil, 0, fmt.Errorf("gltf: accessor %d out of range", a)
+    }
+    acc := l.doc.Accessors[a]
+    comps, ok := gltfComponents[acc.Type]
+    if !ok {
+        return nil, 0, fmt.Errorf("gltf: accessor %d has unsupported type %q", a, acc.Type)
+    }
+    if len(acc.Sparse) > 0 {
+        return nil, 0, fmt.Errorf("gltf: accessor %d is sparse, which is not supported", a)
+    }
+    var size int
+    switch acc.ComponentType {
+    case 5120, 5121:
+        size = 1
+    case 5122, 5123:
+        size = 2
+    case 5125, 5126:
+        size = 4
+    default:
+        return nil, 0, fmt.Errorf("gltf: accessor %d has unknown component type %d", a, acc.ComponentType)
+    }
+    out := make([]float64, acc.Count*comps)
+    if acc.BufferView == nil {
+        return out, comps, nil // all zeros, per the spec
+    }
+    bv := *acc.BufferView
+    if bv < 0 || bv >= len(l.doc.BufferViews) {
+        return nil, 0, fmt.Errorf("gltf: buffer view %d out of range", bv)
+    }
+    view := l.doc.BufferViews[bv]
+    buf, err := l.buffer(view.Buffer)
+    if err != nil {
+        return nil, 0, err
+    }
+    if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset+view.ByteLength > len(buf) {
+        return nil, 0, fmt.Errorf("gltf: buffer view %d runs past its buffer", bv)
+    }
+    data := buf[view.ByteOffset : view.ByteOffset+view.ByteLength]
+    stride := view.ByteStride
+    if stride == 0 {
+        stride = comps * size
+    }
+    if acc.Count > 0 && (acc.ByteOffset < 0 || acc.ByteOffset+(acc.Count-1)*stride+comps*size > len(data)) {
+        return nil, 0, fmt.Errorf("gltf: accessor %d runs past its buffer view", a)
+    }
+    for i := 0; i < acc.Count; i++ {
+        for c := 0; c < comps; c++ {
+            b := data[acc.ByteOffset+i*stride+c*size:]
+            var v float64
+            switch acc.ComponentType {
+            case 5120:
+                v = float64(int8(b[0]))
+            case 5121:
+                v = float64(b[0])
+            case 5122:
+                v = float64(int16(binary.LittleEndian.Uint16(b)))
+            case 5123:
+                v = float64(binary.LittleEndian.Uint16(b))
+            case 5125:
+                v = float64(binary.LittleEndian.Uint32(b))
+            case 5126:
+                v = float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+            }
+            if acc.Normalized {
+                // integers standing for 0..1 (or -1..1), as in quantized UVs
+                switch acc.ComponentType {
+                case 5120:
+                    v = math.Max(v/127, -1)
+                case 5121:
+                    v /= 255
+                case 5122:
+                    v = math.Max(v/32767, -1)
+                case 5123:
+                    v /= 65535
+                }
+            }
+            out[i*comps+c] = v
+        }
+    }
+    return out, comps, nil
+}
+
+// buffer returns the bytes of buffer b: the GLB BIN chunk, a data: URI or a
+// file next to the model.
+func (l *gltfLoader) buffer(b int) ([]byte, error) {
+    if data, ok := l.buffers[b]; ok {
+        return data, nil
+    }
+    if b < 0 || b >= len(l.doc.Buffers) {
+        return nil, fmt.Errorf("gltf: buffer %d out of range", b)
+    }
+    uri := l.doc.Buffers[b].URI
+    var data []byte
+    switch {
+    case uri == "":
+        if b != 0 || l.bin == nil {
+            return nil, fmt.Errorf("gltf: buffer %d has no data", b)
+        }
+        data = l.bin
+    case strings.HasPrefix(uri, "data:"):
+        i := strings.Index(uri, ";base64,")
+        if i < 0 {
+            return nil, fmt.Errorf("gltf: buffer %d: only base64 data URIs are supported", b)
+        }
+        var err error
+        if data, err = base64.StdEncoding.DecodeString(uri[i+len(";base64,"):]); err != nil {
+            return nil, fmt.Errorf("gltf: buffer %d: %v", b, err)
+        }
+    default:
+        if l.dir == "" {
+            return nil, fmt.Errorf("gltf: buffer %d is in %q, but no directory was given to read it from", b, uri)
+        }
+        name, err := url.PathUnescape(uri)
+        if err != nil {
+            return nil, fmt.Errorf("gltf: buffer %d: %v", b, err)
+        }
+        if data, err = os.ReadFile(filepath.Join(l.dir, filepath.FromSlash(name))); err != nil {
+            return nil, fmt.Errorf("gltf: buffer %d: %v", b, err)
+        }
+    }
+    if n := l.doc.Buffers[b].ByteLength; n > 0 && n < len(data) {
+        data = data[:n]
+    }
+    l.buffers[b] = data
+    return data, nil
+}
@@ -0,0 +1,156 @@
+// Package meshio reads (and writes) mesh files into unfolder.Polyhedron values,
+// so real models can be unfolded without hand-writing vertex and face slices.
+package meshio
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+    "strconv"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// LoadOBJ reads a Wavefront OBJ mesh. Polygonal faces of any size are kept as
+// they are, with their texture coordinates ("vt") if every corner has one;
+// normals, materials, groups and other statements are skipped. Negative (relative) indices and "\" line
+// continuations are supported.
+func LoadOBJ(r io.Reader) (unfolder.Polyhedron, error) {
+    poly, _, err := LoadOBJGroups(r)
+    return poly, err
+}
+
+// LoadOBJGroups is LoadOBJ that also returns the group ("g") each face was
+// in as its region, so nets and instructions can use the model's own part
+// names. Faces before any group statement take the object ("o") name; a "g"
+// with several names uses the first.
+func LoadOBJGroups(r io.Reader) (unfolder.Polyhedron, unfolder.FaceRegions, error) {
+    var poly unfolder.Polyhedron
+    var regions unfolder.FaceRegions
+    var uvs []unfolder.Point2
+    object, group := "", ""
+    sc := bufio.NewScanner(r)
+    sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+    lineNo := 0
+    var pending string
+    for sc.Scan() {
+        lineNo++
+        line := sc.Text()
+        if strings.HasSuffix(line, "\\") {
+            pending += strings.TrimSuffix(line, "\\") + " "
+            continue
+        }
+        line = pending + line
+        pending = ""
+
+        if i := strings.IndexByte(line, '#'); i >= 0 {
+            line = line[:i]
+        }
+        fields := strings.Fields(line)
+        if len(fields) == 0 {
+            continue
+        }
+
+        switch fields[0] {
+        case "v":
+            if len(fields) < 4 {
+                return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: vertex needs 3 coordinates", lineNo)
+            }
+            var c [3]float64
+            for i := 0; i < 3; i++ {
+                f, err := strconv.ParseFloat(fields[i+1], 64)
+                if err != nil {
+                    return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: bad coordinate %q", lineNo, fields[i+1])
+                }
+                c[i] = f
+            }
+            poly.Vertices = append(poly.Vertices, unfolder.Vector3{X: c[0], Y: c[1], Z: c[2]})
+
+        case "vt":
+            // "vt u [v [w]]": v defaults to 0, w doesn't matter for a flat texture
+            if len(fields) < 2 {
+                return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: texture coordinate needs a value", lineNo)
+            }
+            var c [2]float64
+            for i := 0; i < 2 && i+1 < len(fields); i++ {
+                f, err := strconv.ParseFloat(fields[i+1], 64)
+                if err != nil {
+                    return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: bad texture coordinate %q", lineNo, fields[i+1])
+                }
+                c[i] = f
+            }
+            uvs = append(uvs, unfolder.Point2{X: c[0], Y: c[1]})
+
+        case "f":
+            if len(fields) < 4 {
+                return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: face needs at least 3 vertices", lineNo)
+            }
+            face := unfolder.Face{Vertices: make([]int, 0, len(fields)-1)}
+            faceUVs := make([]unfolder.Point2, 0, len(fields)-1)
+            for _, ref := range fields[1:] {
+                // "v", "v/vt", "v//vn" or "v/vt/vn": v and vt matter here
+                parts := strings.Split(ref, "/")
+                idx, err := objIndex(parts[0], len(poly.Vertices), "vertex")
+                if err != nil {
+                    return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: %v", lineNo, err)
+                }
+                face.Vertices = append(face.Vertices, idx)
+                if len(parts) > 1 && parts[1] != "" {
+                    t, err := objIndex(parts[1], len(uvs), "texture")
+                    if err != nil {
+                        return unfolder.Polyhedron{}, nil, fmt.Errorf("obj line %d: %v", lineNo, err)
+                    }
+                    faceUVs = append(faceUVs, uvs[t])
+                }
+            }
+            if len(faceUVs) == len(face.Vertices) {
+                face.UVs = faceUVs
+            }
+            poly.Faces = append(poly.Faces, face)
+            region := group
+            if region == "" {
+                region = object
+            }
+            regions = append(regions, region)
+
+        case "o":
+            if len(fields) > 1 {
+                object = strings.Join(fields[1:], " ")
+                group = ""
+                if poly.Name == "" {
+                    poly.Name = object
+                }
+            }
+
+        case "g":
+            group = ""
+            if len(fields) > 1 && fields[1] != "default" {
+                group = fields[1]
+            }
+        }
+    }
+    if err := sc.Err(); err != nil {
+        return unfolder.Polyhedron{}, nil, fmt.Errorf("obj: %v", err)
+    }
+    return poly, regions, nil
+}
+
+// objIndex turns a 1-based (or negative, relative to the n items so far)
+// OBJ index into a 0-based one; what names the kind of item for errors.
+func objIndex(ref string, n int, what string) (int, error) {
+    idx, err := strconv.Atoi(ref)
+    if err != nil || idx == 0 {
+        return 0, fmt.Errorf("bad %s reference %q", what, ref)
+    }
+    if idx < 0 {
+        idx = n + idx // -1 is the last one so far
+    } else {
+        idx-- // OBJ is 1-based
+    }
+    if idx < 0 || idx >= n {
+        return 0, fmt.Errorf("%s reference %q out of range", what, ref)
+    }
+    return idx, nil
+}
@@ -0,0 +1,216 @@
+package meshio
+
+import (
+    "bufio"
+    "fmt"
+    "image/color"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// OFFColors are the colours an OFF file carries, nil where it has none.
+type OFFColors struct {
+    // Vertices are the COFF vertex colours as "red", "green", "blue" and
+    // "alpha" attributes, 0-255 like LoadPLY's, so WritePLY and
+    // VertexAttributes.ForInstances take them as they are.
+    Vertices unfolder.VertexAttributes
+    // Faces has one entry per face, nil for faces without a colour (or with
+    // a colour map index, which isn't supported), ready for
+    // unfolder.PreviewOptions.FaceColors.
+    Faces []color.Color
+}
+
+// LoadOFF reads an ASCII OFF mesh (Geomview's Object File Format, as written
+// by CGAL, MeshLab and the Princeton shape benchmark). Polygonal faces are
+// kept as they are. The header keyword may be OFF with the ST, C and N
+// prefixes, or missing altogether; vertex normals are skipped, vertex
+// texture coordinates (STOFF) become the faces' UVs. Colours, on vertices
+// (COFF) or after a face's indices, are returned apart: 3 or 4 components,
+// 0-255 if any is above 1, else 0-1. Binary, 4D and nOFF files aren't
+// supported.
+func LoadOFF(r io.Reader) (unfolder.Polyhedron, OFFColors, error) {
+    var lines [][]string
+    sc := bufio.NewScanner(r)
+    sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+    for sc.Scan() {
+        line := sc.Text()
+        if i := strings.IndexByte(line, '#'); i >= 0 {
+            line = line[:i]
+        }
+        if fields := strings.Fields(line); len(fields) > 0 {
+            lines = append(lines, fields)
+        }
+    }
+    if err := sc.Err(); err != nil {
+        return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: %v", err)
+    }
+    if len(lines) == 0 {
+        return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: empty file")
+    }
+
+    // "[ST][C][N]OFF", then the counts, on the same line or the next
+    var hasUV, hasColor, hasNormal bool
+    counts := lines[0]
+    lines = lines[1:]
+    if kw := counts[0]; strings.HasSuffix(kw, "OFF") {
+        prefix := strings.TrimSuffix(kw, "OFF")
+        if strings.HasPrefix(prefix, "ST") {
+            hasUV, prefix = true, prefix[2:]
+        }
+        if strings.HasPrefix(prefix, "C") {
+            hasColor, prefix = true, prefix[1:]
+        }
+        if strings.HasPrefix(prefix, "N") {
+            hasNormal, prefix = true, prefix[1:]
+        }
+        if prefix != "" {
+            return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: unsupported header %q", kw)
+        }
+        counts = counts[1:]
+        if len(counts) > 0 && counts[0] == "BINARY" {
+            return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: binary files are not supported")
+        }
+        if len(counts) == 0 {
+            if len(lines) == 0 {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: missing vertex and face counts")
+            }
+            counts, lines = lines[0], lines[1:]
+        }
+    }
+    if len(counts) < 2 {
+        return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: bad counts line %q", strings.Join(counts, " "))
+    }
+    nv, err1 := strconv.Atoi(counts[0])
+    nf, err2 := strconv.Atoi(counts[1])
+    if err1 != nil || err2 != nil || nv < 0 || nf < 0 {
+        return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: bad counts line %q", strings.Join(counts, " "))
+    }
+    if len(lines) < nv+nf {
+        return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: %d vertices and %d faces declared, file has %d more lines", nv, nf, len(lines))
+    }
+
+    var poly unfolder.Polyhedron
+    var colors OFFColors
+    var uvs []unfolder.Point2
+    if hasColor {
+        colors.Vertices = unfolder.VertexAttributes{}
+    }
+    for i, fields := range lines[:nv] {
+        if len(fields) < 3 {
+            return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: vertex %d needs 3 coordinates", i)
+        }
+        var c [3]float64
+        for k := range c {
+            f, err := strconv.ParseFloat(fields[k], 64)
+            if err != nil {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: vertex %d: bad coordinate %q", i, fields[k])
+            }
+            c[k] = f
+        }
+        poly.Vertices = append(poly.Vertices, unfolder.Vector3{X: c[0], Y: c[1], Z: c[2]})
+
+        // the rest is [normal] [colour] [texture], each only if the header says so
+        rest := fields[3:]
+        if hasNormal {
+            if len(rest) < 3 {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: vertex %d: missing normal", i)
+            }
+            rest = rest[3:]
+        }
+        if hasUV {
+            if len(rest) < 2 {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: vertex %d: missing texture coordinates", i)
+            }
+            s, err1 := strconv.ParseFloat(rest[len(rest)-2], 64)
+            t, err2 := strconv.ParseFloat(rest[len(rest)-1], 64)
+            if err1 != nil || err2 != nil {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: vertex %d: bad texture coordinates", i)
+            }
+            uvs = append(uvs, unfolder.Point2{X: s, Y: t})
+            rest = rest[:len(rest)-2]
+        }
+        if hasColor {
+            rgba, ok, err := offColor(rest)
+            if err != nil || !ok {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: vertex %d: bad colour %q", i, strings.Join(rest, " "))
+            }
+            for k, name := range []string{"red", "green", "blue", "alpha"} {
+                colors.Vertices[name] = append(colors.Vertices[name], rgba[k])
+            }
+        }
+    }
+
+    for i, fields := range lines[nv : nv+nf] {
+        n, err := strconv.Atoi(fields[0])
+        if err != nil || n < 0 || len(fields) < n+1 {
+            return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: face %d: bad vertex count %q", i, fields[0])
+        }
+        if n < 3 {
+            return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: face %d has %d vertices", i, n)
+        }
+        face := unfolder.Face{Vertices: make([]int, n)}
+        for k := range face.Vertices {
+            v, err := strconv.Atoi(fields[k+1])
+            if err != nil || v < 0 || v >= nv {
+                return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: face %d: vertex %q out of range", i, fields[k+1])
+            }
+            face.Vertices[k] = v
+        }
+        if hasUV {
+            face.UVs = make([]unfolder.Point2, n)
+            for k, v := range face.Vertices {
+                face.UVs[k] = uvs[v]
+            }
+        }
+        poly.Faces = append(poly.Faces, face)
+
+        rgba, ok, err := offColor(fields[n+1:])
+        if err != nil {
+            return unfolder.Polyhedron{}, OFFColors{}, fmt.Errorf("off: face %d: bad colour %q", i, strings.Join(fields[n+1:], " "))
+        }
+        if ok {
+            if colors.Faces == nil {
+                colors.Faces = make([]color.Color, nf)
+            }
+            colors.Faces[i] = color.NRGBA{R: uint8(rgba[0]), G: uint8(rgba[1]), B: uint8(rgba[2]), A: uint8(rgba[3])}
+        }
+    }
+    return poly, colors, nil
+}
+
+// offColor reads an OFF colour, 3 or 4 components (alpha defaults to opaque),
+// into 0-255. Files write either 0-255 or 0-1, and don't say which: a
+// component above 1 means 0-255, otherwise it's 0-1 ("1 0 0" is red). No
+// fields, or a single colour map index, gives ok false.
+func offColor(fields []string) (rgba [4]float64, ok bool, err error) {
+    switch len(fields) {
+    case 0, 1:
+        return rgba, false, nil
+    case 3, 4:
+    default:
+        return rgba, false, fmt.Errorf("%d colour components", len(fields))
+    }
+    var c [4]float64
+    scale := 255.0
+    for k, f := range fields {
+        x, err := strconv.ParseFloat(f, 64)
+        if err != nil {
+            return rgba, false, err
+        }
+        if x > 1 {
+            scale = 1
+        }
+        c[k] = x
+    }
+    if len(fields) == 3 {
+        c[3] = 255 / scale
+    }
+    for k, x := range c {
+        rgba[k] = math.Round(math.Max(0, math.Min(255, x*scale)))
+    }
+    return rgba, true, nil
+}
@@ -0,0 +1,343 @@
+package meshio
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// PLYOptions controls WritePLY.
+type PLYOptions struct {
+    // Binary writes binary little-endian instead of ASCII.
+    Binary bool
+}
+
+// plySizes is the byte size of every PLY scalar type, by both of its names.
+var plySizes = map[string]int{
+    "char": 1, "int8": 1, "uchar": 1, "uint8": 1,
+    "short": 2, "int16": 2, "ushort": 2, "uint16": 2,
+    "int": 4, "int32": 4, "uint": 4, "uint32": 4,
+    "float": 4, "float32": 4, "double": 8, "float64": 8,
+}
+
+// plyColors are the vertex properties written as uchar, as other tools
+// expect for colours.
+var plyColors = map[string]bool{"red": true, "green": true, "blue": true, "alpha": true}
+
+type plyProperty struct {
+    name      string
+    typ       string
+    countType string // list length type, "" for a scalar
+}
+
+type plyElement struct {
+    name  string
+    count int
+    props []plyProperty
+}
+
+// LoadPLY reads an ASCII or binary little-endian PLY mesh. Polygonal faces
+// are kept as they are. Vertex properties other than x, y and z (colours,
+// normals, texture coordinates, ...) are returned as attributes, so they can
+// be carried over to the net (see VertexAttributes.ForInstances). Other
+// elements and face properties are skipped.
+func LoadPLY(r io.Reader) (unfolder.Polyhedron, unfolder.VertexAttributes, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: %v", err)
+    }
+    format, elements, body, err := parsePLYHeader(data)
+    if err != nil {
+        return unfolder.Polyhedron{}, nil, err
+    }
+
+    var next func(typ string) (float64, error)
+    switch format {
+    case "ascii":
+        fields := bytes.Fields(body)
+        i := 0
+        next = func(string) (float64, error) {
+            if i >= len(fields) {
+                return 0, io.ErrUnexpectedEOF
+            }
+            f, err := strconv.ParseFloat(string(fields[i]), 64)
+            if err != nil {
+                return 0, fmt.Errorf("bad number %q", fields[i])
+            }
+            i++
+            return f, nil
+        }
+    case "binary_little_endian":
+        off := 0
+        next = func(typ string) (float64, error) {
+            n := plySizes[typ]
+            if off+n > len(body) {
+                return 0, io.ErrUnexpectedEOF
+            }
+            b := body[off : off+n]
+            off += n
+            switch typ {
+            case "char", "int8":
+                return float64(int8(b[0])), nil
+            case "uchar", "uint8":
+                return float64(b[0]), nil
+            case "short", "int16":
+                return float64(int16(binary.LittleEndian.Uint16(b))), nil
+            case "ushort", "uint16":
+                return float64(binary.LittleEndian.Uint16(b)), nil
+            case "int", "int32":
+                return float64(int32(binary.LittleEndian.Uint32(b))), nil
+            case "uint", "uint32":
+                return float64(binary.LittleEndian.Uint32(b)), nil
+            case "float", "float32":
+                return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+            default:
+                return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+            }
+        }
+    default:
+        return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: unsupported format %q", format)
+    }
+
+    var poly unfolder.Polyhedron
+    attrs := unfolder.VertexAttributes{}
+    for _, el := range elements {
+        for k := 0; k < el.count; k++ {
+            var v unfolder.Vector3
+            var face []int
+            for _, p := range el.props {
+                if p.countType != "" {
+                    n, err := next(p.countType)
+                    if err != nil {
+                        return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: %s %d: %v", el.name, k, err)
+                    }
+                    list := make([]int, int(n))
+                    for j := range list {
+                        x, err := next(p.typ)
+                        if err != nil {
+                            return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: %s %d: %v", el.name, k, err)
+                        }
+                        list[j] = int(x)
+                    }
+                    if el.name == "face" && (p.name == "vertex_indices" || p.name == "vertex_index") {
+                        face = list
+                    }
+                    continue
+                }
+                x, err := next(p.typ)
+                if err != nil {
+                    return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: %s %d: %v", el.name, k, err)
+                }
+                if el.name != "vertex" {
+                    continue
+                }
+                switch p.name {
+                case "x":
+                    v.X = x
+                case "y":
+                    v.Y = x
+                case "z":
+                    v.Z = x
+                default:
+                    attrs[p.name] = append(attrs[p.name], x)
+                }
+            }
+            switch el.name {
+            case "vertex":
+                poly.Vertices = append(poly.Vertices, v)
+            case "face":
+                for _, vi := range face {
+                    if vi < 0 || vi >= len(poly.Vertices) {
+                        return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: face %d: vertex %d out of range", k, vi)
+                    }
+                }
+                if len(face) < 3 {
+                    return unfolder.Polyhedron{}, nil, fmt.Errorf("ply: face %d has %d vertices", k, len(face))
+                }
+                poly.Faces = append(poly.Faces, unfolder.Face{Vertices: face})
+            }
+        }
+    }
+    return poly, attrs, nil
+}
+
+// parsePLYHeader reads the header up to end_header and returns the format,
+// the elements in file order and the data after the header.
+func parsePLYHeader(data []byte) (string, []plyElement, []byte, error) {
+    var format string
+    var elements []plyElement
+    hasVertex, hasXYZ := false, 0
+    rest := data
+    for lineNo := 1; ; lineNo++ {
+        i := bytes.IndexByte(rest, '\n')
+        if i < 0 {
+            return "", nil, nil, fmt.Errorf("ply: header has no end_header")
+        }
+        line := strings.TrimRight(string(rest[:i]), "\r")
+        rest = rest[i+1:]
+        fields := strings.Fields(line)
+        if lineNo == 1 {
+            if line != "ply" {
+                return "", nil, nil, fmt.Errorf("ply: not a PLY file")
+            }
+            continue
+        }
+        if len(fields) == 0 {
+            continue
+        }
+        switch fields[0] {
+        case "format":
+            if len(fields) < 2 {
+                return "", nil, nil, fmt.Errorf("ply line %d: format needs a type", lineNo)
+            }
+            format = fields[1]
+        case "element":
+            if len(fields) < 3 {
+                return "", nil, nil, fmt.Errorf("ply line %d: element needs a name and a count", lineNo)
+            }
+            n, err := strconv.Atoi(fields[2])
+            if err != nil || n < 0 {
+                return "", nil, nil, fmt.Errorf("ply line %d: bad element count %q", lineNo, fields[2])
+            }
+            elements = append(elements, plyElement{name: fields[1], count: n})
+            hasVertex = hasVertex || fields[1] == "vertex"
+        case "property":
+            if len(elements) == 0 {
+                return "", nil, nil, fmt.Errorf("ply line %d: property before any element", lineNo)
+            }
+            var p plyProperty
+            switch {
+            case len(fields) == 5 && fields[1] == "list":
+                p = plyProperty{name: fields[4], typ: fields[3], countType: fields[2]}
+                if _, ok := plySizes[p.countType]; !ok {
+                    return "", nil, nil, fmt.Errorf("ply line %d: unknown type %q", lineNo, p.countType)
+                }
+            case len(fields) == 3:
+                p = plyProperty{name: fields[2], typ: fields[1]}
+            default:
+                return "", nil, nil, fmt.Errorf("ply line %d: bad property %q", lineNo, line)
+            }
+            if _, ok := plySizes[p.typ]; !ok {
+                return "", nil, nil, fmt.Errorf("ply line %d: unknown type %q", lineNo, p.typ)
+            }
+            el := &elements[len(elements)-1]
+            el.props = append(el.props, p)
+            if el.name == "vertex" && p.countType == "" && (p.name == "x" || p.name == "y" || p.name == "z") {
+                hasXYZ++
+            }
+        case "end_header":
+            if format == "" {
+                return "", nil, nil, fmt.Errorf("ply: header has no format")
+            }
+            if !hasVertex || hasXYZ < 3 {
+                return "", nil, nil, fmt.Errorf("ply: no vertex element with x, y and z")
+            }
+            return format, elements, rest, nil
+        }
+        // comment, obj_info and anything unknown are skipped
+    }
+}
+
+// WritePLY writes poly as a PLY file, ASCII unless opts.Binary, with attrs
+// (may be nil) as extra vertex properties. Colour channels (red, green, blue,
+// alpha) are written as uchar, other attributes as float.
+func WritePLY(w io.Writer, poly unfolder.Polyhedron, attrs unfolder.VertexAttributes, opts PLYOptions) error {
+    if err := attrs.Check(poly); err != nil {
+        return fmt.Errorf("ply: %v", err)
+    }
+    names := attrs.Names()
+    countType := "uchar"
+    for _, f := range poly.Faces {
+        if len(f.Vertices) > 255 {
+            countType = "int"
+        }
+    }
+
+    bw := bufio.NewWriter(w)
+    format := "ascii"
+    if opts.Binary {
+        format = "binary_little_endian"
+    }
+    fmt.Fprintf(bw, "ply\nformat %s 1.0\n", format)
+    if poly.Name != "" {
+        fmt.Fprintf(bw, "comment %s\n", strings.ReplaceAll(poly.Name, "\n", " "))
+    }
+    fmt.Fprintf(bw, "element vertex %d\n", len(poly.Vertices))
+    fmt.Fprintf(bw, "property double x\nproperty double y\nproperty double z\n")
+    types := make([]string, len(names))
+    for i, name := range names {
+        types[i] = "float"
+        if plyColors[name] {
+            types[i] = "uchar"
+        }
+        fmt.Fprintf(bw, "property %s %s\n", types[i], name)
+    }
+    fmt.Fprintf(bw, "element face %d\n", len(poly.Faces))
+    fmt.Fprintf(bw, "property list %s int vertex_indices\n", countType)
+    fmt.Fprintf(bw, "end_header\n")
+
+    // put writes one value, with a separator first in ASCII
+    put := func(typ string, x float64, first bool) {
+        if !opts.Binary {
+            if !first {
+                bw.WriteByte(' ')
+            }
+            if typ == "float" || typ == "double" {
+                bw.WriteString(strconv.FormatFloat(x, 'g', -1, 64))
+            } else {
+                bw.WriteString(strconv.FormatInt(int64(x), 10))
+            }
+            return
+        }
+        var b [8]byte
+        switch typ {
+        case "uchar":
+            bw.WriteByte(byte(x))
+        case "int":
+            binary.LittleEndian.PutUint32(b[:], uint32(int32(x)))
+            bw.Write(b[:4])
+        case "float":
+            binary.LittleEndian.PutUint32(b[:], math.Float32bits(float32(x)))
+            bw.Write(b[:4])
+        default:
+            binary.LittleEndian.PutUint64(b[:], math.Float64bits(x))
+            bw.Write(b[:])
+        }
+    }
+    endLine := func() {
+        if !opts.Binary {
+            bw.WriteByte('\n')
+        }
+    }
+    for i, v := range poly.Vertices {
+        put("double", v.X, true)
+        put("double", v.Y, false)
+        put("double", v.Z, false)
+        for k, name := range names {
+            x := attrs[name][i]
+            if types[k] == "uchar" {
+                x = math.Max(0, math.Min(255, math.Round(x)))
+            }
+            put(types[k], x, false)
+        }
+        endLine()
+    }
+    for _, f := range poly.Faces {
+        put(countType, float64(len(f.Vertices)), true)
+        for _, vi := range f.Vertices {
+            put("int", float64(vi), false)
+        }
+        endLine()
+    }
+    if err := bw.Flush(); err != nil {
+        return fmt.Errorf("ply: %v", err)
+    }
+    return nil
+}
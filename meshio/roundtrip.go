@@ -0,0 +1,45 @@
+package meshio
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+
+    "github.com/yourusername/unfolder"
+)
+
+// RoundTrip is how far a net folded back up and read from STL ends up from
+// the mesh it was unfolded from (see unfolder.MeshDistance; Forward is from
+// the mesh to the folded model).
+type RoundTrip struct {
+    Faces int `json:"faces"` // faces folded back
+    unfolder.DistanceReport
+}
+
+// VerifyRoundTrip checks the whole pipeline end to end: it folds result back
+// to 3D (see unfolder.FoldNet), writes that as binary STL, reads the STL back
+// and measures it against poly. If stl isn't nil it also gets the STL file.
+// A correct unfold comes back within float32 rounding, about 1e-7 of Size.
+func VerifyRoundTrip(poly unfolder.Polyhedron, result *unfolder.UnfoldResult, stl io.Writer) (RoundTrip, error) {
+    var rt RoundTrip
+    folded, err := unfolder.FoldNet(poly, result)
+    if err != nil {
+        return rt, err
+    }
+    var buf bytes.Buffer
+    w := io.Writer(&buf)
+    if stl != nil {
+        w = io.MultiWriter(&buf, stl)
+    }
+    if err := WriteSTL(w, folded); err != nil {
+        return rt, err
+    }
+    back, err := LoadSTL(&buf, STLOptions{WeldEpsilon: -1})
+    if err != nil {
+        return rt, fmt.Errorf("reading the STL back: %v", err)
+    }
+
+    rt.Faces = len(folded.Faces)
+    rt.DistanceReport, err = unfolder.MeshDistance(poly, back, unfolder.DistanceOptions{})
+    return rt, err
+}
@@ -0,0 +1,195 @@
+package meshio
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/binary"
+    "fmt"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+
+    "github.com/yourusername/unfolder"
+)
+
+// STLOptions controls LoadSTL.
+type STLOptions struct {
+    // WeldEpsilon merges vertices closer than this. 0 merges exact duplicates
+    // only, which is what most exporters write; raise it for meshes with
+    // rounding noise. Negative disables welding (every triangle stays separate).
+    WeldEpsilon float64
+}
+
+// LoadSTL reads a binary or ASCII STL file and welds the per-triangle
+// vertices back into a shared-vertex mesh.
+func LoadSTL(r io.Reader, opts STLOptions) (unfolder.Polyhedron, error) {
+    data, err := io.ReadAll(r)
+    if err != nil {
+        return unfolder.Polyhedron{}, fmt.Errorf("stl: %v", err)
+    }
+
+    var poly unfolder.Polyhedron
+    if isBinarySTL(data) {
+        poly, err = parseBinarySTL(data)
+    } else {
+        poly, err = parseASCIISTL(data)
+    }
+    if err != nil {
+        return unfolder.Polyhedron{}, err
+    }
+    if opts.WeldEpsilon >= 0 {
+        poly = unfolder.WeldVertices(poly, opts.WeldEpsilon)
+    }
+    return poly, nil
+}
+
+// isBinarySTL decides by size: binary files are exactly 84 + 50*n bytes.
+// Checking "solid" alone isn't enough, plenty of binary exporters start their
+// header with it.
+func isBinarySTL(data []byte) bool {
+    if len(data) < 84 {
+        return false
+    }
+    n := binary.LittleEndian.Uint32(data[80:84])
+    return uint64(len(data)) == 84+50*uint64(n)
+}
+
+func parseBinarySTL(data []byte) (unfolder.Polyhedron, error) {
+    n := int(binary.LittleEndian.Uint32(data[80:84]))
+    poly := unfolder.Polyhedron{
+        Name:     strings.TrimSpace(strings.TrimPrefix(string(bytes.TrimRight(data[:80], "\x00 ")), "solid")),
+        Vertices: make([]unfolder.Vector3, 0, 3*n),
+        Faces:    make([]unfolder.Face, 0, n),
+    }
+    f32 := func(b []byte) float64 {
+        return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+    }
+    for t := 0; t < n; t++ {
+        rec := data[84+50*t : 84+50*(t+1)]
+        // rec[0:12] is the facet normal, recomputed from the winding instead
+        base := len(poly.Vertices)
+        for k := 0; k < 3; k++ {
+            o := 12 + 12*k
+            poly.Vertices = append(poly.Vertices, unfolder.Vector3{X: f32(rec[o:]), Y: f32(rec[o+4:]), Z: f32(rec[o+8:])})
+        }
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{base, base + 1, base + 2}})
+    }
+    return poly, nil
+}
+
+func parseASCIISTL(data []byte) (unfolder.Polyhedron, error) {
+    var poly unfolder.Polyhedron
+    sc := bufio.NewScanner(bytes.NewReader(data))
+    sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+    lineNo := 0
+    var loop []int
+    sawSolid := false
+    for sc.Scan() {
+        lineNo++
+        fields := strings.Fields(sc.Text())
+        if len(fields) == 0 {
+            continue
+        }
+        switch strings.ToLower(fields[0]) {
+        case "solid":
+            sawSolid = true
+            if poly.Name == "" && len(fields) > 1 {
+                poly.Name = strings.Join(fields[1:], " ")
+            }
+        case "outer":
+            loop = loop[:0]
+        case "vertex":
+            if len(fields) < 4 {
+                return unfolder.Polyhedron{}, fmt.Errorf("stl line %d: vertex needs 3 coordinates", lineNo)
+            }
+            var c [3]float64
+            for i := 0; i < 3; i++ {
+                f, err := strconv.ParseFloat(fields[i+1], 64)
+                if err != nil {
+                    return unfolder.Polyhedron{}, fmt.Errorf("stl line %d: bad coordinate %q", lineNo, fields[i+1])
+                }
+                c[i] = f
+            }
+            loop = append(loop, len(poly.Vertices))
+            poly.Vertices = append(poly.Vertices, unfolder.Vector3{X: c[0], Y: c[1], Z: c[2]})
+        case "endloop":
+            if len(loop) < 3 {
+                return unfolder.Polyhedron{}, fmt.Errorf("stl line %d: facet with %d vertices", lineNo, len(loop))
+            }
+            poly.Faces = append(poly.Faces, unfolder.Face{Vertices: append([]int(nil), loop...)})
+        }
+    }
+    if err := sc.Err(); err != nil {
+        return unfolder.Polyhedron{}, fmt.Errorf("stl: %v", err)
+    }
+    if !sawSolid {
+        return unfolder.Polyhedron{}, fmt.Errorf("stl: neither a binary STL nor an ASCII one starting with \"solid\"")
+    }
+    return poly, nil
+}
+
+// WriteSTL writes poly as a binary STL file. Faces are split into triangles
+// (concave and non-planar ones by ear clipping, the rest as fans) and the
+// facet normals are computed from the winding.
+func WriteSTL(w io.Writer, poly unfolder.Polyhedron) error {
+    tri, _, err := unfolder.Triangulate(poly, 0)
+    if err != nil {
+        return fmt.Errorf("stl: %v", err)
+    }
+    var facets [][3]unfolder.Vector3
+    for _, f := range tri.Faces {
+        for i := 1; i+1 < len(f.Vertices); i++ {
+            facets = append(facets, [3]unfolder.Vector3{
+                tri.Vertices[f.Vertices[0]], tri.Vertices[f.Vertices[i]], tri.Vertices[f.Vertices[i+1]],
+            })
+        }
+    }
+
+    bw := bufio.NewWriter(w)
+    header := make([]byte, 80)
+    // no "solid" at the start: some readers would take the file for ASCII
+    copy(header, poly.Name)
+    bw.Write(header)
+    binary.Write(bw, binary.LittleEndian, uint32(len(facets)))
+    rec := make([]byte, 50)
+    put := func(o int, v unfolder.Vector3) {
+        binary.LittleEndian.PutUint32(rec[o:], math.Float32bits(float32(v.X)))
+        binary.LittleEndian.PutUint32(rec[o+4:], math.Float32bits(float32(v.Y)))
+        binary.LittleEndian.PutUint32(rec[o+8:], math.Float32bits(float32(v.Z)))
+    }
+    for _, t := range facets {
+        n := cross(sub(t[1], t[0]), sub(t[2], t[0]))
+        if l := math.Sqrt(dot(n, n)); l > 0 {
+            n = scale(n, 1/l)
+        }
+        put(0, n)
+        for k := 0; k < 3; k++ {
+            put(12+12*k, t[k])
+        }
+        if _, err := bw.Write(rec); err != nil {
+            return fmt.Errorf("stl: %v", err)
+        }
+    }
+    if err := bw.Flush(); err != nil {
+        return fmt.Errorf("stl: %v", err)
+    }
+    return nil
+}
+
+func sub(a, b unfolder.Vector3) unfolder.Vector3 {
+    return unfolder.Vector3{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}
+}
+
+func scale(a unfolder.Vector3, s float64) unfolder.Vector3 {
+    return unfolder.Vector3{X: a.X * s, Y: a.Y * s, Z: a.Z * s}
+}
+
+func dot(a, b unfolder.Vector3) float64 {
+    return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func cross(a, b unfolder.Vector3) unfolder.Vector3 {
+    return unfolder.Vector3{X: a.Y*b.Z - a.Z*b.Y, Y: a.Z*b.X - a.X*b.Z, Z: a.X*b.Y - a.Y*b.X}
+}
+
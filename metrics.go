@@ -0,0 +1,75 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Net metrics
+// -----------------------------
+
+// NetMetrics sums up the size of a net, in net units, for comparing candidate
+// unfoldings and fitting them to paper.
+type NetMetrics struct {
+    Min         Point2  `json:"min"` // bounding box, tabs and double walls included
+    Max         Point2  `json:"max"`
+    Width       float64 `json:"width"`
+    Height      float64 `json:"height"`
+    BoxArea     float64 `json:"boxArea"`     // Width * Height
+    AspectRatio float64 `json:"aspectRatio"` // long side / short side, 0 for an empty net
+    Area        float64 `json:"area"`        // total area of the placed faces
+    Perimeter   float64 `json:"perimeter"`   // outline of the faces: both sides of every cut
+    CutLength   float64 `json:"cutLength"`   // every cut edge once, i.e. the seams of the model
+    FoldLength  float64 `json:"foldLength"`
+    Faces       int     `json:"faces"` // placed faces
+}
+
+// ComputeNetMetrics measures result. Edge lengths are taken from Face2D, so
+// no mesh is needed.
+func ComputeNetMetrics(result *UnfoldResult) NetMetrics {
+    var m NetMetrics
+    if result == nil {
+        return m
+    }
+    edgeLen := func(face, edge int) (float64, bool) {
+        if face < 0 || face >= len(result.Face2D) {
+            return 0, false
+        }
+        pts := result.Face2D[face].Vertices
+        if len(pts) < 3 || edge < 0 || edge >= len(pts) {
+            return 0, false
+        }
+        a, b := pts[edge], pts[(edge+1)%len(pts)]
+        return math.Hypot(b.X-a.X, b.Y-a.Y), true
+    }
+
+    if lo, hi, ok := sheetBounds(result, nil); ok {
+        m.Min, m.Max = lo, hi
+        m.Width, m.Height = hi.X-lo.X, hi.Y-lo.Y
+        m.BoxArea = m.Width * m.Height
+        if short := math.Min(m.Width, m.Height); short > 0 {
+            m.AspectRatio = math.Max(m.Width, m.Height) / short
+        }
+    }
+    for _, f := range result.Face2D {
+        if len(f.Vertices) >= 3 {
+            m.Area += math.Abs(polygonArea(f.Vertices))
+            m.Faces++
+        }
+    }
+    for _, e := range result.CutEdges {
+        l, okA := edgeLen(e.FaceA, e.EdgeA)
+        m.Perimeter += l
+        if lb, ok := edgeLen(e.FaceB, e.EdgeB); ok {
+            m.Perimeter += lb
+            if !okA {
+                l = lb
+            }
+        }
+        m.CutLength += l
+    }
+    for _, e := range result.FoldEdges {
+        if l, ok := edgeLen(e.FaceA, e.EdgeA); ok {
+            m.FoldLength += l
+        }
+    }
+    return m
+}
@@ -0,0 +1,171 @@
+package unfolder
+
+import (
+    "encoding/json"
+    "fmt"
+    "io"
+)
+
+// -----------------------------
+//   Net file schema migration
+// -----------------------------
+
+// netFileMigration upgrades a decoded net file document from version N to N+1
+// in place. Documents are handled as raw JSON objects so a migration only has
+// to know about the fields it changes.
+type netFileMigration func(doc map[string]json.RawMessage) error
+
+// netFileMigrations maps "from" version -> migration to the next version.
+// When the schema changes, bump NetFileVersion and add an entry here; never
+// edit an existing migration, archived files depend on it.
+var netFileMigrations = map[int]netFileMigration{
+    0: migrateNetFileV0,
+    1: migrateNetFileV1,
+    2: migrateNetFileV2,
+}
+
+// migrateNetFile brings doc up to NetFileVersion and returns the version it
+// started at.
+func migrateNetFile(doc map[string]json.RawMessage) (int, error) {
+    version := 0
+    if raw, ok := doc["version"]; ok {
+        if err := json.Unmarshal(raw, &version); err != nil {
+            return 0, fmt.Errorf("bad version field: %v", err)
+        }
+    } else if _, ok := doc["format"]; ok {
+        return 0, fmt.Errorf("net file has a format but no version")
+    }
+    from := version
+    if version > NetFileVersion {
+        return from, fmt.Errorf("net file version %d is newer than supported version %d", version, NetFileVersion)
+    }
+    for version < NetFileVersion {
+        m, ok := netFileMigrations[version]
+        if !ok {
+            return from, fmt.Errorf("no migration from net file version %d", version)
+        }
+        if err := m(doc); err != nil {
+            return from, fmt.Errorf("migrating net file from version %d: %v", version, err)
+        }
+        version++
+    }
+    return from, nil
+}
+
+// MigrateNetFile reads a net file of any supported version and writes it back
+// in the current version. It returns the version the input was in, so archive
+// tools can tell which files actually changed.
+func MigrateNetFile(r io.Reader, w io.Writer) (int, error) {
+    nf, err := ReadNetFile(r)
+    if err != nil {
+        return 0, err
+    }
+    return nf.MigratedFrom, WriteNetFile(w, nf)
+}
+
+// migrateNetFileV0 upgrades "version 0" files: plain json.Marshal dumps of an
+// UnfoldResult, written by callers before the .unfold format existed.
+func migrateNetFileV0(doc map[string]json.RawMessage) error {
+    var legacy struct {
+        Vertex2D     []Point2
+        Face2D       []Face2D
+        SpanningTree []int
+    }
+    raw, err := json.Marshal(doc)
+    if err != nil {
+        return err
+    }
+    if err := json.Unmarshal(raw, &legacy); err != nil {
+        return err
+    }
+    if legacy.SpanningTree == nil {
+        return fmt.Errorf("not a net file (no version and no SpanningTree)")
+    }
+
+    root := -1
+    for f, p := range legacy.SpanningTree {
+        if p < 0 {
+            root = f
+            break
+        }
+    }
+    vertex2D := make([][2]float64, len(legacy.Vertex2D))
+    for i, p := range legacy.Vertex2D {
+        vertex2D[i] = [2]float64{p.X, p.Y}
+    }
+    face2D := make([][][2]float64, len(legacy.Face2D))
+    for i, f := range legacy.Face2D {
+        pts := make([][2]float64, len(f.Vertices))
+        for j, p := range f.Vertices {
+            pts[j] = [2]float64{p.X, p.Y}
+        }
+        face2D[i] = pts
+    }
+
+    // legacy dumps never recorded the mesh, so the hash stays empty (unknown)
+    upgraded := map[string]interface{}{
+        "format":       NetFileFormat,
+        "version":      1,
+        "mesh":         MeshRef{},
+        "options":      UnfoldOptions{},
+        "rootFace":     root,
+        "spanningTree": legacy.SpanningTree,
+        "vertex2D":     vertex2D,
+        "face2D":       face2D,
+    }
+    for k := range doc {
+        delete(doc, k)
+    }
+    for k, v := range upgraded {
+        b, err := json.Marshal(v)
+        if err != nil {
+            return err
+        }
+        doc[k] = b
+    }
+    return nil
+}
+
+// migrateNetFileV1 replaces the v1 "vertex2D" array (one position per mesh
+// vertex, wrong for vertices on cuts) with "faceVertices", the mesh vertex of
+// every face corner. That is only known if the mesh was embedded; otherwise
+// the corners get -1.
+func migrateNetFileV1(doc map[string]json.RawMessage) error {
+    var face2D [][][2]float64
+    if err := json.Unmarshal(doc["face2D"], &face2D); err != nil {
+        return fmt.Errorf("bad face2D: %v", err)
+    }
+    var mesh MeshRef
+    if raw, ok := doc["mesh"]; ok {
+        if err := json.Unmarshal(raw, &mesh); err != nil {
+            return fmt.Errorf("bad mesh: %v", err)
+        }
+    }
+    faceVertices := make([][]int, len(face2D))
+    for f, pts := range face2D {
+        vs := make([]int, len(pts))
+        for i := range vs {
+            vs[i] = -1
+            if f < len(mesh.Faces) && i < len(mesh.Faces[f]) {
+                vs[i] = mesh.Faces[f][i]
+            }
+        }
+        faceVertices[f] = vs
+    }
+    raw, err := json.Marshal(faceVertices)
+    if err != nil {
+        return err
+    }
+    delete(doc, "vertex2D")
+    doc["faceVertices"] = raw
+    doc["version"] = json.RawMessage("2")
+    return nil
+}
+
+// migrateNetFileV2 only bumps the version: v3 added optional fields v2
+// files go without: the "holes" and "uvs" of the net and of the embedded
+// mesh, and the "splitMesh" and "faceOrigin" of nets of split meshes.
+func migrateNetFileV2(doc map[string]json.RawMessage) error {
+    doc["version"] = json.RawMessage("3")
+    return nil
+}
@@ -0,0 +1,128 @@
+package unfolder
+
+import (
+    "bytes"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestReadNetFileMigrates(t *testing.T) {
+    // the same two-triangle net in every schema version
+    tests := []struct {
+        name         string
+        doc          string
+        from         int
+        root         int
+        faceVertices [][]int
+    }{
+        {
+            name: "v0 UnfoldResult dump",
+            doc: `{"Vertex2D": [{"X": 0, "Y": 0}, {"X": 1, "Y": 0}, {"X": 0, "Y": 1}, {"X": 1, "Y": 1}],
+                "Face2D": [{"Vertices": [{"X": 0, "Y": 0}, {"X": 1, "Y": 0}, {"X": 0, "Y": 1}]},
+                           {"Vertices": [{"X": 1, "Y": 0}, {"X": 1, "Y": 1}, {"X": 0, "Y": 1}]}],
+                "SpanningTree": [-1, 0]}`,
+            from:         0,
+            root:         0,
+            faceVertices: [][]int{{-1, -1, -1}, {-1, -1, -1}},
+        },
+        {
+            name: "v1 without mesh",
+            doc: `{"format": "go-unfold/net", "version": 1, "mesh": {"hash": ""}, "options": {}, "rootFace": 1,
+                "spanningTree": [1, -1], "vertex2D": [[0, 0], [1, 0], [0, 1], [1, 1]],
+                "face2D": [[[0, 0], [1, 0], [0, 1]], [[1, 0], [1, 1], [0, 1]]]}`,
+            from:         1,
+            root:         1,
+            faceVertices: [][]int{{-1, -1, -1}, {-1, -1, -1}},
+        },
+        {
+            name: "v1 with embedded mesh",
+            doc: `{"format": "go-unfold/net", "version": 1, "options": {}, "rootFace": 0,
+                "mesh": {"hash": "x", "vertices": [[0, 0, 0], [1, 0, 0], [0, 1, 0], [1, 1, 0]], "faces": [[0, 1, 2], [1, 3, 2]]},
+                "spanningTree": [-1, 0], "vertex2D": [[0, 0], [1, 0], [0, 1], [1, 1]],
+                "face2D": [[[0, 0], [1, 0], [0, 1]], [[1, 0], [1, 1], [0, 1]]]}`,
+            from:         1,
+            root:         0,
+            faceVertices: [][]int{{0, 1, 2}, {1, 3, 2}},
+        },
+        {
+            name: "v2",
+            doc: `{"format": "go-unfold/net", "version": 2, "mesh": {"hash": "x"}, "options": {}, "rootFace": 0,
+                "spanningTree": [-1, 0], "faceVertices": [[0, 1, 2], [1, 3, 2]],
+                "face2D": [[[0, 0], [1, 0], [0, 1]], [[1, 0], [1, 1], [0, 1]]]}`,
+            from:         2,
+            root:         0,
+            faceVertices: [][]int{{0, 1, 2}, {1, 3, 2}},
+        },
+        {
+            name: "current",
+            doc: `{"format": "go-unfold/net", "version": 3, "mesh": {"hash": "x"}, "options": {}, "rootFace": 0,
+                "spanningTree": [-1, 0], "faceVertices": [[0, 1, 2], [1, 3, 2]],
+                "face2D": [[[0, 0], [1, 0], [0, 1]], [[1, 0], [1, 1], [0, 1]]]}`,
+            from:         3,
+            root:         0,
+            faceVertices: [][]int{{0, 1, 2}, {1, 3, 2}},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            nf, err := ReadNetFile(strings.NewReader(tt.doc))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if nf.Version != NetFileVersion || nf.MigratedFrom != tt.from {
+                t.Errorf("version %d migrated from %d, want %d from %d", nf.Version, nf.MigratedFrom, NetFileVersion, tt.from)
+            }
+            if nf.RootFace != tt.root {
+                t.Errorf("root face %d, want %d", nf.RootFace, tt.root)
+            }
+            if !reflect.DeepEqual(nf.FaceVertices, tt.faceVertices) {
+                t.Errorf("face vertices %v, want %v", nf.FaceVertices, tt.faceVertices)
+            }
+            want := [][]Point2{{{0, 0}, {1, 0}, {0, 1}}, {{1, 0}, {1, 1}, {0, 1}}}
+            res := nf.Result()
+            for f, pts := range want {
+                if !reflect.DeepEqual(res.Face2D[f].Vertices, pts) {
+                    t.Errorf("face %d at %v, want %v", f, res.Face2D[f].Vertices, pts)
+                }
+            }
+
+            // written back it's a current file that reads the same
+            var buf bytes.Buffer
+            from, err := MigrateNetFile(strings.NewReader(tt.doc), &buf)
+            if err != nil || from != tt.from {
+                t.Fatalf("MigrateNetFile = %d, %v", from, err)
+            }
+            again, err := ReadNetFile(&buf)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if again.MigratedFrom != NetFileVersion || !reflect.DeepEqual(again.FaceVertices, nf.FaceVertices) {
+                t.Errorf("migrated file reads as version %d with face vertices %v", again.MigratedFrom, again.FaceVertices)
+            }
+        })
+    }
+}
+
+func TestReadNetFileRejects(t *testing.T) {
+    tests := []struct {
+        name string
+        doc  string
+        want string
+    }{
+        {"newer version", `{"format": "go-unfold/net", "version": 99, "spanningTree": [], "face2D": []}`, "newer than supported"},
+        {"format without version", `{"format": "go-unfold/net", "spanningTree": [], "face2D": []}`, "no version"},
+        {"no version and no tree", `{"Face2D": []}`, "not a net file"},
+        {"other format", `{"format": "something/else", "version": 3, "spanningTree": [], "face2D": []}`, "not a net file"},
+        {"tree and faces disagree", `{"format": "go-unfold/net", "version": 3, "spanningTree": [-1], "face2D": []}`, "tree entries"},
+        {"bad version", `{"version": "three"}`, "bad version"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            _, err := ReadNetFile(strings.NewReader(tt.doc))
+            if err == nil || !strings.Contains(err.Error(), tt.want) {
+                t.Errorf("err = %v, want one containing %q", err, tt.want)
+            }
+        })
+    }
+}
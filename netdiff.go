@@ -0,0 +1,242 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Net diffing
+// -----------------------------
+
+// FacePair is an unordered pair of face indices, stored smaller first.
+type FacePair [2]int
+
+// NetStats are the summary numbers compared by DiffNets.
+type NetStats struct {
+    Faces  int
+    Folds  int
+    Width  float64 // net bounding box
+    Height float64
+    Area   float64 // total face area in 2D
+}
+
+// NetDiff describes what changed between two stored nets.
+type NetDiff struct {
+    MeshChanged  bool // mesh hashes differ
+    RootOld      int
+    RootNew      int
+    FoldsAdded   []FacePair // tree edges present only in the new net
+    FoldsRemoved []FacePair // tree edges present only in the old net
+    CutsAdded    [][2]int   // mesh edges (vertex pairs) newly cut; needs embedded meshes
+    CutsRemoved  [][2]int   // mesh edges no longer cut; needs embedded meshes
+    MovedFaces   []int      // faces whose 2D placement moved by more than the tolerance
+    Old          NetStats
+    New          NetStats
+}
+
+// Empty reports whether the two nets are equivalent.
+func (d *NetDiff) Empty() bool {
+    return !d.MeshChanged && d.RootOld == d.RootNew &&
+        len(d.FoldsAdded) == 0 && len(d.FoldsRemoved) == 0 &&
+        len(d.CutsAdded) == 0 && len(d.CutsRemoved) == 0 &&
+        len(d.MovedFaces) == 0 && d.Old == d.New
+}
+
+// DiffNets compares two stored nets. Placements are compared face by face with
+// the given tolerance (0 means exact). Cut edges can only be diffed when both
+// files embed their mesh; otherwise CutsAdded/CutsRemoved stay empty.
+func DiffNets(oldNet, newNet *NetFile, tol float64) (*NetDiff, error) {
+    if oldNet == nil || newNet == nil {
+        return nil, errors.New("nil net file")
+    }
+    d := &NetDiff{
+        MeshChanged: oldNet.Mesh.Hash != newNet.Mesh.Hash,
+        RootOld:     oldNet.RootFace,
+        RootNew:     newNet.RootFace,
+        Old:         netFileStats(oldNet),
+        New:         netFileStats(newNet),
+    }
+
+    // tree edges
+    oldFolds := treeFacePairs(oldNet.SpanningTree)
+    newFolds := treeFacePairs(newNet.SpanningTree)
+    d.FoldsAdded = facePairsMissing(newFolds, oldFolds)
+    d.FoldsRemoved = facePairsMissing(oldFolds, newFolds)
+
+    // cut edges, if we know the meshes
+    oldMesh, okOld := oldNet.EmbeddedMesh()
+    newMesh, okNew := newNet.EmbeddedMesh()
+    if okOld && okNew {
+        oldCuts, err := netCutEdges(oldMesh, oldFolds)
+        if err != nil {
+            return nil, err
+        }
+        newCuts, err := netCutEdges(newMesh, newFolds)
+        if err != nil {
+            return nil, err
+        }
+        d.CutsAdded = edgesMissing(newCuts, oldCuts)
+        d.CutsRemoved = edgesMissing(oldCuts, newCuts)
+    }
+
+    // moved faces
+    n := len(oldNet.Face2D)
+    if len(newNet.Face2D) < n {
+        n = len(newNet.Face2D)
+    }
+    for f := 0; f < n; f++ {
+        a, b := oldNet.Face2D[f], newNet.Face2D[f]
+        if len(a) != len(b) {
+            d.MovedFaces = append(d.MovedFaces, f)
+            continue
+        }
+        for i := range a {
+            if math.Abs(a[i][0]-b[i][0]) > tol || math.Abs(a[i][1]-b[i][1]) > tol {
+                d.MovedFaces = append(d.MovedFaces, f)
+                break
+            }
+        }
+    }
+    // faces that only exist on one side count as moved too
+    for f := n; f < len(oldNet.Face2D) || f < len(newNet.Face2D); f++ {
+        d.MovedFaces = append(d.MovedFaces, f)
+    }
+
+    return d, nil
+}
+
+// WriteText prints a short human readable summary of the diff.
+func (d *NetDiff) WriteText(w io.Writer) error {
+    var err error
+    p := func(format string, args ...interface{}) {
+        if err == nil {
+            _, err = fmt.Fprintf(w, format, args...)
+        }
+    }
+    if d.Empty() {
+        p("nets are identical\n")
+        return err
+    }
+    if d.MeshChanged {
+        p("mesh changed\n")
+    }
+    if d.RootOld != d.RootNew {
+        p("root face: %d -> %d\n", d.RootOld, d.RootNew)
+    }
+    for _, fp := range d.FoldsRemoved {
+        p("- fold faces %d-%d\n", fp[0], fp[1])
+    }
+    for _, fp := range d.FoldsAdded {
+        p("+ fold faces %d-%d\n", fp[0], fp[1])
+    }
+    for _, e := range d.CutsRemoved {
+        p("- cut edge %d-%d\n", e[0], e[1])
+    }
+    for _, e := range d.CutsAdded {
+        p("+ cut edge %d-%d\n", e[0], e[1])
+    }
+    if len(d.MovedFaces) > 0 {
+        p("moved faces: %v\n", d.MovedFaces)
+    }
+    if d.Old != d.New {
+        p("faces:  %d -> %d\n", d.Old.Faces, d.New.Faces)
+        p("folds:  %d -> %d\n", d.Old.Folds, d.New.Folds)
+        p("size:   %.4g x %.4g -> %.4g x %.4g\n", d.Old.Width, d.Old.Height, d.New.Width, d.New.Height)
+        p("area:   %.6g -> %.6g\n", d.Old.Area, d.New.Area)
+    }
+    return err
+}
+
+// netFileStats computes NetStats straight from the stored placements.
+func netFileStats(nf *NetFile) NetStats {
+    s := NetStats{Faces: len(nf.Face2D)}
+    for _, p := range nf.SpanningTree {
+        if p >= 0 {
+            s.Folds++
+        }
+    }
+    minX, minY := math.Inf(1), math.Inf(1)
+    maxX, maxY := math.Inf(-1), math.Inf(-1)
+    for _, f := range nf.Face2D {
+        var a float64
+        for i := range f {
+            j := (i + 1) % len(f)
+            a += f[i][0]*f[j][1] - f[j][0]*f[i][1]
+            minX, maxX = math.Min(minX, f[i][0]), math.Max(maxX, f[i][0])
+            minY, maxY = math.Min(minY, f[i][1]), math.Max(maxY, f[i][1])
+        }
+        s.Area += math.Abs(a) / 2
+    }
+    if maxX >= minX {
+        s.Width = maxX - minX
+        s.Height = maxY - minY
+    }
+    return s
+}
+
+// treeFacePairs lists the spanning tree edges as sorted face pairs.
+func treeFacePairs(parent []int) map[FacePair]bool {
+    out := make(map[FacePair]bool, len(parent))
+    for f, p := range parent {
+        if p >= 0 {
+            out[FacePair(sortPair(f, p))] = true
+        }
+    }
+    return out
+}
+
+// netCutEdges returns the interior mesh edges whose two faces are not joined
+// by a tree edge.
+func netCutEdges(poly Polyhedron, folds map[FacePair]bool) (map[[2]int]bool, error) {
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+    cuts := make(map[[2]int]bool)
+    for f, nbrs := range adj.Neighbors {
+        for _, nbr := range nbrs {
+            if !folds[FacePair(sortPair(f, nbr.FaceIndex))] {
+                cuts[nbr.SharedEdge] = true
+            }
+        }
+    }
+    return cuts, nil
+}
+
+// facePairsMissing returns the pairs in a that are not in b, sorted.
+func facePairsMissing(a, b map[FacePair]bool) []FacePair {
+    var out []FacePair
+    for fp := range a {
+        if !b[fp] {
+            out = append(out, fp)
+        }
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i][0] != out[j][0] {
+            return out[i][0] < out[j][0]
+        }
+        return out[i][1] < out[j][1]
+    })
+    return out
+}
+
+// edgesMissing returns the edges in a that are not in b, sorted.
+func edgesMissing(a, b map[[2]int]bool) [][2]int {
+    var out [][2]int
+    for e := range a {
+        if !b[e] {
+            out = append(out, e)
+        }
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i][0] != out[j][0] {
+            return out[i][0] < out[j][0]
+        }
+        return out[i][1] < out[j][1]
+    })
+    return out
+}
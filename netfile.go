@@ -0,0 +1,321 @@
+package unfolder
+
+import (
+    "crypto/sha256"
+    "encoding/binary"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "os"
+)
+
+// -----------------------------
+//   .unfold net files
+// -----------------------------
+
+// NetFileFormat is the magic string stored in every .unfold file.
+const NetFileFormat = "go-unfold/net"
+
+// NetFileVersion is the current .unfold schema version.
+const NetFileVersion = 3
+
+// NetFile is everything needed to reproduce a published net: which mesh it was
+// made from, the options used, and the complete unfold result. Floats are
+// written with Go's shortest exact representation, so a loaded NetFile gives
+// back bit-identical coordinates and therefore byte-identical exports.
+type NetFile struct {
+    Format   string        `json:"format"`
+    Version  int           `json:"version"`
+    Mesh     MeshRef       `json:"mesh"`
+    Options  UnfoldOptions `json:"options"`
+    RootFace int           `json:"rootFace"`
+
+    SpanningTree []int          `json:"spanningTree"`
+    Face2D       [][][2]float64 `json:"face2D"`
+    // FaceVertices is the mesh vertex of every corner in Face2D, so vertex
+    // instances can be rebuilt without the mesh (-1 where unknown).
+    FaceVertices [][]int `json:"faceVertices"`
+    // Holes has the placed hole loops of every face, like Face2D, if any
+    // face has holes.
+    Holes       [][][][2]float64 `json:"holes,omitempty"`
+    UVs         [][][2]float64   `json:"uvs,omitempty"` // texture coordinates of every face, if any has them
+    FoldEdges   []NetEdge        `json:"foldEdges,omitempty"`
+    CutEdges    []NetEdge        `json:"cutEdges,omitempty"`
+    DoubleWalls []DoubleWall     `json:"doubleWalls,omitempty"`
+    Tabs        []TabPolygon     `json:"tabs,omitempty"`
+    // SplitMesh and FaceOrigin are UnfoldResult.Mesh and FaceOrigin, for
+    // nets of a mesh whose warped faces were split.
+    SplitMesh  *Polyhedron `json:"splitMesh,omitempty"`
+    FaceOrigin []int       `json:"faceOrigin,omitempty"`
+
+    // MigratedFrom is the schema version the file was stored in before
+    // ReadNetFile upgraded it. It equals Version for current files.
+    MigratedFrom int `json:"-"`
+}
+
+// MeshRef identifies the source mesh of a net. Hash is always set; the mesh
+// itself is only embedded on request (it can be large).
+type MeshRef struct {
+    Name     string         `json:"name,omitempty"`
+    Units    string         `json:"units,omitempty"`
+    Hash     string         `json:"hash"`
+    Vertices [][3]float64   `json:"vertices,omitempty"`
+    Faces    [][]int        `json:"faces,omitempty"`
+    Holes    [][][]int      `json:"holes,omitempty"` // Face.Holes of every face, if any has them
+    UVs      [][][2]float64 `json:"uvs,omitempty"`   // Face.UVs of every face, if any has them
+}
+
+// NewNetFile captures an unfold result together with its inputs.
+func NewNetFile(poly Polyhedron, rootFace int, opts UnfoldOptions, result *UnfoldResult, embedMesh bool) (*NetFile, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    nf := &NetFile{
+        Format:       NetFileFormat,
+        Version:      NetFileVersion,
+        Mesh:         MeshRef{Name: poly.Name, Units: poly.Units, Hash: MeshHash(poly)},
+        Options:      opts,
+        RootFace:     rootFace,
+        SpanningTree: append([]int(nil), result.SpanningTree...),
+        Face2D:       make([][][2]float64, len(result.Face2D)),
+        FaceVertices: make([][]int, len(result.Face2D)),
+        FoldEdges:    append([]NetEdge(nil), result.FoldEdges...),
+        CutEdges:     append([]NetEdge(nil), result.CutEdges...),
+        DoubleWalls:  append([]DoubleWall(nil), result.DoubleWalls...),
+        Tabs:         append([]TabPolygon(nil), result.Tabs...),
+        SplitMesh:    result.Mesh,
+        FaceOrigin:   append([]int(nil), result.FaceOrigin...),
+    }
+    // the corners are of the split mesh's faces, if there is one
+    faces := poly.Faces
+    if result.Mesh != nil {
+        faces = result.Mesh.Faces
+    }
+    for i, f := range result.Face2D {
+        pts := make([][2]float64, len(f.Vertices))
+        vs := make([]int, len(f.Vertices))
+        for j, p := range f.Vertices {
+            pts[j] = [2]float64{p.X, p.Y}
+            vs[j] = -1
+            if i < len(faces) && j < len(faces[i].Vertices) {
+                vs[j] = faces[i].Vertices[j]
+            }
+        }
+        nf.Face2D[i] = pts
+        nf.FaceVertices[i] = vs
+        if len(f.Holes) > 0 && nf.Holes == nil {
+            nf.Holes = make([][][][2]float64, len(result.Face2D))
+        }
+        if len(f.UVs) > 0 && nf.UVs == nil {
+            nf.UVs = make([][][2]float64, len(result.Face2D))
+        }
+    }
+    if nf.Holes != nil {
+        for i, f := range result.Face2D {
+            nf.Holes[i] = holesToWire(f.Holes)
+        }
+    }
+    if nf.UVs != nil {
+        for i, f := range result.Face2D {
+            nf.UVs[i] = pointsToWire(f.UVs)
+        }
+    }
+    if embedMesh {
+        nf.Mesh.Vertices = make([][3]float64, len(poly.Vertices))
+        for i, v := range poly.Vertices {
+            nf.Mesh.Vertices[i] = [3]float64{v.X, v.Y, v.Z}
+        }
+        nf.Mesh.Faces = make([][]int, len(poly.Faces))
+        for i, f := range poly.Faces {
+            nf.Mesh.Faces[i] = append([]int(nil), f.Vertices...)
+            if len(f.Holes) > 0 && nf.Mesh.Holes == nil {
+                nf.Mesh.Holes = make([][][]int, len(poly.Faces))
+            }
+            if len(f.UVs) > 0 && nf.Mesh.UVs == nil {
+                nf.Mesh.UVs = make([][][2]float64, len(poly.Faces))
+            }
+        }
+        if nf.Mesh.Holes != nil {
+            for i, f := range poly.Faces {
+                nf.Mesh.Holes[i] = copyHoles(f.Holes, sameVertex)
+                if nf.Mesh.Holes[i] == nil {
+                    nf.Mesh.Holes[i] = [][]int{}
+                }
+            }
+        }
+        if nf.Mesh.UVs != nil {
+            for i, f := range poly.Faces {
+                nf.Mesh.UVs[i] = pointsToWire(f.UVs)
+            }
+        }
+    }
+    return nf, nil
+}
+
+// Result rebuilds the UnfoldResult stored in the file.
+func (nf *NetFile) Result() *UnfoldResult {
+    res := &UnfoldResult{
+        SpanningTree: append([]int(nil), nf.SpanningTree...),
+        Face2D:       make([]Face2D, len(nf.Face2D)),
+        FoldEdges:    append([]NetEdge(nil), nf.FoldEdges...),
+        CutEdges:     append([]NetEdge(nil), nf.CutEdges...),
+        DoubleWalls:  append([]DoubleWall(nil), nf.DoubleWalls...),
+        Tabs:         append([]TabPolygon(nil), nf.Tabs...),
+        Side:         nf.Options.Side,
+        Mesh:         nf.SplitMesh,
+        FaceOrigin:   append([]int(nil), nf.FaceOrigin...),
+    }
+    for i, f := range nf.Face2D {
+        pts := make([]Point2, len(f))
+        for j, p := range f {
+            pts[j] = Point2{p[0], p[1]}
+            v := -1
+            if i < len(nf.FaceVertices) && j < len(nf.FaceVertices[i]) {
+                v = nf.FaceVertices[i][j]
+            }
+            res.VertexInstances = append(res.VertexInstances, VertexInstance{Face: i, Local: j, Vertex: v, Pos: pts[j]})
+        }
+        res.Face2D[i] = Face2D{Vertices: pts}
+        if i < len(nf.Holes) && len(nf.Holes[i]) > 0 {
+            res.Face2D[i].Holes = holesFromWire(nf.Holes[i])
+        }
+        if i < len(nf.UVs) && len(nf.UVs[i]) > 0 {
+            res.Face2D[i].UVs = pointsFromWire(nf.UVs[i])
+        }
+    }
+    return res
+}
+
+// EmbeddedMesh returns the mesh stored in the file, if it was embedded.
+func (nf *NetFile) EmbeddedMesh() (Polyhedron, bool) {
+    if len(nf.Mesh.Vertices) == 0 {
+        return Polyhedron{}, false
+    }
+    poly := Polyhedron{
+        Name:     nf.Mesh.Name,
+        Units:    nf.Mesh.Units,
+        Vertices: make([]Vector3, len(nf.Mesh.Vertices)),
+        Faces:    make([]Face, len(nf.Mesh.Faces)),
+    }
+    for i, v := range nf.Mesh.Vertices {
+        poly.Vertices[i] = Vector3{v[0], v[1], v[2]}
+    }
+    for i, f := range nf.Mesh.Faces {
+        poly.Faces[i] = Face{Vertices: append([]int(nil), f...)}
+        if i < len(nf.Mesh.Holes) && len(nf.Mesh.Holes[i]) > 0 {
+            poly.Faces[i].Holes = copyHoles(nf.Mesh.Holes[i], sameVertex)
+        }
+        if i < len(nf.Mesh.UVs) && len(nf.Mesh.UVs[i]) > 0 {
+            poly.Faces[i].UVs = pointsFromWire(nf.Mesh.UVs[i])
+        }
+    }
+    return poly, true
+}
+
+// MatchesMesh reports whether poly is the mesh this net was made from.
+func (nf *NetFile) MatchesMesh(poly Polyhedron) bool {
+    return nf.Mesh.Hash == MeshHash(poly)
+}
+
+// WriteNetFile writes nf as indented JSON. The output only depends on the
+// contents of nf, so writing the same net twice gives identical bytes.
+func WriteNetFile(w io.Writer, nf *NetFile) error {
+    enc := json.NewEncoder(w)
+    enc.SetIndent("", "  ")
+    return enc.Encode(nf)
+}
+
+// ReadNetFile parses a .unfold file and checks its format and version. Files
+// written by older versions are migrated forward first (see migrate.go).
+func ReadNetFile(r io.Reader) (*NetFile, error) {
+    var doc map[string]json.RawMessage
+    if err := json.NewDecoder(r).Decode(&doc); err != nil {
+        return nil, fmt.Errorf("error decoding net file: %v", err)
+    }
+    from, err := migrateNetFile(doc)
+    if err != nil {
+        return nil, err
+    }
+    raw, err := json.Marshal(doc)
+    if err != nil {
+        return nil, err
+    }
+    var nf NetFile
+    if err := json.Unmarshal(raw, &nf); err != nil {
+        return nil, fmt.Errorf("error decoding net file: %v", err)
+    }
+    nf.MigratedFrom = from
+    if nf.Format != NetFileFormat {
+        return nil, fmt.Errorf("not a net file (format %q)", nf.Format)
+    }
+    if len(nf.SpanningTree) != len(nf.Face2D) {
+        return nil, fmt.Errorf("net file has %d tree entries but %d faces", len(nf.SpanningTree), len(nf.Face2D))
+    }
+    return &nf, nil
+}
+
+// SaveNetFile writes nf to path.
+func SaveNetFile(path string, nf *NetFile) error {
+    f, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    if err := WriteNetFile(f, nf); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
+
+// LoadNetFile reads a .unfold file from path.
+func LoadNetFile(path string) (*NetFile, error) {
+    f, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer f.Close()
+    return ReadNetFile(f)
+}
+
+// MeshHash returns a hex SHA-256 over the mesh geometry and topology (vertex
+// coordinates bit for bit, then face loops, then hole loops if any face has
+// holes, so meshes without holes hash as they always did). The name is not
+// included, so renaming a model doesn't invalidate its nets.
+func MeshHash(poly Polyhedron) string {
+    h := sha256.New()
+    var buf [8]byte
+    putU64 := func(u uint64) {
+        binary.LittleEndian.PutUint64(buf[:], u)
+        h.Write(buf[:])
+    }
+    putU64(uint64(len(poly.Vertices)))
+    for _, v := range poly.Vertices {
+        putU64(math.Float64bits(v.X))
+        putU64(math.Float64bits(v.Y))
+        putU64(math.Float64bits(v.Z))
+    }
+    putU64(uint64(len(poly.Faces)))
+    for _, f := range poly.Faces {
+        putU64(uint64(len(f.Vertices)))
+        for _, vi := range f.Vertices {
+            putU64(uint64(vi))
+        }
+    }
+    for i, f := range poly.Faces {
+        if len(f.Holes) == 0 {
+            continue
+        }
+        putU64(uint64(i))
+        putU64(uint64(len(f.Holes)))
+        for _, loop := range f.Holes {
+            putU64(uint64(len(loop)))
+            for _, vi := range loop {
+                putU64(uint64(vi))
+            }
+        }
+    }
+    return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,141 @@
+package unfolder
+
+import (
+    "errors"
+    "image"
+    "image/color"
+)
+
+// -----------------------------
+//   Net pictures
+// -----------------------------
+
+// NetImageOptions controls RenderNet.
+type NetImageOptions struct {
+    // Width and Height of the picture in pixels; the net is fitted inside,
+    // Margin pixels from the edges. Default 1024 x 1024, margin 8.
+    Width, Height int
+    Margin        int
+    // Background fills the picture; nil leaves it transparent.
+    Background color.Color
+    // FaceColor fills the faces (and tabs and double walls). Default white.
+    FaceColor color.Color
+    // FaceColors, if set, colours each face by index instead; nil entries
+    // fall back to FaceColor.
+    FaceColors []color.Color
+    // Texture, if set, paints faces that have UVs as RenderTexture does.
+    Texture image.Image
+    // LineWidth of the cut and fold lines in pixels. Default 1.
+    LineWidth float64
+    // Mesh tells mountain from valley folds, as in SVGOptions.
+    Mesh *Polyhedron
+}
+
+// RenderNet draws the net as a picture of a given size, for previews and
+// thumbnails: faces filled, cut and fold lines in the colours of the SVG
+// export. Unlike RenderTexture the size is in pixels, not pixels per unit,
+// so any net comes out the same size. To draw one sheet of a packed net,
+// render that sheet's result from Packing.SheetResults.
+func RenderNet(result *UnfoldResult, opts NetImageOptions) (*image.RGBA, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    mesh, err := netMesh(result, opts.Mesh)
+    if err != nil {
+        return nil, err
+    }
+    opts.Mesh = mesh
+    if opts.Width <= 0 {
+        opts.Width = 1024
+    }
+    if opts.Height <= 0 {
+        opts.Height = 1024
+    }
+    if opts.Margin <= 0 {
+        opts.Margin = 8
+    }
+    if opts.Width*opts.Height > 1<<28 {
+        return nil, errors.New("net image would be too large")
+    }
+    if opts.LineWidth <= 0 {
+        opts.LineWidth = 1
+    }
+    if opts.FaceColor == nil {
+        opts.FaceColor = color.White
+    }
+    lo, hi, ok := sheetBounds(result, nil)
+    if !ok {
+        return nil, errors.New("nothing to draw: no face was placed")
+    }
+    img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+    if opts.Background != nil {
+        bg := color.RGBAModel.Convert(opts.Background).(color.RGBA)
+        for i := 0; i < len(img.Pix); i += 4 {
+            img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+        }
+    }
+    m := float64(opts.Margin)
+    to := fitBox(lo, hi, m, m, float64(opts.Width)-2*m, float64(opts.Height)-2*m)
+    toPx := func(p Point2) Point2 {
+        x, y := to(p)
+        return Point2{X: x, Y: float64(opts.Height) - y} // image rows run down
+    }
+    fill := func(pts []Point2, c color.RGBA) {
+        if len(pts) < 3 {
+            return
+        }
+        a := toPx(pts[0])
+        for i := 1; i+1 < len(pts); i++ {
+            rasterTriangle(img.Bounds(), [3]Point2{a, toPx(pts[i]), toPx(pts[i+1])}, func(x, y int, _ [3]float64) {
+                img.SetRGBA(x, y, c)
+            })
+        }
+    }
+
+    plain := color.RGBAModel.Convert(opts.FaceColor).(color.RGBA)
+    for _, t := range result.Tabs {
+        fill(t.Vertices, plain)
+    }
+    for _, dw := range result.DoubleWalls {
+        fill(dw.Vertices, plain)
+    }
+    for fIdx, f := range result.Face2D {
+        if len(f.Vertices) < 3 {
+            continue
+        }
+        if opts.Texture != nil && len(f.UVs) == len(f.Vertices) {
+            a := toPx(f.Vertices[0])
+            for i := 1; i+1 < len(f.Vertices); i++ {
+                b, c := toPx(f.Vertices[i]), toPx(f.Vertices[i+1])
+                drawTexturedTriangle(img, opts.Texture, [3]Point2{a, b, c}, [3]Point2{f.UVs[0], f.UVs[i], f.UVs[i+1]})
+            }
+            continue
+        }
+        c := plain
+        if fIdx < len(opts.FaceColors) && opts.FaceColors[fIdx] != nil {
+            c = color.RGBAModel.Convert(opts.FaceColors[fIdx]).(color.RGBA)
+        }
+        fill(f.Vertices, c)
+    }
+    // punch the holes back out
+    var bg color.RGBA
+    if opts.Background != nil {
+        bg = color.RGBAModel.Convert(opts.Background).(color.RGBA)
+    }
+    for _, f := range result.Face2D {
+        for _, hole := range f.Holes {
+            fill(hole, bg)
+        }
+    }
+
+    lineOf := func(pts []Point2) {
+        for i := range pts {
+            drawLine(img, toPx(pts[i]), toPx(pts[(i+1)%len(pts)]), opts.LineWidth, color.RGBA{0, 0, 0, 255})
+        }
+    }
+    for _, t := range result.Tabs {
+        lineOf(t.Vertices)
+    }
+    drawNetLines(img, result, opts.Mesh, toPx, opts.LineWidth)
+    return img, nil
+}
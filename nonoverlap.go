@@ -0,0 +1,255 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Overlap-avoiding unfolding
+// -----------------------------
+
+// ErrOverlapUnavoidable means the search tried every spanning tree it is
+// allowed to build and each one overlaps.
+var ErrOverlapUnavoidable = errors.New("no overlap-free net found")
+
+// ErrSearchBudget means the search gave up after UnfoldOptions.SearchBudget
+// placements without finding an overlap-free net.
+var ErrSearchBudget = errors.New("search budget exhausted")
+
+// defaultSearchBudget bounds UnfoldMeshNonOverlapping when opts.SearchBudget is 0.
+const defaultSearchBudget = 100000
+
+// UnfoldMeshNonOverlapping unfolds like UnfoldMesh, but whenever a face would
+// overlap the part of the net already laid out, it tries the face's other
+// placed neighbours as parent, backtracking to earlier faces when none fit.
+//
+// Faces are attached in BFS order from rootFace and each may hang off any
+// earlier neighbour, so if an overlap-free net of that shape exists it is found
+// (given enough budget). Otherwise ErrOverlapUnavoidable or ErrSearchBudget
+// is returned. opts.Constraints narrow the choice: no face hangs off a
+// must-cut edge, and a face with a must-fold edge to an earlier face hangs
+// off that one (it can't have two). With opts.Material the search places
+// faces at their own sizes, and a net that overlaps once the bend allowances
+// are made is passed over like any other clash (bends crossing at a mesh
+// corner aside, see MaterialSpec).
+//
+// The other options apply as in UnfoldMeshWithOptions: opts.MemoryLimit,
+// opts.RequireManifold and opts.Components are checked before the search.
+func UnfoldMeshNonOverlapping(poly Polyhedron, rootFace int, opts UnfoldOptions) (result *UnfoldResult, err error) {
+    at := &progress{stage: "planarity", face: -1}
+    defer at.recover(&err)
+    if len(poly.Faces) == 0 {
+        return nil, errors.New("polyhedron has no faces")
+    }
+    if rootFace < 0 || rootFace >= len(poly.Faces) {
+        return nil, fmt.Errorf("root face %d out of range", rootFace)
+    }
+    budget := opts.SearchBudget
+    if budget <= 0 {
+        budget = defaultSearchBudget
+    }
+    m, err := prepareMesh(poly, opts, at)
+    if err != nil {
+        return nil, err
+    }
+    poly, adjacency := m.poly, m.adjacency
+    nFaces := len(poly.Faces)
+
+    // BFS order of the reachable faces
+    order := []int{rootFace}
+    inOrder := make([]bool, nFaces)
+    inOrder[rootFace] = true
+    for i := 0; i < len(order); i++ {
+        for _, nbr := range adjacency.Neighbors[order[i]] {
+            if !inOrder[nbr.FaceIndex] {
+                inOrder[nbr.FaceIndex] = true
+                order = append(order, nbr.FaceIndex)
+            }
+        }
+    }
+    pos := make([]int, nFaces)
+    for i := range pos {
+        pos[i] = -1
+    }
+    for i, f := range order {
+        pos[f] = i
+    }
+
+    var fold map[[2]int]bool
+    if !opts.Constraints.empty() {
+        if fold, err = constraintEdges(poly, adjacency, opts.Constraints); err != nil {
+            return nil, err
+        }
+    }
+
+    // candidate hinges per face: one per earlier neighbour, using the first
+    // adjacency entry like unfoldAlongTree does
+    type hinge struct {
+        parent int
+        nbr    FaceNeighbor
+    }
+    cands := make([][]hinge, nFaces)
+    for _, p := range order {
+        seen := make(map[int]bool)
+        for _, nbr := range adjacency.Neighbors[p] {
+            c := nbr.FaceIndex
+            if seen[c] || pos[c] <= pos[p] {
+                continue
+            }
+            seen[c] = true
+            if f, ok := fold[nbr.SharedEdge]; ok && !f {
+                continue // must cut
+            }
+            cands[c] = append(cands[c], hinge{parent: p, nbr: nbr})
+        }
+    }
+    for _, c := range order[1:] {
+        var must []hinge
+        for _, h := range cands[c] {
+            if fold[h.nbr.SharedEdge] {
+                must = append(must, h)
+            }
+        }
+        switch {
+        case len(must) > 1:
+            return nil, fmt.Errorf("face %d has must-fold edges to faces %d and %d, which both come before it from root face %d; only one can be folded here, try another root",
+                c, must[0].parent, must[1].parent, rootFace)
+        case len(must) == 1:
+            cands[c] = must
+        case len(cands[c]) == 0:
+            return nil, fmt.Errorf("must-cut edges leave face %d no face before it from root face %d to hang off, try another root", c, rootFace)
+        }
+    }
+
+    // mesh size sets the overlap tolerance
+    lo, hi := boundingBox(poly.Vertices)
+    eps := length(sub(hi, lo)) * 1e-9
+    if eps == 0 {
+        eps = 1e-12
+    }
+
+    at.stage, at.face = "overlap search", rootFace
+    face2D := make([]Face2D, nFaces)
+    boxes := make([]box2, nFaces)
+    if err := placeRootFace(poly, rootFace, &face2D[rootFace]); err != nil {
+        return nil, fmt.Errorf("failed to place root face: %w", err)
+    }
+    boxes[rootFace] = polyBox(face2D[rootFace].Vertices)
+
+    parent := make([]int, nFaces)
+    for i := range parent {
+        parent[i] = -1
+    }
+    choice := make([]int, len(order))
+    steps := 0
+    for k := 1; ; {
+        for k < len(order) {
+            f := order[k]
+            at.face = f
+            ok := false
+            for choice[k] < len(cands[f]) {
+                h := cands[f][choice[k]]
+                choice[k]++
+                steps++
+                if steps > budget {
+                    return nil, fmt.Errorf("%w after %d placements", ErrSearchBudget, budget)
+                }
+
+                pFace := poly.Faces[h.parent]
+                i0, i1 := h.nbr.ThisFaceEdge[0], h.nbr.ThisFaceEdge[1]
+                pts, err := hingeFace(poly, f, pFace.Vertices[i0], pFace.Vertices[i1],
+                    face2D[h.parent].Vertices[i0], face2D[h.parent].Vertices[i1])
+                if err != nil {
+                    return nil, fmt.Errorf("failed to place face %d adjacent to %d: %w", f, h.parent, err)
+                }
+                if opts.Anchoring == AnchorBestFit {
+                    pts = bestFitAnchor(poly, h.parent, f, face2D[h.parent].Vertices, pts)
+                }
+                b := polyBox(pts)
+                clash := false
+                for _, g := range order[:k] {
+                    bg := boxes[g]
+                    if b.minX >= bg.maxX-eps || bg.minX >= b.maxX-eps || b.minY >= bg.maxY-eps || bg.minY >= b.maxY-eps {
+                        continue
+                    }
+                    if polygonsOverlap(pts, face2D[g].Vertices, eps) {
+                        clash = true
+                        break
+                    }
+                }
+                if clash {
+                    continue
+                }
+                face2D[f] = Face2D{Vertices: pts}
+                boxes[f] = b
+                parent[f] = h.parent
+                ok = true
+                break
+            }
+            if ok {
+                k++
+                if k < len(order) {
+                    choice[k] = 0
+                }
+                continue
+            }
+            // nothing fits: undo this face and retry the previous one
+            choice[k] = 0
+            face2D[f] = Face2D{}
+            parent[f] = -1
+            k--
+            if k == 0 {
+                return nil, ErrOverlapUnavoidable
+            }
+            face2D[order[k]] = Face2D{}
+            parent[order[k]] = -1
+        }
+
+        // lay the net out for real along the tree we found
+        at.stage, at.face = "placement", rootFace
+        result, err = unfoldAlongTree(poly, adjacency, rootFace, parent, opts, nil)
+        if err != nil || opts.Material == nil || len(order) < 2 || !materialOverlaps(poly, result) {
+            break
+        }
+        // the bend allowances pushed faces into each other: look on from the
+        // last face's next choice
+        at.stage = "overlap search"
+        k = len(order) - 1
+        face2D[order[k]] = Face2D{}
+        parent[order[k]] = -1
+    }
+    if err == nil && m.split != nil {
+        result.Mesh, result.FaceOrigin = m.split, m.origin
+    }
+    return result, err
+}
+
+// materialOverlaps reports whether the bend allowances made faces of result
+// overlap. Faces meeting at a mesh corner don't count: their bends cross
+// there whatever the net (see MaterialSpec).
+func materialOverlaps(poly Polyhedron, result *UnfoldResult) bool {
+    for _, o := range DetectOverlaps(result) {
+        corner := false
+        for _, a := range poly.Faces[o.FaceA].Vertices {
+            for _, b := range poly.Faces[o.FaceB].Vertices {
+                corner = corner || a == b
+            }
+        }
+        if !corner {
+            return true
+        }
+    }
+    return false
+}
+
+// polyBox returns the bounding box of a polygon.
+func polyBox(pts []Point2) box2 {
+    b := box2{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1), ok: true}
+    for _, p := range pts {
+        b.minX, b.maxX = math.Min(b.minX, p.X), math.Max(b.maxX, p.X)
+        b.minY, b.maxY = math.Min(b.minY, p.Y), math.Max(b.maxY, p.Y)
+    }
+    return b
+}
@@ -0,0 +1,178 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+    "sync"
+)
+
+// -----------------------------
+//   Net optimization (search over roots and strategies)
+// -----------------------------
+
+// Objective scores a candidate net; lower is better.
+type Objective func(result *UnfoldResult) float64
+
+// The stock objectives. Ties are broken by bounding box area.
+var (
+    // MinOverlaps counts overlapping face pairs.
+    MinOverlaps Objective = func(r *UnfoldResult) float64 { return float64(len(DetectOverlaps(r))) }
+    // MinOverlapArea adds up the overlapping area, so slivers count for little.
+    MinOverlapArea Objective = func(r *UnfoldResult) float64 { return MeasureOverlaps(r).Total }
+    // MinBoxArea is the bounding box area of the net.
+    MinBoxArea Objective = func(r *UnfoldResult) float64 { return ComputeNetMetrics(r).BoxArea }
+    // MinCutLength is the total length of the seams.
+    MinCutLength Objective = func(r *UnfoldResult) float64 { return ComputeNetMetrics(r).CutLength }
+)
+
+// OptimizeBudget bounds OptimizeUnfold.
+type OptimizeBudget struct {
+    // Candidates is how many (root face, strategy) pairs are unfolded and
+    // scored. Default 64.
+    Candidates int
+
+    // Workers is how many candidates are unfolded at the same time. Default 1.
+    Workers int
+}
+
+// Optimized is the winner of OptimizeUnfold.
+type Optimized struct {
+    Result   *UnfoldResult
+    Root     int
+    Strategy SpanningStrategy
+    Score    float64
+    Tried    int // candidates that unfolded
+}
+
+// OptimizeUnfold unfolds poly from many root faces with several spanning
+// strategies (breadth first, dihedral MST and steepest edge along a few
+// directions) and returns the net with the lowest objective score; a nil
+// objective means MinOverlaps. Roots are spread over the mesh starting from
+// the largest face. Which candidates are tried depends on the budget only,
+// not on the number of workers.
+func OptimizeUnfold(poly Polyhedron, objective Objective, budget OptimizeBudget) (opt *Optimized, err error) {
+    at := &progress{stage: "adjacency", face: -1}
+    defer at.recover(&err)
+    nFaces := len(poly.Faces)
+    if nFaces == 0 {
+        return nil, errors.New("polyhedron has no faces")
+    }
+    if objective == nil {
+        objective = MinOverlaps
+    }
+    if budget.Candidates <= 0 {
+        budget.Candidates = 64
+    }
+    if budget.Workers <= 0 {
+        budget.Workers = 1
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+
+    strategies := []SpanningStrategy{BreadthFirst{}, DihedralMST{}}
+    for _, d := range sphereDirections(4) {
+        strategies = append(strategies, SteepestEdge{Direction: d})
+    }
+    nRoots := (budget.Candidates + len(strategies) - 1) / len(strategies)
+    if nRoots > nFaces {
+        nRoots = nFaces
+    }
+    type candidate struct {
+        root     int
+        strategy SpanningStrategy
+        result   *UnfoldResult
+        score    float64
+        box      float64
+    }
+    // a candidate that panics (a strategy or objective of the caller's
+    // included) is skipped like one that fails; the worker goroutines are out
+    // of reach of the recover above
+    try := func(c *candidate) (err error) {
+        at := &progress{stage: "candidate", face: c.root}
+        defer at.recover(&err)
+        parent, root, err := c.strategy.SpanningTree(poly, adj, c.root)
+        if err != nil {
+            return err
+        }
+        res, err := unfoldAlongTree(poly, adj, root, parent, UnfoldOptions{}, nil)
+        if err != nil {
+            return err
+        }
+        c.root, c.result = root, res
+        c.score, c.box = objective(res), ComputeNetMetrics(res).BoxArea
+        return nil
+    }
+    at.stage = "candidates"
+    var cands []candidate
+    for _, root := range spreadRoots(poly, nRoots) {
+        for _, s := range strategies {
+            if len(cands) < budget.Candidates {
+                cands = append(cands, candidate{root: root, strategy: s})
+            }
+        }
+    }
+
+    var wg sync.WaitGroup
+    jobs := make(chan int)
+    for w := 0; w < budget.Workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                if try(&cands[i]) != nil {
+                    cands[i].result = nil
+                }
+            }
+        }()
+    }
+    for i := range cands {
+        jobs <- i
+    }
+    close(jobs)
+    wg.Wait()
+
+    var ok []candidate
+    for _, c := range cands {
+        if c.result != nil && !math.IsNaN(c.score) {
+            ok = append(ok, c)
+        }
+    }
+    if len(ok) == 0 {
+        return nil, errors.New("no candidate net could be unfolded")
+    }
+    sort.SliceStable(ok, func(i, j int) bool {
+        if ok[i].score != ok[j].score {
+            return ok[i].score < ok[j].score
+        }
+        return ok[i].box < ok[j].box
+    })
+    best := ok[0]
+    return &Optimized{Result: best.result, Root: best.root, Strategy: best.strategy, Score: best.score, Tried: len(ok)}, nil
+}
+
+// spreadRoots picks n root faces: the largest face, then faces spread evenly
+// through the face list.
+func spreadRoots(poly Polyhedron, n int) []int {
+    nFaces := len(poly.Faces)
+    largest, best := 0, -1.0
+    for i, f := range poly.Faces {
+        if a := length(faceNormal(poly, f)); a > best {
+            largest, best = i, a
+        }
+    }
+    roots := []int{largest}
+    seen := map[int]bool{largest: true}
+    for k := 0; len(roots) < n && k < nFaces; k++ {
+        f := k * nFaces / n % nFaces
+        for seen[f] {
+            f = (f + 1) % nFaces
+        }
+        seen[f] = true
+        roots = append(roots, f)
+    }
+    return roots
+}
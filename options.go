@@ -0,0 +1,66 @@
+package unfolder
+
+// UnfoldOptions tweaks how UnfoldMeshWithOptions runs. The zero value behaves
+// exactly like UnfoldMesh.
+type UnfoldOptions struct {
+    // MemoryLimit caps the estimated peak memory (in bytes) the unfold may use.
+    // The estimate is computed from vertex/face/edge counts before anything big
+    // is allocated; meshes over the limit fail with ErrMemoryLimit. 0 = no limit.
+    MemoryLimit int64 `json:"memoryLimit,omitempty"`
+
+    // ReportMemory fills UnfoldResult.Memory with the actual allocations per stage.
+    // This calls runtime.ReadMemStats between stages, which briefly stops the world,
+    // so leave it off for hot paths.
+    ReportMemory bool `json:"reportMemory,omitempty"`
+
+    // ValidateOnly runs Preflight and stops: the returned result only carries
+    // the Validation report, no net is produced.
+    ValidateOnly bool `json:"validateOnly,omitempty"`
+
+    // DetectOverlaps runs DetectOverlaps on the finished net and stores the
+    // colliding face pairs in UnfoldResult.Overlaps.
+    DetectOverlaps bool `json:"detectOverlaps,omitempty"`
+
+    // DoubleWalled adds a fold-over copy of every face (see AddDoubleWalls).
+    DoubleWalled bool `json:"doubleWalled,omitempty"`
+
+    // SearchBudget caps how many face placements UnfoldMeshNonOverlapping may
+    // try before giving up. 0 = 100000.
+    SearchBudget int `json:"searchBudget,omitempty"`
+
+    // Anchoring picks how each face is positioned against its parent; the
+    // default hinges it on the shared edge (see AnchorMode).
+    Anchoring AnchorMode `json:"anchoring,omitempty"`
+
+    // Components says what to do if the mesh falls apart into several
+    // components: unfold only the root face's (the default), or fail with
+    // ErrDisconnected. See UnfoldComponents for a net per component.
+    Components ComponentMode `json:"components,omitempty"`
+
+    // RequireManifold fails with ErrNonManifold if an edge is shared by
+    // three or more faces, instead of cutting it.
+    RequireManifold bool `json:"requireManifold,omitempty"`
+
+    // PlanarityTolerance is how far (mesh units) a face's corners may be
+    // from its plane; faces beyond it fail or are split, as NonPlanar says.
+    // 0 = no check: warped faces are laid flat from their first three
+    // corners and come out distorted.
+    PlanarityTolerance float64       `json:"planarityTolerance,omitempty"`
+    NonPlanar          NonPlanarMode `json:"nonPlanar,omitempty"`
+
+    // Side is the surface of the model the net shows; SideInterior mirrors
+    // the net and swaps mountain and valley folds.
+    Side NetSide `json:"side,omitempty"`
+
+    // Constraints force edges to be cut or folded, overriding the spanning
+    // tree where they disagree; ErrConstraintConflict if they can't be met.
+    Constraints *Constraints `json:"constraints,omitempty"`
+
+    // Material makes room for the bends of thick material and the cutter's
+    // kerf, see MaterialSpec. nil folds on a line.
+    Material *MaterialSpec `json:"material,omitempty"`
+
+    // Strategy picks the spanning tree; nil means BreadthFirst from the root
+    // face. Strategies may choose a different root (see LargestFaceRoot).
+    Strategy SpanningStrategy `json:"-"`
+}
@@ -0,0 +1,197 @@
+package unfolder
+
+import (
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Overlap detection
+// -----------------------------
+
+// OverlapPair is two placed faces whose interiors intersect in the net.
+// FaceA < FaceB.
+type OverlapPair struct {
+    FaceA int `json:"faceA"`
+    FaceB int `json:"faceB"`
+}
+
+// DetectOverlaps checks every pair of placed faces for overlapping interiors.
+// Faces that merely touch (shared fold edges, vertices on edges) don't count.
+// Unplaced faces are ignored. The result is sorted.
+func DetectOverlaps(result *UnfoldResult) []OverlapPair {
+    if result == nil {
+        return nil
+    }
+    boxes, eps := faceBoxes(result.Face2D)
+
+    // sweep over x so we only test faces whose boxes can touch
+    order := make([]int, 0, len(boxes))
+    for f, b := range boxes {
+        if b.ok {
+            order = append(order, f)
+        }
+    }
+    sort.Slice(order, func(i, j int) bool { return boxes[order[i]].minX < boxes[order[j]].minX })
+
+    var out []OverlapPair
+    for i, fa := range order {
+        a := boxes[fa]
+        for _, fb := range order[i+1:] {
+            b := boxes[fb]
+            if b.minX >= a.maxX-eps {
+                break
+            }
+            if b.minY >= a.maxY-eps || a.minY >= b.maxY-eps {
+                continue
+            }
+            if polygonsOverlap(result.Face2D[fa].Vertices, result.Face2D[fb].Vertices, eps) {
+                p := OverlapPair{FaceA: fa, FaceB: fb}
+                if fb < fa {
+                    p = OverlapPair{FaceA: fb, FaceB: fa}
+                }
+                out = append(out, p)
+            }
+        }
+    }
+    sort.Slice(out, func(i, j int) bool {
+        if out[i].FaceA != out[j].FaceA {
+            return out[i].FaceA < out[j].FaceA
+        }
+        return out[i].FaceB < out[j].FaceB
+    })
+    return out
+}
+
+// box2 is an axis aligned 2D box; ok is false for unplaced faces.
+type box2 struct {
+    minX, minY, maxX, maxY float64
+    ok                     bool
+}
+
+// faceBoxes returns the bounding box of every face and a distance tolerance
+// scaled to the size of the whole net.
+func faceBoxes(faces []Face2D) ([]box2, float64) {
+    boxes := make([]box2, len(faces))
+    all := box2{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1)}
+    for f, face := range faces {
+        if len(face.Vertices) < 3 {
+            continue
+        }
+        b := box2{minX: math.Inf(1), minY: math.Inf(1), maxX: math.Inf(-1), maxY: math.Inf(-1), ok: true}
+        for _, p := range face.Vertices {
+            b.minX, b.maxX = math.Min(b.minX, p.X), math.Max(b.maxX, p.X)
+            b.minY, b.maxY = math.Min(b.minY, p.Y), math.Max(b.maxY, p.Y)
+        }
+        boxes[f] = b
+        all.minX, all.maxX = math.Min(all.minX, b.minX), math.Max(all.maxX, b.maxX)
+        all.minY, all.maxY = math.Min(all.minY, b.minY), math.Max(all.maxY, b.maxY)
+    }
+    eps := 1e-9
+    if d := math.Hypot(all.maxX-all.minX, all.maxY-all.minY); d > 0 && !math.IsInf(d, 0) {
+        eps = d * 1e-9
+    }
+    return boxes, eps
+}
+
+// polygonsOverlap reports whether the interiors of two simple polygons
+// intersect by more than eps.
+func polygonsOverlap(a, b []Point2, eps float64) bool {
+    // 1) a proper crossing of two edges (not at their endpoints)
+    for i := range a {
+        a0, a1 := a[i], a[(i+1)%len(a)]
+        la := math.Hypot(a1.X-a0.X, a1.Y-a0.Y)
+        for j := range b {
+            b0, b1 := b[j], b[(j+1)%len(b)]
+            lb := math.Hypot(b1.X-b0.X, b1.Y-b0.Y)
+            t, s, ok := segmentIntersection(a0, a1, b0, b1)
+            if ok && t*la > eps && (1-t)*la > eps && s*lb > eps && (1-s)*lb > eps {
+                return true
+            }
+        }
+    }
+    // 2) no crossings: one may still lie inside the other (or they coincide).
+    //    Probe vertices, edge midpoints and an interior point of each.
+    return probesInside(a, b, eps) || probesInside(b, a, eps)
+}
+
+// probesInside reports whether any probe point of a lies strictly inside b.
+func probesInside(a, b []Point2, eps float64) bool {
+    probes := make([]Point2, 0, 2*len(a)+1)
+    probes = append(probes, interiorPoint(a))
+    for i := range a {
+        p, q := a[i], a[(i+1)%len(a)]
+        probes = append(probes, p, Point2{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2})
+    }
+    for _, p := range probes {
+        if strictlyInside(p, b, eps) {
+            return true
+        }
+    }
+    return false
+}
+
+// strictlyInside reports whether p is inside pts and more than eps away from
+// its boundary.
+func strictlyInside(p Point2, pts []Point2, eps float64) bool {
+    if !pointInPolygon(p, pts) {
+        return false
+    }
+    for i := range pts {
+        if pointSegmentDistance(p, pts[i], pts[(i+1)%len(pts)]) <= eps {
+            return false
+        }
+    }
+    return true
+}
+
+// pointSegmentDistance returns the distance from p to segment ab.
+func pointSegmentDistance(p, a, b Point2) float64 {
+    dx, dy := b.X-a.X, b.Y-a.Y
+    l2 := dx*dx + dy*dy
+    if l2 == 0 {
+        return math.Hypot(p.X-a.X, p.Y-a.Y)
+    }
+    t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / l2
+    t = math.Max(0, math.Min(1, t))
+    return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
+
+// interiorPoint returns a point inside a simple polygon: the centroid of its
+// first ear, which is also correct for concave polygons.
+func interiorPoint(pts []Point2) Point2 {
+    n := len(pts)
+    ccw := polygonArea(pts) > 0
+    for i := 0; i < n; i++ {
+        a, b, c := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+        cr := (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+        if cr == 0 || (cr > 0) != ccw {
+            continue // reflex or flat corner
+        }
+        tri := []Point2{a, b, c}
+        ear := true
+        for k := 0; k < n; k++ {
+            if k == i || k == (i+n-1)%n || k == (i+1)%n {
+                continue
+            }
+            if pointInPolygon(pts[k], tri) {
+                ear = false
+                break
+            }
+        }
+        if ear {
+            return Point2{X: (a.X + b.X + c.X) / 3, Y: (a.Y + b.Y + c.Y) / 3}
+        }
+    }
+    // degenerate polygon: fall back to the vertex average
+    var c Point2
+    for _, p := range pts {
+        c.X += p.X
+        c.Y += p.Y
+    }
+    if n > 0 {
+        c.X /= float64(n)
+        c.Y /= float64(n)
+    }
+    return c
+}
@@ -0,0 +1,124 @@
+package unfolder
+
+import "math"
+
+// -----------------------------
+//   Overlap area
+// -----------------------------
+
+// OverlapArea is how much two overlapping faces cover each other.
+type OverlapArea struct {
+    FaceA    int     `json:"faceA"`
+    FaceB    int     `json:"faceB"`
+    Area     float64 `json:"area"`     // in net units²
+    Fraction float64 `json:"fraction"` // Area over the smaller face's area
+}
+
+// OverlapReport measures every overlap in a net. Total adds up the pairs, so
+// a spot covered by three faces counts three times.
+type OverlapReport struct {
+    Pairs   []OverlapArea `json:"pairs"`
+    Total   float64       `json:"total"`
+    Largest OverlapArea   `json:"largest"` // zero if there are no overlaps
+}
+
+// MeasureOverlaps works out the overlapping area of every overlapping face
+// pair (result.Overlaps if set, else DetectOverlaps), so slivers a few
+// rounding errors wide can be told apart from faces folded onto each other.
+// Pairs are in the order of the overlap list.
+func MeasureOverlaps(result *UnfoldResult) OverlapReport {
+    var rep OverlapReport
+    if result == nil {
+        return rep
+    }
+    pairs := result.Overlaps
+    if pairs == nil {
+        pairs = DetectOverlaps(result)
+    }
+    rep.Pairs = make([]OverlapArea, 0, len(pairs))
+    for _, p := range pairs {
+        a, b := result.Face2D[p.FaceA].Vertices, result.Face2D[p.FaceB].Vertices
+        o := OverlapArea{FaceA: p.FaceA, FaceB: p.FaceB, Area: intersectionArea(a, b)}
+        if small := math.Min(math.Abs(polygonArea(a)), math.Abs(polygonArea(b))); small > 0 {
+            o.Fraction = o.Area / small
+        }
+        rep.Pairs = append(rep.Pairs, o)
+        rep.Total += o.Area
+        if o.Area > rep.Largest.Area {
+            rep.Largest = o
+        }
+    }
+    return rep
+}
+
+// intersectionArea returns the area two simple polygons share. Each polygon
+// is split into a fan of signed triangles around its first corner (the
+// signs cancel where a concave polygon's fan covers too much), so the
+// intersection is the signed sum of convex triangle-triangle intersections.
+func intersectionArea(a, b []Point2) float64 {
+    if len(a) < 3 || len(b) < 3 {
+        return 0
+    }
+    var total float64
+    for i := 1; i+1 < len(a); i++ {
+        ta := []Point2{a[0], a[i], a[i+1]}
+        sa := signOf(polygonArea(ta))
+        if sa == 0 {
+            continue
+        }
+        for j := 1; j+1 < len(b); j++ {
+            tb := []Point2{b[0], b[j], b[j+1]}
+            sb := signOf(polygonArea(tb))
+            if sb == 0 {
+                continue
+            }
+            total += sa * sb * math.Abs(polygonArea(clipConvex(ccw(ta), ccw(tb))))
+        }
+    }
+    return math.Abs(total)
+}
+
+// clipConvex clips the convex CCW polygon subject by the convex CCW polygon
+// clip (Sutherland-Hodgman).
+func clipConvex(subject, clip []Point2) []Point2 {
+    out := subject
+    for i := range clip {
+        if len(out) == 0 {
+            break
+        }
+        c0, c1 := clip[i], clip[(i+1)%len(clip)]
+        side := func(p Point2) float64 { return (c1.X-c0.X)*(p.Y-c0.Y) - (c1.Y-c0.Y)*(p.X-c0.X) }
+        in := out
+        out = nil
+        for k := range in {
+            p, q := in[k], in[(k+1)%len(in)]
+            sp, sq := side(p), side(q)
+            if sp >= 0 {
+                out = append(out, p)
+            }
+            if (sp >= 0) != (sq >= 0) {
+                t := sp / (sp - sq)
+                out = append(out, Point2{X: p.X + t*(q.X-p.X), Y: p.Y + t*(q.Y-p.Y)})
+            }
+        }
+    }
+    return out
+}
+
+// ccw returns the triangle in CCW order.
+func ccw(t []Point2) []Point2 {
+    if polygonArea(t) < 0 {
+        return []Point2{t[0], t[2], t[1]}
+    }
+    return t
+}
+
+func signOf(x float64) float64 {
+    switch {
+    case x > 0:
+        return 1
+    case x < 0:
+        return -1
+    }
+    return 0
+}
@@ -0,0 +1,136 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Reference overlays (rulers, scale figures)
+// -----------------------------
+
+// Overlay is extra 2D artwork placed on an exported sheet next to the net,
+// such as a ruler or a scale figure. Coordinates are in net units.
+type Overlay struct {
+    Name      string
+    Polylines []OverlayLine
+    Texts     []OverlayText
+
+    // Cut marks the polylines as cutting lines (stencil outlines, seam
+    // allowances): exporters draw them like the net's cut edges instead of
+    // as reference artwork. Texts are never cut.
+    Cut bool
+}
+
+// OverlayLine is a polyline; Closed joins the last point back to the first.
+type OverlayLine struct {
+    Points []Point2
+    Closed bool
+}
+
+// OverlayText is a text label anchored at its baseline start.
+type OverlayText struct {
+    At   Point2
+    Text string
+    Size float64 // cap height in net units
+}
+
+// Translate moves the whole overlay by (dx, dy) in place and returns it.
+func (o *Overlay) Translate(dx, dy float64) *Overlay {
+    for i := range o.Polylines {
+        for j := range o.Polylines[i].Points {
+            o.Polylines[i].Points[j].X += dx
+            o.Polylines[i].Points[j].Y += dy
+        }
+    }
+    for i := range o.Texts {
+        o.Texts[i].At.X += dx
+        o.Texts[i].At.Y += dy
+    }
+    return o
+}
+
+// Bounds returns the overlay's bounding box.
+func (o *Overlay) Bounds() (min, max Point2) {
+    min = Point2{math.Inf(1), math.Inf(1)}
+    max = Point2{math.Inf(-1), math.Inf(-1)}
+    grow := func(p Point2) {
+        min.X, min.Y = math.Min(min.X, p.X), math.Min(min.Y, p.Y)
+        max.X, max.Y = math.Max(max.X, p.X), math.Max(max.Y, p.Y)
+    }
+    for _, l := range o.Polylines {
+        for _, p := range l.Points {
+            grow(p)
+        }
+    }
+    for _, t := range o.Texts {
+        grow(t.At)
+        grow(Point2{t.At.X + 0.6*t.Size*float64(len(t.Text)), t.At.Y + t.Size})
+    }
+    return min, max
+}
+
+// Ruler draws a ruler of the given length starting at the origin, with a tick
+// every tick units (longer and labelled every fifth). unit is only used for
+// the labels, e.g. "mm".
+func Ruler(length, tick float64, unit string) *Overlay {
+    if tick <= 0 || length <= 0 {
+        return &Overlay{Name: "ruler"}
+    }
+    major := 3 * tick
+    o := &Overlay{Name: "ruler"}
+    o.Polylines = append(o.Polylines, OverlayLine{Points: []Point2{{0, 0}, {length, 0}}})
+    n := int(math.Floor(length/tick + 1e-9))
+    for i := 0; i <= n; i++ {
+        x := float64(i) * tick
+        h := tick
+        if i%5 == 0 {
+            h = major
+            o.Texts = append(o.Texts, OverlayText{
+                At:   Point2{x, major + tick/2},
+                Text: fmt.Sprintf("%g", x),
+                Size: tick * 1.5,
+            })
+        }
+        o.Polylines = append(o.Polylines, OverlayLine{Points: []Point2{{x, 0}, {x, h}}})
+    }
+    o.Texts = append(o.Texts, OverlayText{At: Point2{length + tick, 0}, Text: unit, Size: tick * 1.5})
+    return o
+}
+
+// humanOutline is a standing figure 1 unit tall, feet centred on the origin.
+var humanOutline = []Point2{
+    {-0.09, 0}, {-0.02, 0}, {0, 0.40}, {0.02, 0}, {0.09, 0},
+    {0.07, 0.48}, {0.09, 0.50}, {0.12, 0.48}, {0.14, 0.50}, {0.12, 0.82},
+    {0.03, 0.85}, {0.03, 0.87}, {-0.03, 0.87}, {-0.03, 0.85}, {-0.12, 0.82},
+    {-0.14, 0.50}, {-0.12, 0.48}, {-0.09, 0.50}, {-0.07, 0.48},
+}
+
+// HumanFigure draws a standing person of realHeight (in model units, e.g.
+// 1750 for a model in mm) at the model scale (e.g. 1.0/100), feet on the
+// origin, with a caption like "1750 @ 1:100".
+func HumanFigure(realHeight, modelScale float64) *Overlay {
+    h := realHeight * modelScale
+    o := &Overlay{Name: "human"}
+    body := make([]Point2, len(humanOutline))
+    for i, p := range humanOutline {
+        body[i] = Point2{p.X * h, p.Y * h}
+    }
+    o.Polylines = append(o.Polylines, OverlayLine{Points: body, Closed: true})
+
+    // head
+    const segs = 16
+    head := make([]Point2, segs)
+    for i := range head {
+        a := 2 * math.Pi * float64(i) / segs
+        head[i] = Point2{0.065 * h * math.Cos(a), (0.935 + 0.065*math.Sin(a)) * h}
+    }
+    o.Polylines = append(o.Polylines, OverlayLine{Points: head, Closed: true})
+
+    caption := fmt.Sprintf("%g", realHeight)
+    if modelScale > 0 && modelScale != 1 {
+        caption += fmt.Sprintf(" @ 1:%g", math.Round(1/modelScale*1000)/1000)
+    }
+    o.Texts = append(o.Texts, OverlayText{At: Point2{-0.15 * h, -0.12 * h}, Text: caption, Size: 0.06 * h})
+    return o
+}
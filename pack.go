@@ -0,0 +1,321 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Packing pieces onto sheets
+// -----------------------------
+
+// PiecePlacement says where PackNets put one piece: turned by Angle
+// (radians, CCW) about the origin, then moved by Offset, onto sheet Sheet.
+// Each sheet has its own coordinates, from (0, 0) to the sheet size.
+type PiecePlacement struct {
+    Piece  int     `json:"piece"` // index into the packed results
+    Sheet  int     `json:"sheet"`
+    Angle  float64 `json:"angle"`
+    Offset Point2  `json:"offset"`
+}
+
+// Point maps a point of the piece to its place on the sheet.
+func (p PiecePlacement) Point(q Point2) Point2 {
+    c, s := math.Cos(p.Angle), math.Sin(p.Angle)
+    return Point2{X: q.X*c - q.Y*s + p.Offset.X, Y: q.X*s + q.Y*c + p.Offset.Y}
+}
+
+// Apply returns a copy of res moved to its place on the sheet, tabs and
+// double walls included.
+func (p PiecePlacement) Apply(res *UnfoldResult) *UnfoldResult {
+    out := transformResult(res, p.Angle, p.Offset)
+    for _, t := range res.Tabs {
+        t.Vertices = p.points(t.Vertices)
+        out.Tabs = append(out.Tabs, t)
+    }
+    for _, dw := range res.DoubleWalls {
+        dw.Vertices = p.points(dw.Vertices)
+        out.DoubleWalls = append(out.DoubleWalls, dw)
+    }
+    return out
+}
+
+func (p PiecePlacement) points(pts []Point2) []Point2 {
+    out := make([]Point2, len(pts))
+    for i, q := range pts {
+        out[i] = p.Point(q)
+    }
+    return out
+}
+
+// Packing is the result of PackNets.
+type Packing struct {
+    Width, Height float64          // the sheet size packed into
+    Sheets        int              // sheets used
+    Placements    []PiecePlacement // one per piece, in the order of the pieces
+}
+
+// PackNets nests the pieces of a split net (one UnfoldResult per piece, as
+// from UnfoldMeshSegmented, UnfoldStrips or CompactPieces) onto as few
+// sheetW x sheetH sheets as it can, at least spacing apart. Pieces can sit
+// right at the edge of a sheet; take the printer's margin off the sheet
+// size.
+//
+// Each piece is packed as its smallest bounding rectangle, turned either of
+// the two ways that make that rectangle axis aligned, largest piece first,
+// onto the first sheet with room for it (maximal rectangles, best short
+// side fit). Pieces don't nest into each other's concave bits, so compact
+// them first. Tabs and double walls count as part of the piece. A piece
+// bigger than a sheet either way round is an error.
+//
+// The results aren't changed; apply the placements with
+// PiecePlacement.Apply, or get one result per sheet with SheetResults.
+func PackNets(results []UnfoldResult, sheetW, sheetH, spacing float64) (*Packing, error) {
+    if !(sheetW > 0 && sheetH > 0) {
+        return nil, fmt.Errorf("sheet size %gx%g is not positive", sheetW, sheetH)
+    }
+    if spacing < 0 {
+        return nil, fmt.Errorf("negative spacing %g", spacing)
+    }
+    type shape struct {
+        angles [2]float64
+        lo     [2]Point2 // lower left corner after turning by angles[k]
+        w, h   float64   // size at angles[0]; at angles[1] it's h x w
+    }
+    shapes := make([]shape, len(results))
+    for i := range results {
+        pts := resultPoints(&results[i])
+        if len(pts) == 0 {
+            return nil, fmt.Errorf("piece %d has no placed faces", i)
+        }
+        _, theta := minAreaRectOf(pts)
+        s := shape{angles: [2]float64{-theta, math.Pi/2 - theta}}
+        for k, a := range s.angles {
+            lo, hi := turnedBounds(pts, a)
+            s.lo[k] = lo
+            if k == 0 {
+                s.w, s.h = hi.X-lo.X, hi.Y-lo.Y
+            }
+        }
+        shapes[i] = s
+    }
+
+    order := make([]int, len(results))
+    for i := range order {
+        order[i] = i
+    }
+    sort.SliceStable(order, func(a, b int) bool {
+        sa, sb := shapes[order[a]], shapes[order[b]]
+        if aa, ab := sa.w*sa.h, sb.w*sb.h; aa != ab {
+            return aa > ab
+        }
+        return math.Max(sa.w, sa.h) > math.Max(sb.w, sb.h)
+    })
+
+    // every piece is padded by spacing on its top and right, the sheets too,
+    // so pieces end up spacing apart and can still touch the sheet's edge
+    eps := 1e-9 * math.Max(sheetW, sheetH)
+    W, H := sheetW+spacing, sheetH+spacing
+    pk := &Packing{Width: sheetW, Height: sheetH, Placements: make([]PiecePlacement, len(results))}
+    var sheets []*maxRects
+    for _, i := range order {
+        s := shapes[i]
+        w, h := s.w+spacing, s.h+spacing
+        if !(w <= W+eps && h <= H+eps) && !(h <= W+eps && w <= H+eps) {
+            return nil, fmt.Errorf("piece %d (%.4gx%.4g) doesn't fit on a %gx%g sheet", i, s.w, s.h, sheetW, sheetH)
+        }
+        placed := false
+        for n := 0; !placed; n++ {
+            if n == len(sheets) {
+                sheets = append(sheets, newMaxRects(W, H))
+            }
+            x, y, turned, ok := sheets[n].insert(w, h, eps)
+            if !ok {
+                continue
+            }
+            k := 0
+            if turned {
+                k = 1
+            }
+            pk.Placements[i] = PiecePlacement{
+                Piece:  i,
+                Sheet:  n,
+                Angle:  s.angles[k],
+                Offset: Point2{X: x - s.lo[k].X, Y: y - s.lo[k].Y},
+            }
+            placed = true
+        }
+    }
+    pk.Sheets = len(sheets)
+    return pk, nil
+}
+
+// SheetResults puts the packed pieces together into one result per sheet,
+// for the exporters. The pieces must be parts of the same mesh (their Face2D
+// the same length) with no face placed twice, as a split net's are.
+func (pk *Packing) SheetResults(results []UnfoldResult) ([]*UnfoldResult, error) {
+    if len(pk.Placements) != len(results) {
+        return nil, fmt.Errorf("packing has %d pieces, got %d results", len(pk.Placements), len(results))
+    }
+    if len(results) == 0 {
+        return nil, errors.New("no pieces")
+    }
+    nFaces := len(results[0].Face2D)
+    out := make([]*UnfoldResult, pk.Sheets)
+    for n := range out {
+        out[n] = &UnfoldResult{
+            Face2D:       make([]Face2D, nFaces),
+            SpanningTree: make([]int, nFaces),
+            Side:         results[0].Side,
+        }
+        for f := range out[n].SpanningTree {
+            out[n].SpanningTree[f] = -1
+        }
+    }
+    placedIn := make([]int, nFaces)
+    for f := range placedIn {
+        placedIn[f] = -1
+    }
+    for i, p := range pk.Placements {
+        if len(results[i].Face2D) != nFaces {
+            return nil, fmt.Errorf("piece %d has %d faces, piece 0 has %d", i, len(results[i].Face2D), nFaces)
+        }
+        if p.Sheet < 0 || p.Sheet >= pk.Sheets {
+            return nil, fmt.Errorf("piece %d is on sheet %d of %d", i, p.Sheet, pk.Sheets)
+        }
+        moved := p.Apply(&results[i])
+        sheet := out[p.Sheet]
+        for f, f2 := range moved.Face2D {
+            if len(f2.Vertices) == 0 {
+                continue
+            }
+            if placedIn[f] >= 0 {
+                return nil, fmt.Errorf("face %d is in both piece %d and piece %d", f, placedIn[f], i)
+            }
+            placedIn[f] = i
+            sheet.Face2D[f] = f2
+            if f < len(moved.SpanningTree) {
+                sheet.SpanningTree[f] = moved.SpanningTree[f]
+            }
+        }
+        sheet.VertexInstances = append(sheet.VertexInstances, moved.VertexInstances...)
+        sheet.FoldEdges = append(sheet.FoldEdges, moved.FoldEdges...)
+        sheet.CutEdges = append(sheet.CutEdges, moved.CutEdges...)
+        sheet.Tabs = append(sheet.Tabs, moved.Tabs...)
+        sheet.DoubleWalls = append(sheet.DoubleWalls, moved.DoubleWalls...)
+    }
+    for _, sheet := range out {
+        sortNetEdges(sheet.FoldEdges)
+        sortNetEdges(sheet.CutEdges)
+    }
+    return out, nil
+}
+
+// resultPoints lists every point of the placed faces, tabs and double walls.
+func resultPoints(res *UnfoldResult) []Point2 {
+    var pts []Point2
+    for _, f := range res.Face2D {
+        pts = append(pts, f.Vertices...)
+    }
+    for _, t := range res.Tabs {
+        pts = append(pts, t.Vertices...)
+    }
+    for _, dw := range res.DoubleWalls {
+        pts = append(pts, dw.Vertices...)
+    }
+    return pts
+}
+
+// turnedBounds is the bounding box of pts turned by angle about the origin.
+func turnedBounds(pts []Point2, angle float64) (lo, hi Point2) {
+    c, s := math.Cos(angle), math.Sin(angle)
+    lo = Point2{math.Inf(1), math.Inf(1)}
+    hi = Point2{math.Inf(-1), math.Inf(-1)}
+    for _, p := range pts {
+        x, y := p.X*c-p.Y*s, p.X*s+p.Y*c
+        lo.X, lo.Y = math.Min(lo.X, x), math.Min(lo.Y, y)
+        hi.X, hi.Y = math.Max(hi.X, x), math.Max(hi.Y, y)
+    }
+    return lo, hi
+}
+
+// maxRects tracks the free space of one sheet as the maximal empty
+// rectangles left in it, which may overlap.
+type maxRects struct {
+    free []rect2
+}
+
+type rect2 struct{ x, y, w, h float64 }
+
+func newMaxRects(w, h float64) *maxRects {
+    return &maxRects{free: []rect2{{0, 0, w, h}}}
+}
+
+// insert finds room for a w x h rectangle, either way round, in the free
+// rectangle it fits most snugly (the smaller leftover side the smallest),
+// and takes it out of the free space. turned says it went in as h x w.
+func (m *maxRects) insert(w, h, eps float64) (x, y float64, turned, ok bool) {
+    best := math.Inf(1)
+    var got rect2
+    for _, r := range m.free {
+        for k, d := range [2][2]float64{{w, h}, {h, w}} {
+            if d[0] > r.w+eps || d[1] > r.h+eps {
+                continue
+            }
+            fit := math.Min(r.w-d[0], r.h-d[1])
+            if fit < best {
+                best, ok = fit, true
+                got = rect2{r.x, r.y, d[0], d[1]}
+                turned = k == 1
+            }
+        }
+    }
+    if !ok {
+        return 0, 0, false, false
+    }
+    m.cut(got)
+    return got.x, got.y, turned, true
+}
+
+// cut takes u out of the free rectangles, splitting every one it overlaps
+// into the (up to four) maximal rectangles around it, and drops free
+// rectangles inside others.
+func (m *maxRects) cut(u rect2) {
+    var next []rect2
+    for _, r := range m.free {
+        if u.x >= r.x+r.w || u.x+u.w <= r.x || u.y >= r.y+r.h || u.y+u.h <= r.y {
+            next = append(next, r)
+            continue
+        }
+        if u.x > r.x {
+            next = append(next, rect2{r.x, r.y, u.x - r.x, r.h})
+        }
+        if u.x+u.w < r.x+r.w {
+            next = append(next, rect2{u.x + u.w, r.y, r.x + r.w - u.x - u.w, r.h})
+        }
+        if u.y > r.y {
+            next = append(next, rect2{r.x, r.y, r.w, u.y - r.y})
+        }
+        if u.y+u.h < r.y+r.h {
+            next = append(next, rect2{r.x, u.y + u.h, r.w, r.y + r.h - u.y - u.h})
+        }
+    }
+    inside := func(a, b rect2) bool {
+        return a.x >= b.x && a.y >= b.y && a.x+a.w <= b.x+b.w && a.y+a.h <= b.y+b.h
+    }
+    m.free = m.free[:0]
+    for i, a := range next {
+        keep := true
+        for j, b := range next {
+            if i != j && inside(a, b) && (!inside(b, a) || j < i) {
+                keep = false
+                break
+            }
+        }
+        if keep {
+            m.free = append(m.free, a)
+        }
+    }
+}
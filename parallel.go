@@ -0,0 +1,228 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "runtime"
+    "sort"
+    "sync"
+)
+
+// -----------------------------
+//   Parallel unfolding (large meshes)
+// -----------------------------
+
+// UnfoldMeshParallel is UnfoldMesh for big meshes (100k+ faces): the edge map
+// is built in shards by several goroutines, and once the spanning tree is
+// known the subtrees near the root are placed concurrently, each face only
+// needing its parent's position. workers <= 0 means GOMAXPROCS. The net is
+// the same as UnfoldMesh's.
+func UnfoldMeshParallel(poly Polyhedron, rootFace int, workers int) (result *UnfoldResult, err error) {
+    at := &progress{stage: "adjacency", face: -1}
+    defer at.recover(&err)
+    nFaces := len(poly.Faces)
+    if nFaces == 0 {
+        return nil, errors.New("polyhedron has no faces")
+    }
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, err
+    }
+    if workers <= 0 {
+        workers = runtime.GOMAXPROCS(0)
+    }
+    adj := buildFaceAdjacencyParallel(poly, workers)
+    at.stage = "spanning-tree"
+    parent := BuildFaceSpanningTree(adj, rootFace, nFaces)
+
+    at.stage, at.face = "placement", rootFace
+    face2Ds := make([]Face2D, nFaces)
+    if err := placeRootFace(poly, rootFace, &face2Ds[rootFace]); err != nil {
+        return nil, fmt.Errorf("failed to place root face: %w", err)
+    }
+    placed := make([]bool, nFaces)
+    placed[rootFace] = true
+
+    // placeChildren places the children of f and returns them
+    placeChildren := func(f int, folds *[]NetEdge) ([]int, error) {
+        var kids []int
+        for _, nbr := range adj.Neighbors[f] {
+            c := nbr.FaceIndex
+            if parent[c] != f || placed[c] {
+                continue
+            }
+            nbr := nbr
+            if err := placeAdjacentFace(poly, f, c, &face2Ds[f], &face2Ds[c], &nbr, AnchorEdge); err != nil {
+                return nil, fmt.Errorf("failed to place face %d adjacent to %d: %w", c, f, err)
+            }
+            placed[c] = true
+            kids = append(kids, c)
+            childEdge, _ := findEdgeInFace(poly.Faces[c], nbr.SharedEdge)
+            *folds = append(*folds, NetEdge{
+                Vertices: nbr.SharedEdge,
+                FaceA:    f,
+                EdgeA:    nbr.ThisFaceEdge[0],
+                FaceB:    c,
+                EdgeB:    childEdge[0],
+            })
+        }
+        return kids, nil
+    }
+
+    // 1) breadth first near the root until there are enough subtrees to share
+    var folds []NetEdge
+    frontier := []int{rootFace}
+    for len(frontier) > 0 && len(frontier) < 4*workers {
+        f := frontier[0]
+        frontier = frontier[1:]
+        at.face = f
+        kids, err := placeChildren(f, &folds)
+        if err != nil {
+            return nil, err
+        }
+        frontier = append(frontier, kids...)
+    }
+
+    // 2) every frontier face roots a subtree no other goroutine touches; a
+    // panic in one is its error, the recover above can't see other goroutines
+    var wg sync.WaitGroup
+    jobs := make(chan int)
+    subFolds := make([][]NetEdge, len(frontier))
+    errs := make([]error, len(frontier))
+    subtree := func(i int) (err error) {
+        at := &progress{stage: "placement", face: frontier[i]}
+        defer at.recover(&err)
+        stack := []int{frontier[i]}
+        for len(stack) > 0 {
+            f := stack[len(stack)-1]
+            stack = stack[:len(stack)-1]
+            at.face = f
+            kids, err := placeChildren(f, &subFolds[i])
+            if err != nil {
+                return err
+            }
+            stack = append(stack, kids...)
+        }
+        return nil
+    }
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                errs[i] = subtree(i)
+            }
+        }()
+    }
+    for i := range frontier {
+        jobs <- i
+    }
+    close(jobs)
+    wg.Wait()
+    for i, err := range errs {
+        if err != nil {
+            return nil, err
+        }
+        folds = append(folds, subFolds[i]...)
+    }
+
+    at.stage, at.face = "post-processing", -1
+    return finishResult(poly, face2Ds, parent, folds, UnfoldOptions{}, nil), nil
+}
+
+// edgeEntry is one face edge on its way into the sharded edge map.
+type edgeEntry struct {
+    edge [2]int
+    face int
+}
+
+// adjPair is two faces sharing an edge, found by one shard.
+type adjPair struct {
+    edge   [2]int
+    f0, f1 int
+    e0, e1 [2]int
+}
+
+// buildFaceAdjacencyParallel is BuildFaceAdjacency with the edge map split
+// into shards by edge: workers first sort their range of faces' edges into
+// shards, then each shard is matched up on its own. Only the final merge into
+// Neighbors is sequential.
+func buildFaceAdjacencyParallel(poly Polyhedron, workers int) *FaceAdjacency {
+    nFaces := len(poly.Faces)
+    shards := workers
+    shardOf := func(e [2]int) int {
+        return int((uint64(e[0])*2654435761 ^ uint64(e[1])) % uint64(shards))
+    }
+
+    // 1) faces -> per-worker, per-shard edge lists
+    buckets := make([][][]edgeEntry, workers)
+    var wg sync.WaitGroup
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func(w int) {
+            defer wg.Done()
+            buckets[w] = make([][]edgeEntry, shards)
+            for f := w * nFaces / workers; f < (w+1)*nFaces/workers; f++ {
+                vs := poly.Faces[f].Vertices
+                for i := range vs {
+                    e := sortPair(vs[i], vs[(i+1)%len(vs)])
+                    s := shardOf(e)
+                    buckets[w][s] = append(buckets[w][s], edgeEntry{e, f})
+                }
+            }
+        }(w)
+    }
+    wg.Wait()
+
+    // 2) shards -> face pairs
+    pairs := make([][]adjPair, shards)
+    for s := 0; s < shards; s++ {
+        wg.Add(1)
+        go func(s int) {
+            defer wg.Done()
+            edgeMap := make(map[[2]int][]int)
+            var order [][2]int
+            for w := 0; w < workers; w++ {
+                for _, en := range buckets[w][s] {
+                    if _, ok := edgeMap[en.edge]; !ok {
+                        order = append(order, en.edge)
+                    }
+                    edgeMap[en.edge] = append(edgeMap[en.edge], en.face)
+                }
+            }
+            for _, e := range order {
+                faces := edgeMap[e]
+                if len(faces) != 2 {
+                    continue
+                }
+                e0, err0 := findEdgeInFace(poly.Faces[faces[0]], e)
+                e1, err1 := findEdgeInFace(poly.Faces[faces[1]], e)
+                if err0 != nil || err1 != nil {
+                    continue
+                }
+                pairs[s] = append(pairs[s], adjPair{e, faces[0], faces[1], e0, e1})
+            }
+        }(s)
+    }
+    wg.Wait()
+
+    // 3) merge, then put every face's list in edge order like BuildFaceAdjacency
+    adj := &FaceAdjacency{Neighbors: make([][]FaceNeighbor, nFaces)}
+    for _, ps := range pairs {
+        for _, p := range ps {
+            adj.Neighbors[p.f0] = append(adj.Neighbors[p.f0], FaceNeighbor{FaceIndex: p.f1, SharedEdge: p.edge, ThisFaceEdge: p.e0})
+            adj.Neighbors[p.f1] = append(adj.Neighbors[p.f1], FaceNeighbor{FaceIndex: p.f0, SharedEdge: p.edge, ThisFaceEdge: p.e1})
+        }
+    }
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func(w int) {
+            defer wg.Done()
+            for f := w * nFaces / workers; f < (w+1)*nFaces/workers; f++ {
+                nbrs := adj.Neighbors[f]
+                sort.Slice(nbrs, func(i, j int) bool { return nbrs[i].ThisFaceEdge[0] < nbrs[j].ThisFaceEdge[0] })
+            }
+        }(w)
+    }
+    wg.Wait()
+    return adj
+}
@@ -0,0 +1,490 @@
+package unfolder
+
+import (
+    "bufio"
+    "bytes"
+    "compress/zlib"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+)
+
+// -----------------------------
+//   PDF export with page tiling
+// -----------------------------
+
+// PageSize is a paper size in millimetres (portrait).
+type PageSize struct {
+    Name          string
+    Width, Height float64
+}
+
+// Common paper sizes.
+var (
+    PageA4     = PageSize{"A4", 210, 297}
+    PageA3     = PageSize{"A3", 297, 420}
+    PageLetter = PageSize{"Letter", 215.9, 279.4}
+    PageLegal  = PageSize{"Legal", 215.9, 355.6}
+)
+
+// PDFOptions controls ExportPDF. The zero value prints on A4 at
+// 1 net unit = 1 mm, or at the mesh's real size if Mesh has Units.
+type PDFOptions struct {
+    // Page is the paper size. Default PageA4.
+    Page PageSize
+    // Landscape swaps the page width and height.
+    Landscape bool
+    // Margin is the unprinted border on every side, in mm. Default 10 (most
+    // home printers can't print closer to the edge).
+    Margin float64
+    // Overlap is how much neighbouring tiles share, in mm, so the pages can be
+    // lined up and glued. Default 10.
+    Overlap float64
+    // Scale converts net units to millimetres. Default: from Mesh.Units if
+    // set, else 1.
+    Scale float64
+    // StrokeWidth of net lines, in mm. Default 0.2.
+    StrokeWidth float64
+    // NoRegistrationMarks leaves out the alignment crosses and page labels.
+    NoRegistrationMarks bool
+
+    // Duplex follows every page with its back, for printing on both sides:
+    // the net mirrored so the back of every face lands right behind its
+    // front, with BackOverlays (folding instructions, say) instead of the
+    // front artwork and the outline in light grey. Targets in the margins sit
+    // in the same spot on both sides; hold the sheet against the light to
+    // check the printer's registration.
+    Duplex bool
+    // DuplexShortEdge is for printers set to flip on the short edge; the
+    // default is the long edge.
+    DuplexShortEdge bool
+    // DuplexOffset moves the back pages by this much, in mm (X right, Y up),
+    // to make up for a printer that doesn't line the two sides up.
+    DuplexOffset Point2
+    // BackOverlays are drawn on the back pages, in net coordinates like
+    // Overlays.
+    BackOverlays []*Overlay
+
+    // Mesh, FaceLabels, LabelSize, Overlays, EdgeLabels, FaceGroups,
+    // MinFoldAngle and FoldHeatmap work as in SVGOptions (LabelSize in mm).
+    Mesh         *Polyhedron
+    FaceLabels   bool
+    LabelSize    float64
+    Overlays     []*Overlay
+    EdgeLabels   bool
+    FaceGroups   []int
+    MinFoldAngle float64
+    FoldHeatmap  bool
+}
+
+const mmToPt = 72 / 25.4
+
+// ExportPDF writes the net as a PDF, tiled across as many pages as it needs at
+// the requested scale. Tiles are numbered row by row from the top left and
+// overlap by opts.Overlap; every page carries a cross at each tile corner that
+// falls on it, and the crosses of neighbouring pages coincide once the
+// overlapping strips are laid on top of each other.
+func ExportPDF(result *UnfoldResult, w io.Writer, opts PDFOptions) error {
+    pages, page, err := pdfNetPages(result, opts)
+    if err != nil {
+        return err
+    }
+    return writePDF(w, pages, page.Width*mmToPt, page.Height*mmToPt)
+}
+
+// pdfNetPages draws the pages of ExportPDF and returns them with the paper
+// size they're on (turned for Landscape).
+func pdfNetPages(result *UnfoldResult, opts PDFOptions) ([][]byte, PageSize, error) {
+    if result == nil {
+        return nil, PageSize{}, errors.New("nil unfold result")
+    }
+    mesh, err := netMesh(result, opts.Mesh)
+    if err != nil {
+        return nil, PageSize{}, err
+    }
+    opts.Mesh = mesh
+    if opts.EdgeLabels {
+        n := len(opts.Overlays)
+        opts.Overlays = append(opts.Overlays[:n:n], EdgeLabelOverlay(result, AssignEdgeLabels(result), 0))
+    }
+    if opts.Page.Width <= 0 || opts.Page.Height <= 0 {
+        opts.Page = PageA4
+    }
+    if opts.Landscape {
+        opts.Page.Width, opts.Page.Height = opts.Page.Height, opts.Page.Width
+    }
+    if opts.Margin <= 0 {
+        opts.Margin = 10
+    }
+    if opts.Overlap < 0 {
+        opts.Overlap = 0
+    } else if opts.Overlap == 0 {
+        opts.Overlap = 10
+    }
+    if opts.Scale <= 0 {
+        opts.Scale = exportScale(opts.Mesh, "mm")
+    }
+    if opts.StrokeWidth <= 0 {
+        opts.StrokeWidth = 0.2
+    }
+    if opts.LabelSize <= 0 {
+        opts.LabelSize = 3
+    }
+
+    // printable area per page, and the step between tiles
+    pw := opts.Page.Width - 2*opts.Margin
+    ph := opts.Page.Height - 2*opts.Margin
+    if pw <= opts.Overlap || ph <= opts.Overlap {
+        return nil, PageSize{}, fmt.Errorf("page %s leaves no room for tiles with %gmm margins and %gmm overlap", opts.Page.Name, opts.Margin, opts.Overlap)
+    }
+    stepX, stepY := pw-opts.Overlap, ph-opts.Overlap
+
+    lo, hi, ok := sheetBounds(result, opts.Overlays)
+    if !ok {
+        return nil, PageSize{}, errors.New("nothing to draw: no face was placed")
+    }
+    // drawing size in mm; the drawing's top left is the top left of tile (0,0)
+    dw := (hi.X - lo.X) * opts.Scale
+    dh := (hi.Y - lo.Y) * opts.Scale
+    cols := tileCount(dw, pw, stepX)
+    rows := tileCount(dh, ph, stepY)
+
+    // net units -> mm measured from the drawing's top left, Y up
+    toMM := func(p Point2) (float64, float64) {
+        return (p.X - lo.X) * opts.Scale, (p.Y - hi.Y) * opts.Scale
+    }
+
+    lines := sheetLines(result, opts.Mesh, opts.FaceGroups, opts.MinFoldAngle*math.Pi/180)
+    // tile draws page (r, c), or its back
+    tile := func(r, c int, back bool) []byte {
+        var cs pdfContent
+        // page space: origin at the bottom left of the printable area, in mm
+        cs.op("q %s 0 0 %s 0 0 cm", pdfNum(mmToPt), pdfNum(mmToPt))
+        cs.op("1 0 0 1 %s %s cm", pdfNum(opts.Margin), pdfNum(opts.Margin))
+        if back {
+            cs.op("1 0 0 1 %s %s cm", pdfNum(opts.DuplexOffset.X), pdfNum(opts.DuplexOffset.Y))
+            if !opts.NoRegistrationMarks {
+                pdfDuplexTargets(&cs, pw, ph, opts.Margin)
+            }
+            // the back of the sheet is the front turned over its flip edge;
+            // margins are the same all round, so mirroring the printable
+            // area mirrors the page
+            if longVertical := opts.Page.Height >= opts.Page.Width; longVertical != opts.DuplexShortEdge {
+                cs.op("-1 0 0 1 %s 0 cm", pdfNum(pw))
+                cs.flipX = true
+            } else {
+                cs.op("1 0 0 -1 0 %s cm", pdfNum(ph))
+                cs.flipY = true
+            }
+        } else if opts.Duplex && !opts.NoRegistrationMarks {
+            pdfDuplexTargets(&cs, pw, ph, opts.Margin)
+        }
+        cs.op("q 0 0 %s %s re W n", pdfNum(pw), pdfNum(ph))
+        // drawing space -> this tile
+        cs.op("1 0 0 1 %s %s cm", pdfNum(-float64(c)*stepX), pdfNum(ph+float64(r)*stepY))
+        cs.op("%s w 1 J 1 j", pdfNum(opts.StrokeWidth))
+
+        overlays := opts.Overlays
+        if back {
+            // just the outline, to place things by; folds look the other way
+            // round from the back and the front artwork doesn't belong here
+            cs.op("0.75 0.75 0.75 RG [] 0 d")
+            n := 0
+            for _, l := range lines {
+                if l.class == lineCut {
+                    ax, ay := toMM(l.a)
+                    bx, by := toMM(l.b)
+                    cs.op("%s %s m %s %s l", pdfNum(ax), pdfNum(ay), pdfNum(bx), pdfNum(by))
+                    n++
+                }
+            }
+            if n > 0 {
+                cs.op("S")
+            }
+            overlays = opts.BackOverlays
+        } else {
+            for _, class := range []string{lineCut, lineMountain, lineValley, lineFold} {
+                n := 0
+                for _, l := range lines {
+                    if l.class != class {
+                        continue
+                    }
+                    ax, ay := toMM(l.a)
+                    bx, by := toMM(l.b)
+                    if opts.FoldHeatmap && l.bend >= 0 {
+                        // its own colour, so stroke it on its own
+                        if n > 0 {
+                            cs.op("S")
+                            n = 0
+                        }
+                        r, g, b := foldHeat(l.bend)
+                        cs.op("%s", pdfLineStyle(class, opts.StrokeWidth))
+                        cs.op("%s %s %s RG %s %s m %s %s l S", pdfNum(r), pdfNum(g), pdfNum(b), pdfNum(ax), pdfNum(ay), pdfNum(bx), pdfNum(by))
+                        continue
+                    }
+                    if n == 0 {
+                        cs.op("%s", pdfLineStyle(class, opts.StrokeWidth))
+                    }
+                    cs.op("%s %s m %s %s l", pdfNum(ax), pdfNum(ay), pdfNum(bx), pdfNum(by))
+                    n++
+                }
+                if n > 0 {
+                    cs.op("S")
+                }
+            }
+        }
+
+        cs.op("[] 0 d 0.33 0.33 0.33 RG 0.33 0.33 0.33 rg")
+        if opts.FaceLabels {
+            for _, l := range sheetLabels(result, opts.FaceGroups) {
+                x, y := toMM(l.at)
+                cs.text(x-pdfTextWidth(l.text, opts.LabelSize)/2, y-0.35*opts.LabelSize, opts.LabelSize, l.text)
+            }
+        }
+        for _, o := range overlays {
+            if o == nil {
+                continue
+            }
+            if o.Cut {
+                cs.op("%s", pdfLineStyle(lineCut, opts.StrokeWidth))
+            } else {
+                cs.op("0.33 0.33 0.33 RG")
+            }
+            for _, l := range o.Polylines {
+                if len(l.Points) < 2 {
+                    continue
+                }
+                for i, p := range l.Points {
+                    x, y := toMM(p)
+                    if i == 0 {
+                        cs.op("%s %s m", pdfNum(x), pdfNum(y))
+                    } else {
+                        cs.op("%s %s l", pdfNum(x), pdfNum(y))
+                    }
+                }
+                if l.Closed {
+                    cs.op("h")
+                }
+                cs.op("S")
+            }
+            for _, t := range o.Texts {
+                // Size is a cap height; Helvetica caps are 0.72 em
+                x, y := toMM(t.At)
+                cs.text(x, y, t.Size*opts.Scale/0.72, t.Text)
+            }
+        }
+        cs.op("Q") // end of clipped net
+
+        if !opts.NoRegistrationMarks {
+            cs.op("0 0 0 RG 0 0 0 rg 0.1 w [] 0 d")
+            // tile corners in page space: own corners plus the neighbours'
+            // corners that fall inside the overlap
+            for _, x := range []float64{0, opts.Overlap, stepX, pw} {
+                for _, y := range []float64{0, opts.Overlap, stepY, ph} {
+                    if pdfMarkWanted(x, c, cols, opts.Overlap, stepX) && pdfMarkWanted(ph-y, r, rows, opts.Overlap, stepY) {
+                        cs.op("%s %s m %s %s l %s %s m %s %s l S", pdfNum(x-3), pdfNum(y), pdfNum(x+3), pdfNum(y), pdfNum(x), pdfNum(y-3), pdfNum(x), pdfNum(y+3))
+                    }
+                }
+            }
+            label := fmt.Sprintf("page %d of %d (row %d, column %d)", r*cols+c+1, rows*cols, r+1, c+1)
+            if back {
+                label = fmt.Sprintf("back of page %d of %d (row %d, column %d)", r*cols+c+1, rows*cols, r+1, c+1)
+            }
+            y := -opts.Margin/2 - 1
+            if cs.flipY {
+                y = ph + opts.Margin/2 - 2 // keep it in the bottom margin once flipped
+            }
+            cs.text(0, y, 3, label)
+        }
+        cs.op("Q")
+        return cs.Bytes()
+    }
+
+    var pages [][]byte
+    for r := 0; r < rows; r++ {
+        for c := 0; c < cols; c++ {
+            pages = append(pages, tile(r, c, false))
+            if opts.Duplex {
+                pages = append(pages, tile(r, c, true))
+            }
+        }
+    }
+    return pages, opts.Page, nil
+}
+
+// tileCount returns how many tiles of size page, advancing by step, cover
+// length.
+func tileCount(length, page, step float64) int {
+    if length <= page {
+        return 1
+    }
+    return 1 + int(math.Ceil((length-page)/step-1e-9))
+}
+
+// pdfMarkWanted decides whether a registration mark at offset d (from the
+// tile's left, or top) is useful on tile i of n: inner overlap marks only
+// appear where there is a neighbour on that side.
+func pdfMarkWanted(d float64, i, n int, overlap, step float64) bool {
+    const eps = 1e-9
+    switch {
+    case overlap == 0:
+        return true
+    case math.Abs(d-overlap) < eps:
+        return i > 0
+    case math.Abs(d-step) < eps:
+        return i < n-1
+    }
+    return true
+}
+
+// pdfDuplexTargets draws a target in the middle of each margin, around the
+// printable area of size pw x ph. They are symmetric about both page axes, so
+// the front's and the back's coincide when the printer registers exactly.
+func pdfDuplexTargets(cs *pdfContent, pw, ph, margin float64) {
+    const r, k = 2.0, 0.5523 // circle radius; bezier handle for a quarter circle
+    cs.op("0 0 0 RG 0.1 w [] 0 d")
+    for _, p := range []Point2{{X: pw / 2, Y: -margin / 2}, {X: pw / 2, Y: ph + margin/2}, {X: -margin / 2, Y: ph / 2}, {X: pw + margin/2, Y: ph / 2}} {
+        x, y := p.X, p.Y
+        cs.op("%s %s m %s %s l %s %s m %s %s l S", pdfNum(x-1.5*r), pdfNum(y), pdfNum(x+1.5*r), pdfNum(y), pdfNum(x), pdfNum(y-1.5*r), pdfNum(x), pdfNum(y+1.5*r))
+        cs.op("%s %s m", pdfNum(x+r), pdfNum(y))
+        cs.op("%s %s %s %s %s %s c", pdfNum(x+r), pdfNum(y+k*r), pdfNum(x+k*r), pdfNum(y+r), pdfNum(x), pdfNum(y+r))
+        cs.op("%s %s %s %s %s %s c", pdfNum(x-k*r), pdfNum(y+r), pdfNum(x-r), pdfNum(y+k*r), pdfNum(x-r), pdfNum(y))
+        cs.op("%s %s %s %s %s %s c", pdfNum(x-r), pdfNum(y-k*r), pdfNum(x-k*r), pdfNum(y-r), pdfNum(x), pdfNum(y-r))
+        cs.op("%s %s %s %s %s %s c S", pdfNum(x+k*r), pdfNum(y-r), pdfNum(x+r), pdfNum(y-k*r), pdfNum(x+r), pdfNum(y))
+    }
+}
+
+// pdfLineStyle returns the colour and dash operators of a line class.
+func pdfLineStyle(class string, sw float64) string {
+    switch class {
+    case lineMountain:
+        return fmt.Sprintf("0.8 0 0 RG [%s %s %s %s] 0 d", pdfNum(8*sw), pdfNum(4*sw), pdfNum(sw), pdfNum(4*sw))
+    case lineValley:
+        return fmt.Sprintf("0 0 0.8 RG [%s %s] 0 d", pdfNum(6*sw), pdfNum(4*sw))
+    case lineFold:
+        return fmt.Sprintf("0.47 0.47 0.47 RG [%s %s] 0 d", pdfNum(6*sw), pdfNum(4*sw))
+    }
+    return "0 0 0 RG [] 0 d"
+}
+
+// pdfContent builds a page content stream. flipX and flipY say the drawing
+// is mirrored (the back of a duplex page), so text has to be turned back.
+type pdfContent struct {
+    bytes.Buffer
+    flipX, flipY bool
+}
+
+func (c *pdfContent) op(format string, args ...interface{}) {
+    fmt.Fprintf(c, format, args...)
+    c.WriteByte('\n')
+}
+
+// text writes s with its baseline starting at (x, y), size in current units.
+// On a mirrored page the glyphs are mirrored back and the text covers the
+// same spot it would unmirrored.
+func (c *pdfContent) text(x, y, size float64, s string) {
+    switch {
+    case c.flipX:
+        c.op("BT /F1 %s Tf -1 0 0 1 %s %s Tm (%s) Tj ET", pdfNum(size), pdfNum(x+pdfTextWidth(s, size)), pdfNum(y), pdfEscape(s))
+    case c.flipY:
+        c.op("BT /F1 %s Tf 1 0 0 -1 %s %s Tm (%s) Tj ET", pdfNum(size), pdfNum(x), pdfNum(y+0.72*size), pdfEscape(s))
+    default:
+        c.op("BT /F1 %s Tf %s %s Td (%s) Tj ET", pdfNum(size), pdfNum(x), pdfNum(y), pdfEscape(s))
+    }
+}
+
+// pdfTextWidth estimates the width of s in Helvetica: digits are 0.556 em and
+// that's a fair average for everything else too.
+func pdfTextWidth(s string, size float64) float64 {
+    return 0.556 * size * float64(len(s))
+}
+
+func pdfNum(v float64) string {
+    s := strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+    if s == "-0" {
+        return "0"
+    }
+    return s
+}
+
+// pdfEscape escapes a string literal. The standard fonts only know Latin-1,
+// so anything else becomes '?'.
+func pdfEscape(s string) string {
+    var b strings.Builder
+    for _, r := range s {
+        switch {
+        case r == '(' || r == ')' || r == '\\':
+            b.WriteByte('\\')
+            b.WriteRune(r)
+        case r < 32:
+            b.WriteByte(' ')
+        case r < 128:
+            b.WriteRune(r)
+        case r < 256:
+            fmt.Fprintf(&b, "\\%03o", r)
+        default:
+            b.WriteByte('?')
+        }
+    }
+    return b.String()
+}
+
+// writePDF writes a minimal PDF 1.4 file: catalog, page tree, one Helvetica
+// font and one compressed content stream per page. Page sizes are in points.
+func writePDF(w io.Writer, pages [][]byte, width, height float64) error {
+    bw := bufio.NewWriter(w)
+    var offsets []int
+    pos := 0
+    write := func(format string, args ...interface{}) {
+        n, _ := fmt.Fprintf(bw, format, args...)
+        pos += n
+    }
+    // objects: 1 catalog, 2 page tree, 3 font, then (page, content) per page
+    begin := func() {
+        offsets = append(offsets, pos)
+        write("%d 0 obj\n", len(offsets))
+    }
+
+    write("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+    begin()
+    write("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+    begin()
+    kids := make([]string, len(pages))
+    for i := range pages {
+        kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+    }
+    write("<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(pages))
+    begin()
+    write("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>\nendobj\n")
+
+    for i, content := range pages {
+        begin()
+        write("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>\nendobj\n",
+            pdfNum(width), pdfNum(height), 5+2*i)
+
+        var z bytes.Buffer
+        zw := zlib.NewWriter(&z)
+        if _, err := zw.Write(content); err != nil {
+            return err
+        }
+        if err := zw.Close(); err != nil {
+            return err
+        }
+        begin()
+        write("<< /Length %d /Filter /FlateDecode >>\nstream\n", z.Len())
+        n, _ := bw.Write(z.Bytes())
+        pos += n
+        write("\nendstream\nendobj\n")
+    }
+
+    xref := pos
+    write("xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+    for _, off := range offsets {
+        write("%010d 00000 n \n", off)
+    }
+    write("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+    return bw.Flush()
+}
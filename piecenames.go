@@ -0,0 +1,141 @@
+package unfolder
+
+import (
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// -----------------------------
+//   Piece names
+// -----------------------------
+
+// PieceNames names pieces (lists of faces, as in GluingGraph.Pieces) for
+// instructions, by what they are rather than where they fall in the slice,
+// so that re-exporting after a small edit to the mesh keeps the names.
+//
+// A piece whose faces are mostly (by area) in one region is named after it.
+// Otherwise the name says where the piece sits on the model, from its
+// centre against the middle of all the pieces, with +Y up and +Z to the
+// front as in OBJ and glTF files: "top", "bottom front", "back left" ...,
+// or "middle". A lone piece without a region is "model". Pieces that end up
+// with the same name are numbered top to bottom, front to back, then left
+// to right: "wing 1", "wing 2".
+func PieceNames(poly Polyhedron, pieces [][]int, regions FaceRegions) []string {
+    names := make([]string, len(pieces))
+    if len(pieces) == 0 {
+        return names
+    }
+    valid := func(f int) bool { return f >= 0 && f < len(poly.Faces) && len(poly.Faces[f].Vertices) >= 3 }
+
+    // every piece's area-weighted centre, and the box around all of them
+    centres := make([]Vector3, len(pieces))
+    var all []Vector3
+    for i, faces := range pieces {
+        var sum Vector3
+        area, n := 0.0, 0
+        var plain Vector3 // unweighted, for pieces with no area
+        for _, f := range faces {
+            if !valid(f) {
+                continue
+            }
+            c := faceCentroid(poly, poly.Faces[f])
+            a := length(faceNormal(poly, poly.Faces[f])) / 2
+            sum = add(sum, scale(c, a))
+            plain = add(plain, c)
+            area += a
+            n++
+            for _, v := range poly.Faces[f].Vertices {
+                if v >= 0 && v < len(poly.Vertices) {
+                    all = append(all, poly.Vertices[v])
+                }
+            }
+        }
+        switch {
+        case area > 0:
+            centres[i] = scale(sum, 1/area)
+        case n > 0:
+            centres[i] = scale(plain, 1/float64(n))
+        }
+    }
+    lo, hi := boundingBox(all)
+    mid := scale(add(lo, hi), 0.5)
+    half := scale(sub(hi, lo), 0.5)
+
+    for i, faces := range pieces {
+        // the region with most of the piece's area
+        if len(regions) > 0 {
+            byRegion := make(map[string]float64)
+            for _, f := range faces {
+                if valid(f) && f < len(regions) && regions[f] != "" {
+                    byRegion[regions[f]] += length(faceNormal(poly, poly.Faces[f]))
+                }
+            }
+            best := 0.0
+            for r, a := range byRegion {
+                if a > best || (a == best && r < names[i]) {
+                    names[i], best = r, a
+                }
+            }
+            if names[i] != "" {
+                continue
+            }
+        }
+        if len(pieces) == 1 {
+            names[i] = "model"
+            continue
+        }
+        // a third of the way out from the middle counts as off to that side
+        side := func(d, h float64, plus, minus string) string {
+            switch {
+            case h <= 0:
+                return ""
+            case d/h > 1.0/3:
+                return plus
+            case d/h < -1.0/3:
+                return minus
+            }
+            return ""
+        }
+        d := sub(centres[i], mid)
+        var words []string
+        for _, w := range []string{
+            side(d.Y, half.Y, "top", "bottom"),
+            side(d.Z, half.Z, "front", "back"),
+            side(d.X, half.X, "right", "left"),
+        } {
+            if w != "" {
+                words = append(words, w)
+            }
+        }
+        names[i] = strings.Join(words, " ")
+        if names[i] == "" {
+            names[i] = "middle"
+        }
+    }
+
+    // number the pieces sharing a name
+    same := make(map[string][]int)
+    for i, n := range names {
+        same[n] = append(same[n], i)
+    }
+    for n, group := range same {
+        if len(group) < 2 {
+            continue
+        }
+        sort.SliceStable(group, func(a, b int) bool {
+            ca, cb := centres[group[a]], centres[group[b]]
+            switch {
+            case ca.Y != cb.Y:
+                return ca.Y > cb.Y
+            case ca.Z != cb.Z:
+                return ca.Z > cb.Z
+            }
+            return ca.X < cb.X
+        })
+        for k, i := range group {
+            names[i] = n + " " + strconv.Itoa(k+1)
+        }
+    }
+    return names
+}
@@ -0,0 +1,124 @@
+package unfolder
+
+import (
+    "fmt"
+    "sort"
+)
+
+// -----------------------------
+//   Non-planar faces
+// -----------------------------
+
+// NonPlanarMode says what UnfoldMeshWithOptions and
+// UnfoldMeshNonOverlapping do with faces past UnfoldOptions.PlanarityTolerance.
+type NonPlanarMode int
+
+const (
+    // NonPlanarError fails with an error wrapping the worst face's
+    // ErrNonPlanarFace; NonPlanarFaces lists them all.
+    NonPlanarError NonPlanarMode = iota
+    // NonPlanarTriangulate splits those faces into triangles (folding
+    // along their short diagonals) and unfolds that, see
+    // UnfoldResult.Mesh.
+    NonPlanarTriangulate
+)
+
+// NonPlanarFaces lists the faces of poly with a corner further than tol from
+// the face's plane (through the corners' centroid, with the Newell normal),
+// worst first. Placement flattens a face from its first three corners, so
+// such a face comes out distorted by about its Deviation.
+func NonPlanarFaces(poly Polyhedron, tol float64) []ErrNonPlanarFace {
+    var out []ErrNonPlanarFace
+    for f, face := range poly.Faces {
+        if len(face.Vertices) <= 3 || !faceInRange(poly, face) {
+            continue
+        }
+        if w := faceWarp(poly, face); w > tol {
+            out = append(out, ErrNonPlanarFace{Face: f, Deviation: w})
+        }
+    }
+    sort.SliceStable(out, func(i, j int) bool { return out[i].Deviation > out[j].Deviation })
+    return out
+}
+
+// checkPlanarity applies opts.PlanarityTolerance to poly: an error, or the
+// mesh with the warped faces split and where its faces came from (nil if
+// nothing needed splitting).
+func checkPlanarity(poly Polyhedron, opts UnfoldOptions) (*Polyhedron, []int, error) {
+    if opts.PlanarityTolerance <= 0 {
+        return nil, nil, nil
+    }
+    warped := NonPlanarFaces(poly, opts.PlanarityTolerance)
+    if len(warped) == 0 {
+        return nil, nil, nil
+    }
+    if opts.NonPlanar != NonPlanarTriangulate {
+        return nil, nil, nonPlanarError(warped, opts.PlanarityTolerance)
+    }
+    faces := make([]int, len(warped))
+    for i, w := range warped {
+        faces[i] = w.Face
+    }
+    split, origin := splitFaces(poly, faces)
+    return &split, origin, nil
+}
+
+// nonPlanarError is the error for the faces NonPlanarFaces found.
+func nonPlanarError(warped []ErrNonPlanarFace, tol float64) error {
+    return fmt.Errorf("%d faces are further than %g from flat; worst: %w", len(warped), tol, &warped[0])
+}
+
+// splitFaces triangulates the given faces of poly like Triangulate does. Every
+// other face keeps its index; a split face's first triangle takes its place
+// and the rest go on the end. origin maps each face of the result to the face
+// of poly it came from. Faces with holes can't be split and are left as they
+// are.
+func splitFaces(poly Polyhedron, faces []int) (Polyhedron, []int) {
+    out := Polyhedron{Vertices: poly.Vertices, Name: poly.Name, Units: poly.Units, Faces: append([]Face(nil), poly.Faces...)}
+    origin := make([]int, len(poly.Faces))
+    for f := range origin {
+        origin[f] = f
+    }
+    sorted := append([]int(nil), faces...)
+    sort.Ints(sorted)
+    for _, f := range sorted {
+        face := poly.Faces[f]
+        if len(face.Holes) > 0 {
+            continue
+        }
+        textured := len(face.UVs) == len(face.Vertices)
+        for k, tri := range earClip(poly, face) {
+            t := Face{Vertices: make([]int, 3)}
+            for i, c := range tri {
+                t.Vertices[i] = face.Vertices[c]
+                if textured {
+                    t.UVs = append(t.UVs, face.UVs[c])
+                }
+            }
+            if k == 0 {
+                out.Faces[f] = t
+                continue
+            }
+            out.Faces = append(out.Faces, t)
+            origin = append(origin, f)
+        }
+    }
+    return out, origin
+}
+
+// faceInRange reports whether every vertex of face is a vertex of poly.
+func faceInRange(poly Polyhedron, face Face) bool {
+    for _, v := range face.Vertices {
+        if v < 0 || v >= len(poly.Vertices) {
+            return false
+        }
+    }
+    for _, hole := range face.Holes {
+        for _, v := range hole {
+            if v < 0 || v >= len(poly.Vertices) {
+                return false
+            }
+        }
+    }
+    return true
+}
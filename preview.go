@@ -0,0 +1,288 @@
+package unfolder
+
+import (
+    "bufio"
+    "errors"
+    "fmt"
+    "image"
+    "image/color"
+    "io"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   3D preview rendering
+// -----------------------------
+
+// Camera is where a preview looks at the model from. The projection is
+// orthographic, so only the direction matters.
+type Camera struct {
+    // View points from the model towards the viewer. Default (1, 1, 1).
+    View Vector3
+    // Up is up in the picture. Default +Y, as in OBJ and glTF files.
+    Up Vector3
+}
+
+// previewFace is a face as the camera sees it.
+type previewFace struct {
+    face  int
+    pts   []Point2  // projected, Y up
+    z     []float64 // towards the viewer
+    depth float64   // mean of z
+    shade float64   // 0..1, light on the face
+}
+
+// project returns the faces of poly seen through c, farthest first, and the
+// bounds of the picture. Faces are lit from over the viewer's shoulder;
+// faces turned away (the inside of an open surface) come out darker.
+func (c Camera) project(poly Polyhedron) ([]previewFace, Point2, Point2) {
+    view, up := c.View, c.Up
+    if length(view) == 0 {
+        view = Vector3{1, 1, 1}
+    }
+    if length(up) == 0 {
+        up = Vector3{0, 1, 0}
+    }
+    d := normalize(view)
+    right := normalize(cross(up, d))
+    if length(right) == 0 {
+        right, _ = orthoBasis(d)
+    }
+    upv := cross(d, right)
+    light := normalize(add(add(d, scale(upv, 0.6)), scale(right, -0.3)))
+
+    var faces []previewFace
+    lo := Point2{math.Inf(1), math.Inf(1)}
+    hi := Point2{math.Inf(-1), math.Inf(-1)}
+    for fIdx, face := range poly.Faces {
+        if len(face.Vertices) < 3 {
+            continue
+        }
+        pf := previewFace{face: fIdx, pts: make([]Point2, len(face.Vertices)), z: make([]float64, len(face.Vertices))}
+        for i, v := range face.Vertices {
+            p := poly.Vertices[v]
+            pf.pts[i] = Point2{X: dot(p, right), Y: dot(p, upv)}
+            pf.z[i] = dot(p, d)
+            pf.depth += pf.z[i] / float64(len(face.Vertices))
+            lo.X, lo.Y = math.Min(lo.X, pf.pts[i].X), math.Min(lo.Y, pf.pts[i].Y)
+            hi.X, hi.Y = math.Max(hi.X, pf.pts[i].X), math.Max(hi.Y, pf.pts[i].Y)
+        }
+        n := normalize(faceNormal(poly, face))
+        pf.shade = 0.35 + 0.6*math.Abs(dot(n, light))
+        if dot(n, d) < 0 {
+            pf.shade *= 0.6
+        }
+        faces = append(faces, pf)
+    }
+    sort.SliceStable(faces, func(i, j int) bool { return faces[i].depth < faces[j].depth })
+    return faces, lo, hi
+}
+
+// PreviewOptions controls RenderPreview and ExportPreviewSVG.
+type PreviewOptions struct {
+    Camera Camera
+    // Width and Height of the picture in pixels; the model is fitted inside,
+    // Margin pixels from the edges. Default 512 x 512, margin 8.
+    Width, Height int
+    Margin        int
+    // Color of the faces before shading. Default light grey.
+    Color color.Color
+    // FaceColors, if set, colours each face by index instead; nil entries
+    // fall back to Color.
+    FaceColors []color.Color
+    // Background fills the picture; nil leaves it transparent.
+    Background color.Color
+    // Edges outlines every face.
+    Edges bool
+    // Texture, if set, paints faces that have UVs (RenderPreview only).
+    Texture image.Image
+}
+
+func (o *PreviewOptions) defaults() {
+    if o.Width <= 0 {
+        o.Width = 512
+    }
+    if o.Height <= 0 {
+        o.Height = 512
+    }
+    if o.Margin <= 0 {
+        o.Margin = 8
+    }
+    if o.Color == nil {
+        o.Color = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+    }
+}
+
+// faceColor is the unshaded colour of face f.
+func (o *PreviewOptions) faceColor(f int) color.RGBA {
+    c := o.Color
+    if f < len(o.FaceColors) && o.FaceColors[f] != nil {
+        c = o.FaceColors[f]
+    }
+    return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+// RenderPreview draws poly flat shaded, as seen through opts.Camera, with a
+// depth buffer so crossing and concave faces come out right.
+func RenderPreview(poly Polyhedron, opts PreviewOptions) (*image.RGBA, error) {
+    opts.defaults()
+    faces, lo, hi := opts.Camera.project(poly)
+    if len(faces) == 0 {
+        return nil, errors.New("nothing to draw: no faces")
+    }
+    img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+    if opts.Background != nil {
+        bg := color.RGBAModel.Convert(opts.Background).(color.RGBA)
+        for i := 0; i < len(img.Pix); i += 4 {
+            img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+        }
+    }
+    to := fitBox(lo, hi, float64(opts.Margin), float64(opts.Margin), float64(opts.Width-2*opts.Margin), float64(opts.Height-2*opts.Margin))
+    toPx := func(p Point2) Point2 {
+        x, y := to(p)
+        return Point2{X: x, Y: float64(opts.Height) - y} // image rows run down
+    }
+    zbuf := make([]float64, opts.Width*opts.Height)
+    for i := range zbuf {
+        zbuf[i] = math.Inf(-1)
+    }
+    for _, pf := range faces {
+        base := opts.faceColor(pf.face)
+        uvs := poly.Faces[pf.face].UVs
+        textured := opts.Texture != nil && len(uvs) == len(pf.pts)
+        flat := color.RGBA{
+            uint8(float64(base.R)*pf.shade + 0.5),
+            uint8(float64(base.G)*pf.shade + 0.5),
+            uint8(float64(base.B)*pf.shade + 0.5),
+            base.A,
+        }
+        a := toPx(pf.pts[0])
+        for k := 1; k+1 < len(pf.pts); k++ {
+            tri := [3]Point2{a, toPx(pf.pts[k]), toPx(pf.pts[k+1])}
+            z := [3]float64{pf.z[0], pf.z[k], pf.z[k+1]}
+            rasterTriangle(img.Bounds(), tri, func(x, y int, w [3]float64) {
+                depth := w[0]*z[0] + w[1]*z[1] + w[2]*z[2]
+                i := y*opts.Width + x
+                if depth < zbuf[i] {
+                    return
+                }
+                zbuf[i] = depth
+                if !textured {
+                    img.SetRGBA(x, y, flat)
+                    return
+                }
+                uv := Point2{
+                    X: w[0]*uvs[0].X + w[1]*uvs[k].X + w[2]*uvs[k+1].X,
+                    Y: w[0]*uvs[0].Y + w[1]*uvs[k].Y + w[2]*uvs[k+1].Y,
+                }
+                c := sampleTexture(opts.Texture, uv)
+                c.R = uint16(float64(c.R) * pf.shade)
+                c.G = uint16(float64(c.G) * pf.shade)
+                c.B = uint16(float64(c.B) * pf.shade)
+                img.SetRGBA64(x, y, c)
+            })
+        }
+    }
+
+    if opts.Edges {
+        // outlines of the faces in front; a hair of depth slack so an edge
+        // isn't hidden by its own face
+        eps := 1e-3 * math.Hypot(hi.X-lo.X, hi.Y-lo.Y)
+        edge := color.RGBA{0x33, 0x33, 0x33, 0xff}
+        for _, pf := range faces {
+            for i := range pf.pts {
+                j := (i + 1) % len(pf.pts)
+                a, b := toPx(pf.pts[i]), toPx(pf.pts[j])
+                steps := int(math.Ceil(math.Max(math.Abs(b.X-a.X), math.Abs(b.Y-a.Y)))) + 1
+                for s := 0; s <= steps; s++ {
+                    t := float64(s) / float64(steps)
+                    x, y := int(a.X+(b.X-a.X)*t), int(a.Y+(b.Y-a.Y)*t)
+                    if x < 0 || y < 0 || x >= opts.Width || y >= opts.Height {
+                        continue
+                    }
+                    if z := pf.z[i] + (pf.z[j]-pf.z[i])*t; z+eps >= zbuf[y*opts.Width+x] {
+                        img.SetRGBA(x, y, edge)
+                    }
+                }
+            }
+        }
+    }
+    return img, nil
+}
+
+// rasterTriangle calls fill for every pixel of r whose centre lies in the
+// triangle pts (pixel coordinates), with the barycentric weights of the
+// centre.
+func rasterTriangle(r image.Rectangle, pts [3]Point2, fill func(x, y int, w [3]float64)) {
+    a, b, c := pts[0], pts[1], pts[2]
+    area := (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+    if area == 0 {
+        return
+    }
+    x0 := int(math.Max(float64(r.Min.X), math.Floor(math.Min(a.X, math.Min(b.X, c.X)))))
+    x1 := int(math.Min(float64(r.Max.X-1), math.Ceil(math.Max(a.X, math.Max(b.X, c.X)))))
+    y0 := int(math.Max(float64(r.Min.Y), math.Floor(math.Min(a.Y, math.Min(b.Y, c.Y)))))
+    y1 := int(math.Min(float64(r.Max.Y-1), math.Ceil(math.Max(a.Y, math.Max(b.Y, c.Y)))))
+    // a hair of slack so neighbouring triangles leave no gaps between them
+    const eps = -1e-6
+    for y := y0; y <= y1; y++ {
+        for x := x0; x <= x1; x++ {
+            px, py := float64(x)+0.5, float64(y)+0.5
+            wa := ((b.X-px)*(c.Y-py) - (b.Y-py)*(c.X-px)) / area
+            wb := ((c.X-px)*(a.Y-py) - (c.Y-py)*(a.X-px)) / area
+            wc := 1 - wa - wb
+            if wa < eps || wb < eps || wc < eps {
+                continue
+            }
+            fill(x, y, [3]float64{wa, wb, wc})
+        }
+    }
+}
+
+// ExportPreviewSVG draws poly as RenderPreview does, but as vector shapes:
+// shaded polygons painted far to near, for reports and pages that scale.
+// Faces that cut through each other can come out in the wrong order; the
+// Texture option is ignored.
+func ExportPreviewSVG(poly Polyhedron, w io.Writer, opts PreviewOptions) error {
+    opts.defaults()
+    faces, lo, hi := opts.Camera.project(poly)
+    if len(faces) == 0 {
+        return errors.New("nothing to draw: no faces")
+    }
+    to := fitBox(lo, hi, float64(opts.Margin), float64(opts.Margin), float64(opts.Width-2*opts.Margin), float64(opts.Height-2*opts.Margin))
+
+    bw := bufio.NewWriter(w)
+    fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", opts.Width, opts.Height, opts.Width, opts.Height)
+    if opts.Background != nil {
+        r, g, b, _ := opts.Background.RGBA()
+        fmt.Fprintf(bw, "<rect width=\"100%%\" height=\"100%%\" fill=\"#%02x%02x%02x\"/>\n", r>>8, g>>8, b>>8)
+    }
+    stroke := "none"
+    if opts.Edges {
+        stroke = "#333333"
+    }
+    fmt.Fprintf(bw, "<g stroke=\"%s\" stroke-width=\"0.5\" stroke-linejoin=\"round\">\n", stroke)
+    for _, pf := range faces {
+        fmt.Fprint(bw, "<polygon points=\"")
+        for i, p := range pf.pts {
+            x, y := to(p)
+            if i > 0 {
+                bw.WriteByte(' ')
+            }
+            fmt.Fprintf(bw, "%s,%s", svgNum(x), svgNum(float64(opts.Height)-y))
+        }
+        // without an outline of their own, faces get one in their fill so
+        // antialiasing leaves no hairline gaps between them
+        base := opts.faceColor(pf.face)
+        fill := fmt.Sprintf("#%02x%02x%02x", uint8(float64(base.R)*pf.shade+0.5), uint8(float64(base.G)*pf.shade+0.5), uint8(float64(base.B)*pf.shade+0.5))
+        if opts.Edges {
+            fmt.Fprintf(bw, "\" fill=\"%s\"/>\n", fill)
+        } else {
+            fmt.Fprintf(bw, "\" fill=\"%s\" stroke=\"%s\"/>\n", fill, fill)
+        }
+    }
+    fmt.Fprintln(bw, "</g>")
+    fmt.Fprintln(bw, "</svg>")
+    return bw.Flush()
+}
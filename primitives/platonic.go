@@ -0,0 +1,121 @@
+package primitives
+
+import (
+    "math"
+    "sort"
+
+    "github.com/yourusername/unfolder"
+)
+
+// -----------------------------
+//   Platonic solids (circumradius 1)
+// -----------------------------
+
+// Tetrahedron returns a regular tetrahedron.
+func Tetrahedron() unfolder.Polyhedron {
+    return fromEdgeLength("tetrahedron", []unfolder.Vector3{
+        {X: 1, Y: 1, Z: 1}, {X: 1, Y: -1, Z: -1}, {X: -1, Y: 1, Z: -1}, {X: -1, Y: -1, Z: 1},
+    })
+}
+
+// Octahedron returns a regular octahedron.
+func Octahedron() unfolder.Polyhedron {
+    return fromEdgeLength("octahedron", []unfolder.Vector3{
+        {X: 1}, {X: -1}, {Y: 1}, {Y: -1}, {Z: 1}, {Z: -1},
+    })
+}
+
+// Icosahedron returns a regular icosahedron.
+func Icosahedron() unfolder.Polyhedron {
+    phi := (1 + math.Sqrt(5)) / 2
+    var pts []unfolder.Vector3
+    for _, a := range []float64{-1, 1} {
+        for _, b := range []float64{-phi, phi} {
+            pts = append(pts,
+                unfolder.Vector3{Y: a, Z: b},
+                unfolder.Vector3{X: a, Y: b},
+                unfolder.Vector3{X: b, Z: a})
+        }
+    }
+    return fromEdgeLength("icosahedron", pts)
+}
+
+// Dodecahedron returns a regular dodecahedron, built as the dual of the
+// icosahedron: a corner per icosahedron face, a pentagon per vertex.
+func Dodecahedron() unfolder.Polyhedron {
+    ico := Icosahedron()
+    poly := unfolder.Polyhedron{Name: "dodecahedron"}
+    around := make([][]int, len(ico.Vertices))
+    for fi, f := range ico.Faces {
+        var c unfolder.Vector3
+        for _, vi := range f.Vertices {
+            v := ico.Vertices[vi]
+            c.X, c.Y, c.Z = c.X+v.X, c.Y+v.Y, c.Z+v.Z
+        }
+        poly.Vertices = append(poly.Vertices, normalize(c))
+        for _, vi := range f.Vertices {
+            around[vi] = append(around[vi], fi)
+        }
+    }
+    for vi, fs := range around {
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: sortAround(poly.Vertices, fs, ico.Vertices[vi])})
+    }
+    orientOutward(&poly)
+    return poly
+}
+
+// fromEdgeLength makes the convex solid whose faces are the triangles of
+// pts with all three sides the shortest distance between any two points,
+// scaled to radius 1. That's every face of the triangle-faced Platonic
+// solids.
+func fromEdgeLength(name string, pts []unfolder.Vector3) unfolder.Polyhedron {
+    edge := math.Inf(1)
+    for i := range pts {
+        for j := i + 1; j < len(pts); j++ {
+            edge = math.Min(edge, dist(pts[i], pts[j]))
+        }
+    }
+    near := func(i, j int) bool { return math.Abs(dist(pts[i], pts[j])-edge) < 1e-9*edge }
+    poly := unfolder.Polyhedron{Name: name}
+    for _, p := range pts {
+        poly.Vertices = append(poly.Vertices, normalize(p))
+    }
+    for i := range pts {
+        for j := i + 1; j < len(pts); j++ {
+            if !near(i, j) {
+                continue
+            }
+            for k := j + 1; k < len(pts); k++ {
+                if near(i, k) && near(j, k) {
+                    poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{i, j, k}})
+                }
+            }
+        }
+    }
+    orientOutward(&poly)
+    return poly
+}
+
+// sortAround orders the points idx by angle around axis (through the origin).
+func sortAround(pts []unfolder.Vector3, idx []int, axis unfolder.Vector3) []int {
+    axis = normalize(axis)
+    // any vector not parallel to axis gives the reference direction
+    ref := unfolder.Vector3{X: 1}
+    if math.Abs(axis.X) > 0.9 {
+        ref = unfolder.Vector3{Y: 1}
+    }
+    u := normalize(cross(axis, ref))
+    w := cross(axis, u)
+    angle := make(map[int]float64, len(idx))
+    for _, i := range idx {
+        p := pts[i]
+        angle[i] = math.Atan2(p.X*w.X+p.Y*w.Y+p.Z*w.Z, p.X*u.X+p.Y*u.Y+p.Z*u.Z)
+    }
+    out := append([]int(nil), idx...)
+    sort.Slice(out, func(a, b int) bool { return angle[out[a]] < angle[out[b]] })
+    return out
+}
+
+func cross(a, b unfolder.Vector3) unfolder.Vector3 {
+    return unfolder.Vector3{X: a.Y*b.Z - a.Z*b.Y, Y: a.Z*b.X - a.X*b.Z, Z: a.X*b.Y - a.Y*b.X}
+}
@@ -0,0 +1,167 @@
+// Package primitives builds standard solids as unfolder.Polyhedron values:
+// boxes, the Platonic solids, prisms, pyramids and spheres. They're handy for
+// trying strategies and for printing nets of the usual shapes.
+//
+// Every solid is centred on the origin with outward CCW faces. Unless a size
+// is given it fits the cube [-1, 1]³: the Platonic solids and spheres have
+// radius 1, prisms, pyramids, cylinders and cones have base radius 1 and run
+// from z = -1 to z = 1.
+package primitives
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/yourusername/unfolder"
+)
+
+// Cube returns a cube with the given edge length.
+func Cube(size float64) (unfolder.Polyhedron, error) {
+    poly, err := Box(size, size, size)
+    poly.Name = "cube"
+    return poly, err
+}
+
+// Box returns a box w wide (x), h high (y) and d deep (z).
+func Box(w, h, d float64) (unfolder.Polyhedron, error) {
+    if !(w > 0 && h > 0 && d > 0) {
+        return unfolder.Polyhedron{}, fmt.Errorf("box sides must be positive, got %g x %g x %g", w, h, d)
+    }
+    x, y, z := w/2, h/2, d/2
+    poly := unfolder.Polyhedron{
+        Name: "box",
+        Vertices: []unfolder.Vector3{
+            {X: -x, Y: -y, Z: -z}, {X: x, Y: -y, Z: -z}, {X: x, Y: y, Z: -z}, {X: -x, Y: y, Z: -z},
+            {X: -x, Y: -y, Z: z}, {X: x, Y: -y, Z: z}, {X: x, Y: y, Z: z}, {X: -x, Y: y, Z: z},
+        },
+        Faces: []unfolder.Face{
+            {Vertices: []int{0, 3, 2, 1}}, // back (-z)
+            {Vertices: []int{4, 5, 6, 7}}, // front (+z)
+            {Vertices: []int{0, 1, 5, 4}}, // bottom (-y)
+            {Vertices: []int{1, 2, 6, 5}}, // right (+x)
+            {Vertices: []int{2, 3, 7, 6}}, // top (+y)
+            {Vertices: []int{3, 0, 4, 7}}, // left (-x)
+        },
+    }
+    return poly, nil
+}
+
+// Prism returns a prism over a regular n-gon.
+func Prism(n int) (unfolder.Polyhedron, error) {
+    if n < 3 {
+        return unfolder.Polyhedron{}, fmt.Errorf("prism needs at least 3 sides, got %d", n)
+    }
+    poly := unfolder.Polyhedron{Name: fmt.Sprintf("prism-%d", n)}
+    poly.Vertices = append(ring(n, 1, -1, 0), ring(n, 1, 1, 0)...)
+    poly.Faces = append(poly.Faces, capFace(0, n, false), capFace(n, n, true))
+    for i := 0; i < n; i++ {
+        j := (i + 1) % n
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{i, j, n + j, n + i}})
+    }
+    return poly, nil
+}
+
+// Antiprism returns an antiprism over a regular n-gon: the top ring is turned
+// by half a step and the sides are 2n triangles.
+func Antiprism(n int) (unfolder.Polyhedron, error) {
+    if n < 3 {
+        return unfolder.Polyhedron{}, fmt.Errorf("antiprism needs at least 3 sides, got %d", n)
+    }
+    poly := unfolder.Polyhedron{Name: fmt.Sprintf("antiprism-%d", n)}
+    poly.Vertices = append(ring(n, 1, -1, 0), ring(n, 1, 1, math.Pi/float64(n))...)
+    poly.Faces = append(poly.Faces, capFace(0, n, false), capFace(n, n, true))
+    for i := 0; i < n; i++ {
+        j := (i + 1) % n
+        poly.Faces = append(poly.Faces,
+            unfolder.Face{Vertices: []int{i, j, n + i}},
+            unfolder.Face{Vertices: []int{j, n + j, n + i}})
+    }
+    return poly, nil
+}
+
+// Pyramid returns a pyramid over a regular n-gon.
+func Pyramid(n int) (unfolder.Polyhedron, error) {
+    if n < 3 {
+        return unfolder.Polyhedron{}, fmt.Errorf("pyramid needs at least 3 sides, got %d", n)
+    }
+    poly := unfolder.Polyhedron{Name: fmt.Sprintf("pyramid-%d", n)}
+    poly.Vertices = append(ring(n, 1, -1, 0), unfolder.Vector3{Z: 1})
+    poly.Faces = append(poly.Faces, capFace(0, n, false))
+    for i := 0; i < n; i++ {
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{i, (i + 1) % n, n}})
+    }
+    return poly, nil
+}
+
+// Cylinder approximates a cylinder with the given number of side panels. It's
+// a prism; the name just says what it stands for.
+func Cylinder(segments int) (unfolder.Polyhedron, error) {
+    poly, err := Prism(segments)
+    poly.Name = fmt.Sprintf("cylinder-%d", segments)
+    return poly, err
+}
+
+// Cone approximates a cone with the given number of side panels (a pyramid).
+func Cone(segments int) (unfolder.Polyhedron, error) {
+    poly, err := Pyramid(segments)
+    poly.Name = fmt.Sprintf("cone-%d", segments)
+    return poly, err
+}
+
+// ring returns n points on a circle of radius r at height z, starting at
+// angle phase.
+func ring(n int, r, z, phase float64) []unfolder.Vector3 {
+    pts := make([]unfolder.Vector3, n)
+    for i := range pts {
+        a := phase + 2*math.Pi*float64(i)/float64(n)
+        pts[i] = unfolder.Vector3{X: r * math.Cos(a), Y: r * math.Sin(a), Z: z}
+    }
+    return pts
+}
+
+// capFace is the n-gon of vertices start..start+n-1 (a ring), facing +z if up
+// and -z otherwise.
+func capFace(start, n int, up bool) unfolder.Face {
+    vs := make([]int, n)
+    for i := range vs {
+        if up {
+            vs[i] = start + i
+        } else {
+            vs[i] = start + n - 1 - i
+        }
+    }
+    return unfolder.Face{Vertices: vs}
+}
+
+// orientOutward reverses faces that point towards the origin. Only right for
+// convex solids around the origin, which is all this package makes.
+func orientOutward(poly *unfolder.Polyhedron) {
+    for _, f := range poly.Faces {
+        var n, c unfolder.Vector3
+        for i, vi := range f.Vertices {
+            a, b := poly.Vertices[vi], poly.Vertices[f.Vertices[(i+1)%len(f.Vertices)]]
+            // Newell's method
+            n.X += (a.Y - b.Y) * (a.Z + b.Z)
+            n.Y += (a.Z - b.Z) * (a.X + b.X)
+            n.Z += (a.X - b.X) * (a.Y + b.Y)
+            c.X, c.Y, c.Z = c.X+a.X, c.Y+a.Y, c.Z+a.Z
+        }
+        if n.X*c.X+n.Y*c.Y+n.Z*c.Z < 0 {
+            for i, j := 0, len(f.Vertices)-1; i < j; i, j = i+1, j-1 {
+                f.Vertices[i], f.Vertices[j] = f.Vertices[j], f.Vertices[i]
+            }
+        }
+    }
+}
+
+func normalize(v unfolder.Vector3) unfolder.Vector3 {
+    l := math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z)
+    if l == 0 {
+        return v
+    }
+    return unfolder.Vector3{X: v.X / l, Y: v.Y / l, Z: v.Z / l}
+}
+
+func dist(a, b unfolder.Vector3) float64 {
+    return math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+}
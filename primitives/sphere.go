@@ -0,0 +1,88 @@
+package primitives
+
+import (
+    "fmt"
+    "math"
+
+    "github.com/yourusername/unfolder"
+)
+
+// -----------------------------
+//   Spheres (radius 1)
+// -----------------------------
+
+// UVSphere returns a latitude/longitude sphere with lat bands from pole to
+// pole and long segments around. The polar bands are triangle fans, the rest
+// planar trapezoids.
+func UVSphere(lat, long int) (unfolder.Polyhedron, error) {
+    if lat < 2 || long < 3 {
+        return unfolder.Polyhedron{}, fmt.Errorf("uv sphere needs at least 2 bands and 3 segments, got %d x %d", lat, long)
+    }
+    poly := unfolder.Polyhedron{Name: fmt.Sprintf("uvsphere-%dx%d", lat, long)}
+    poly.Vertices = append(poly.Vertices, unfolder.Vector3{Z: -1}) // south pole
+    for i := 1; i < lat; i++ {
+        theta := math.Pi * float64(i) / float64(lat)
+        poly.Vertices = append(poly.Vertices, ring(long, math.Sin(theta), -math.Cos(theta), 0)...)
+    }
+    north := len(poly.Vertices)
+    poly.Vertices = append(poly.Vertices, unfolder.Vector3{Z: 1})
+
+    at := func(band, j int) int { return 1 + (band-1)*long + j%long } // band 1..lat-1
+    for j := 0; j < long; j++ {
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{0, at(1, j+1), at(1, j)}})
+        for b := 1; b < lat-1; b++ {
+            poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{at(b, j), at(b, j+1), at(b+1, j+1), at(b+1, j)}})
+        }
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{at(lat-1, j), at(lat-1, j+1), north}})
+    }
+    return poly, nil
+}
+
+// GeodesicSphere returns an icosahedron with every face split into freq²
+// triangles, pushed out onto the sphere. freq 1 is the icosahedron itself.
+func GeodesicSphere(freq int) (unfolder.Polyhedron, error) {
+    if freq < 1 {
+        return unfolder.Polyhedron{}, fmt.Errorf("geodesic frequency must be at least 1, got %d", freq)
+    }
+    ico := Icosahedron()
+    poly := unfolder.Polyhedron{Name: fmt.Sprintf("geodesic-%d", freq)}
+
+    // points on shared edges are made once per face, so weld them by position
+    index := make(map[[3]int64]int)
+    vertex := func(p unfolder.Vector3) int {
+        p = normalize(p)
+        key := [3]int64{int64(math.Round(p.X * 1e9)), int64(math.Round(p.Y * 1e9)), int64(math.Round(p.Z * 1e9))}
+        if i, ok := index[key]; ok {
+            return i
+        }
+        index[key] = len(poly.Vertices)
+        poly.Vertices = append(poly.Vertices, p)
+        return index[key]
+    }
+    for _, f := range ico.Faces {
+        a, b, c := ico.Vertices[f.Vertices[0]], ico.Vertices[f.Vertices[1]], ico.Vertices[f.Vertices[2]]
+        // point (i, j) is a + i/freq (b - a) + j/freq (c - a)
+        grid := make([][]int, freq+1)
+        for i := 0; i <= freq; i++ {
+            grid[i] = make([]int, freq+1-i)
+            for j := range grid[i] {
+                s, t := float64(i)/float64(freq), float64(j)/float64(freq)
+                grid[i][j] = vertex(unfolder.Vector3{
+                    X: a.X + s*(b.X-a.X) + t*(c.X-a.X),
+                    Y: a.Y + s*(b.Y-a.Y) + t*(c.Y-a.Y),
+                    Z: a.Z + s*(b.Z-a.Z) + t*(c.Z-a.Z),
+                })
+            }
+        }
+        for i := 0; i < freq; i++ {
+            for j := 0; j < freq-i; j++ {
+                poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{grid[i][j], grid[i+1][j], grid[i][j+1]}})
+                if j+1 < freq-i {
+                    poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{grid[i+1][j], grid[i+1][j+1], grid[i][j+1]}})
+                }
+            }
+        }
+    }
+    orientOutward(&poly)
+    return poly, nil
+}
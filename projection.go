@@ -0,0 +1,256 @@
+package unfolder
+
+import (
+    "errors"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Projection (shadow) outlines
+// -----------------------------
+
+// ProjectionOutline returns the orthographic silhouette of poly seen along
+// axis: the outline of the union of all projected faces. Outer boundaries
+// come back counter-clockwise, holes (e.g. the inside of a ring) clockwise,
+// in the same plane basis StackedSlices uses, so for axis = +Z the outline is
+// in plain (X, Y). Useful for backing boards and display stands.
+//
+// This is exact but O(E^2) in the number of projected edges; decimate very
+// large meshes first.
+func ProjectionOutline(poly Polyhedron, axis Vector3) ([][]Point2, error) {
+    if len(poly.Faces) == 0 {
+        return nil, errors.New("polyhedron has no faces")
+    }
+    if length(axis) == 0 {
+        return nil, errors.New("projection axis is zero")
+    }
+    axis = normalize(axis)
+    u, v := planeBasis(axis)
+
+    // 1) project faces, skipping ones seen edge-on
+    var polys [][]Point2
+    minX, minY := math.Inf(1), math.Inf(1)
+    maxX, maxY := math.Inf(-1), math.Inf(-1)
+    for _, face := range poly.Faces {
+        pts := make([]Point2, len(face.Vertices))
+        for i, vi := range face.Vertices {
+            p := poly.Vertices[vi]
+            pts[i] = Point2{X: dot(p, u), Y: dot(p, v)}
+            minX, maxX = math.Min(minX, pts[i].X), math.Max(maxX, pts[i].X)
+            minY, maxY = math.Min(minY, pts[i].Y), math.Max(maxY, pts[i].Y)
+        }
+        polys = append(polys, pts)
+    }
+    size := math.Hypot(maxX-minX, maxY-minY)
+    if size == 0 {
+        return nil, errors.New("projection is a single point")
+    }
+    eps := size * 1e-9
+    kept := polys[:0]
+    for _, pts := range polys {
+        if math.Abs(polygonArea(pts)) > eps*eps {
+            kept = append(kept, pts)
+        }
+    }
+    polys = kept
+
+    // 2) every projected edge, split wherever another edge crosses or touches it
+    type seg struct{ a, b Point2 }
+    var segs []seg
+    for _, pts := range polys {
+        for i := range pts {
+            segs = append(segs, seg{pts[i], pts[(i+1)%len(pts)]})
+        }
+    }
+    splits := make([][]float64, len(segs))
+    splitPts := make([][]Point2, len(segs))
+    addSplit := func(i int, t float64, p Point2) {
+        if t > 1e-12 && t < 1-1e-12 {
+            splits[i] = append(splits[i], t)
+            splitPts[i] = append(splitPts[i], p)
+        }
+    }
+    for i := range segs {
+        for j := i + 1; j < len(segs); j++ {
+            a, b := segs[i], segs[j]
+            if math.Max(a.a.X, a.b.X) < math.Min(b.a.X, b.b.X)-eps ||
+                math.Max(b.a.X, b.b.X) < math.Min(a.a.X, a.b.X)-eps ||
+                math.Max(a.a.Y, a.b.Y) < math.Min(b.a.Y, b.b.Y)-eps ||
+                math.Max(b.a.Y, b.b.Y) < math.Min(a.a.Y, a.b.Y)-eps {
+                continue
+            }
+            if t, s, ok := segmentIntersection(a.a, a.b, b.a, b.b); ok {
+                // compute the point once so both halves share exact coordinates
+                p := Point2{X: a.a.X + t*(a.b.X-a.a.X), Y: a.a.Y + t*(a.b.Y-a.a.Y)}
+                addSplit(i, t, p)
+                addSplit(j, s, p)
+                continue
+            }
+            // collinear overlaps and T-junctions: split at the other's endpoints
+            for _, p := range []Point2{b.a, b.b} {
+                if t, ok := pointOnSegment(p, a.a, a.b, eps); ok {
+                    addSplit(i, t, p)
+                }
+            }
+            for _, p := range []Point2{a.a, a.b} {
+                if t, ok := pointOnSegment(p, b.a, b.b, eps); ok {
+                    addSplit(j, t, p)
+                }
+            }
+        }
+    }
+
+    // 3) keep the pieces with faces on exactly one side, oriented so the
+    //    covered side is on the left
+    type key struct{ ax, ay, bx, by float64 }
+    seen := make(map[key]bool)
+    next := make(map[Point2][]Point2)
+    var starts []Point2
+    for i, s := range segs {
+        ts := append([]float64{0}, splits[i]...)
+        ps := append([]Point2{s.a}, splitPts[i]...)
+        idx := make([]int, len(ts))
+        for k := range idx {
+            idx[k] = k
+        }
+        sort.Slice(idx, func(x, y int) bool { return ts[idx[x]] < ts[idx[y]] })
+        chain := make([]Point2, 0, len(ps)+1)
+        for _, k := range idx {
+            chain = append(chain, ps[k])
+        }
+        chain = append(chain, s.b)
+
+        for k := 0; k+1 < len(chain); k++ {
+            p, q := chain[k], chain[k+1]
+            if math.Hypot(q.X-p.X, q.Y-p.Y) <= eps {
+                continue
+            }
+            // dedupe pieces shared by neighbouring faces
+            kk := key{p.X, p.Y, q.X, q.Y}
+            if q.X < p.X || (q.X == p.X && q.Y < p.Y) {
+                kk = key{q.X, q.Y, p.X, p.Y}
+            }
+            if seen[kk] {
+                continue
+            }
+            seen[kk] = true
+
+            mid := Point2{X: (p.X + q.X) / 2, Y: (p.Y + q.Y) / 2}
+            d := math.Hypot(q.X-p.X, q.Y-p.Y)
+            off := size * 1e-7
+            nx, ny := -(q.Y-p.Y)/d*off, (q.X-p.X)/d*off // left normal
+            left := coveredBy(Point2{mid.X + nx, mid.Y + ny}, polys)
+            right := coveredBy(Point2{mid.X - nx, mid.Y - ny}, polys)
+            switch {
+            case left && !right:
+                next[p] = append(next[p], q)
+                starts = append(starts, p)
+            case right && !left:
+                next[q] = append(next[q], p)
+                starts = append(starts, q)
+            }
+        }
+    }
+
+    // 4) chain boundary pieces into loops
+    var loops [][]Point2
+    for _, start := range starts {
+        if len(next[start]) == 0 {
+            continue
+        }
+        loop := []Point2{start}
+        for cur := start; ; {
+            outs := next[cur]
+            if len(outs) == 0 {
+                break // shouldn't happen for a closed outline; drop the chain
+            }
+            nxt := outs[len(outs)-1]
+            next[cur] = outs[:len(outs)-1]
+            if nxt == start {
+                loops = append(loops, simplifyCollinear(loop, eps))
+                break
+            }
+            loop = append(loop, nxt)
+            cur = nxt
+        }
+    }
+    return loops, nil
+}
+
+// segmentIntersection returns the parameters where segments ab and cd cross,
+// if they properly intersect (parallel segments never do here).
+func segmentIntersection(a, b, c, d Point2) (t, s float64, ok bool) {
+    rx, ry := b.X-a.X, b.Y-a.Y
+    qx, qy := d.X-c.X, d.Y-c.Y
+    den := rx*qy - ry*qx
+    if math.Abs(den) < 1e-18 {
+        return 0, 0, false
+    }
+    wx, wy := c.X-a.X, c.Y-a.Y
+    t = (wx*qy - wy*qx) / den
+    s = (wx*ry - wy*rx) / den
+    if t < 0 || t > 1 || s < 0 || s > 1 {
+        return 0, 0, false
+    }
+    return t, s, true
+}
+
+// pointOnSegment reports whether p lies on segment ab (within eps) and where.
+func pointOnSegment(p, a, b Point2, eps float64) (float64, bool) {
+    dx, dy := b.X-a.X, b.Y-a.Y
+    l2 := dx*dx + dy*dy
+    if l2 == 0 {
+        return 0, false
+    }
+    t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / l2
+    if t <= 0 || t >= 1 {
+        return 0, false
+    }
+    cx, cy := a.X+t*dx, a.Y+t*dy
+    if math.Hypot(p.X-cx, p.Y-cy) > eps {
+        return 0, false
+    }
+    return t, true
+}
+
+// coveredBy reports whether p is inside any of the polygons.
+func coveredBy(p Point2, polys [][]Point2) bool {
+    for _, pts := range polys {
+        if pointInPolygon(p, pts) {
+            return true
+        }
+    }
+    return false
+}
+
+// pointInPolygon is the usual even-odd ray casting test.
+func pointInPolygon(p Point2, pts []Point2) bool {
+    in := false
+    for i, j := 0, len(pts)-1; i < len(pts); j, i = i, i+1 {
+        a, b := pts[i], pts[j]
+        if (a.Y > p.Y) != (b.Y > p.Y) &&
+            p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+            in = !in
+        }
+    }
+    return in
+}
+
+// simplifyCollinear drops loop vertices that sit on a straight line between
+// their neighbours (left over from edge splitting).
+func simplifyCollinear(loop []Point2, eps float64) []Point2 {
+    if len(loop) < 4 {
+        return loop
+    }
+    var out []Point2
+    n := len(loop)
+    for i := 0; i < n; i++ {
+        a, b, c := loop[(i+n-1)%n], loop[i], loop[(i+1)%n]
+        cr := (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+        if math.Abs(cr) > eps*math.Hypot(c.X-a.X, c.Y-a.Y) {
+            out = append(out, b)
+        }
+    }
+    return out
+}
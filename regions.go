@@ -0,0 +1,141 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+    "sort"
+    "strconv"
+)
+
+// -----------------------------
+//   Named regions
+// -----------------------------
+
+// FaceRegions names the region ("roof", "wall", ...) each face belongs to,
+// indexed by face. An empty name means the face is in no region. Regions come
+// from OBJ groups (meshio.LoadOBJGroups) or from RegionsFromMap, and are used
+// to word instructions and label nets in terms of the model's parts.
+type FaceRegions []string
+
+// RegionsFromMap builds FaceRegions for poly from a region -> faces map. A
+// face may only be in one region.
+func RegionsFromMap(poly Polyhedron, m map[string][]int) (FaceRegions, error) {
+    regions := make(FaceRegions, len(poly.Faces))
+    names := make([]string, 0, len(m))
+    for name := range m {
+        names = append(names, name)
+    }
+    sort.Strings(names) // deterministic error messages
+    for _, name := range names {
+        if name == "" {
+            return nil, fmt.Errorf("empty region name")
+        }
+        for _, f := range m[name] {
+            if f < 0 || f >= len(poly.Faces) {
+                return nil, fmt.Errorf("region %q: face %d out of range", name, f)
+            }
+            if regions[f] != "" && regions[f] != name {
+                return nil, fmt.Errorf("face %d is in both %q and %q", f, regions[f], name)
+            }
+            regions[f] = name
+        }
+    }
+    return regions, nil
+}
+
+// Name returns the region of face f, or "face f" if it has none.
+func (r FaceRegions) Name(f int) string {
+    if f >= 0 && f < len(r) && r[f] != "" {
+        return r[f]
+    }
+    return "face " + strconv.Itoa(f)
+}
+
+// GlueInstructions words every mate of g as a step, in mate order:
+// "glue roof edge B to wall edge B". Faces without a region are called by
+// their index. Mates between two named pieces say which:
+// "glue face 3 edge C (top piece) to face 8 edge C (bottom piece)".
+func GlueInstructions(g *GluingGraph, regions FaceRegions) []string {
+    if g == nil {
+        return nil
+    }
+    names := make(map[int]string, len(g.Pieces))
+    for _, p := range g.Pieces {
+        names[p.ID] = p.Name
+    }
+    steps := make([]string, len(g.Mates))
+    for i, m := range g.Mates {
+        a, b := names[m.PieceA], names[m.PieceB]
+        if m.PieceA != m.PieceB && a != "" && b != "" {
+            steps[i] = fmt.Sprintf("glue %s edge %s (%s piece) to %s edge %s (%s piece)", regions.Name(m.FaceA), m.Code, a, regions.Name(m.FaceB), m.Code, b)
+            continue
+        }
+        steps[i] = fmt.Sprintf("glue %s edge %s to %s edge %s", regions.Name(m.FaceA), m.Code, regions.Name(m.FaceB), m.Code)
+    }
+    return steps
+}
+
+// RegionOverlay writes each region's name on the net, once per group of
+// folded-together faces of that region, centred in its largest face. size is
+// the text height; 0 picks a quarter of the median edge length.
+func RegionOverlay(result *UnfoldResult, regions FaceRegions, size float64) *Overlay {
+    o := &Overlay{Name: "regions"}
+    if result == nil {
+        return o
+    }
+    if size <= 0 {
+        var lengths []float64
+        for _, f := range result.Face2D {
+            for i, a := range f.Vertices {
+                b := f.Vertices[(i+1)%len(f.Vertices)]
+                lengths = append(lengths, math.Hypot(b.X-a.X, b.Y-a.Y))
+            }
+        }
+        if len(lengths) == 0 {
+            return o
+        }
+        sort.Float64s(lengths)
+        size = lengths[len(lengths)/2] / 4
+    }
+
+    // group the faces of each region that are joined by folds
+    group := make([]int, len(result.Face2D))
+    for i := range group {
+        group[i] = i
+    }
+    var find func(int) int
+    find = func(x int) int {
+        if group[x] != x {
+            group[x] = find(group[x])
+        }
+        return group[x]
+    }
+    for _, e := range result.FoldEdges {
+        if e.FaceA < len(regions) && e.FaceB < len(regions) && regions[e.FaceA] != "" && regions[e.FaceA] == regions[e.FaceB] {
+            if a, b := find(e.FaceA), find(e.FaceB); a != b {
+                group[b] = a
+            }
+        }
+    }
+    best := make(map[int]int)
+    for f, f2 := range result.Face2D {
+        if f >= len(regions) || regions[f] == "" || len(f2.Vertices) < 3 {
+            continue
+        }
+        g := find(f)
+        if b, ok := best[g]; !ok || math.Abs(polygonArea(f2.Vertices)) > math.Abs(polygonArea(result.Face2D[b].Vertices)) {
+            best[g] = f
+        }
+    }
+    faces := make([]int, 0, len(best))
+    for _, f := range best {
+        faces = append(faces, f)
+    }
+    sort.Ints(faces)
+    for _, f := range faces {
+        c := interiorPoint(result.Face2D[f].Vertices)
+        w := 0.6 * size * float64(len(regions[f]))
+        o.Texts = append(o.Texts, OverlayText{At: Point2{X: c.X - w/2, Y: c.Y - size/2}, Text: regions[f], Size: size})
+    }
+    return o
+}
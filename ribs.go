@@ -0,0 +1,234 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Internal ribs / formers
+// -----------------------------
+
+// RibOptions controls GenerateRibs.
+type RibOptions struct {
+    Axis  Vector3 // ribs are perpendicular to this axis
+    Count int     // ribs along Axis, evenly spaced inside the model
+
+    // CrossAxis/CrossCount add a second family of ribs perpendicular to the
+    // first. Crossing ribs get interlocking half-depth slots (egg-crate).
+    CrossAxis  Vector3
+    CrossCount int
+
+    Inset     float64 // shrink outlines by this much so ribs sit inside the skin
+    Thickness float64 // rib material thickness = slot width
+}
+
+// Rib is one flat internal piece.
+type Rib struct {
+    Family  int    // 0 = Axis ribs, 1 = CrossAxis ribs
+    Index   int    // position within its family
+    Label   string // e.g. "A2" / "B0"
+    Plane   Plane
+    Loops   [][]Point2 // outlines in the plane's basis (see planeBasis)
+    Notches [][]Point2 // slot rectangles to cut out of the outline
+}
+
+// GenerateRibs cuts the closed mesh at evenly spaced planes to make stiffening
+// ribs for large paper models. Ribs of the two families get matching slots
+// wherever they cross so they slide together.
+func GenerateRibs(poly Polyhedron, opts RibOptions) ([]Rib, error) {
+    if opts.Count <= 0 {
+        return nil, errors.New("rib count must be positive")
+    }
+    if length(opts.Axis) == 0 {
+        return nil, errors.New("rib axis is zero")
+    }
+    if len(poly.Vertices) == 0 {
+        return nil, errors.New("polyhedron has no vertices")
+    }
+
+    ribs, err := ribFamily(poly, opts.Axis, opts.Count, 0, opts.Inset)
+    if err != nil {
+        return nil, err
+    }
+    if opts.CrossCount <= 0 {
+        return ribs, nil
+    }
+    if length(opts.CrossAxis) == 0 {
+        return nil, errors.New("cross rib axis is zero")
+    }
+    if length(cross(opts.Axis, opts.CrossAxis)) < 1e-9*length(opts.Axis)*length(opts.CrossAxis) {
+        return nil, errors.New("cross rib axis is parallel to rib axis")
+    }
+    crossRibs, err := ribFamily(poly, opts.CrossAxis, opts.CrossCount, 1, opts.Inset)
+    if err != nil {
+        return nil, err
+    }
+
+    // slots: family 0 is slotted from one end of each crossing line, family 1
+    // from the other, each to half depth
+    width := opts.Thickness
+    if width <= 0 {
+        width = 0.01 * ribExtent(poly)
+    }
+    for i := range ribs {
+        for j := range crossRibs {
+            a, b := &ribs[i], &crossRibs[j]
+            up := normalize(cross(a.Plane.Normal, b.Plane.Normal))
+            a.Notches = append(a.Notches, ribSlots(a, b.Plane, up, width)...)
+            b.Notches = append(b.Notches, ribSlots(b, a.Plane, scale(up, -1), width)...)
+        }
+    }
+    return append(ribs, crossRibs...), nil
+}
+
+// ribFamily slices count evenly spaced ribs perpendicular to axis.
+func ribFamily(poly Polyhedron, axis Vector3, count, family int, inset float64) ([]Rib, error) {
+    axis = normalize(axis)
+    lo, hi := math.Inf(1), math.Inf(-1)
+    for _, p := range poly.Vertices {
+        h := dot(p, axis)
+        lo, hi = math.Min(lo, h), math.Max(hi, h)
+    }
+    var ribs []Rib
+    for i := 0; i < count; i++ {
+        h := lo + float64(i+1)*(hi-lo)/float64(count+1)
+        pl := Plane{Point: scale(axis, h), Normal: axis}
+        sec, err := SlicePlane(poly, pl)
+        if err != nil {
+            return nil, fmt.Errorf("rib %d: %v", i, err)
+        }
+        rib := Rib{
+            Family: family,
+            Index:  i,
+            Label:  fmt.Sprintf("%c%d", 'A'+family, i),
+            Plane:  pl,
+        }
+        loops, _ := sec.Flatten()
+        for _, l := range loops {
+            if inset > 0 {
+                l = offsetLoop(l, inset)
+            }
+            if len(l) >= 3 {
+                rib.Loops = append(rib.Loops, l)
+            }
+        }
+        ribs = append(ribs, rib)
+    }
+    return ribs, nil
+}
+
+// ribSlots returns the slot rectangles (in rib's 2D basis) where the plane
+// other crosses the rib, running from the end of each crossing that lies
+// towards dir3 back to its middle.
+func ribSlots(rib *Rib, other Plane, dir3 Vector3, width float64) [][]Point2 {
+    n := normalize(rib.Plane.Normal)
+    m := normalize(other.Normal)
+    u, v := planeBasis(n)
+
+    // a point on both planes: solve in the span of n and m
+    d1 := dot(rib.Plane.Point, n)
+    d2 := dot(other.Point, m)
+    nm := dot(n, m)
+    det := 1 - nm*nm
+    if det < 1e-12 {
+        return nil
+    }
+    c1 := (d1 - d2*nm) / det
+    c2 := (d2 - d1*nm) / det
+    p3 := add(scale(n, c1), scale(m, c2))
+
+    o := Point2{X: dot(p3, u), Y: dot(p3, v)}
+    d := Point2{X: dot(dir3, u), Y: dot(dir3, v)}
+
+    // crossings of the line o + t*d with the outline
+    var ts []float64
+    for _, loop := range rib.Loops {
+        for i := range loop {
+            a, b := loop[i], loop[(i+1)%len(loop)]
+            far := Point2{X: o.X + d.X, Y: o.Y + d.Y}
+            if t, s, ok := lineSegmentIntersection(o, far, a, b); ok && s >= 0 && s < 1 {
+                ts = append(ts, t)
+            }
+        }
+    }
+    sort.Float64s(ts)
+
+    var slots [][]Point2
+    nx, ny := -d.Y*width/2, d.X*width/2
+    for k := 0; k+1 < len(ts); k += 2 {
+        // inside span [ts[k], ts[k+1]]; slot from its far end to the middle
+        t0, t1 := (ts[k]+ts[k+1])/2, ts[k+1]
+        p0 := Point2{X: o.X + t0*d.X, Y: o.Y + t0*d.Y}
+        p1 := Point2{X: o.X + t1*d.X, Y: o.Y + t1*d.Y}
+        slots = append(slots, []Point2{
+            {p0.X + nx, p0.Y + ny}, {p0.X - nx, p0.Y - ny},
+            {p1.X - nx, p1.Y - ny}, {p1.X + nx, p1.Y + ny},
+        })
+    }
+    return slots
+}
+
+// lineSegmentIntersection intersects the infinite line through p, q with the
+// segment ab. t is the parameter on the line, s on the segment.
+func lineSegmentIntersection(p, q, a, b Point2) (t, s float64, ok bool) {
+    rx, ry := q.X-p.X, q.Y-p.Y
+    sx, sy := b.X-a.X, b.Y-a.Y
+    den := rx*sy - ry*sx
+    if math.Abs(den) < 1e-18 {
+        return 0, 0, false
+    }
+    wx, wy := a.X-p.X, a.Y-p.Y
+    t = (wx*sy - wy*sx) / den
+    s = (wx*ry - wy*rx) / den
+    return t, s, true
+}
+
+// offsetLoop moves every edge of a loop inwards by d (mitered corners, with
+// the miter clamped at 4d). Clockwise loops (holes) grow instead, which is
+// also "inwards" for the material.
+func offsetLoop(pts []Point2, d float64) []Point2 {
+    n := len(pts)
+    if n < 3 {
+        return pts
+    }
+    if polygonArea(pts) < 0 {
+        d = -d
+    }
+    out := make([]Point2, n)
+    for i := 0; i < n; i++ {
+        a, b, c := pts[(i+n-1)%n], pts[i], pts[(i+1)%n]
+        n1 := leftNormal(a, b)
+        n2 := leftNormal(b, c)
+        bx, by := n1.X+n2.X, n1.Y+n2.Y
+        bl := math.Hypot(bx, by)
+        if bl < 1e-12 {
+            out[i] = Point2{X: b.X + n1.X*d, Y: b.Y + n1.Y*d}
+            continue
+        }
+        bx, by = bx/bl, by/bl
+        // distance along the bisector that keeps both edges d away
+        cosHalf := bx*n1.X + by*n1.Y
+        m := d / math.Max(cosHalf, 0.25)
+        out[i] = Point2{X: b.X + bx*m, Y: b.Y + by*m}
+    }
+    return out
+}
+
+// leftNormal is the unit normal on the left of a->b.
+func leftNormal(a, b Point2) Point2 {
+    dx, dy := b.X-a.X, b.Y-a.Y
+    l := math.Hypot(dx, dy)
+    if l == 0 {
+        return Point2{}
+    }
+    return Point2{X: -dy / l, Y: dx / l}
+}
+
+// ribExtent is the diagonal of the mesh bounding box.
+func ribExtent(poly Polyhedron) float64 {
+    lo, hi := boundingBox(poly.Vertices)
+    return length(sub(hi, lo))
+}
@@ -0,0 +1,251 @@
+package unfolder
+
+import (
+    "errors"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Seam allowances (fabric, leather)
+// -----------------------------
+
+// SeamCorner selects how the allowance is drawn around outside corners.
+type SeamCorner int
+
+const (
+    // SeamCornerMiter extends the two offset lines until they meet. Corners
+    // sharper than MiterLimit fall back to SeamCornerBevel.
+    SeamCornerMiter SeamCorner = iota
+    // SeamCornerBevel joins the offset lines with a straight cut.
+    SeamCornerBevel
+    // SeamCornerRound joins the offset lines with an arc around the corner.
+    SeamCornerRound
+)
+
+// SeamOptions controls SeamAllowances.
+type SeamOptions struct {
+    // Width of the allowance along seams (cut edges joining two faces).
+    Width float64
+    // HemWidth is used instead of Width on mesh boundary edges, which are
+    // hemmed rather than sewn to anything. 0 = Width.
+    HemWidth float64
+    // Corner treatment at outside corners.
+    Corner SeamCorner
+    // MiterLimit caps how far a mitered corner may stick out, as a multiple
+    // of the allowance width. Default 4.
+    MiterLimit float64
+    // ArcSegments is the number of segments per 90 degrees of a rounded
+    // corner. Default 4.
+    ArcSegments int
+}
+
+// SeamPiece is one boundary loop of the net with its allowance. A net that
+// encloses a gap has one extra piece per gap; its loops run clockwise.
+type SeamPiece struct {
+    // SewLine is the net boundary (the stitching line), with the fabric on
+    // its left.
+    SewLine []Point2
+    // CutLine is the outline to cut along, the allowance outside SewLine.
+    CutLine []Point2
+}
+
+// SeamAllowances traces the outline of the net along its cut edges (folds are
+// continuous fabric, so they don't get an allowance) and offsets it outward.
+// Unlike glue tabs, the allowance runs along the whole outline of a piece.
+func SeamAllowances(result *UnfoldResult, opts SeamOptions) ([]SeamPiece, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    if opts.Width <= 0 {
+        return nil, errors.New("seam allowance width must be positive")
+    }
+    if opts.HemWidth <= 0 {
+        opts.HemWidth = opts.Width
+    }
+    if opts.MiterLimit <= 0 {
+        opts.MiterLimit = 4
+    }
+    if opts.ArcSegments <= 0 {
+        opts.ArcSegments = 4
+    }
+
+    loops, widths := netOutline(result, opts)
+    pieces := make([]SeamPiece, len(loops))
+    for i, loop := range loops {
+        pieces[i] = SeamPiece{SewLine: loop, CutLine: offsetOutline(loop, widths[i], opts)}
+    }
+    return pieces, nil
+}
+
+// Overlay returns the cutting line as an overlay, so exporters can draw it.
+func (p SeamPiece) Overlay() *Overlay {
+    return &Overlay{
+        Name:      "seam-allowance",
+        Polylines: []OverlayLine{{Points: append([]Point2(nil), p.CutLine...), Closed: true}},
+        Cut:       true,
+    }
+}
+
+// netOutline chains the directed cut sides of every placed face (fabric on the
+// left) into closed loops, and returns the allowance width of each loop edge.
+func netOutline(result *UnfoldResult, opts SeamOptions) ([][]Point2, [][]float64) {
+    type seg struct {
+        a, b  Point2
+        width float64
+        used  bool
+    }
+    _, eps := faceBoxes(result.Face2D)
+    key := func(p Point2) [2]int64 {
+        return [2]int64{int64(math.Round(p.X / eps)), int64(math.Round(p.Y / eps))}
+    }
+
+    var segs []*seg
+    addSide := func(face, edge int, width float64) {
+        if face < 0 || face >= len(result.Face2D) {
+            return
+        }
+        pts := result.Face2D[face].Vertices
+        if len(pts) < 3 {
+            return
+        }
+        a, b := pts[edge], pts[(edge+1)%len(pts)]
+        if polygonArea(pts) < 0 {
+            a, b = b, a
+        }
+        segs = append(segs, &seg{a: a, b: b, width: width})
+    }
+    for _, e := range result.CutEdges {
+        w := opts.Width
+        if e.FaceB < 0 {
+            w = opts.HemWidth
+        }
+        addSide(e.FaceA, e.EdgeA, w)
+        if e.FaceB >= 0 {
+            addSide(e.FaceB, e.EdgeB, w)
+        }
+    }
+
+    from := make(map[[2]int64][]*seg)
+    for _, s := range segs {
+        from[key(s.a)] = append(from[key(s.a)], s)
+    }
+
+    var loops [][]Point2
+    var widths [][]float64
+    for _, start := range segs {
+        if start.used {
+            continue
+        }
+        var loop []Point2
+        var ws []float64
+        cur := start
+        for cur != nil && !cur.used {
+            cur.used = true
+            loop = append(loop, cur.a)
+            ws = append(ws, cur.width)
+            // where the outline touches itself, take the rightmost turn so
+            // the loop follows the outside of the piece
+            var next *seg
+            best := math.Inf(1)
+            din := math.Atan2(cur.b.Y-cur.a.Y, cur.b.X-cur.a.X)
+            for _, s := range from[key(cur.b)] {
+                if s.used {
+                    continue
+                }
+                turn := math.Remainder(math.Atan2(s.b.Y-s.a.Y, s.b.X-s.a.X)-din, 2*math.Pi)
+                if turn < best {
+                    best, next = turn, s
+                }
+            }
+            cur = next
+        }
+        if len(loop) >= 3 {
+            loops = append(loops, loop)
+            widths = append(widths, ws)
+        }
+    }
+
+    // outer loops first, biggest first
+    idx := make([]int, len(loops))
+    for i := range idx {
+        idx[i] = i
+    }
+    sort.SliceStable(idx, func(i, j int) bool { return polygonArea(loops[idx[i]]) > polygonArea(loops[idx[j]]) })
+    sortedLoops := make([][]Point2, len(loops))
+    sortedWidths := make([][]float64, len(loops))
+    for i, k := range idx {
+        sortedLoops[i], sortedWidths[i] = loops[k], widths[k]
+    }
+    return sortedLoops, sortedWidths
+}
+
+// offsetOutline offsets every edge i of loop (fabric on the left) to its right
+// by widths[i] and joins neighbouring offset edges according to opts.Corner.
+func offsetOutline(loop []Point2, widths []float64, opts SeamOptions) []Point2 {
+    n := len(loop)
+    type line struct{ a, b, dir Point2 }
+    off := make([]line, n)
+    for i := 0; i < n; i++ {
+        a, b := loop[i], loop[(i+1)%n]
+        l := math.Hypot(b.X-a.X, b.Y-a.Y)
+        if l == 0 {
+            off[i] = line{a: a, b: b}
+            continue
+        }
+        dir := Point2{X: (b.X - a.X) / l, Y: (b.Y - a.Y) / l}
+        nx, ny := dir.Y*widths[i], -dir.X*widths[i] // right-hand normal
+        off[i] = line{a: Point2{X: a.X + nx, Y: a.Y + ny}, b: Point2{X: b.X + nx, Y: b.Y + ny}, dir: dir}
+    }
+
+    var out []Point2
+    for j := 0; j < n; j++ {
+        in, outL := off[(j+n-1)%n], off[j]
+        corner := loop[j]
+        w := math.Max(widths[(j+n-1)%n], widths[j])
+        turn := in.dir.X*outL.dir.Y - in.dir.Y*outL.dir.X
+
+        // inside corner (or straight): the offset lines cross
+        if turn <= 1e-12 {
+            if t, _, ok := lineSegmentIntersection(in.a, in.b, outL.a, outL.b); ok {
+                out = append(out, Point2{X: in.a.X + t*(in.b.X-in.a.X), Y: in.a.Y + t*(in.b.Y-in.a.Y)})
+            } else if math.Hypot(outL.a.X-in.b.X, outL.a.Y-in.b.Y) < 1e-12*w {
+                out = append(out, in.b)
+            } else {
+                out = append(out, in.b, outL.a) // straight on, but the width changes
+            }
+            continue
+        }
+
+        switch opts.Corner {
+        case SeamCornerMiter:
+            if t, _, ok := lineSegmentIntersection(in.a, in.b, outL.a, outL.b); ok {
+                p := Point2{X: in.a.X + t*(in.b.X-in.a.X), Y: in.a.Y + t*(in.b.Y-in.a.Y)}
+                if math.Hypot(p.X-corner.X, p.Y-corner.Y) <= opts.MiterLimit*w {
+                    out = append(out, p)
+                    continue
+                }
+            }
+            out = append(out, in.b, outL.a)
+        case SeamCornerRound:
+            a0 := math.Atan2(in.b.Y-corner.Y, in.b.X-corner.X)
+            a1 := math.Atan2(outL.a.Y-corner.Y, outL.a.X-corner.X)
+            sweep := math.Mod(a1-a0+2*math.Pi, 2*math.Pi)
+            r0 := math.Hypot(in.b.X-corner.X, in.b.Y-corner.Y)
+            r1 := math.Hypot(outL.a.X-corner.X, outL.a.Y-corner.Y)
+            steps := int(math.Ceil(sweep / (math.Pi / 2) * float64(opts.ArcSegments)))
+            if steps < 1 {
+                steps = 1
+            }
+            for k := 0; k <= steps; k++ {
+                f := float64(k) / float64(steps)
+                r := r0 + f*(r1-r0)
+                ang := a0 + f*sweep
+                out = append(out, Point2{X: corner.X + r*math.Cos(ang), Y: corner.Y + r*math.Sin(ang)})
+            }
+        default: // SeamCornerBevel
+            out = append(out, in.b, outL.a)
+        }
+    }
+    return out
+}
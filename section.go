@@ -0,0 +1,284 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+)
+
+// -----------------------------
+//   Sectional (sliced) models
+// -----------------------------
+
+// Plane is an oriented plane through Point with the given Normal. The
+// "below" side is where dot(p - Point, Normal) < 0.
+type Plane struct {
+    Point  Vector3
+    Normal Vector3
+}
+
+// SignedDistance returns how far p is above the plane (in units of |Normal|).
+func (pl Plane) SignedDistance(p Vector3) float64 {
+    return dot(sub(p, pl.Point), pl.Normal)
+}
+
+// sectionEps is the distance under which a vertex counts as lying on the plane.
+const sectionEps = 1e-9
+
+// Section is one unfolded piece of a sliced model.
+type Section struct {
+    Slab   int // index of the slab (in SliceSlabs order) this piece belongs to
+    Poly   Polyhedron
+    Result *UnfoldResult
+}
+
+// UnfoldSections cuts a closed mesh with the given planes (e.g. one per building
+// floor), closes every cut with a cap face, and unfolds each resulting slab
+// separately. Slabs that fall apart into several pieces (two towers on one
+// floor) give one Section per piece.
+func UnfoldSections(poly Polyhedron, planes []Plane, opts UnfoldOptions) ([]Section, error) {
+    slabs, err := SliceSlabs(poly, planes)
+    if err != nil {
+        return nil, err
+    }
+    var out []Section
+    for sIdx, slab := range slabs {
+        adj, err := BuildFaceAdjacency(slab)
+        if err != nil {
+            return nil, fmt.Errorf("error building adjacency for slab %d: %v", sIdx, err)
+        }
+        for _, comp := range faceComponents(adj, len(slab.Faces)) {
+            piece, _ := subMesh(slab, comp)
+            res, err := UnfoldMeshWithOptions(piece, 0, opts)
+            if err != nil {
+                return nil, fmt.Errorf("failed to unfold slab %d: %w", sIdx, err)
+            }
+            out = append(out, Section{Slab: sIdx, Poly: piece, Result: res})
+        }
+    }
+    return out, nil
+}
+
+// SliceSlabs cuts poly with every plane in turn and returns the closed,
+// non-empty pieces. Caps are built from the cut outlines; nested outlines (a
+// floor with a courtyard) become holes in the cap around them. Faces are
+// assumed convex - triangulate concave ones first.
+func SliceSlabs(poly Polyhedron, planes []Plane) ([]Polyhedron, error) {
+    pieces := []Polyhedron{poly}
+    for pIdx, pl := range planes {
+        if length(pl.Normal) == 0 {
+            return nil, fmt.Errorf("plane %d has a zero normal", pIdx)
+        }
+        var next []Polyhedron
+        for _, piece := range pieces {
+            below, above, err := SplitByPlane(piece, pl)
+            if err != nil {
+                return nil, fmt.Errorf("plane %d: %v", pIdx, err)
+            }
+            for _, half := range []Polyhedron{below, above} {
+                if len(half.Faces) > 0 {
+                    next = append(next, half)
+                }
+            }
+        }
+        pieces = next
+    }
+    return pieces, nil
+}
+
+// SplitByPlane cuts a closed mesh in two and caps both halves. Either half may
+// come back empty if the plane misses the mesh.
+func SplitByPlane(poly Polyhedron, pl Plane) (below, above Polyhedron, err error) {
+    c := clipByPlane(poly, pl)
+
+    belowCaps, err := chainCapLoops(c.capBelow, c.verts, pl.Normal)
+    if err != nil {
+        return Polyhedron{}, Polyhedron{}, err
+    }
+    aboveCaps, err := chainCapLoops(c.capAbove, c.verts, pl.Normal)
+    if err != nil {
+        return Polyhedron{}, Polyhedron{}, err
+    }
+
+    full := Polyhedron{Vertices: c.verts, Name: poly.Name, Units: poly.Units}
+    if len(c.belowFaces) > 0 {
+        full.Faces = append(c.belowFaces, belowCaps...)
+        below, _ = subMesh(full, allFaces(len(full.Faces)))
+    }
+    if len(c.aboveFaces) > 0 {
+        full.Faces = append(c.aboveFaces, aboveCaps...)
+        above, _ = subMesh(full, allFaces(len(full.Faces)))
+    }
+    return below, above, nil
+}
+
+// planeClip is a mesh clipped against a plane: the face loops on either side
+// (indexing verts, which extends the original vertices with the new ones on
+// the plane) and the directed cut edges each side's cap has to run along.
+type planeClip struct {
+    verts      []Vector3
+    belowFaces []Face
+    aboveFaces []Face
+    capBelow   [][2]int // the below cap runs along these, the above cap reversed
+    capAbove   [][2]int
+}
+
+func clipByPlane(poly Polyhedron, pl Plane) *planeClip {
+    dist := planeDistances(poly, pl)
+
+    c := &planeClip{verts: append([]Vector3(nil), poly.Vertices...)}
+    cutVertex := make(map[[2]int]int) // original edge -> new vertex on the plane
+    splitEdge := func(a, b int) int {
+        key := sortPair(a, b)
+        if v, ok := cutVertex[key]; ok {
+            return v
+        }
+        c.verts = append(c.verts, slicePoint(poly, dist, sliceKey(key)))
+        cutVertex[key] = len(c.verts) - 1
+        return len(c.verts) - 1
+    }
+
+    var inPlane [][2]int // boundaries of faces lying in the plane, reversed
+    for _, face := range poly.Faces {
+        n := len(face.Vertices)
+        allOn, anyBelow, anyAbove := true, false, false
+        for _, v := range face.Vertices {
+            allOn = allOn && dist[v] == 0
+            anyBelow = anyBelow || dist[v] < 0
+            anyAbove = anyAbove || dist[v] > 0
+        }
+        if allOn {
+            // face lies in the plane: it closes whichever side it faces away from
+            if dot(faceNormal(poly, face), pl.Normal) > 0 {
+                c.belowFaces = append(c.belowFaces, face)
+                for i := 0; i < n; i++ {
+                    inPlane = append(inPlane, [2]int{face.Vertices[(i+1)%n], face.Vertices[i]})
+                }
+            } else {
+                c.aboveFaces = append(c.aboveFaces, face)
+            }
+            continue
+        }
+
+        var lo, hi []int
+        for i := 0; i < n; i++ {
+            a, b := face.Vertices[i], face.Vertices[(i+1)%n]
+            if dist[a] <= 0 {
+                lo = append(lo, a)
+            }
+            if dist[a] >= 0 {
+                hi = append(hi, a)
+            }
+            if (dist[a] < 0 && dist[b] > 0) || (dist[a] > 0 && dist[b] < 0) {
+                x := splitEdge(a, b)
+                lo = append(lo, x)
+                hi = append(hi, x)
+            }
+        }
+        if anyBelow && len(lo) >= 3 {
+            c.belowFaces = append(c.belowFaces, Face{Vertices: lo})
+        }
+        if anyAbove && len(hi) >= 3 {
+            c.aboveFaces = append(c.aboveFaces, Face{Vertices: hi})
+        }
+    }
+
+    // the cap runs along the cross section, minus whatever faces lying in
+    // the plane already cover (they'd run the same edges the other way)
+    edges := inPlane
+    for _, s := range planeSegments(poly, pl, dist) {
+        var e [2]int
+        for i, k := range s {
+            e[i] = k[0]
+            if k[0] != k[1] {
+                e[i] = splitEdge(k[0], k[1])
+            }
+        }
+        edges = append(edges, e)
+    }
+    count := make(map[[2]int]int)
+    for _, e := range edges {
+        count[e]++
+    }
+    for _, e := range edges {
+        r := [2]int{e[1], e[0]}
+        switch {
+        case count[e] == 0: // cancelled already
+        case count[r] > 0:
+            count[e]--
+            count[r]--
+        default:
+            count[e]--
+            c.capBelow = append(c.capBelow, e)
+            c.capAbove = append(c.capAbove, r)
+        }
+    }
+    return c
+}
+
+// chainCapLoops links directed cap edges into closed loops and makes a cap
+// face of each outermost loop, with the loops directly inside it (running the
+// other way already) as its Holes. A loop inside a hole is a cap again, and
+// so on. verts and normal place the loops on the cut plane.
+func chainCapLoops(edges [][2]int, verts []Vector3, normal Vector3) ([]Face, error) {
+    chained, open := chainEdges(edges)
+    if len(open) > 0 {
+        return nil, errors.New("cut outline is open; the mesh must be closed to be sliced")
+    }
+    var loops [][]int
+    for _, loop := range chained {
+        if len(loop) >= 3 {
+            loops = append(loops, loop)
+        }
+    }
+
+    // how deep each loop is nested; odd depths are holes
+    u, v := planeBasis(normalize(normal))
+    flat := make([][]Point2, len(loops))
+    for i, loop := range loops {
+        pts := make([]Vector3, len(loop))
+        for k, vi := range loop {
+            pts[k] = verts[vi]
+        }
+        flat[i] = projectLoop(pts, u, v)
+    }
+    inside := func(i, j int) bool { return i != j && pointInPolygon(flat[i][0], flat[j]) }
+    depth := make([]int, len(loops))
+    for i := range loops {
+        for j := range loops {
+            if inside(i, j) {
+                depth[i]++
+            }
+        }
+    }
+
+    var faces []Face
+    capOf := make(map[int]int) // loop -> its face
+    for i, loop := range loops {
+        if depth[i]%2 == 0 {
+            capOf[i] = len(faces)
+            faces = append(faces, Face{Vertices: loop})
+        }
+    }
+    for i, loop := range loops {
+        if depth[i]%2 == 0 {
+            continue
+        }
+        for j := range loops {
+            if depth[j] == depth[i]-1 && inside(i, j) {
+                f := &faces[capOf[j]]
+                f.Holes = append(f.Holes, loop)
+                break
+            }
+        }
+    }
+    return faces, nil
+}
+
+// allFaces returns [0, 1, ..., n-1].
+func allFaces(n int) []int {
+    out := make([]int, n)
+    for i := range out {
+        out[i] = i
+    }
+    return out
+}
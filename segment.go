@@ -0,0 +1,211 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+)
+
+// -----------------------------
+//   Segmented unfolding (several patches)
+// -----------------------------
+
+// PatchSeam is a mesh edge cut between two patches of a segmented unfold.
+type PatchSeam struct {
+    Vertices [2]int `json:"vertices"` // global vertex indices, smaller first
+    PatchA   int    `json:"patchA"`
+    FaceA    int    `json:"faceA"`
+    EdgeA    int    `json:"edgeA"` // local edge index in FaceA
+    PatchB   int    `json:"patchB"`
+    FaceB    int    `json:"faceB"`
+    EdgeB    int    `json:"edgeB"`
+}
+
+// UnfoldMeshSegmented unfolds models that don't fit in one overlap-free net.
+// It grows patches greedily: starting from the lowest unassigned face, it
+// hinges on neighbours breadth first and leaves out any face that would
+// overlap the patch so far; those start or join later patches. Every patch
+// is free of overlaps by construction.
+//
+// Each result uses the mesh's global face and vertex indices; faces of other
+// patches are simply not placed (empty Face2D, parent -1), so the results can
+// be exported as they are. Edges between patches are cut edges with FaceB = -1
+// in their patch and are listed in the returned seams.
+func UnfoldMeshSegmented(poly Polyhedron, opts UnfoldOptions) (results []UnfoldResult, seams []PatchSeam, err error) {
+    at := &progress{stage: "adjacency", face: -1}
+    defer at.recover(&err)
+    nFaces := len(poly.Faces)
+    if nFaces == 0 {
+        return nil, nil, errors.New("polyhedron has no faces")
+    }
+    estimate := EstimateMemory(poly)
+    if opts.MemoryLimit > 0 && estimate > opts.MemoryLimit {
+        return nil, nil, fmt.Errorf("%w: estimated %d bytes, limit %d", ErrMemoryLimit, estimate, opts.MemoryLimit)
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+
+    patchOf := make([]int, nFaces)
+    for i := range patchOf {
+        patchOf[i] = -1
+    }
+    at.stage = "patch growth"
+    for seed := 0; seed < nFaces; seed++ {
+        if patchOf[seed] >= 0 {
+            continue
+        }
+        at.face = seed
+        res, err := growPatch(poly, adj, seed, len(results), patchOf, opts)
+        if err != nil {
+            return nil, nil, err
+        }
+        results = append(results, *res)
+    }
+    at.stage, at.face = "seams", -1
+    return results, patchSeams(poly, adj, patchOf), nil
+}
+
+// patchSeams lists the edges between faces of different patches, patchOf
+// giving every face's patch.
+func patchSeams(poly Polyhedron, adj *FaceAdjacency, patchOf []int) []PatchSeam {
+    var seams []PatchSeam
+    for f := range poly.Faces {
+        for _, nbr := range adj.Neighbors[f] {
+            g := nbr.FaceIndex
+            if g <= f || patchOf[f] == patchOf[g] {
+                continue
+            }
+            ge, _ := findEdgeInFace(poly.Faces[g], nbr.SharedEdge)
+            seams = append(seams, PatchSeam{
+                Vertices: nbr.SharedEdge,
+                PatchA:   patchOf[f],
+                FaceA:    f,
+                EdgeA:    nbr.ThisFaceEdge[0],
+                PatchB:   patchOf[g],
+                FaceB:    g,
+                EdgeB:    ge[0],
+            })
+        }
+    }
+    sort.SliceStable(seams, func(i, j int) bool {
+        if seams[i].Vertices != seams[j].Vertices {
+            if seams[i].Vertices[0] != seams[j].Vertices[0] {
+                return seams[i].Vertices[0] < seams[j].Vertices[0]
+            }
+            return seams[i].Vertices[1] < seams[j].Vertices[1]
+        }
+        return seams[i].FaceA < seams[j].FaceA
+    })
+    return seams
+}
+
+// growPatch places seed and then every unassigned face it can reach without
+// overlapping, recording the patch index in patchOf.
+func growPatch(poly Polyhedron, adj *FaceAdjacency, seed, patch int, patchOf []int, opts UnfoldOptions) (*UnfoldResult, error) {
+    nFaces := len(poly.Faces)
+    face2D := make([]Face2D, nFaces)
+    parent := make([]int, nFaces)
+    for i := range parent {
+        parent[i] = -1
+    }
+    if err := placeRootFace(poly, seed, &face2D[seed]); err != nil {
+        return nil, fmt.Errorf("failed to place face %d: %w", seed, err)
+    }
+    patchOf[seed] = patch
+    members := []int{seed}
+    boxes := map[int]box2{seed: polyBox(face2D[seed].Vertices)}
+    var folds []NetEdge
+    eps := patchEps(poly)
+
+    for q := 0; q < len(members); q++ {
+        f := members[q]
+        for _, nbr := range adj.Neighbors[f] {
+            g := nbr.FaceIndex
+            if patchOf[g] >= 0 {
+                continue
+            }
+            pFace := poly.Faces[f]
+            i0, i1 := nbr.ThisFaceEdge[0], nbr.ThisFaceEdge[1]
+            pts, err := hingeFace(poly, g, pFace.Vertices[i0], pFace.Vertices[i1], face2D[f].Vertices[i0], face2D[f].Vertices[i1])
+            if err != nil {
+                continue // degenerate hinge: leave the face for another patch
+            }
+            if opts.Anchoring == AnchorBestFit {
+                pts = bestFitAnchor(poly, f, g, face2D[f].Vertices, pts)
+            }
+            b := polyBox(pts)
+            if overlapsPatch(pts, b, members, face2D, boxes, eps) {
+                continue
+            }
+
+            face2D[g].Vertices = pts
+            parent[g] = f
+            patchOf[g] = patch
+            boxes[g] = b
+            members = append(members, g)
+            ge, _ := findEdgeInFace(poly.Faces[g], nbr.SharedEdge)
+            folds = append(folds, NetEdge{Vertices: nbr.SharedEdge, FaceA: f, EdgeA: i0, FaceB: g, EdgeB: ge[0]})
+        }
+    }
+    if err := applyMaterial(poly, face2D, folds, opts.Material); err != nil {
+        return nil, err
+    }
+    return patchResult(poly, face2D, parent, folds, func(f int) bool { return patchOf[f] == patch }, opts), nil
+}
+
+// patchEps is the overlap tolerance for laying out patches: the net is as
+// big as the mesh, give or take, so it's sized on that.
+func patchEps(poly Polyhedron) float64 {
+    lo, hi := boundingBox(poly.Vertices)
+    if eps := 1e-9 * length(sub(hi, lo)); eps > 0 {
+        return eps
+    }
+    return 1e-9
+}
+
+// overlapsPatch reports whether the face at pts (bounding box b) would
+// overlap any of the members already placed.
+func overlapsPatch(pts []Point2, b box2, members []int, face2D []Face2D, boxes map[int]box2, eps float64) bool {
+    for _, m := range members {
+        bm := boxes[m]
+        if b.minX >= bm.maxX-eps || bm.minX >= b.maxX-eps || b.minY >= bm.maxY-eps || bm.minY >= b.maxY-eps {
+            continue
+        }
+        if polygonsOverlap(pts, face2D[m].Vertices, eps) {
+            return true
+        }
+    }
+    return false
+}
+
+// patchResult assembles the result of a patch laid out by hand: the faces
+// for which in is true, placed in face2D and joined by folds.
+func patchResult(poly Polyhedron, face2D []Face2D, parent []int, folds []NetEdge, in func(int) bool, opts UnfoldOptions) *UnfoldResult {
+    sortNetEdges(folds)
+
+    // cut edges of this patch only; edges to other patches become boundary
+    cuts := pieceCutEdges(classifyEdges(poly, folds), in)
+
+    attachHoles(poly, face2D)
+    if opts.Side == SideInterior {
+        mirrorFaces(face2D)
+    }
+    attachUVs(poly, face2D)
+    res := &UnfoldResult{
+        Face2D:          face2D,
+        VertexInstances: vertexInstances(poly, face2D),
+        SpanningTree:    parent,
+        FoldEdges:       folds,
+        CutEdges:        cuts,
+        Side:            opts.Side,
+    }
+    if opts.DetectOverlaps {
+        res.Overlaps = DetectOverlaps(res)
+    }
+    if opts.DoubleWalled {
+        AddDoubleWalls(res)
+    }
+    return res
+}
@@ -0,0 +1,321 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+    "strconv"
+)
+
+// -----------------------------
+//   Drawing a net (shared by the exporters)
+// -----------------------------
+
+// Line classes of a drawn net.
+const (
+    lineCut      = "cut"
+    lineMountain = "mountain"
+    lineValley   = "valley"
+    lineFold     = "fold"
+)
+
+// sheetLine is one line segment of a drawn net, in net units.
+type sheetLine struct {
+    class string
+    a, b  Point2
+    bend  float64 // how far a fold turns away from flat, radians; -1 if unknown or not a fold
+}
+
+// netMesh returns the mesh the faces of result are numbered by, to tell
+// mountain from valley folds with: the split mesh if warped faces were split,
+// else mesh, which then has to have as many faces as the net.
+func netMesh(result *UnfoldResult, mesh *Polyhedron) (*Polyhedron, error) {
+    if result.Mesh != nil {
+        return result.Mesh, nil
+    }
+    if mesh != nil && len(mesh.Faces) != len(result.Face2D) {
+        return nil, fmt.Errorf("mesh has %d faces, the net %d (pass the mesh the net was unfolded from)", len(mesh.Faces), len(result.Face2D))
+    }
+    return mesh, nil
+}
+
+// sheetLines returns every line of the net: both sides of each cut edge, the
+// outlines of holes, double walls and tabs, then the folds. mesh is optional and only needed
+// to tell mountain from valley folds. groups is optional too (see
+// SVGOptions.FaceGroups): flat folds inside a group are left out. With a mesh,
+// so are folds bending less than minBend (radians).
+func sheetLines(result *UnfoldResult, mesh *Polyhedron, groups []int, minBend float64) []sheetLine {
+    faceEdge := func(face, edge int) (Point2, Point2, bool) {
+        if face < 0 || face >= len(result.Face2D) {
+            return Point2{}, Point2{}, false
+        }
+        pts := result.Face2D[face].Vertices
+        if len(pts) < 3 || edge < 0 || edge >= len(pts) {
+            return Point2{}, Point2{}, false
+        }
+        return pts[edge], pts[(edge+1)%len(pts)], true
+    }
+
+    // double-wall and tab hinges are folds, not cuts
+    type side struct{ face, edge int }
+    hinge := make(map[side]bool, len(result.DoubleWalls)+len(result.Tabs))
+    for _, dw := range result.DoubleWalls {
+        hinge[side{dw.Face, dw.Edge}] = true
+    }
+    for _, t := range result.Tabs {
+        hinge[side{t.Face, t.Edge}] = true
+    }
+
+    var lines []sheetLine
+    for _, e := range result.CutEdges {
+        sides := []side{{e.FaceA, e.EdgeA}}
+        if e.FaceB >= 0 {
+            sides = append(sides, side{e.FaceB, e.EdgeB})
+        }
+        for _, s := range sides {
+            if hinge[s] {
+                continue
+            }
+            if a, b, ok := faceEdge(s.face, s.edge); ok {
+                lines = append(lines, sheetLine{lineCut, a, b, -1})
+            }
+        }
+    }
+    for _, f := range result.Face2D {
+        for _, hole := range f.Holes {
+            for i := range hole {
+                lines = append(lines, sheetLine{lineCut, hole[i], hole[(i+1)%len(hole)], -1})
+            }
+        }
+    }
+    for _, dw := range result.DoubleWalls {
+        n := len(dw.Vertices)
+        for i := 0; i < n; i++ {
+            if i == dw.Edge {
+                continue
+            }
+            lines = append(lines, sheetLine{lineCut, dw.Vertices[i], dw.Vertices[(i+1)%n], -1})
+        }
+    }
+    for _, t := range result.Tabs {
+        // Vertices[0..1] is the hinge
+        n := len(t.Vertices)
+        for i := 1; i < n; i++ {
+            lines = append(lines, sheetLine{lineCut, t.Vertices[i], t.Vertices[(i+1)%n], -1})
+        }
+    }
+
+    for _, e := range result.FoldEdges {
+        class := foldClass(mesh, result.Side, e)
+        if sameGroup(groups, e.FaceA, e.FaceB) && (mesh == nil || class == lineFold) {
+            continue
+        }
+        bend := -1.0
+        if mesh != nil {
+            bend = math.Abs(math.Pi - DihedralAngle(*mesh, e))
+            if bend < minBend {
+                continue
+            }
+        }
+        if a, b, ok := faceEdge(e.FaceA, e.EdgeA); ok {
+            lines = append(lines, sheetLine{class, a, b, bend})
+        }
+    }
+    // double walls and tabs fold in towards the inside of the model
+    inward := lineValley
+    if result.Side == SideInterior {
+        inward = lineMountain
+    }
+    for _, dw := range result.DoubleWalls {
+        if a, b, ok := faceEdge(dw.Face, dw.Edge); ok {
+            lines = append(lines, sheetLine{inward, a, b, math.Pi})
+        }
+    }
+    for _, t := range result.Tabs {
+        if len(t.Vertices) >= 2 {
+            lines = append(lines, sheetLine{inward, t.Vertices[0], t.Vertices[1], -1})
+        }
+    }
+    return lines
+}
+
+func sameGroup(groups []int, a, b int) bool {
+    return a >= 0 && b >= 0 && a < len(groups) && b < len(groups) && groups[a] == groups[b]
+}
+
+// sheetLabel is a face label of a drawn net.
+type sheetLabel struct {
+    at   Point2
+    text string
+}
+
+// sheetLabels returns one label per placed face, or with groups, one per
+// group (on its largest face) showing the group number.
+func sheetLabels(result *UnfoldResult, groups []int) []sheetLabel {
+    var labels []sheetLabel
+    best := make(map[int]int) // group -> index into labels
+    bestArea := make(map[int]float64)
+    for fIdx, f := range result.Face2D {
+        if len(f.Vertices) < 3 {
+            continue
+        }
+        if fIdx >= len(groups) {
+            labels = append(labels, sheetLabel{interiorPoint(f.Vertices), strconv.Itoa(fIdx)})
+            continue
+        }
+        g := groups[fIdx]
+        area := math.Abs(polygonArea(f.Vertices))
+        l := sheetLabel{interiorPoint(f.Vertices), strconv.Itoa(g)}
+        if i, ok := best[g]; !ok {
+            best[g] = len(labels)
+            bestArea[g] = area
+            labels = append(labels, l)
+        } else if area > bestArea[g] {
+            bestArea[g] = area
+            labels[i] = l
+        }
+    }
+    return labels
+}
+
+// foldHeat maps a fold's bend (0 = flat, π = folded right back) onto a
+// blue-green-yellow-red ramp, as 0..1 RGB.
+func foldHeat(bend float64) (r, g, b float64) {
+    t := math.Max(0, math.Min(1, bend/math.Pi))
+    switch {
+    case t < 1.0/3:
+        u := 3 * t
+        return 0, u, 1 - u // blue -> green
+    case t < 2.0/3:
+        u := 3*t - 1
+        return u, 1, 0 // green -> yellow
+    default:
+        u := 3*t - 2
+        return 1, 1 - u, 0 // yellow -> red
+    }
+}
+
+// foldClass picks the line style of a fold. Seen from the outside of the
+// model, convex edges rise towards the viewer (mountain) and concave ones sink
+// away (valley); seen from the inside (side) it's the other way round.
+func foldClass(mesh *Polyhedron, side NetSide, e NetEdge) string {
+    if mesh == nil {
+        return lineFold
+    }
+    theta := DihedralAngle(*mesh, e)
+    switch {
+    case math.Abs(theta-math.Pi) < 1e-9:
+        return lineFold
+    case (theta < math.Pi) == (side == SideExterior):
+        return lineMountain
+    default:
+        return lineValley
+    }
+}
+
+// sheetBounds returns the bounding box of the net, its double walls and the
+// overlays. ok is false if there is nothing to draw.
+func sheetBounds(result *UnfoldResult, overlays []*Overlay) (lo, hi Point2, ok bool) {
+    lo = Point2{math.Inf(1), math.Inf(1)}
+    hi = Point2{math.Inf(-1), math.Inf(-1)}
+    grow := func(p Point2) {
+        lo.X, lo.Y = math.Min(lo.X, p.X), math.Min(lo.Y, p.Y)
+        hi.X, hi.Y = math.Max(hi.X, p.X), math.Max(hi.Y, p.Y)
+    }
+    for _, f := range result.Face2D {
+        for _, p := range f.Vertices {
+            grow(p)
+        }
+    }
+    for _, dw := range result.DoubleWalls {
+        for _, p := range dw.Vertices {
+            grow(p)
+        }
+    }
+    for _, t := range result.Tabs {
+        for _, p := range t.Vertices {
+            grow(p)
+        }
+    }
+    for _, o := range overlays {
+        if o == nil {
+            continue
+        }
+        omin, omax := o.Bounds()
+        if omin.X <= omax.X {
+            grow(omin)
+            grow(omax)
+        }
+    }
+    return lo, hi, lo.X <= hi.X
+}
+
+// sheetPath is a run of connected sheet lines.
+type sheetPath struct {
+    points []Point2
+    closed bool
+}
+
+// chainSheetLines joins segments that share end points into paths (open
+// chains first, starting from their loose ends, then closed loops), so
+// plotters and laser cutters don't jump between tiny segments. Endpoints are
+// matched within eps.
+func chainSheetLines(lines []sheetLine, eps float64) []sheetPath {
+    if eps <= 0 {
+        eps = 1e-9
+    }
+    key := func(p Point2) [2]int64 {
+        return [2]int64{int64(math.Round(p.X / eps)), int64(math.Round(p.Y / eps))}
+    }
+    at := make(map[[2]int64][]int)
+    for i, l := range lines {
+        at[key(l.a)] = append(at[key(l.a)], i)
+        at[key(l.b)] = append(at[key(l.b)], i)
+    }
+    used := make([]bool, len(lines))
+
+    walk := func(start int, from Point2) sheetPath {
+        path := sheetPath{points: []Point2{from}}
+        cur, p := start, from
+        for cur >= 0 {
+            used[cur] = true
+            l := lines[cur]
+            if key(l.a) == key(p) {
+                p = l.b
+            } else {
+                p = l.a
+            }
+            path.points = append(path.points, p)
+            cur = -1
+            for _, j := range at[key(p)] {
+                if !used[j] {
+                    cur = j
+                    break
+                }
+            }
+        }
+        if n := len(path.points); n > 2 && key(path.points[0]) == key(path.points[n-1]) {
+            path.points = path.points[:n-1]
+            path.closed = true
+        }
+        return path
+    }
+
+    var paths []sheetPath
+    // loose ends first so open chains aren't broken in the middle
+    for i, l := range lines {
+        if used[i] {
+            continue
+        }
+        for _, p := range []Point2{l.a, l.b} {
+            if len(at[key(p)])%2 == 1 && !used[i] {
+                paths = append(paths, walk(i, p))
+            }
+        }
+    }
+    for i, l := range lines {
+        if !used[i] {
+            paths = append(paths, walk(i, l.a))
+        }
+    }
+    return paths
+}
@@ -0,0 +1,54 @@
+package unfolder
+
+// -----------------------------
+//   Which side of the net faces up
+// -----------------------------
+
+// NetSide says which surface of the model the net shows: the side facing
+// the viewer, and so the side that gets printed.
+type NetSide int
+
+const (
+    // SideExterior shows the outside of the model: print on the outside.
+    // Folds are drawn as seen from the outside.
+    SideExterior NetSide = iota
+    // SideInterior shows the inside, for printing on the inside (hidden
+    // guide lines, liners, models glued from the inside). The net comes out
+    // mirrored, as if the exterior net were turned over left to right, and
+    // mountain and valley folds swap.
+    SideInterior
+)
+
+// String returns "exterior" or "interior".
+func (s NetSide) String() string {
+    if s == SideInterior {
+        return "interior"
+    }
+    return "exterior"
+}
+
+// mirrorFaces turns the net over left to right (x -> -x) in place,
+// replacing the corner slices rather than writing through them, as they
+// may be shared with another result.
+func mirrorFaces(face2Ds []Face2D) {
+    for i, f := range face2Ds {
+        if f.Vertices == nil {
+            continue
+        }
+        pts := make([]Point2, len(f.Vertices))
+        for j, p := range f.Vertices {
+            pts[j] = Point2{X: 0 - p.X, Y: p.Y} // 0 - x keeps 0 from becoming -0
+        }
+        face2Ds[i].Vertices = pts
+        if f.Holes != nil {
+            holes := make([][]Point2, len(f.Holes))
+            for h, loop := range f.Holes {
+                holes[h] = make([]Point2, len(loop))
+                for j, p := range loop {
+                    holes[h][j] = Point2{X: 0 - p.X, Y: p.Y}
+                }
+            }
+            face2Ds[i].Holes = holes
+        }
+    }
+}
@@ -0,0 +1,406 @@
+package unfolder
+
+import (
+    "container/heap"
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Simplification (quadric edge collapse)
+// -----------------------------
+
+// Simplify reduces poly to about targetFaces triangles by collapsing edges,
+// cheapest first, where the cost of moving two vertices into one is how far
+// the new vertex is from the planes of their faces (Garland and Heckbert's
+// quadric error metric). A scan of half a million triangles comes down to
+// the few hundred faces worth folding from paper.
+//
+// Polygons are triangulated first (MergeCoplanarFaces joins flat ones up
+// again). The open edges of the mesh stay where they are (only vertices on
+// straight runs of them are taken out), and no collapse is made that would
+// pinch the surface (an edge or vertex shared by more than a fan of faces),
+// fold a face over or close up a tetrahedron, so a manifold mesh stays
+// manifold with the same shells and holes. Edges shared
+// by three or more faces are never collapsed. If that leaves nothing to
+// collapse, the result has more than targetFaces faces.
+//
+// Texture coordinates and Face.Holes are dropped (the holes are filled in);
+// the name and units are kept.
+func Simplify(poly Polyhedron, targetFaces int) (out Polyhedron, err error) {
+    defer (&progress{stage: "simplification", face: -1}).recover(&err)
+    if targetFaces < 4 {
+        return Polyhedron{}, fmt.Errorf("target of %d faces is too few, need at least 4", targetFaces)
+    }
+    s := &simplifier{pos: append([]Vector3(nil), poly.Vertices...)}
+    for f, face := range poly.Faces {
+        if len(face.Vertices) < 3 {
+            continue
+        }
+        if !faceInRange(poly, face) {
+            return Polyhedron{}, &ErrDegenerateFace{Face: f, Reason: "vertex out of range"}
+        }
+        for _, tri := range earClip(poly, face) {
+            s.tris = append(s.tris, [3]int{face.Vertices[tri[0]], face.Vertices[tri[1]], face.Vertices[tri[2]]})
+        }
+    }
+    s.init()
+    for s.faces > targetFaces && s.queue.Len() > 0 {
+        c := heap.Pop(&s.queue).(collapse)
+        if s.stale(c) {
+            continue
+        }
+        s.collapse(c)
+    }
+    return s.result(poly), nil
+}
+
+// quadric is a symmetric 4x4 matrix, upper triangle row by row: the sum of
+// squared distances to a set of planes is [p 1] Q [p 1]^T.
+type quadric [10]float64
+
+func planeQuadric(n Vector3, d, w float64) quadric {
+    return quadric{
+        w * n.X * n.X, w * n.X * n.Y, w * n.X * n.Z, w * n.X * d,
+        w * n.Y * n.Y, w * n.Y * n.Z, w * n.Y * d,
+        w * n.Z * n.Z, w * n.Z * d,
+        w * d * d,
+    }
+}
+
+func (q *quadric) add(o quadric) {
+    for i := range q {
+        q[i] += o[i]
+    }
+}
+
+func (q quadric) eval(p Vector3) float64 {
+    x, y, z := p.X, p.Y, p.Z
+    return q[0]*x*x + 2*q[1]*x*y + 2*q[2]*x*z + 2*q[3]*x +
+        q[4]*y*y + 2*q[5]*y*z + 2*q[6]*y +
+        q[7]*z*z + 2*q[8]*z + q[9]
+}
+
+// optimum is the point where q is least, if the system is well posed.
+func (q quadric) optimum() (Vector3, bool) {
+    a, b, c := q[0], q[1], q[2]
+    e, f, i := q[4], q[5], q[7]
+    det := a*(e*i-f*f) - b*(b*i-f*c) + c*(b*f-e*c)
+    if math.Abs(det) < 1e-12*math.Abs(a*e*i) || det == 0 {
+        return Vector3{}, false
+    }
+    // solve A p = -(q3, q6, q8) by Cramer's rule
+    r := Vector3{-q[3], -q[6], -q[8]}
+    x := (r.X*(e*i-f*f) - b*(r.Y*i-f*r.Z) + c*(r.Y*f-e*r.Z)) / det
+    y := (a*(r.Y*i-f*r.Z) - r.X*(b*i-f*c) + c*(b*r.Z-r.Y*c)) / det
+    z := (a*(e*r.Z-r.Y*f) - b*(b*r.Z-r.Y*c) + r.X*(b*f-e*c)) / det
+    return Vector3{x, y, z}, true
+}
+
+// collapse is a candidate edge collapse: b into a, moved to pos.
+type collapse struct {
+    cost   float64
+    a, b   int
+    pos    Vector3
+    stampA int
+    stampB int
+}
+
+type collapseHeap []collapse
+
+func (h collapseHeap) Len() int            { return len(h) }
+func (h collapseHeap) Less(i, j int) bool  { return h[i].cost < h[j].cost }
+func (h collapseHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *collapseHeap) Push(x interface{}) { *h = append(*h, x.(collapse)) }
+func (h *collapseHeap) Pop() interface{} {
+    old := *h
+    x := old[len(old)-1]
+    *h = old[:len(old)-1]
+    return x
+}
+
+type simplifier struct {
+    pos    []Vector3
+    tris   [][3]int
+    dead   []bool  // per triangle
+    gone   []bool  // per vertex, merged into another
+    vtris  [][]int // triangles round each vertex, dead ones included
+    q      []quadric
+    stamp  []int // bumped whenever a vertex moves, to spot stale candidates
+    locked []bool
+    faces  int
+    queue  collapseHeap
+}
+
+func (s *simplifier) init() {
+    nv := len(s.pos)
+    s.dead = make([]bool, len(s.tris))
+    s.gone = make([]bool, nv)
+    s.vtris = make([][]int, nv)
+    s.q = make([]quadric, nv)
+    s.stamp = make([]int, nv)
+    s.locked = make([]bool, nv)
+    s.faces = len(s.tris)
+
+    edges := make(map[[2]int][]int)
+    for t, tri := range s.tris {
+        n := cross(sub(s.pos[tri[1]], s.pos[tri[0]]), sub(s.pos[tri[2]], s.pos[tri[0]]))
+        area := length(n) / 2
+        if area > 0 {
+            n = normalize(n)
+            pq := planeQuadric(n, -dot(n, s.pos[tri[0]]), area)
+            for _, v := range tri {
+                s.q[v].add(pq)
+            }
+        }
+        for k, v := range tri {
+            s.vtris[v] = append(s.vtris[v], t)
+            e := sortPair(v, tri[(k+1)%3])
+            edges[e] = append(edges[e], t)
+        }
+    }
+    // edges of three or more faces hold their vertices still; open edges
+    // are kept by push
+    for e, ts := range edges {
+        if len(ts) > 2 {
+            s.locked[e[0]], s.locked[e[1]] = true, true
+        }
+    }
+    for e := range edges {
+        s.push(e[0], e[1])
+    }
+}
+
+// push queues collapsing the edge a-b, at its best position. Open edges
+// stay where they are: a vertex on one only takes in its inside neighbours,
+// at its own place, and only goes itself into the next vertex along the
+// open edge if it's on a straight run of it.
+func (s *simplifier) push(a, b int) {
+    if s.locked[a] || s.locked[b] {
+        return
+    }
+    q := s.q[a]
+    q.add(s.q[b])
+    ra, rb := s.rim(a), s.rim(b)
+    switch {
+    case ra == nil && rb == nil:
+    case rb == nil:
+        s.pushAt(a, b, q, s.pos[a])
+        return
+    case ra == nil:
+        s.pushAt(b, a, q, s.pos[b])
+        return
+    default:
+        if s.straight(b, a, rb) {
+            s.pushAt(a, b, q, s.pos[a])
+        } else if s.straight(a, b, ra) {
+            s.pushAt(b, a, q, s.pos[b])
+        }
+        return
+    }
+    mid := scale(add(s.pos[a], s.pos[b]), 0.5)
+    best, cost := mid, q.eval(mid)
+    for _, p := range []Vector3{s.pos[a], s.pos[b]} {
+        if c := q.eval(p); c < cost {
+            best, cost = p, c
+        }
+    }
+    if p, ok := q.optimum(); ok {
+        // the optimum can run off far away if the faces are nearly
+        // parallel; only take it near the edge
+        if c := q.eval(p); c < cost && length(sub(p, mid)) <= length(sub(s.pos[a], s.pos[b])) {
+            best, cost = p, c
+        }
+    }
+    heap.Push(&s.queue, collapse{cost: cost, a: a, b: b, pos: best, stampA: s.stamp[a], stampB: s.stamp[b]})
+}
+
+// pushAt queues collapsing b into a at pos, with a's and b's quadrics q.
+func (s *simplifier) pushAt(a, b int, q quadric, pos Vector3) {
+    heap.Push(&s.queue, collapse{cost: q.eval(pos), a: a, b: b, pos: pos, stampA: s.stamp[a], stampB: s.stamp[b]})
+}
+
+// rim returns the neighbours v shares an open edge with, nil if it's inside
+// the surface.
+func (s *simplifier) rim(v int) []int {
+    count := make(map[int]int)
+    for _, t := range s.ring(v) {
+        for _, u := range s.tris[t] {
+            if u != v {
+                count[u]++
+            }
+        }
+    }
+    var out []int
+    for u, n := range count {
+        if n == 1 {
+            out = append(out, u)
+        }
+    }
+    return out
+}
+
+// straight reports whether rim vertex b (with open-edge neighbours rb) can
+// go into its open-edge neighbour a without moving the open edge: b lies on
+// the line from a to its other neighbour there.
+func (s *simplifier) straight(b, a int, rb []int) bool {
+    if len(rb) != 2 || (rb[0] != a && rb[1] != a) {
+        return false
+    }
+    c := rb[0]
+    if c == a {
+        c = rb[1]
+    }
+    ac, ab := sub(s.pos[c], s.pos[a]), sub(s.pos[b], s.pos[a])
+    return length(cross(ac, ab)) <= 1e-9*dot(ac, ac) && dot(ab, ac) > 0 && dot(ab, ab) < dot(ac, ac)
+}
+
+func (s *simplifier) stale(c collapse) bool {
+    return s.gone[c.a] || s.gone[c.b] || s.stamp[c.a] != c.stampA || s.stamp[c.b] != c.stampB
+}
+
+// ring lists the live triangles round v.
+func (s *simplifier) ring(v int) []int {
+    live := s.vtris[v][:0]
+    for _, t := range s.vtris[v] {
+        if !s.dead[t] {
+            live = append(live, t)
+        }
+    }
+    s.vtris[v] = live
+    return live
+}
+
+// collapse merges c.b into c.a at c.pos, if that keeps the mesh sound.
+func (s *simplifier) collapse(c collapse) {
+    a, b := c.a, c.b
+    ta, tb := s.ring(a), s.ring(b)
+
+    // the edge's own faces, and the vertices opposite it
+    var shared []int
+    opposite := make(map[int]bool)
+    for _, t := range ta {
+        if s.hasVertex(t, b) {
+            shared = append(shared, t)
+            for _, v := range s.tris[t] {
+                if v != a && v != b {
+                    opposite[v] = true
+                }
+            }
+        }
+    }
+    if len(shared) == 0 || len(shared) > 2 {
+        return
+    }
+
+    // link condition: a and b may only have the opposite vertices as
+    // common neighbours, else the collapse pinches the surface
+    na, nb := s.neighbours(a, ta), s.neighbours(b, tb)
+    union := 0
+    for v := range na {
+        if v != b {
+            union++
+        }
+        if nb[v] && !opposite[v] {
+            return
+        }
+    }
+    for v := range nb {
+        if v != a && !na[v] {
+            union++
+        }
+    }
+    if union <= 2 {
+        return // a tetrahedron (or less) would fall flat
+    }
+    if len(shared) == 2 && s.rim(a) != nil && s.rim(b) != nil {
+        return // an inside edge between two holes' edges: they'd touch
+    }
+
+    // no face may turn over or collapse
+    for _, ring := range [2][]int{ta, tb} {
+        for _, t := range ring {
+            if s.hasVertex(t, a) && s.hasVertex(t, b) {
+                continue
+            }
+            tri := s.tris[t]
+            before := cross(sub(s.pos[tri[1]], s.pos[tri[0]]), sub(s.pos[tri[2]], s.pos[tri[0]]))
+            var p [3]Vector3
+            for k, v := range tri {
+                p[k] = s.pos[v]
+                if v == a || v == b {
+                    p[k] = c.pos
+                }
+            }
+            after := cross(sub(p[1], p[0]), sub(p[2], p[0]))
+            if dot(before, after) <= 0.1*length(before)*length(after) || length(after) == 0 {
+                return
+            }
+        }
+    }
+
+    for _, t := range shared {
+        s.dead[t] = true
+        s.faces--
+    }
+    for _, t := range tb {
+        if s.dead[t] {
+            continue
+        }
+        for k, v := range s.tris[t] {
+            if v == b {
+                s.tris[t][k] = a
+            }
+        }
+        s.vtris[a] = append(s.vtris[a], t)
+    }
+    s.gone[b] = true
+    s.vtris[b] = nil
+    s.pos[a] = c.pos
+    s.q[a].add(s.q[b])
+    s.stamp[a]++
+    for v := range s.neighbours(a, s.ring(a)) {
+        s.push(a, v)
+    }
+}
+
+func (s *simplifier) hasVertex(t, v int) bool {
+    tri := s.tris[t]
+    return tri[0] == v || tri[1] == v || tri[2] == v
+}
+
+func (s *simplifier) neighbours(v int, ring []int) map[int]bool {
+    out := make(map[int]bool)
+    for _, t := range ring {
+        for _, u := range s.tris[t] {
+            if u != v {
+                out[u] = true
+            }
+        }
+    }
+    return out
+}
+
+// result packs the live triangles and the vertices they use.
+func (s *simplifier) result(poly Polyhedron) Polyhedron {
+    out := Polyhedron{Name: poly.Name, Units: poly.Units}
+    remap := make([]int, len(s.pos))
+    for i := range remap {
+        remap[i] = -1
+    }
+    for t, tri := range s.tris {
+        if s.dead[t] {
+            continue
+        }
+        f := Face{Vertices: make([]int, 3)}
+        for k, v := range tri {
+            if remap[v] < 0 {
+                remap[v] = len(out.Vertices)
+                out.Vertices = append(out.Vertices, s.pos[v])
+            }
+            f.Vertices[k] = remap[v]
+        }
+        out.Faces = append(out.Faces, f)
+    }
+    return out
+}
@@ -0,0 +1,145 @@
+package unfolder
+
+import (
+    "errors"
+    "math"
+    "testing"
+)
+
+// testSphere is a UV sphere of radius 1 with rings bands of segs triangles
+// around, wound CCW from outside.
+func testSphere(rings, segs int) Polyhedron {
+    poly := Polyhedron{Vertices: []Vector3{{0, 0, 1}}}
+    for r := 1; r < rings; r++ {
+        theta := math.Pi * float64(r) / float64(rings)
+        for s := 0; s < segs; s++ {
+            phi := 2 * math.Pi * float64(s) / float64(segs)
+            poly.Vertices = append(poly.Vertices, Vector3{math.Sin(theta) * math.Cos(phi), math.Sin(theta) * math.Sin(phi), math.Cos(theta)})
+        }
+    }
+    poly.Vertices = append(poly.Vertices, Vector3{0, 0, -1})
+    south := len(poly.Vertices) - 1
+    at := func(r, s int) int { return 1 + (r-1)*segs + s%segs }
+    tri := func(a, b, c int) { poly.Faces = append(poly.Faces, Face{Vertices: []int{a, b, c}}) }
+    for s := 0; s < segs; s++ {
+        tri(0, at(1, s), at(1, s+1))
+        for r := 1; r+1 < rings; r++ {
+            tri(at(r, s), at(r+1, s), at(r+1, s+1))
+            tri(at(r, s), at(r+1, s+1), at(r, s+1))
+        }
+        tri(south, at(rings-1, s+1), at(rings-1, s))
+    }
+    return poly
+}
+
+// testGrid is the unit square split into n by n pairs of triangles, an open
+// mesh.
+func testGrid(n int) Polyhedron {
+    var poly Polyhedron
+    for j := 0; j <= n; j++ {
+        for i := 0; i <= n; i++ {
+            // a gentle bump, so collapses aren't all free
+            x, y := float64(i)/float64(n), float64(j)/float64(n)
+            poly.Vertices = append(poly.Vertices, Vector3{x, y, 0.1 * math.Sin(math.Pi*x) * math.Sin(math.Pi*y)})
+        }
+    }
+    at := func(i, j int) int { return j*(n+1) + i }
+    for j := 0; j < n; j++ {
+        for i := 0; i < n; i++ {
+            poly.Faces = append(poly.Faces,
+                Face{Vertices: []int{at(i, j), at(i+1, j), at(i+1, j+1)}},
+                Face{Vertices: []int{at(i, j), at(i+1, j+1), at(i, j+1)}})
+        }
+    }
+    return poly
+}
+
+func TestSimplify(t *testing.T) {
+    tests := []struct {
+        name   string
+        poly   Polyhedron
+        target int
+        closed bool
+        atMost bool // the target can be reached
+    }{
+        {"sphere to 200", testSphere(16, 24), 200, true, true},
+        {"sphere to 40", testSphere(16, 24), 40, true, true},
+        {"sphere to 4", testSphere(8, 12), 4, true, false},
+        {"cube", testCube(), 4, true, false},
+        {"already small enough", testSphere(4, 6), 1000, true, true},
+        {"open grid", testGrid(12), 50, false, true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            tt.poly.Name, tt.poly.Units = "mesh", "mm"
+            out, err := Simplify(tt.poly, tt.target)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if tt.atMost && len(out.Faces) > tt.target {
+                t.Errorf("%d faces, want at most %d", len(out.Faces), tt.target)
+            }
+            if len(out.Faces) < 4 && tt.closed {
+                t.Errorf("only %d faces left of a closed mesh", len(out.Faces))
+            }
+            if out.Name != "mesh" || out.Units != "mm" {
+                t.Errorf("name %q, units %q", out.Name, out.Units)
+            }
+            for f, face := range out.Faces {
+                if len(face.Vertices) != 3 {
+                    t.Fatalf("face %d has %d vertices", f, len(face.Vertices))
+                }
+            }
+
+            r, err := ValidateMesh(out)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(r.NonManifoldEdges) > 0 || len(r.WindingConflicts) > 0 || len(r.DuplicateFaces) > 0 {
+                t.Errorf("not a clean manifold: %+v", r.Issues)
+            }
+            if tt.closed {
+                used := r.Vertices - len(r.UnreferencedVertices)
+                if len(r.BoundaryEdges) > 0 || used-r.Edges+r.Faces != 2 {
+                    t.Errorf("%d boundary edges, Euler characteristic %d; want a closed sphere", len(r.BoundaryEdges), used-r.Edges+r.Faces)
+                }
+            }
+        })
+    }
+}
+
+func TestSimplifyKeepsOpenEdges(t *testing.T) {
+    poly := testGrid(12)
+    out, err := Simplify(poly, 30)
+    if err != nil {
+        t.Fatal(err)
+    }
+    onRim := func(p Vector3) bool {
+        return p.X == 0 || p.X == 1 || p.Y == 0 || p.Y == 1
+    }
+    r, err := ValidateMesh(out)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(r.BoundaryEdges) == 0 {
+        t.Fatal("open grid came out closed")
+    }
+    for _, e := range r.BoundaryEdges {
+        a, b := out.Vertices[e[0]], out.Vertices[e[1]]
+        if !onRim(a) || !onRim(b) || (a.X != b.X && a.Y != b.Y) {
+            t.Errorf("boundary edge %v to %v is off the rim", a, b)
+        }
+    }
+}
+
+func TestSimplifyErrors(t *testing.T) {
+    if _, err := Simplify(testCube(), 3); err == nil {
+        t.Error("target of 3 faces: no error")
+    }
+    poly := testSphere(4, 6)
+    poly.Faces[5].Vertices[2] = 999
+    var degenerate *ErrDegenerateFace
+    if _, err := Simplify(poly, 10); !errors.As(err, &degenerate) || degenerate.Face != 5 {
+        t.Errorf("vertex out of range: err = %v", err)
+    }
+}
@@ -0,0 +1,127 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Stacked slices (contours)
+// -----------------------------
+
+// SliceLayer is one horizontal cut through a mesh, flattened into the slicing
+// plane. Stacking the layers (e.g. laser-cut sheets of thickness Interval)
+// rebuilds the model as a topographic/contour model.
+type SliceLayer struct {
+    Index   int
+    Height  float64    // position along the slicing axis
+    Label   string     // e.g. "L03 h=7.5", meant to be engraved on the sheet
+    LabelAt Point2     // a spot for the label, near the middle of the largest outline
+    Loops   [][]Point2 // closed outlines (counter-clockwise seen from above)
+    Open    [][]Point2 // open contour lines, only produced by open surfaces like terrain
+}
+
+// StackedSlices slices poly with planes perpendicular to up, every interval
+// units, starting interval/2 above the lowest point so the first layer isn't
+// empty. 2D coordinates use a fixed basis of the slicing plane; for up = +Z
+// that's plain (X, Y), so every layer lines up with the others.
+func StackedSlices(poly Polyhedron, up Vector3, interval float64) ([]SliceLayer, error) {
+    if interval <= 0 {
+        return nil, errors.New("slice interval must be positive")
+    }
+    if len(poly.Vertices) == 0 {
+        return nil, errors.New("polyhedron has no vertices")
+    }
+    if length(up) == 0 {
+        return nil, errors.New("up vector is zero")
+    }
+    up = normalize(up)
+
+    lo, hi := math.Inf(1), math.Inf(-1)
+    for _, p := range poly.Vertices {
+        h := dot(p, up)
+        lo = math.Min(lo, h)
+        hi = math.Max(hi, h)
+    }
+
+    // only the faces near each plane need clipping
+    bvh, err := NewBVH(poly, 0)
+    if err != nil {
+        return nil, err
+    }
+    var layers []SliceLayer
+    for h := lo + interval/2; h < hi; h += interval {
+        pl := Plane{Point: scale(up, h), Normal: up}
+        near := Polyhedron{Vertices: poly.Vertices}
+        for _, f := range bvh.FacesNearPlane(pl, sectionEps) {
+            near.Faces = append(near.Faces, poly.Faces[f])
+        }
+        sec, err := SlicePlane(near, pl)
+        if err != nil {
+            return nil, fmt.Errorf("slice at %g: %v", h, err)
+        }
+        idx := len(layers)
+        layer := SliceLayer{
+            Index:  idx,
+            Height: h,
+            Label:  fmt.Sprintf("L%02d h=%g", idx, h),
+        }
+        layer.Loops, layer.Open = sec.Flatten()
+        layer.LabelAt = labelPoint(layer.Loops)
+        layers = append(layers, layer)
+    }
+    return layers, nil
+}
+
+// planeBasis returns two unit vectors spanning the plane perpendicular to the
+// unit vector n, such that (u, v, n) is right handed. For n = +Z it returns X, Y.
+func planeBasis(n Vector3) (u, v Vector3) {
+    ref := Vector3{X: 1}
+    if math.Abs(n.X) > 0.9 {
+        ref = Vector3{Y: 1}
+    }
+    // u = ref projected into the plane
+    u = normalize(sub(ref, scale(n, dot(ref, n))))
+    v = cross(n, u)
+    return u, v
+}
+
+// projectLoop flattens 3D points onto the (u, v) plane basis.
+func projectLoop(pts []Vector3, u, v Vector3) []Point2 {
+    out := make([]Point2, len(pts))
+    for i, p := range pts {
+        out[i] = Point2{X: dot(p, u), Y: dot(p, v)}
+    }
+    return out
+}
+
+// labelPoint picks the vertex average of the largest loop as label anchor.
+func labelPoint(loops [][]Point2) Point2 {
+    best, bestArea := -1, -1.0
+    for i, l := range loops {
+        if a := math.Abs(polygonArea(l)); a > bestArea {
+            best, bestArea = i, a
+        }
+    }
+    if best < 0 || len(loops[best]) == 0 {
+        return Point2{}
+    }
+    var c Point2
+    for _, p := range loops[best] {
+        c.X += p.X
+        c.Y += p.Y
+    }
+    n := float64(len(loops[best]))
+    return Point2{X: c.X / n, Y: c.Y / n}
+}
+
+// polygonArea returns the signed (shoelace) area, positive for CCW loops.
+func polygonArea(pts []Point2) float64 {
+    var a float64
+    for i := range pts {
+        j := (i + 1) % len(pts)
+        a += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
+    }
+    return a / 2
+}
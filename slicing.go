@@ -0,0 +1,215 @@
+package unfolder
+
+import (
+    "errors"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Plane / mesh intersection
+// -----------------------------
+
+// CrossSection is where a plane cuts a mesh.
+type CrossSection struct {
+    Plane Plane
+    // Loops are the closed outlines, counter-clockwise seen from the side
+    // the normal points to (outer outlines of a closed mesh; holes come out
+    // clockwise), like the cap of the part below the plane.
+    Loops [][]Vector3
+    // Open are outline pieces that end at a mesh boundary; only open
+    // surfaces (terrain, a single wall) have them.
+    Open [][]Vector3
+}
+
+// Flatten returns the outlines in 2D, in a fixed basis of the plane: for a
+// +Z normal that's plain (X, Y), so sections of parallel planes line up.
+func (s CrossSection) Flatten() (loops, open [][]Point2) {
+    u, v := planeBasis(normalize(s.Plane.Normal))
+    for _, l := range s.Loops {
+        loops = append(loops, projectLoop(l, u, v))
+    }
+    for _, l := range s.Open {
+        open = append(open, projectLoop(l, u, v))
+    }
+    return loops, open
+}
+
+// SlicePlane intersects poly with pl and links the pieces into polylines.
+// Every face is cut on its own, concave ones included, and the pieces meet
+// exactly where faces share an edge or vertex. Vertices within a hair of the
+// plane count as just above it, so vertices, edges and faces lying in the
+// plane don't produce doubled or dangling pieces; outlines that touch at a
+// vertex still come out as proper loops.
+func SlicePlane(poly Polyhedron, pl Plane) (CrossSection, error) {
+    sec := CrossSection{Plane: pl}
+    if length(pl.Normal) == 0 {
+        return sec, errors.New("plane normal is zero")
+    }
+    dist := planeDistances(poly, pl)
+    segs := planeSegments(poly, pl, dist)
+
+    // number the points in the order they turn up, for chaining
+    ids := make(map[sliceKey]int)
+    var keys []sliceKey
+    id := func(k sliceKey) int {
+        if i, ok := ids[k]; ok {
+            return i
+        }
+        ids[k] = len(keys)
+        keys = append(keys, k)
+        return len(keys) - 1
+    }
+    edges := make([][2]int, len(segs))
+    for i, s := range segs {
+        edges[i] = [2]int{id(s[0]), id(s[1])}
+    }
+    loops, open := chainEdges(edges)
+    toPoints := func(idx []int) []Vector3 {
+        pts := make([]Vector3, len(idx))
+        for i, k := range idx {
+            pts[i] = slicePoint(poly, dist, keys[k])
+        }
+        return pts
+    }
+    for _, l := range loops {
+        sec.Loops = append(sec.Loops, toPoints(l))
+    }
+    for _, l := range open {
+        sec.Open = append(sec.Open, toPoints(l))
+    }
+    return sec, nil
+}
+
+// sliceKey names a point where a plane crosses the mesh: {v, v} for mesh
+// vertex v lying on the plane, {a, b} with a < b for a point inside edge ab.
+// Both faces of an edge name (and place) the point the same way.
+type sliceKey [2]int
+
+// planeDistances returns the signed distance of every vertex from pl, with
+// the ones within sectionEps snapped to 0.
+func planeDistances(poly Polyhedron, pl Plane) []float64 {
+    dist := make([]float64, len(poly.Vertices))
+    for i, v := range poly.Vertices {
+        if d := pl.SignedDistance(v); math.Abs(d) >= sectionEps {
+            dist[i] = d
+        }
+    }
+    return dist
+}
+
+// planeSegments cuts every face with pl and returns the pieces, directed so
+// that they run counter-clockwise around the normal for the outer outline of
+// a closed, outward-wound mesh. Vertices at distance 0 count as above. The
+// crossings of each face are sorted along the cut line and paired up, so
+// concave faces that the plane crosses several times come out right.
+func planeSegments(poly Polyhedron, pl Plane, dist []float64) [][2]sliceKey {
+    type crossing struct {
+        key sliceKey
+        s   float64
+    }
+    var segs [][2]sliceKey
+    var xs []crossing
+    for _, face := range poly.Faces {
+        dir := cross(pl.Normal, faceNormal(poly, face))
+        if length(dir) == 0 {
+            continue // parallel to the plane (or degenerate)
+        }
+        xs = xs[:0]
+        n := len(face.Vertices)
+        for i := 0; i < n; i++ {
+            a, b := face.Vertices[i], face.Vertices[(i+1)%n]
+            if (dist[a] < 0) == (dist[b] < 0) {
+                continue
+            }
+            var k sliceKey
+            switch {
+            case dist[a] == 0:
+                k = sliceKey{a, a}
+            case dist[b] == 0:
+                k = sliceKey{b, b}
+            default:
+                k = sliceKey(sortPair(a, b))
+            }
+            xs = append(xs, crossing{k, dot(slicePoint(poly, dist, k), dir)})
+        }
+        sort.Slice(xs, func(i, j int) bool {
+            if xs[i].s != xs[j].s {
+                return xs[i].s < xs[j].s
+            }
+            if xs[i].key[0] != xs[j].key[0] {
+                return xs[i].key[0] < xs[j].key[0]
+            }
+            return xs[i].key[1] < xs[j].key[1]
+        })
+        for i := 0; i+1 < len(xs); i += 2 {
+            if xs[i].key != xs[i+1].key {
+                segs = append(segs, [2]sliceKey{xs[i].key, xs[i+1].key})
+            }
+        }
+    }
+    return segs
+}
+
+// slicePoint returns the position of k.
+func slicePoint(poly Polyhedron, dist []float64, k sliceKey) Vector3 {
+    a, b := k[0], k[1]
+    if a == b {
+        return poly.Vertices[a]
+    }
+    t := dist[a] / (dist[a] - dist[b])
+    pa, pb := poly.Vertices[a], poly.Vertices[b]
+    return add(pa, scale(sub(pb, pa), t))
+}
+
+// chainEdges links directed edges into closed loops and, where the edges
+// don't balance out (open surfaces), open chains from a point with more edges
+// leaving than arriving. A point several loops pass through is fine. Walks
+// start in index order so the output is the same every run.
+func chainEdges(edges [][2]int) (loops, open [][]int) {
+    out := make(map[int][]int)
+    surplus := make(map[int]int)
+    for i, e := range edges {
+        out[e[0]] = append(out[e[0]], i)
+        surplus[e[0]]++
+        surplus[e[1]]--
+    }
+    starts := make([]int, 0, len(out))
+    for u := range out {
+        starts = append(starts, u)
+    }
+    sort.Ints(starts)
+
+    used := make([]bool, len(edges))
+    nextEdge := func(u int) int {
+        for _, e := range out[u] {
+            if !used[e] {
+                return e
+            }
+        }
+        return -1
+    }
+    walk := func(e int) []int {
+        chain := []int{edges[e][0]}
+        for ; e >= 0; e = nextEdge(edges[e][1]) {
+            used[e] = true
+            chain = append(chain, edges[e][1])
+        }
+        return chain
+    }
+    // open chains first, so the loop pass below only sees cycles
+    for _, u := range starts {
+        for ; surplus[u] > 0; surplus[u]-- {
+            if e := nextEdge(u); e >= 0 {
+                open = append(open, walk(e))
+            }
+        }
+    }
+    for _, u := range starts {
+        for e := nextEdge(u); e >= 0; e = nextEdge(u) {
+            chain := walk(e)
+            loops = append(loops, chain[:len(chain)-1]) // last is u again
+        }
+    }
+    return loops, open
+}
@@ -0,0 +1,63 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Smooth regions (curved surfaces)
+// -----------------------------
+
+// SmoothRegions finds the curved surfaces of a tessellated model: runs of
+// faces joined by edges that bend less than maxBend degrees from flat, like
+// the many thin faces of a cylinder or cone exported from CAD. It returns
+// the region of every face, numbered from 0 in the order of each region's
+// lowest face; a face with no gentle edges is a region on its own.
+//
+// Such a surface is developable and is rolled into shape rather than folded
+// edge by edge, so it's best drawn as one outline: unfold with DihedralMST,
+// which keeps every region in one piece as far as the surface allows (a
+// closed tube still needs one seam), and pass the regions as FaceGroups with
+// MinFoldAngle = maxBend to the exporters, which then draw only the real
+// creases.
+func SmoothRegions(poly Polyhedron, maxBend float64) ([]int, error) {
+    if maxBend < 0 || maxBend >= 180 {
+        return nil, fmt.Errorf("bend limit %v outside [0, 180)", maxBend)
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+    limit := maxBend * math.Pi / 180
+
+    region := make([]int, len(poly.Faces))
+    for i := range region {
+        region[i] = -1
+    }
+    n := 0
+    for seed := range poly.Faces {
+        if region[seed] >= 0 {
+            continue
+        }
+        region[seed] = n
+        queue := []int{seed}
+        for len(queue) > 0 {
+            f := queue[0]
+            queue = queue[1:]
+            for _, nbr := range adj.Neighbors[f] {
+                g := nbr.FaceIndex
+                if region[g] >= 0 {
+                    continue
+                }
+                e := NetEdge{FaceA: f, FaceB: g, Vertices: nbr.SharedEdge}
+                if math.Abs(math.Pi-DihedralAngle(poly, e)) < limit {
+                    region[g] = n
+                    queue = append(queue, g)
+                }
+            }
+        }
+        n++
+    }
+    return region, nil
+}
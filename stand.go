@@ -0,0 +1,160 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Display stands
+// -----------------------------
+
+// StandKind selects the stand shape GenerateStand builds.
+type StandKind int
+
+const (
+    // StandWedge is a triangular prism that props the model up at an angle.
+    StandWedge StandKind = iota
+    // StandCradle is a U-shaped block the model sits in.
+    StandCradle
+)
+
+// StandOptions sizes a display stand. Zero values pick defaults relative to
+// the model's bounding box.
+type StandOptions struct {
+    Kind   StandKind
+    Height float64 // stand height; default 1/4 of the model height
+    Margin float64 // extra length/width beyond the model footprint; default 5%
+    Wall   float64 // cradle wall thickness; default 10% of the cradle width
+}
+
+// Stand is a generated stand: its mesh (already positioned under the model,
+// +Z up) and its net, ready to be added to the kit next to the model's pieces.
+type Stand struct {
+    Kind   StandKind
+    Poly   Polyhedron
+    Result *UnfoldResult
+}
+
+// GenerateStand builds a stand fitted to poly's bounding box and unfolds it.
+// The stand runs along the longer horizontal side of the model and its top
+// touches the model's lowest point.
+func GenerateStand(poly Polyhedron, opts StandOptions) (*Stand, error) {
+    if len(poly.Vertices) == 0 {
+        return nil, errors.New("polyhedron has no vertices")
+    }
+    lo, hi := boundingBox(poly.Vertices)
+    ext := sub(hi, lo)
+    if ext.X <= 0 || ext.Y <= 0 {
+        return nil, errors.New("model has no horizontal footprint")
+    }
+
+    margin := opts.Margin
+    if margin <= 0 {
+        margin = 0.05 * math.Max(ext.X, ext.Y)
+    }
+    height := opts.Height
+    if height <= 0 {
+        height = ext.Z / 4
+        if height <= 0 {
+            height = 0.25 * math.Min(ext.X, ext.Y)
+        }
+    }
+
+    // local frame: extrude along the long axis, profile across the short one
+    long, short := ext.X, ext.Y
+    alongX := ext.X >= ext.Y
+    if !alongX {
+        long, short = ext.Y, ext.X
+    }
+    length := long + 2*margin
+    width := short + 2*margin
+
+    var profile []Point2 // (across, up), counter-clockwise
+    switch opts.Kind {
+    case StandWedge:
+        profile = []Point2{{-width / 2, 0}, {width / 2, 0}, {-width / 2, height}}
+    case StandCradle:
+        wall := opts.Wall
+        if wall <= 0 {
+            wall = 0.1 * width
+        }
+        if 2*wall >= width {
+            return nil, fmt.Errorf("cradle wall %g too thick for width %g", wall, width)
+        }
+        in := width/2 - wall
+        notch := height / 2
+        profile = []Point2{
+            {-width / 2, 0}, {width / 2, 0}, {width / 2, height},
+            {in, height}, {in, height - notch}, {-in, height - notch},
+            {-in, height}, {-width / 2, height},
+        }
+    default:
+        return nil, fmt.Errorf("unknown stand kind %d", opts.Kind)
+    }
+
+    stand := extrudeProfile(profile, length)
+    stand.Name = poly.Name + "-stand"
+    stand.Units = poly.Units
+
+    // move into place: centred under the model, top at the model's lowest point
+    center := scale(add(lo, hi), 0.5)
+    for i, v := range stand.Vertices {
+        // local x = along, y = across, z = up
+        p := Vector3{X: v.X - length/2, Y: v.Y, Z: v.Z - height}
+        if !alongX {
+            // rotate 90 degrees about Z so "along" becomes +Y
+            p = Vector3{X: -p.Y, Y: p.X, Z: p.Z}
+        }
+        stand.Vertices[i] = Vector3{X: center.X + p.X, Y: center.Y + p.Y, Z: lo.Z + p.Z}
+    }
+
+    res, err := UnfoldMesh(stand, 0)
+    if err != nil {
+        return nil, fmt.Errorf("failed to unfold stand: %v", err)
+    }
+    return &Stand{Kind: opts.Kind, Poly: stand, Result: res}, nil
+}
+
+// extrudeProfile sweeps a counter-clockwise (y, z) profile along +X from 0 to
+// length, producing a closed prism with outward (CCW) winding.
+func extrudeProfile(profile []Point2, length float64) Polyhedron {
+    n := len(profile)
+    poly := Polyhedron{Vertices: make([]Vector3, 0, 2*n)}
+    for _, p := range profile {
+        poly.Vertices = append(poly.Vertices, Vector3{X: 0, Y: p.X, Z: p.Y})
+    }
+    for _, p := range profile {
+        poly.Vertices = append(poly.Vertices, Vector3{X: length, Y: p.X, Z: p.Y})
+    }
+
+    // end caps: x = length faces +X (profile order), x = 0 faces -X (reversed)
+    front := make([]int, n)
+    back := make([]int, n)
+    for i := 0; i < n; i++ {
+        front[i] = n + i
+        back[i] = n - 1 - i
+    }
+    poly.Faces = append(poly.Faces, Face{Vertices: back}, Face{Vertices: front})
+
+    // sides
+    for i := 0; i < n; i++ {
+        j := (i + 1) % n
+        poly.Faces = append(poly.Faces, Face{Vertices: []int{i, j, n + j, n + i}})
+    }
+    return poly
+}
+
+// boundingBox returns the component-wise min and max of pts.
+func boundingBox(pts []Vector3) (lo, hi Vector3) {
+    if len(pts) == 0 {
+        return
+    }
+    lo, hi = pts[0], pts[0]
+    for _, p := range pts[1:] {
+        lo = Vector3{minf(lo.X, p.X), minf(lo.Y, p.Y), minf(lo.Z, p.Z)}
+        hi = Vector3{maxf(hi.X, p.X), maxf(hi.Y, p.Y), maxf(hi.Z, p.Z)}
+    }
+    return lo, hi
+}
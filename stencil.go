@@ -0,0 +1,214 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Stencil mode (faces as separate cutouts)
+// -----------------------------
+
+// StencilOptions controls Stencil.
+type StencilOptions struct {
+    // Faces to cut out; nil means every face. A face listed twice is still
+    // one piece.
+    Faces []int
+    // NotchDepth is how deep the registration notches go into the piece.
+    // 0 = 5% of the median edge length, negative = no notches. Notches are
+    // never deeper than a quarter of their edge.
+    NotchDepth float64
+    // Gap between pieces in the layout. 0 = 20% of the median edge length.
+    Gap float64
+}
+
+// StencilPiece is one face laid flat as its own cutout.
+type StencilPiece struct {
+    Face    int
+    Corners []Point2 // the face polygon, in layout position, same order as the face
+    Outline []Point2 // cutting outline: Corners with the notches cut in
+    Notched []int    // local edges of the face that got a notch
+}
+
+// StencilSheet is the result of Stencil: the pieces laid out in rows.
+type StencilSheet struct {
+    Pieces []StencilPiece
+}
+
+// Stencil lays every selected face out as a separate piece, for building
+// from rigid material that can't fold. Each edge shared by two selected faces
+// gets a V notch at its midpoint on both pieces, so mating edges can be lined
+// up by their notches.
+func Stencil(poly Polyhedron, opts StencilOptions) (*StencilSheet, error) {
+    faces := opts.Faces
+    if faces == nil {
+        faces = allFaces(len(poly.Faces))
+    }
+    if len(faces) == 0 {
+        return nil, errors.New("no faces selected")
+    }
+    // one piece per face, however often it was listed
+    selected := make(map[int]bool, len(faces))
+    unique := make([]int, 0, len(faces))
+    for _, f := range faces {
+        if f < 0 || f >= len(poly.Faces) {
+            return nil, fmt.Errorf("face index %d out of range", f)
+        }
+        if !selected[f] {
+            selected[f] = true
+            unique = append(unique, f)
+        }
+    }
+    faces = unique
+
+    // which edges are shared between selected faces
+    edgeFaces := make(map[[2]int]int)
+    var lengths []float64
+    for f := range selected {
+        vs := poly.Faces[f].Vertices
+        for i := range vs {
+            a, b := vs[i], vs[(i+1)%len(vs)]
+            edgeFaces[sortPair(a, b)]++
+            lengths = append(lengths, length(sub(poly.Vertices[b], poly.Vertices[a])))
+        }
+    }
+    if len(lengths) == 0 {
+        return nil, errors.New("selected faces have no edges")
+    }
+    sort.Float64s(lengths)
+    median := lengths[len(lengths)/2]
+    if opts.NotchDepth == 0 {
+        opts.NotchDepth = 0.05 * median
+    }
+    if opts.Gap <= 0 {
+        opts.Gap = 0.2 * median
+    }
+
+    // lay each face flat on its own
+    pieces := make([]StencilPiece, 0, len(faces))
+    for _, f := range faces {
+        var f2 Face2D
+        if err := placeRootFace(poly, f, &f2); err != nil {
+            return nil, fmt.Errorf("failed to flatten face %d: %w", f, err)
+        }
+        p := StencilPiece{Face: f, Corners: f2.Vertices}
+        vs := poly.Faces[f].Vertices
+        for i := range vs {
+            if opts.NotchDepth > 0 && edgeFaces[sortPair(vs[i], vs[(i+1)%len(vs)])] == 2 {
+                p.Notched = append(p.Notched, i)
+            }
+        }
+        p.Outline = notchOutline(p.Corners, p.Notched, opts.NotchDepth)
+        pieces = append(pieces, p)
+    }
+
+    layoutRows(pieces, opts.Gap)
+    return &StencilSheet{Pieces: pieces}, nil
+}
+
+// notchOutline inserts a V notch (as wide as it is deep, times two) in the
+// middle of each listed edge, pointing into the polygon.
+func notchOutline(pts []Point2, edges []int, depth float64) []Point2 {
+    notched := make(map[int]bool, len(edges))
+    for _, e := range edges {
+        notched[e] = true
+    }
+    inward := 1.0
+    if polygonArea(pts) < 0 {
+        inward = -1
+    }
+    var out []Point2
+    for i, a := range pts {
+        out = append(out, a)
+        if !notched[i] {
+            continue
+        }
+        b := pts[(i+1)%len(pts)]
+        l := math.Hypot(b.X-a.X, b.Y-a.Y)
+        d := math.Min(depth, l/4)
+        if l == 0 || d <= 0 {
+            continue
+        }
+        tx, ty := (b.X-a.X)/l, (b.Y-a.Y)/l
+        n := leftNormal(a, b)
+        m := Point2{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+        out = append(out,
+            Point2{X: m.X - tx*d, Y: m.Y - ty*d},
+            Point2{X: m.X + inward*n.X*d, Y: m.Y + inward*n.Y*d},
+            Point2{X: m.X + tx*d, Y: m.Y + ty*d})
+    }
+    return out
+}
+
+// layoutRows moves the pieces into rows (tallest first), keeping the sheet
+// roughly square. Rows grow downwards from the origin.
+func layoutRows(pieces []StencilPiece, gap float64) {
+    boxes := make([]box2, len(pieces))
+    area, widest := 0.0, 0.0
+    for i, p := range pieces {
+        boxes[i] = polyBox(p.Outline)
+        w, h := boxes[i].maxX-boxes[i].minX, boxes[i].maxY-boxes[i].minY
+        area += (w + gap) * (h + gap)
+        widest = math.Max(widest, w)
+    }
+    rowWidth := math.Max(widest, math.Sqrt(area))
+
+    order := make([]int, len(pieces))
+    for i := range order {
+        order[i] = i
+    }
+    sort.SliceStable(order, func(i, j int) bool {
+        return boxes[order[i]].maxY-boxes[order[i]].minY > boxes[order[j]].maxY-boxes[order[j]].minY
+    })
+
+    x, top, rowH := 0.0, 0.0, 0.0
+    for _, i := range order {
+        b := boxes[i]
+        w, h := b.maxX-b.minX, b.maxY-b.minY
+        if x > 0 && x+w > rowWidth {
+            x, top, rowH = 0, top-rowH-gap, 0
+        }
+        dx, dy := x-b.minX, top-h-b.minY
+        for k := range pieces[i].Corners {
+            pieces[i].Corners[k].X += dx
+            pieces[i].Corners[k].Y += dy
+        }
+        for k := range pieces[i].Outline {
+            pieces[i].Outline[k].X += dx
+            pieces[i].Outline[k].Y += dy
+        }
+        x += w + gap
+        rowH = math.Max(rowH, h)
+    }
+}
+
+// Result returns the sheet as an UnfoldResult with one placed face per piece
+// and no edges, so it can be passed to the exporters (with FaceLabels)
+// together with Overlay.
+func (s *StencilSheet) Result(poly Polyhedron) *UnfoldResult {
+    res := &UnfoldResult{
+        Face2D:       make([]Face2D, len(poly.Faces)),
+        SpanningTree: make([]int, len(poly.Faces)),
+    }
+    for i := range res.SpanningTree {
+        res.SpanningTree[i] = -1
+    }
+    for _, p := range s.Pieces {
+        res.Face2D[p.Face] = Face2D{Vertices: append([]Point2(nil), p.Corners...)}
+    }
+    attachHoles(poly, res.Face2D)
+    attachUVs(poly, res.Face2D)
+    res.VertexInstances = vertexInstances(poly, res.Face2D)
+    return res
+}
+
+// Overlay returns the notched outlines as a cut overlay.
+func (s *StencilSheet) Overlay() *Overlay {
+    o := &Overlay{Name: "stencil", Cut: true}
+    for _, p := range s.Pieces {
+        o.Polylines = append(o.Polylines, OverlayLine{Points: append([]Point2(nil), p.Outline...), Closed: true})
+    }
+    return o
+}
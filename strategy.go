@@ -0,0 +1,352 @@
+package unfolder
+
+import (
+    "container/heap"
+    "errors"
+    "fmt"
+    "math"
+    "math/rand"
+)
+
+// -----------------------------
+//   Spanning tree strategies
+// -----------------------------
+
+// SpanningStrategy picks the spanning tree of the face graph that the net is
+// folded along. It returns the parent of every face (-1 for the root and for
+// faces it can't reach) and the root it chose; rootFace is the caller's
+// request, which strategies may override.
+type SpanningStrategy interface {
+    SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) (parent []int, root int, err error)
+}
+
+// BreadthFirst is the original strategy: BFS from the root face.
+type BreadthFirst struct{}
+
+// SpanningTree implements SpanningStrategy.
+func (BreadthFirst) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    return BuildFaceSpanningTree(adj, rootFace, len(poly.Faces)), rootFace, nil
+}
+
+// SteepestEdge cuts edges that run steeply along Direction and folds the
+// ones across it: a minimum spanning tree of the face graph weighted by each
+// edge's steepness (how close it runs to Direction). That's in the spirit of
+// classic steepest-edge unfolding, which cuts the steepest upward edge at
+// every vertex of a convex polyhedron, but it isn't that rule and doesn't
+// share its good record against overlaps; in return any mesh gets a valid
+// tree.
+type SteepestEdge struct {
+    // Direction to measure steepness against. Zero means slightly tilted +Z
+    // (tilted so axis-aligned models don't produce ties).
+    Direction Vector3
+}
+
+// SpanningTree implements SpanningStrategy.
+func (s SteepestEdge) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    dir := s.Direction
+    if length(dir) == 0 {
+        dir = Vector3{0.1, 0.2, 1}
+    }
+    dir = normalize(dir)
+    weight := func(_ int, nbr FaceNeighbor) float64 {
+        e := sub(poly.Vertices[nbr.SharedEdge[1]], poly.Vertices[nbr.SharedEdge[0]])
+        if l := length(e); l > 0 {
+            return math.Abs(dot(e, dir)) / l
+        }
+        return 0
+    }
+    return minSpanningTree(adj, rootFace, len(poly.Faces), weight), rootFace, nil
+}
+
+// DihedralMST folds along the flattest edges and cuts along the sharpest
+// ones: a minimum spanning tree of the face graph weighted by how far each
+// edge bends away from flat. Cuts then tend to follow the model's creases.
+type DihedralMST struct{}
+
+// SpanningTree implements SpanningStrategy.
+func (DihedralMST) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    weight := func(f int, nbr FaceNeighbor) float64 {
+        e := NetEdge{FaceA: f, FaceB: nbr.FaceIndex, Vertices: nbr.SharedEdge}
+        return math.Abs(math.Pi - DihedralAngle(poly, e))
+    }
+    return minSpanningTree(adj, rootFace, len(poly.Faces), weight), rootFace, nil
+}
+
+// WeightFunc scores the edge between faces faceA and faceB, given by its two
+// vertex indices (smaller first), for WeightedTree: the lower the weight, the
+// more the edge is worth folding; the highest weights are cut.
+type WeightFunc func(faceA, faceB int, edge [2]int) float64
+
+// WeightedTree is a minimum spanning tree of the face graph under a caller's
+// edge weights, for biasing the cuts in ways the other strategies don't, e.g.
+// the negated edge length to cut short edges, or a weight that's high on
+// edges a texture or the finished model hides. Weight is called once per
+// face pair and edge, with faceA < faceB; NaN weights are an error.
+type WeightedTree struct {
+    Weight WeightFunc
+}
+
+// SpanningTree implements SpanningStrategy.
+func (t WeightedTree) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    if t.Weight == nil {
+        return nil, 0, errors.New("WeightedTree has no Weight function")
+    }
+    type pair struct {
+        a, b int
+        edge [2]int
+    }
+    weights := make(map[pair]float64)
+    for f, nbrs := range adj.Neighbors {
+        for _, nbr := range nbrs {
+            a, b := f, nbr.FaceIndex
+            if a > b {
+                a, b = b, a
+            }
+            k := pair{a, b, sortPair(nbr.SharedEdge[0], nbr.SharedEdge[1])}
+            if _, ok := weights[k]; ok {
+                continue
+            }
+            w := t.Weight(k.a, k.b, k.edge)
+            if math.IsNaN(w) {
+                return nil, 0, fmt.Errorf("weight of edge %d-%d (faces %d and %d) is NaN", k.edge[0], k.edge[1], a, b)
+            }
+            weights[k] = w
+        }
+    }
+    weight := func(f int, nbr FaceNeighbor) float64 {
+        a, b := f, nbr.FaceIndex
+        if a > b {
+            a, b = b, a
+        }
+        return weights[pair{a, b, sortPair(nbr.SharedEdge[0], nbr.SharedEdge[1])}]
+    }
+    return minSpanningTree(adj, rootFace, len(poly.Faces), weight), rootFace, nil
+}
+
+// MinBoundingBox tries a number of candidate trees (breadth first, dihedral
+// MST and steepest-edge along Tries directions), unfolds each and keeps the
+// one whose net has the smallest bounding box area.
+type MinBoundingBox struct {
+    // Tries is the number of steepest-edge directions to try. Default 16.
+    Tries int
+}
+
+// SpanningTree implements SpanningStrategy.
+func (m MinBoundingBox) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    tries := m.Tries
+    if tries <= 0 {
+        tries = 16
+    }
+    cands := []SpanningStrategy{BreadthFirst{}, DihedralMST{}}
+    for _, d := range sphereDirections(tries) {
+        cands = append(cands, SteepestEdge{Direction: d})
+    }
+
+    var best []int
+    bestRoot, bestArea := rootFace, math.Inf(1)
+    for _, c := range cands {
+        parent, root, err := c.SpanningTree(poly, adj, rootFace)
+        if err != nil {
+            return nil, 0, err
+        }
+        res, err := unfoldAlongTree(poly, adj, root, parent, UnfoldOptions{}, nil)
+        if err != nil {
+            continue
+        }
+        lo, hi, ok := sheetBounds(res, nil)
+        if !ok {
+            continue
+        }
+        if area := (hi.X - lo.X) * (hi.Y - lo.Y); area < bestArea {
+            best, bestRoot, bestArea = parent, root, area
+        }
+    }
+    if best == nil {
+        return nil, 0, errors.New("no candidate spanning tree could be unfolded")
+    }
+    return best, bestRoot, nil
+}
+
+// LargestFaceRoot roots the net at the face with the largest area (a stable
+// base to build the model up from) and lets Strategy pick the tree.
+// A nil Strategy means BreadthFirst.
+type LargestFaceRoot struct {
+    Strategy SpanningStrategy
+}
+
+// SpanningTree implements SpanningStrategy; rootFace is ignored.
+func (l LargestFaceRoot) SpanningTree(poly Polyhedron, adj *FaceAdjacency, _ int) ([]int, int, error) {
+    if len(poly.Faces) == 0 {
+        return nil, 0, errors.New("polyhedron has no faces")
+    }
+    root, best := 0, -1.0
+    for i, f := range poly.Faces {
+        if a := length(faceNormal(poly, f)); a > best {
+            root, best = i, a
+        }
+    }
+    s := l.Strategy
+    if s == nil {
+        s = BreadthFirst{}
+    }
+    return s.SpanningTree(poly, adj, root)
+}
+
+// RandomTree picks a uniformly random spanning tree. The same Seed always
+// gives the same tree, so random searches can be replayed.
+type RandomTree struct {
+    Seed int64
+}
+
+// SpanningTree implements SpanningStrategy.
+func (t RandomTree) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    r := rand.New(rand.NewSource(t.Seed))
+    return RandomSpanningTree(r, adj, len(poly.Faces), rootFace), rootFace, nil
+}
+
+// RandomSpanningTree draws a uniformly random spanning tree of root's
+// component with Wilson's algorithm (loop-erased random walks). Faces in
+// other components get parent -1.
+func RandomSpanningTree(r *rand.Rand, adj *FaceAdjacency, nFaces, root int) []int {
+    parent := make([]int, nFaces)
+    inTree := make([]bool, nFaces)
+    reach := make([]bool, nFaces)
+    for i := range parent {
+        parent[i] = -1
+    }
+    // only walk faces in root's component, or the walks never end
+    queue := []int{root}
+    reach[root] = true
+    for i := 0; i < len(queue); i++ {
+        for _, nbr := range adj.Neighbors[queue[i]] {
+            if !reach[nbr.FaceIndex] {
+                reach[nbr.FaceIndex] = true
+                queue = append(queue, nbr.FaceIndex)
+            }
+        }
+    }
+
+    inTree[root] = true
+    next := make([]int, nFaces)
+    for _, start := range queue {
+        // loop-erased random walk from start until it hits the tree
+        for f := start; !inTree[f]; f = next[f] {
+            nbrs := adj.Neighbors[f]
+            next[f] = nbrs[r.Intn(len(nbrs))].FaceIndex
+        }
+        for f := start; !inTree[f]; f = next[f] {
+            parent[f] = next[f]
+            inTree[f] = true
+        }
+    }
+    return parent
+}
+
+// FixedTree answers with a tree picked elsewhere (a suggestion, a saved net,
+// an editor). Parent must have one entry per face; rootFace is ignored.
+type FixedTree struct {
+    Parent []int
+    Root   int
+}
+
+// SpanningTree implements SpanningStrategy.
+func (t FixedTree) SpanningTree(poly Polyhedron, _ *FaceAdjacency, _ int) ([]int, int, error) {
+    if len(t.Parent) != len(poly.Faces) {
+        return nil, 0, fmt.Errorf("tree has %d faces, polyhedron %d", len(t.Parent), len(poly.Faces))
+    }
+    if err := checkRoot(poly, t.Root); err != nil {
+        return nil, 0, err
+    }
+    return append([]int(nil), t.Parent...), t.Root, nil
+}
+
+func checkRoot(poly Polyhedron, rootFace int) error {
+    if rootFace < 0 || rootFace >= len(poly.Faces) {
+        return fmt.Errorf("root face %d out of range", rootFace)
+    }
+    return nil
+}
+
+// minSpanningTree runs Prim's algorithm over the face graph from root. Ties
+// go to the lower face index so equal weights still give a reproducible tree.
+func minSpanningTree(adj *FaceAdjacency, root, nFaces int, weight func(face int, nbr FaceNeighbor) float64) []int {
+    parent := make([]int, nFaces)
+    for i := range parent {
+        parent[i] = -1
+    }
+    done := make([]bool, nFaces)
+    h := &mstHeap{}
+    heap.Push(h, mstItem{face: root, parent: -1})
+    for h.Len() > 0 {
+        it := heap.Pop(h).(mstItem)
+        if done[it.face] {
+            continue
+        }
+        done[it.face] = true
+        parent[it.face] = it.parent
+        for _, nbr := range adj.Neighbors[it.face] {
+            if !done[nbr.FaceIndex] {
+                heap.Push(h, mstItem{face: nbr.FaceIndex, parent: it.face, w: weight(it.face, nbr)})
+            }
+        }
+    }
+    return parent
+}
+
+type mstItem struct {
+    face, parent int
+    w            float64
+}
+
+type mstHeap []mstItem
+
+func (h mstHeap) Len() int { return len(h) }
+func (h mstHeap) Less(i, j int) bool {
+    if h[i].w != h[j].w {
+        return h[i].w < h[j].w
+    }
+    if h[i].face != h[j].face {
+        return h[i].face < h[j].face
+    }
+    return h[i].parent < h[j].parent
+}
+func (h mstHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *mstHeap) Push(x interface{}) { *h = append(*h, x.(mstItem)) }
+func (h *mstHeap) Pop() interface{} {
+    old := *h
+    it := old[len(old)-1]
+    *h = old[:len(old)-1]
+    return it
+}
+
+// sphereDirections spreads n directions evenly over the upper hemisphere
+// (steepness only depends on the axis, not its sign), Fibonacci style.
+func sphereDirections(n int) []Vector3 {
+    golden := math.Pi * (3 - math.Sqrt(5))
+    out := make([]Vector3, n)
+    for i := range out {
+        z := 1 - (float64(i)+0.5)/float64(n)
+        r := math.Sqrt(1 - z*z)
+        a := golden * float64(i)
+        out[i] = Vector3{r * math.Cos(a), r * math.Sin(a), z}
+    }
+    return out
+}
@@ -0,0 +1,222 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+)
+
+// -----------------------------
+//   Strip unfolding
+// -----------------------------
+
+// StripOptions controls UnfoldStrips.
+type StripOptions struct {
+    // MaxFaces caps the faces in a strip, e.g. to fit the length of the
+    // material. 0 = no limit.
+    MaxFaces int
+    // AllowOverlaps lets a strip run on over itself. By default a strip
+    // ends where its next face would overlap it.
+    AllowOverlaps bool
+    // Unfold is used for Anchoring, Side, DetectOverlaps, DoubleWalled,
+    // Material and MemoryLimit; the spanning tree options don't apply, a
+    // strip is its own tree.
+    Unfold UnfoldOptions
+}
+
+// FaceStrip is one strip of an UnfoldStrips net: a path of faces, each
+// folded to the next, with no branches.
+type FaceStrip struct {
+    Faces  []int // global face indices, from one end of the strip to the other
+    Result *UnfoldResult
+}
+
+// UnfoldStrips decomposes poly into strips of faces and unfolds each one on
+// its own, for materials that come in strips (paper strips, folded sheet
+// metal). Strips are grown greedily from the lowest face not yet in one,
+// both ways: across the opposite edge where a face has one (straight runs
+// of quads), else turning the other way from the last turn (the zig-zag of
+// a triangle strip), and among equals into the face with the fewest free
+// neighbours, so as not to strand faces. A strip ends when it runs out of
+// free neighbours, reaches opts.MaxFaces or, unless opts.AllowOverlaps,
+// would overlap itself.
+//
+// The strips come out in the order they were grown. As with
+// UnfoldMeshSegmented each result uses the mesh's global face and vertex
+// indices and leaves the faces of other strips unplaced, and edges between
+// strips are returned as seams (with strip indices for patches). The
+// spanning tree of a strip is rooted at its seed face, which may lie
+// anywhere along it.
+func UnfoldStrips(poly Polyhedron, opts StripOptions) (strips []FaceStrip, seams []PatchSeam, err error) {
+    at := &progress{stage: "adjacency", face: -1}
+    defer at.recover(&err)
+    nFaces := len(poly.Faces)
+    if nFaces == 0 {
+        return nil, nil, errors.New("polyhedron has no faces")
+    }
+    if opts.MaxFaces < 0 {
+        return nil, nil, fmt.Errorf("negative MaxFaces %d", opts.MaxFaces)
+    }
+    estimate := EstimateMemory(poly)
+    if opts.Unfold.MemoryLimit > 0 && estimate > opts.Unfold.MemoryLimit {
+        return nil, nil, fmt.Errorf("%w: estimated %d bytes, limit %d", ErrMemoryLimit, estimate, opts.Unfold.MemoryLimit)
+    }
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+
+    stripOf := make([]int, nFaces)
+    for i := range stripOf {
+        stripOf[i] = -1
+    }
+    at.stage = "strip growth"
+    for seed := 0; seed < nFaces; seed++ {
+        if stripOf[seed] >= 0 {
+            continue
+        }
+        at.face = seed
+        s, err := growStrip(poly, adj, seed, len(strips), stripOf, opts)
+        if err != nil {
+            return nil, nil, err
+        }
+        strips = append(strips, *s)
+    }
+    at.stage, at.face = "seams", -1
+    return strips, patchSeams(poly, adj, stripOf), nil
+}
+
+// stripEnd is one growing end of a strip.
+type stripEnd struct {
+    face  int
+    entry [2]int // the edge the strip came in over; {-1, -1} at a lone seed
+    pivot int    // vertex the last two hinges share, -1 if none
+}
+
+// growStrip lays out the strip starting at seed, recording its index in
+// stripOf.
+func growStrip(poly Polyhedron, adj *FaceAdjacency, seed, strip int, stripOf []int, opts StripOptions) (*FaceStrip, error) {
+    nFaces := len(poly.Faces)
+    face2D := make([]Face2D, nFaces)
+    parent := make([]int, nFaces)
+    for i := range parent {
+        parent[i] = -1
+    }
+    if err := placeRootFace(poly, seed, &face2D[seed]); err != nil {
+        return nil, fmt.Errorf("failed to place face %d: %w", seed, err)
+    }
+    stripOf[seed] = strip
+    members := []int{seed}
+    boxes := map[int]box2{seed: polyBox(face2D[seed].Vertices)}
+    var folds []NetEdge
+    eps := patchEps(poly)
+
+    // free counts the neighbours of g not in any strip yet
+    free := func(g int) int {
+        n := 0
+        for _, nbr := range adj.Neighbors[g] {
+            if stripOf[nbr.FaceIndex] < 0 {
+                n++
+            }
+        }
+        return n
+    }
+
+    // step adds the best free neighbour of end that fits, if any
+    step := func(end stripEnd) (stripEnd, bool) {
+        type candidate struct {
+            nbr        FaceNeighbor
+            turn, free int
+            pivot      int
+        }
+        var cands []candidate
+        for _, nbr := range adj.Neighbors[end.face] {
+            g := nbr.FaceIndex
+            if stripOf[g] >= 0 {
+                continue
+            }
+            // 0: straight across, 1: turning the other way, 2: turning
+            // about the same vertex again (a fan)
+            c := candidate{nbr: nbr, free: free(g), pivot: -1}
+            for _, v := range nbr.SharedEdge {
+                if v == end.entry[0] || v == end.entry[1] {
+                    c.pivot, c.turn = v, 1
+                    if v == end.pivot {
+                        c.turn = 2
+                    }
+                }
+            }
+            cands = append(cands, c)
+        }
+        sort.SliceStable(cands, func(i, j int) bool {
+            if cands[i].turn != cands[j].turn {
+                return cands[i].turn < cands[j].turn
+            }
+            if cands[i].free != cands[j].free {
+                return cands[i].free < cands[j].free
+            }
+            return cands[i].nbr.FaceIndex < cands[j].nbr.FaceIndex
+        })
+
+        f, pFace := end.face, poly.Faces[end.face]
+        for _, c := range cands {
+            g := c.nbr.FaceIndex
+            i0, i1 := c.nbr.ThisFaceEdge[0], c.nbr.ThisFaceEdge[1]
+            pts, err := hingeFace(poly, g, pFace.Vertices[i0], pFace.Vertices[i1], face2D[f].Vertices[i0], face2D[f].Vertices[i1])
+            if err != nil {
+                continue // degenerate hinge: leave the face for another strip
+            }
+            if opts.Unfold.Anchoring == AnchorBestFit {
+                pts = bestFitAnchor(poly, f, g, face2D[f].Vertices, pts)
+            }
+            b := polyBox(pts)
+            if !opts.AllowOverlaps && overlapsPatch(pts, b, members, face2D, boxes, eps) {
+                continue
+            }
+
+            face2D[g].Vertices = pts
+            parent[g] = f
+            stripOf[g] = strip
+            boxes[g] = b
+            members = append(members, g)
+            ge, _ := findEdgeInFace(poly.Faces[g], c.nbr.SharedEdge)
+            folds = append(folds, NetEdge{Vertices: c.nbr.SharedEdge, FaceA: f, EdgeA: i0, FaceB: g, EdgeB: ge[0]})
+            return stripEnd{face: g, entry: c.nbr.SharedEdge, pivot: c.pivot}, true
+        }
+        return end, false
+    }
+    full := func() bool { return opts.MaxFaces > 0 && len(members) >= opts.MaxFaces }
+
+    // forwards from the seed, then backwards from it, straight on from the
+    // first hinge
+    var tail []int
+    end := stripEnd{face: seed, entry: [2]int{-1, -1}, pivot: -1}
+    for ok := true; ok && !full(); {
+        if end, ok = step(end); ok {
+            tail = append(tail, end.face)
+        }
+    }
+    var head []int
+    end = stripEnd{face: seed, entry: [2]int{-1, -1}, pivot: -1}
+    if len(folds) > 0 {
+        end.entry = folds[0].Vertices
+    }
+    for ok := true; ok && !full(); {
+        if end, ok = step(end); ok {
+            head = append(head, end.face)
+        }
+    }
+
+    faces := make([]int, 0, len(members))
+    for i := len(head) - 1; i >= 0; i-- {
+        faces = append(faces, head[i])
+    }
+    faces = append(faces, seed)
+    faces = append(faces, tail...)
+
+    if err := applyMaterial(poly, face2D, folds, opts.Unfold.Material); err != nil {
+        return nil, err
+    }
+    res := patchResult(poly, face2D, parent, folds, func(f int) bool { return stripOf[f] == strip }, opts.Unfold)
+    return &FaceStrip{Faces: faces, Result: res}, nil
+}
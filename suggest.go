@@ -0,0 +1,267 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "sort"
+)
+
+// -----------------------------
+//   Cut suggestions for overlapping nets
+// -----------------------------
+
+// Suggestion is one way to get rid of overlaps: cut the fold edge Edge. On
+// its own that splits the net in two, which separates every overlapping pair
+// whose tree path runs through Edge. If Reconnect is set, the cut edge
+// Reconnect is folded instead so the net stays in one piece, and
+// SpanningTree/Root hold the resulting tree (see Strategy).
+type Suggestion struct {
+    Edge         NetEdge       `json:"edge"`
+    Reconnect    *NetEdge      `json:"reconnect,omitempty"`
+    Resolves     []OverlapPair `json:"resolves"`  // overlaps this gets rid of
+    Remaining    int           `json:"remaining"` // overlaps left afterwards, new ones included
+    Pieces       int           `json:"pieces"`    // pieces the net is in afterwards
+    ExtraSeam    float64       `json:"extraSeam"` // change in total cut length (mesh units)
+    SpanningTree []int         `json:"spanningTree,omitempty"`
+    Root         int           `json:"root"`
+}
+
+// String reads like "cut edge 3-7 (faces 2/5): separates 2 of 3 overlaps
+// into another piece, +1.41 seam".
+func (s Suggestion) String() string {
+    msg := fmt.Sprintf("cut edge %d-%d (faces %d/%d)", s.Edge.Vertices[0], s.Edge.Vertices[1], s.Edge.FaceA, s.Edge.FaceB)
+    if r := s.Reconnect; r != nil {
+        msg += fmt.Sprintf(" and fold edge %d-%d (faces %d/%d) instead", r.Vertices[0], r.Vertices[1], r.FaceA, r.FaceB)
+    }
+    total := len(s.Resolves) + s.Remaining
+    if s.Reconnect == nil {
+        msg += fmt.Sprintf(": separates %d of %d overlaps into another piece", len(s.Resolves), total)
+    } else {
+        msg += fmt.Sprintf(": %d overlaps left", s.Remaining)
+    }
+    return msg + fmt.Sprintf(", %+.3g seam", s.ExtraSeam)
+}
+
+// Strategy returns a SpanningStrategy that unfolds the suggested tree, for
+// suggestions that keep the net in one piece (nil otherwise).
+func (s Suggestion) Strategy() SpanningStrategy {
+    if s.SpanningTree == nil {
+        return nil
+    }
+    return FixedTree{Parent: s.SpanningTree, Root: s.Root}
+}
+
+// maxReconnects caps how many cut edges are tried as the replacement for each
+// suggested cut; every try is a full re-unfold.
+const maxReconnects = 32
+
+// SuggestCuts ranks fold edges to cut when result still has overlaps. It uses
+// result.Overlaps if set, otherwise runs DetectOverlaps. For every fold edge
+// on the tree path between two overlapping faces it suggests cutting that
+// edge, plus cut-and-refold swaps (re-unfolded and re-checked) that keep the
+// net in one piece and leave fewer overlaps (only for nets in one piece to
+// begin with). Suggestions are ranked by
+// overlaps left, then pieces, then extra seam length; max caps how many are
+// returned (0 = all). A net without overlaps gets no suggestions.
+func SuggestCuts(poly Polyhedron, result *UnfoldResult, max int) ([]Suggestion, error) {
+    if result == nil {
+        return nil, errors.New("nil result")
+    }
+    nFaces := len(poly.Faces)
+    parent := result.SpanningTree
+    if len(parent) != nFaces || len(result.Face2D) != nFaces {
+        return nil, fmt.Errorf("result has %d faces, mesh %d", len(parent), nFaces)
+    }
+    overlaps := result.Overlaps
+    if overlaps == nil {
+        overlaps = DetectOverlaps(result)
+    }
+    if len(overlaps) == 0 {
+        return nil, nil
+    }
+    root := -1
+    for f, p := range parent {
+        if p < 0 && len(result.Face2D[f].Vertices) > 0 {
+            root = f
+            break
+        }
+    }
+    if root < 0 {
+        return nil, errors.New("result has no root face")
+    }
+    pieces := 0
+    for f, p := range parent {
+        if p < 0 && len(result.Face2D[f].Vertices) > 0 {
+            pieces++
+        }
+    }
+
+    depth := make([]int, nFaces)
+    for f := range depth {
+        depth[f] = -1
+    }
+    var depthOf func(f int) int
+    depthOf = func(f int) int {
+        if depth[f] < 0 {
+            if parent[f] < 0 {
+                depth[f] = 0
+            } else {
+                depth[f] = depthOf(parent[f]) + 1
+            }
+        }
+        return depth[f]
+    }
+
+    // which overlaps each tree edge (named by its child face) separates
+    separates := make(map[int][]OverlapPair)
+    for _, o := range overlaps {
+        a, b := o.FaceA, o.FaceB
+        for a != b {
+            if depthOf(a) < depthOf(b) {
+                a, b = b, a
+            }
+            if parent[a] < 0 {
+                break // different trees: already apart
+            }
+            separates[a] = append(separates[a], o)
+            a = parent[a]
+        }
+    }
+    folds := make(map[[2]int]NetEdge)
+    for _, e := range result.FoldEdges {
+        folds[sortPair(e.FaceA, e.FaceB)] = e
+    }
+    edgeLen := func(e NetEdge) float64 {
+        return length(sub(poly.Vertices[e.Vertices[1]], poly.Vertices[e.Vertices[0]]))
+    }
+
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        return nil, fmt.Errorf("error building adjacency: %v", err)
+    }
+    children := make([][]int, nFaces)
+    for f, p := range parent {
+        if p >= 0 {
+            children[p] = append(children[p], f)
+        }
+    }
+
+    var out []Suggestion
+    for c, resolved := range separates {
+        edge, ok := folds[sortPair(c, parent[c])]
+        if !ok {
+            continue
+        }
+        out = append(out, Suggestion{
+            Edge:      edge,
+            Resolves:  resolved,
+            Remaining: len(overlaps) - len(resolved),
+            Pieces:    pieces + 1,
+            ExtraSeam: edgeLen(edge),
+            Root:      root,
+        })
+
+        // swaps: fold a cut edge between c's subtree and the rest instead.
+        // Re-unfolding only lays out one tree, so nets already in pieces
+        // just get the plain cuts.
+        if pieces > 1 {
+            continue
+        }
+        inSub := make([]bool, nFaces)
+        stack := []int{c}
+        for len(stack) > 0 {
+            f := stack[len(stack)-1]
+            stack = stack[:len(stack)-1]
+            inSub[f] = true
+            stack = append(stack, children[f]...)
+        }
+        var bridges []NetEdge
+        for _, e := range result.CutEdges {
+            if e.FaceB >= 0 && inSub[e.FaceA] != inSub[e.FaceB] {
+                bridges = append(bridges, e)
+            }
+        }
+        // longer edges first: folding them saves the most seam
+        sort.SliceStable(bridges, func(i, j int) bool { return edgeLen(bridges[i]) > edgeLen(bridges[j]) })
+        if len(bridges) > maxReconnects {
+            bridges = bridges[:maxReconnects]
+        }
+        for _, e := range bridges {
+            u, v := e.FaceA, e.FaceB // u outside, v inside the subtree
+            if inSub[u] {
+                u, v = v, u
+            }
+            tree := append([]int(nil), parent...)
+            for x, prev := v, u; ; {
+                next := parent[x]
+                tree[x] = prev
+                if x == c {
+                    break
+                }
+                x, prev = next, x
+            }
+            res, err := unfoldAlongTree(poly, adj, root, tree, UnfoldOptions{}, nil)
+            if err != nil {
+                continue
+            }
+            left := DetectOverlaps(res)
+            if len(left) >= len(overlaps) {
+                continue
+            }
+            reconnect := e
+            out = append(out, Suggestion{
+                Edge:         edge,
+                Reconnect:    &reconnect,
+                Resolves:     overlapsGone(overlaps, left),
+                Remaining:    len(left),
+                Pieces:       pieces,
+                ExtraSeam:    edgeLen(edge) - edgeLen(e),
+                SpanningTree: tree,
+                Root:         root,
+            })
+        }
+    }
+
+    sort.SliceStable(out, func(i, j int) bool {
+        a, b := out[i], out[j]
+        if a.Remaining != b.Remaining {
+            return a.Remaining < b.Remaining
+        }
+        if a.Pieces != b.Pieces {
+            return a.Pieces < b.Pieces
+        }
+        if a.ExtraSeam != b.ExtraSeam {
+            return a.ExtraSeam < b.ExtraSeam
+        }
+        if a.Edge.Vertices != b.Edge.Vertices {
+            return lessEdge(a.Edge.Vertices, b.Edge.Vertices)
+        }
+        return a.Reconnect != nil && (b.Reconnect == nil || lessEdge(a.Reconnect.Vertices, b.Reconnect.Vertices))
+    })
+    if max > 0 && len(out) > max {
+        out = out[:max]
+    }
+    return out, nil
+}
+
+// overlapsGone returns the pairs of before that aren't in after (both sorted).
+func overlapsGone(before, after []OverlapPair) []OverlapPair {
+    left := make(map[OverlapPair]bool, len(after))
+    for _, o := range after {
+        left[o] = true
+    }
+    gone := []OverlapPair{}
+    for _, o := range before {
+        if !left[o] {
+            gone = append(gone, o)
+        }
+    }
+    return gone
+}
+
+func lessEdge(a, b [2]int) bool {
+    if a[0] != b[0] {
+        return a[0] < b[0]
+    }
+    return a[1] < b[1]
+}
@@ -0,0 +1,247 @@
+package unfolder
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/base64"
+    "encoding/xml"
+    "errors"
+    "fmt"
+    "image"
+    "image/png"
+    "io"
+    "math"
+    "strconv"
+    "strings"
+)
+
+// -----------------------------
+//   SVG export
+// -----------------------------
+
+// SVGOptions controls ExportSVG. The zero value gives a millimetre sheet at
+// 1 net unit = 1 mm, or at the mesh's real size if Mesh has Units.
+type SVGOptions struct {
+    // Scale converts net units to Units. Default: from Mesh.Units if set,
+    // else 1.
+    Scale float64
+    // Units of the sheet: "mm" (default), "cm", "in", "pt" or "px".
+    Units string
+    // Margin around the drawing, in Units. Default 5.
+    Margin float64
+    // StrokeWidth of all lines, in Units. Default 0.2.
+    StrokeWidth float64
+
+    // Mesh is the polyhedron the net was unfolded from. When set, folds are
+    // drawn as mountain or valley folds (as seen from the printed side, the
+    // outside of the model unless the net's Side is SideInterior); without it
+    // every fold gets the same style. A net of a split mesh (UnfoldResult.Mesh
+    // set) uses that instead; otherwise Mesh needs a face for each of the net's.
+    Mesh *Polyhedron
+
+    // FaceLabels writes each face's index inside it.
+    FaceLabels bool
+    // LabelSize is the font size of face labels, in Units. Default 3.
+    LabelSize float64
+
+    // Overlays (rulers, scale figures, ...) are drawn in net coordinates.
+    Overlays []*Overlay
+    // EdgeLabels prints the same number on both copies of every cut edge,
+    // see AssignEdgeLabels.
+    EdgeLabels bool
+
+    // FoldHeatmap colours each fold by how sharply it bends, from blue
+    // (flat) through green and yellow to red (folded right back), keeping
+    // the mountain/valley dash patterns. Needs Mesh.
+    FoldHeatmap bool
+
+    // FaceGroups maps each face to a group, such as the original face of a
+    // triangulated mesh (see Triangulate). Flat folds between faces of the
+    // same group aren't drawn and labels show the group, once per group.
+    FaceGroups []int
+    // MinFoldAngle leaves out folds that bend less than this many degrees
+    // from flat, like those between the thin faces of a tessellated
+    // cylinder, which is rolled rather than folded (see SmoothRegions).
+    // Needs Mesh. 0 draws every fold.
+    MinFoldAngle float64
+
+    // Texture is drawn under the lines: every face with UVs shows its part
+    // of it (see RenderTexture), embedded in the SVG as a PNG.
+    Texture image.Image
+    // TextureResolution is the embedded image's pixels per sheet unit.
+    // Default: 300 per inch.
+    TextureResolution float64
+}
+
+// ExportSVG writes the net as an SVG sheet: cut edges solid, mountain folds
+// dash-dotted, valley folds dashed. Double walls are drawn with their hinge as
+// a valley fold. The net's Y axis points up, as in the unfold result.
+func ExportSVG(result *UnfoldResult, w io.Writer, opts SVGOptions) error {
+    if result == nil {
+        return errors.New("nil unfold result")
+    }
+    mesh, err := netMesh(result, opts.Mesh)
+    if err != nil {
+        return err
+    }
+    opts.Mesh = mesh
+    if opts.EdgeLabels {
+        n := len(opts.Overlays)
+        opts.Overlays = append(opts.Overlays[:n:n], EdgeLabelOverlay(result, AssignEdgeLabels(result), 0))
+    }
+    if opts.Units == "" {
+        opts.Units = "mm"
+    }
+    if opts.Scale <= 0 {
+        opts.Scale = exportScale(opts.Mesh, opts.Units)
+    }
+    switch opts.Units {
+    case "mm", "cm", "in", "pt", "px":
+    default:
+        return fmt.Errorf("unsupported SVG units %q", opts.Units)
+    }
+    if opts.Margin <= 0 {
+        opts.Margin = 5
+    }
+    if opts.StrokeWidth <= 0 {
+        opts.StrokeWidth = 0.2
+    }
+    if opts.LabelSize <= 0 {
+        opts.LabelSize = 3
+    }
+
+    lo, hi, ok := sheetBounds(result, opts.Overlays)
+    if !ok {
+        return errors.New("nothing to draw: no face was placed")
+    }
+
+    width := (hi.X-lo.X)*opts.Scale + 2*opts.Margin
+    height := (hi.Y-lo.Y)*opts.Scale + 2*opts.Margin
+    // sheet coordinates: scaled, shifted into the margin, Y flipped
+    sheet := func(p Point2) (x, y string) {
+        return svgNum((p.X-lo.X)*opts.Scale + opts.Margin), svgNum((hi.Y-p.Y)*opts.Scale + opts.Margin)
+    }
+    pt := func(p Point2) string {
+        x, y := sheet(p)
+        return x + "," + y
+    }
+
+    bw := bufio.NewWriter(w)
+    sw := svgNum(opts.StrokeWidth)
+    fmt.Fprintf(bw, "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+    fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s%s\" height=\"%s%s\" viewBox=\"0 0 %s %s\">\n",
+        svgNum(width), opts.Units, svgNum(height), opts.Units, svgNum(width), svgNum(height))
+    fmt.Fprintf(bw, "<style>\n")
+    fmt.Fprintf(bw, "  line, polyline, polygon { fill: none; stroke-width: %s; stroke-linecap: round; }\n", sw)
+    fmt.Fprintf(bw, "  .%s { stroke: #000; }\n", lineCut)
+    fmt.Fprintf(bw, "  .%s { stroke: #c00; stroke-dasharray: %s %s %s %s; }\n", lineMountain,
+        svgNum(8*opts.StrokeWidth), svgNum(4*opts.StrokeWidth), svgNum(opts.StrokeWidth), svgNum(4*opts.StrokeWidth))
+    fmt.Fprintf(bw, "  .%s { stroke: #00c; stroke-dasharray: %s %s; }\n", lineValley,
+        svgNum(6*opts.StrokeWidth), svgNum(4*opts.StrokeWidth))
+    fmt.Fprintf(bw, "  .%s { stroke: #777; stroke-dasharray: %s %s; }\n", lineFold,
+        svgNum(6*opts.StrokeWidth), svgNum(4*opts.StrokeWidth))
+    fmt.Fprintf(bw, "  .overlay { stroke: #555; }\n")
+    fmt.Fprintf(bw, "  text { font-family: sans-serif; fill: #555; }\n")
+    fmt.Fprintf(bw, "</style>\n")
+
+    if opts.Texture != nil {
+        res := opts.TextureResolution
+        if res <= 0 {
+            f, _ := UnitFactor(opts.Units, "in")
+            res = 300 * f
+        }
+        img, err := RenderTexture(result, opts.Texture, TextureOptions{Scale: opts.Scale * res})
+        if err != nil {
+            return err
+        }
+        var buf bytes.Buffer
+        if err := png.Encode(&buf, img); err != nil {
+            return err
+        }
+        // the image's top left corner is the top left of the faces' bounds
+        flo, fhi, _ := sheetBounds(result, nil)
+        x, y := sheet(Point2{X: flo.X, Y: fhi.Y})
+        fmt.Fprintf(bw, "<image id=\"texture\" x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" preserveAspectRatio=\"none\" href=\"data:image/png;base64,%s\"/>\n",
+            x, y, svgNum(float64(img.Bounds().Dx())/res), svgNum(float64(img.Bounds().Dy())/res), base64.StdEncoding.EncodeToString(buf.Bytes()))
+    }
+
+    lines := sheetLines(result, opts.Mesh, opts.FaceGroups, opts.MinFoldAngle*math.Pi/180)
+    for _, group := range []struct {
+        id    string
+        folds bool
+    }{{"cuts", false}, {"folds", true}} {
+        fmt.Fprintf(bw, "<g id=\"%s\">\n", group.id)
+        for _, l := range lines {
+            if (l.class != lineCut) != group.folds {
+                continue
+            }
+            if opts.FoldHeatmap && l.bend >= 0 {
+                r, g, b := foldHeat(l.bend)
+                fmt.Fprintf(bw, "  <polyline class=\"%s\" style=\"stroke: #%02x%02x%02x\" points=\"%s %s\"/>\n",
+                    l.class, int(math.Round(r*255)), int(math.Round(g*255)), int(math.Round(b*255)), pt(l.a), pt(l.b))
+                continue
+            }
+            fmt.Fprintf(bw, "  <polyline class=\"%s\" points=\"%s %s\"/>\n", l.class, pt(l.a), pt(l.b))
+        }
+        fmt.Fprintf(bw, "</g>\n")
+    }
+
+    if opts.FaceLabels {
+        fmt.Fprintf(bw, "<g id=\"labels\" font-size=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\">\n", svgNum(opts.LabelSize))
+        for _, l := range sheetLabels(result, opts.FaceGroups) {
+            x, y := sheet(l.at)
+            fmt.Fprintf(bw, "  <text x=\"%s\" y=\"%s\">%s</text>\n", x, y, l.text)
+        }
+        fmt.Fprintf(bw, "</g>\n")
+    }
+
+    for _, o := range opts.Overlays {
+        if o == nil {
+            continue
+        }
+        class := "overlay"
+        if o.Cut {
+            class = lineCut
+        }
+        fmt.Fprintf(bw, "<g id=\"overlay-%s\" class=\"%s\">\n", svgEscape(o.Name), class)
+        for _, l := range o.Polylines {
+            if len(l.Points) < 2 {
+                continue
+            }
+            tag := "polyline"
+            if l.Closed {
+                tag = "polygon"
+            }
+            coords := make([]string, len(l.Points))
+            for i, p := range l.Points {
+                coords[i] = pt(p)
+            }
+            fmt.Fprintf(bw, "  <%s class=\"%s\" points=\"%s\"/>\n", tag, class, strings.Join(coords, " "))
+        }
+        for _, t := range o.Texts {
+            // Size is a cap height; caps are roughly 0.7 em in sans-serif fonts
+            x, y := sheet(t.At)
+            fmt.Fprintf(bw, "  <text x=\"%s\" y=\"%s\" font-size=\"%s\">%s</text>\n",
+                x, y, svgNum(t.Size*opts.Scale/0.7), svgEscape(t.Text))
+        }
+        fmt.Fprintf(bw, "</g>\n")
+    }
+
+    fmt.Fprintf(bw, "</svg>\n")
+    return bw.Flush()
+}
+
+// svgNum formats a coordinate with at most 4 decimals and no trailing zeros.
+func svgNum(v float64) string {
+    s := strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+    if s == "-0" {
+        return "0"
+    }
+    return s
+}
+
+func svgEscape(s string) string {
+    var b strings.Builder
+    xml.EscapeText(&b, []byte(s))
+    return b.String()
+}
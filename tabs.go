@@ -0,0 +1,144 @@
+package unfolder
+
+import (
+    "math"
+    "sort"
+)
+
+// -----------------------------
+//   Glue tabs
+// -----------------------------
+
+// TabOptions controls GenerateTabs.
+type TabOptions struct {
+    // Width is how far a tab sticks out from its edge. 0 = 15% of the
+    // median cut edge length.
+    Width float64
+    // TaperAngle is the angle (degrees) between the edge and the tab's
+    // slanted sides. Default 45.
+    TaperAngle float64
+}
+
+// TabPolygon is a trapezoidal glue tab on one side of a cut edge. The first
+// two vertices are the edge it hangs off (a fold line); the tab is glued
+// under the mating edge.
+type TabPolygon struct {
+    Face     int      `json:"face"`     // face the tab is attached to
+    Edge     int      `json:"edge"`     // local edge index in Face
+    MateFace int      `json:"mateFace"` // face the tab is glued to
+    MateEdge int      `json:"mateEdge"`
+    Vertices []Point2 `json:"vertices"`
+}
+
+// GenerateTabs puts one tab on every cut edge that joins two faces (mesh
+// boundary edges have nothing to be glued to). It tries FaceA's side first,
+// then FaceB's, then each side again at half and a quarter of the width, and
+// leaves out tabs that collide with the net, its double walls or the tabs
+// already placed. Assign the result to result.Tabs to have the exporters
+// draw the tabs.
+func GenerateTabs(result *UnfoldResult, opts TabOptions) []TabPolygon {
+    if result == nil {
+        return nil
+    }
+    if opts.TaperAngle <= 0 || opts.TaperAngle > 90 {
+        opts.TaperAngle = 45
+    }
+    edgeLen := func(face, edge int) float64 {
+        pts := result.Face2D[face].Vertices
+        a, b := pts[edge], pts[(edge+1)%len(pts)]
+        return math.Hypot(b.X-a.X, b.Y-a.Y)
+    }
+    var cuts []NetEdge
+    var lengths []float64
+    for _, e := range result.CutEdges {
+        if e.FaceB < 0 || len(result.Face2D[e.FaceA].Vertices) < 3 || len(result.Face2D[e.FaceB].Vertices) < 3 {
+            continue
+        }
+        cuts = append(cuts, e)
+        lengths = append(lengths, edgeLen(e.FaceA, e.EdgeA))
+    }
+    if len(cuts) == 0 {
+        return nil
+    }
+    if opts.Width <= 0 {
+        sort.Float64s(lengths)
+        opts.Width = 0.15 * lengths[len(lengths)/2]
+    }
+
+    _, eps := faceBoxes(result.Face2D)
+    var obstacles [][]Point2
+    var boxes []box2
+    addObstacle := func(pts []Point2) {
+        obstacles = append(obstacles, pts)
+        boxes = append(boxes, polyBox(pts))
+    }
+    for _, f := range result.Face2D {
+        if len(f.Vertices) >= 3 {
+            addObstacle(f.Vertices)
+        }
+    }
+    for _, dw := range result.DoubleWalls {
+        addObstacle(dw.Vertices)
+    }
+    for _, t := range result.Tabs {
+        addObstacle(t.Vertices)
+    }
+    free := func(tab []Point2) bool {
+        tb := polyBox(tab)
+        for i, o := range obstacles {
+            b := boxes[i]
+            if tb.maxX < b.minX-eps || b.maxX < tb.minX-eps || tb.maxY < b.minY-eps || b.maxY < tb.minY-eps {
+                continue
+            }
+            if polygonsOverlap(tab, o, eps) {
+                return false
+            }
+        }
+        return true
+    }
+
+    var tabs []TabPolygon
+    for _, e := range cuts {
+        sides := [][4]int{{e.FaceA, e.EdgeA, e.FaceB, e.EdgeB}, {e.FaceB, e.EdgeB, e.FaceA, e.EdgeA}}
+    search:
+        for _, w := range []float64{opts.Width, opts.Width / 2, opts.Width / 4} {
+            for _, s := range sides {
+                pts := tabShape(result.Face2D[s[0]].Vertices, s[1], w, opts.TaperAngle)
+                if pts == nil || !free(pts) {
+                    continue
+                }
+                tabs = append(tabs, TabPolygon{Face: s[0], Edge: s[1], MateFace: s[2], MateEdge: s[3], Vertices: pts})
+                addObstacle(pts)
+                break search
+            }
+        }
+    }
+    return tabs
+}
+
+// tabShape builds the trapezoid outside edge i of a placed face. Edges too
+// short for the full taper get a triangle instead.
+func tabShape(face []Point2, i int, w, taperDeg float64) []Point2 {
+    a, b := face[i], face[(i+1)%len(face)]
+    l := math.Hypot(b.X-a.X, b.Y-a.Y)
+    if l == 0 {
+        return nil
+    }
+    tx, ty := (b.X-a.X)/l, (b.Y-a.Y)/l
+    n := leftNormal(a, b)
+    if polygonArea(face) > 0 {
+        n = Point2{X: -n.X, Y: -n.Y} // CCW face: outside is on the right
+    }
+    inset := w / math.Tan(taperDeg*math.Pi/180)
+    if 2*inset >= l {
+        // triangle with the requested taper, lower than w
+        h := l / 2 * math.Tan(taperDeg*math.Pi/180)
+        apex := Point2{X: (a.X+b.X)/2 + n.X*h, Y: (a.Y+b.Y)/2 + n.Y*h}
+        return []Point2{a, b, apex}
+    }
+    return []Point2{
+        a, b,
+        {X: b.X + n.X*w - tx*inset, Y: b.Y + n.Y*w - ty*inset},
+        {X: a.X + n.X*w + tx*inset, Y: a.Y + n.Y*w + ty*inset},
+    }
+}
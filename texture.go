@@ -0,0 +1,184 @@
+package unfolder
+
+import (
+    "errors"
+    "image"
+    "image/color"
+    "math"
+)
+
+// -----------------------------
+//   Textured nets
+// -----------------------------
+
+// TextureOptions controls RenderTexture.
+type TextureOptions struct {
+    // Scale is pixels per net unit. Default: the longer side of the net
+    // comes out 2048 pixels.
+    Scale float64
+    // Margin around the net, in pixels.
+    Margin int
+    // Background fills the image before the faces are drawn; nil leaves it
+    // transparent.
+    Background color.Color
+    // Lines draws the cut and fold lines over the texture, in the colours of
+    // the SVG export (solid, LineWidth pixels wide).
+    Lines bool
+    // LineWidth in pixels. Default 1.
+    LineWidth float64
+    // Mesh tells mountain from valley folds for Lines, as in SVGOptions.
+    Mesh *Polyhedron
+}
+
+// attachUVs copies the texture coordinates of every placed face of poly onto
+// its 2D face, so the net knows what part of the texture each face shows.
+func attachUVs(poly Polyhedron, face2D []Face2D) {
+    for f := range face2D {
+        if f < len(poly.Faces) && len(face2D[f].Vertices) > 0 && len(poly.Faces[f].UVs) == len(face2D[f].Vertices) {
+            face2D[f].UVs = poly.Faces[f].UVs
+        }
+    }
+}
+
+// RenderTexture rasterizes the net with every face showing its part of tex,
+// the way it looks on the model (like Pepakura's textured output): each face
+// is mapped from its UVs onto its place in the net, sampled bilinearly and
+// wrapping outside 0..1. Faces without UVs stay empty. Pixel (0, 0) is the
+// top left corner of the net's bounding box, less the margin; the net's Y
+// axis points up, as in the SVG export.
+func RenderTexture(result *UnfoldResult, tex image.Image, opts TextureOptions) (*image.RGBA, error) {
+    if result == nil {
+        return nil, errors.New("nil unfold result")
+    }
+    mesh, err := netMesh(result, opts.Mesh)
+    if err != nil {
+        return nil, err
+    }
+    opts.Mesh = mesh
+    if tex == nil || tex.Bounds().Empty() {
+        return nil, errors.New("texture is empty")
+    }
+    lo, hi, ok := sheetBounds(result, nil)
+    if !ok {
+        return nil, errors.New("nothing to draw: no face was placed")
+    }
+    s := opts.Scale
+    if s <= 0 {
+        s = 2048 / math.Max(hi.X-lo.X, hi.Y-lo.Y)
+    }
+    m := opts.Margin
+    if m < 0 {
+        m = 0
+    }
+    w := int(math.Ceil((hi.X-lo.X)*s)) + 2*m
+    h := int(math.Ceil((hi.Y-lo.Y)*s)) + 2*m
+    if w <= 0 || h <= 0 || w*h > 1<<28 {
+        return nil, errors.New("texture image would be empty or too large; pick another scale")
+    }
+    img := image.NewRGBA(image.Rect(0, 0, w, h))
+    if opts.Background != nil {
+        bg := color.RGBAModel.Convert(opts.Background).(color.RGBA)
+        for i := 0; i < len(img.Pix); i += 4 {
+            img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = bg.R, bg.G, bg.B, bg.A
+        }
+    }
+    // net units -> pixels
+    toPx := func(p Point2) Point2 {
+        return Point2{X: (p.X-lo.X)*s + float64(m), Y: (hi.Y-p.Y)*s + float64(m)}
+    }
+
+    for _, f := range result.Face2D {
+        if len(f.Vertices) < 3 || len(f.UVs) != len(f.Vertices) {
+            continue
+        }
+        a := toPx(f.Vertices[0])
+        for i := 1; i+1 < len(f.Vertices); i++ {
+            b, c := toPx(f.Vertices[i]), toPx(f.Vertices[i+1])
+            drawTexturedTriangle(img, tex, [3]Point2{a, b, c}, [3]Point2{f.UVs[0], f.UVs[i], f.UVs[i+1]})
+        }
+    }
+
+    if opts.Lines {
+        drawNetLines(img, result, opts.Mesh, toPx, opts.LineWidth)
+    }
+    return img, nil
+}
+
+// drawTexturedTriangle fills the pixels whose centres fall in the triangle
+// pts (pixel coordinates) with tex sampled at the interpolated uvs.
+func drawTexturedTriangle(img *image.RGBA, tex image.Image, pts, uvs [3]Point2) {
+    rasterTriangle(img.Bounds(), pts, func(x, y int, w [3]float64) {
+        uv := Point2{
+            X: w[0]*uvs[0].X + w[1]*uvs[1].X + w[2]*uvs[2].X,
+            Y: w[0]*uvs[0].Y + w[1]*uvs[1].Y + w[2]*uvs[2].Y,
+        }
+        img.SetRGBA64(x, y, sampleTexture(tex, uv))
+    })
+}
+
+// sampleTexture reads tex at uv (V up) with bilinear filtering, wrapping
+// around outside 0..1.
+func sampleTexture(tex image.Image, uv Point2) color.RGBA64 {
+    bounds := tex.Bounds()
+    w, h := bounds.Dx(), bounds.Dy()
+    x := uv.X*float64(w) - 0.5
+    y := (1-uv.Y)*float64(h) - 0.5
+    fx, fy := math.Floor(x), math.Floor(y)
+    tx, ty := x-fx, y-fy
+    wrap := func(v, n int) int {
+        v %= n
+        if v < 0 {
+            v += n
+        }
+        return v
+    }
+    var sum [4]float64
+    for _, k := range [4]struct {
+        dx, dy int
+        wt     float64
+    }{{0, 0, (1 - tx) * (1 - ty)}, {1, 0, tx * (1 - ty)}, {0, 1, (1 - tx) * ty}, {1, 1, tx * ty}} {
+        px := bounds.Min.X + wrap(int(fx)+k.dx, w)
+        py := bounds.Min.Y + wrap(int(fy)+k.dy, h)
+        r, g, b, a := tex.At(px, py).RGBA()
+        sum[0] += k.wt * float64(r)
+        sum[1] += k.wt * float64(g)
+        sum[2] += k.wt * float64(b)
+        sum[3] += k.wt * float64(a)
+    }
+    return color.RGBA64{uint16(sum[0] + 0.5), uint16(sum[1] + 0.5), uint16(sum[2] + 0.5), uint16(sum[3] + 0.5)}
+}
+
+// drawNetLines strokes the cut and fold lines of result in the colours of
+// the SVG export, lw pixels wide (default 1).
+func drawNetLines(img *image.RGBA, result *UnfoldResult, mesh *Polyhedron, toPx func(Point2) Point2, lw float64) {
+    if lw <= 0 {
+        lw = 1
+    }
+    colors := map[string]color.RGBA{
+        lineCut:      {0, 0, 0, 255},
+        lineMountain: {0xcc, 0, 0, 255},
+        lineValley:   {0, 0, 0xcc, 255},
+        lineFold:     {0x77, 0x77, 0x77, 255},
+    }
+    for _, l := range sheetLines(result, mesh, nil, 0) {
+        drawLine(img, toPx(l.a), toPx(l.b), lw, colors[l.class])
+    }
+}
+
+// drawLine strokes a to b (pixel coordinates) with a square pen width pixels
+// wide.
+func drawLine(img *image.RGBA, a, b Point2, width float64, c color.RGBA) {
+    steps := int(math.Ceil(math.Max(math.Abs(b.X-a.X), math.Abs(b.Y-a.Y)))) + 1
+    half := width / 2
+    for i := 0; i <= steps; i++ {
+        t := float64(i) / float64(steps)
+        px, py := a.X+(b.X-a.X)*t, a.Y+(b.Y-a.Y)*t
+        for y := int(math.Floor(py - half + 0.5)); y < int(math.Floor(py+half+0.5)); y++ {
+            for x := int(math.Floor(px - half + 0.5)); x < int(math.Floor(px+half+0.5)); x++ {
+                if image.Pt(x, y).In(img.Bounds()) {
+                    img.SetRGBA(x, y, c)
+                }
+            }
+        }
+    }
+}
@@ -0,0 +1,290 @@
+package unfolder
+
+import (
+    "fmt"
+    "sort"
+    "strconv"
+    "strings"
+)
+
+// -----------------------------
+//   Topology: Euler characteristic, genus, boundaries, shells
+// -----------------------------
+
+// TopologyReport describes the shape of a mesh's surface, the things no
+// amount of folding changes: how many separate shells it has, how many
+// holes go through them (genus) and how many open boundaries they have.
+type TopologyReport struct {
+    Vertices int `json:"vertices"` // used by some face
+    Edges    int `json:"edges"`
+    Faces    int `json:"faces"`
+    // Euler is the Euler characteristic V - E + F: 2 for a sphere, 0 for a
+    // torus, 1 for a disk.
+    Euler int `json:"euler"`
+    // Genus adds up the genus of every shell; -1 if any isn't an orientable
+    // manifold, where it isn't defined.
+    Genus         int  `json:"genus"`
+    BoundaryLoops int  `json:"boundaryLoops"`
+    Manifold      bool `json:"manifold"`
+    Orientable    bool `json:"orientable"`
+    // Shells are the connected pieces (faces sharing a vertex are
+    // connected), largest first. UnfoldMesh only unfolds the shell of the
+    // root face.
+    Shells []ShellTopology `json:"shells"`
+}
+
+// ShellTopology describes one connected piece of a mesh.
+type ShellTopology struct {
+    Faces         []int `json:"faces"` // ascending
+    Vertices      int   `json:"vertices"`
+    Edges         int   `json:"edges"`
+    Euler         int   `json:"euler"`
+    Genus         int   `json:"genus"` // -1 unless Manifold and Orientable
+    BoundaryLoops int   `json:"boundaryLoops"`
+    // Manifold is false if an edge has 3 or more faces, or the faces
+    // around a vertex don't form a single fan (two shells touching there).
+    Manifold bool `json:"manifold"`
+    // Orientable is false for one-sided surfaces like a Möbius strip, whose
+    // faces can't all be wound the same way.
+    Orientable bool `json:"orientable"`
+}
+
+// Closed reports whether the shell has no boundary: it encloses a volume.
+func (s ShellTopology) Closed() bool {
+    return s.BoundaryLoops == 0
+}
+
+// Kind names the shell's shape: "sphere", "disk", "annulus", "torus",
+// "genus-2 surface", "genus-1 surface with 3 boundaries" and so on, or
+// "non-manifold" / "non-orientable".
+func (s ShellTopology) Kind() string {
+    switch {
+    case !s.Manifold:
+        return "non-manifold"
+    case !s.Orientable:
+        return "non-orientable"
+    case s.Genus == 0 && s.BoundaryLoops == 0:
+        return "sphere"
+    case s.Genus == 0 && s.BoundaryLoops == 1:
+        return "disk"
+    case s.Genus == 0 && s.BoundaryLoops == 2:
+        return "annulus"
+    case s.Genus == 1 && s.BoundaryLoops == 0:
+        return "torus"
+    }
+    kind := "genus-" + strconv.Itoa(s.Genus) + " surface"
+    if s.BoundaryLoops > 0 {
+        kind += " with " + strconv.Itoa(s.BoundaryLoops) + " boundaries"
+        if s.BoundaryLoops == 1 {
+            kind = strings.TrimSuffix(kind, "ies") + "y"
+        }
+    }
+    return kind
+}
+
+// Topology counts the vertices, edges and faces of poly and works out, for
+// every shell, its Euler characteristic, boundary loops and genus
+// (V - E + F = 2 - 2g - b for an orientable surface). Use it before
+// unfolding to spot several shells (only the root face's gets unfolded),
+// open surfaces and handles, which tend to unfold with overlaps unless cut
+// into patches (see UnfoldMeshSegmented). Only faces with out of range
+// vertices or fewer than 3 vertices are errors.
+func Topology(poly Polyhedron) (report *TopologyReport, err error) {
+    defer (&progress{stage: "topology", face: -1}).recover(&err)
+    for fIdx, face := range poly.Faces {
+        if len(face.Vertices) < 3 {
+            return nil, &ErrDegenerateFace{Face: fIdx, Reason: "fewer than 3 vertices"}
+        }
+        for _, v := range face.Vertices {
+            if v < 0 || v >= len(poly.Vertices) {
+                return nil, &ErrDegenerateFace{Face: fIdx, Reason: fmt.Sprintf("vertex %d out of range", v)}
+            }
+        }
+    }
+
+    // shells: faces joined through their vertices
+    uf := make([]int, len(poly.Vertices))
+    for i := range uf {
+        uf[i] = i
+    }
+    var find func(int) int
+    find = func(x int) int {
+        if uf[x] != x {
+            uf[x] = find(uf[x])
+        }
+        return uf[x]
+    }
+    for _, face := range poly.Faces {
+        for _, v := range face.Vertices[1:] {
+            uf[find(v)] = find(face.Vertices[0])
+        }
+    }
+    shellOf := make(map[int]int) // union-find root -> shell
+    var shells [][]int
+    for fIdx, face := range poly.Faces {
+        r := find(face.Vertices[0])
+        s, ok := shellOf[r]
+        if !ok {
+            s = len(shells)
+            shellOf[r] = s
+            shells = append(shells, nil)
+        }
+        shells[s] = append(shells[s], fIdx)
+    }
+    sort.SliceStable(shells, func(i, j int) bool { return len(shells[i]) > len(shells[j]) })
+
+    rep := &TopologyReport{Faces: len(poly.Faces), Manifold: true, Orientable: true}
+    for _, faces := range shells {
+        s := shellTopology(poly, faces)
+        rep.Vertices += s.Vertices
+        rep.Edges += s.Edges
+        rep.BoundaryLoops += s.BoundaryLoops
+        rep.Manifold = rep.Manifold && s.Manifold
+        rep.Orientable = rep.Orientable && s.Orientable
+        if s.Genus < 0 || rep.Genus < 0 {
+            rep.Genus = -1
+        } else {
+            rep.Genus += s.Genus
+        }
+        rep.Shells = append(rep.Shells, s)
+    }
+    rep.Euler = rep.Vertices - rep.Edges + rep.Faces
+    return rep, nil
+}
+
+// shellTopology measures the connected shell made of faces.
+func shellTopology(poly Polyhedron, faces []int) ShellTopology {
+    s := ShellTopology{Faces: faces, Manifold: true, Orientable: true}
+
+    // every edge with the faces on it, each with the vertex it runs from
+    type use struct{ face, from int }
+    edges := make(map[[2]int][]use)
+    around := make(map[int][]int) // vertex -> faces on it
+    for _, f := range faces {
+        vs := poly.Faces[f].Vertices
+        for i, v := range vs {
+            w := vs[(i+1)%len(vs)]
+            e := sortPair(v, w)
+            edges[e] = append(edges[e], use{f, v})
+            around[v] = append(around[v], f)
+        }
+    }
+    s.Vertices = len(around)
+    s.Edges = len(edges)
+    s.Euler = s.Vertices - s.Edges + len(faces)
+
+    // boundary loops: pieces of the graph of boundary edges
+    loopOf := make(map[int]int)
+    var find func(int) int
+    find = func(x int) int {
+        if p, ok := loopOf[x]; ok && p != x {
+            loopOf[x] = find(p)
+            return loopOf[x]
+        }
+        return x
+    }
+    boundaryVerts := make(map[int]bool)
+    for e, us := range edges {
+        switch {
+        case len(us) == 1:
+            boundaryVerts[e[0]], boundaryVerts[e[1]] = true, true
+            if a, b := find(e[0]), find(e[1]); a != b {
+                loopOf[a] = b
+            }
+        case len(us) > 2:
+            s.Manifold = false
+        }
+    }
+    for v := range boundaryVerts {
+        if find(v) == v {
+            s.BoundaryLoops++
+        }
+    }
+
+    // a vertex is manifold if its faces form one fan, linked through the
+    // edges at the vertex
+    for v, fs := range around {
+        if len(fs) < 2 {
+            continue
+        }
+        idx := make(map[int]int, len(fs))
+        fan := make([]int, 0, len(fs))
+        for _, f := range fs {
+            if _, ok := idx[f]; !ok {
+                idx[f] = len(fan)
+                fan = append(fan, len(fan))
+            }
+        }
+        root := func(x int) int {
+            for fan[x] != x {
+                x = fan[x]
+            }
+            return x
+        }
+        groups := len(fan)
+        for _, f := range fs {
+            vs := poly.Faces[f].Vertices
+            for i, u := range vs {
+                if u != v {
+                    continue
+                }
+                for _, w := range []int{vs[(i+1)%len(vs)], vs[(i+len(vs)-1)%len(vs)]} {
+                    for _, o := range edges[sortPair(v, w)] {
+                        if a, b := root(idx[f]), root(idx[o.face]); a != b {
+                            fan[a] = b
+                            groups--
+                        }
+                    }
+                }
+            }
+        }
+        if groups > 1 {
+            s.Manifold = false
+        }
+    }
+
+    // orientable if the faces can be flipped so every manifold edge runs
+    // opposite ways in its two faces
+    type link struct {
+        face int
+        same bool // both faces run the edge the same way
+    }
+    flip := make(map[int]bool, len(faces))
+    seen := make(map[int]bool, len(faces))
+    nbrs := make(map[int][]link)
+    for _, us := range edges {
+        if len(us) != 2 || us[0].face == us[1].face {
+            continue
+        }
+        same := us[0].from == us[1].from
+        nbrs[us[0].face] = append(nbrs[us[0].face], link{us[1].face, same})
+        nbrs[us[1].face] = append(nbrs[us[1].face], link{us[0].face, same})
+    }
+    for _, start := range faces {
+        if seen[start] {
+            continue
+        }
+        seen[start] = true
+        queue := []int{start}
+        for len(queue) > 0 {
+            f := queue[0]
+            queue = queue[1:]
+            for _, n := range nbrs[f] {
+                want := flip[f] != n.same
+                if !seen[n.face] {
+                    seen[n.face] = true
+                    flip[n.face] = want
+                    queue = append(queue, n.face)
+                } else if flip[n.face] != want {
+                    s.Orientable = false
+                }
+            }
+        }
+    }
+
+    s.Genus = -1
+    if s.Manifold && s.Orientable {
+        s.Genus = (2 - s.BoundaryLoops - s.Euler) / 2
+    }
+    return s
+}
@@ -0,0 +1,141 @@
+package unfolder
+
+import "fmt"
+
+// -----------------------------
+//   Spanning tree validation
+// -----------------------------
+
+// ValidateSpanningTree checks a parent array from outside the package (a
+// saved net, an editor, a strategy of the caller's, a fuzzer) before anything
+// is laid out along it: one entry per face, every parent link along an edge
+// the two faces really share, no cycles, a single root, and every face
+// reachable from that root in the tree. Faces the root can't reach must be
+// -1. adj may be nil, it's built from poly then.
+//
+// The error is an *ErrInvalidTree naming the first face found at fault.
+func ValidateSpanningTree(poly Polyhedron, adj *FaceAdjacency, parent []int) error {
+    _, err := treeRoot(poly, adj, parent, -1)
+    return err
+}
+
+// treeRoot is ValidateSpanningTree that also returns the tree's root, which
+// must be want unless that's -1. If no face has a parent the root is want,
+// or else the first face without neighbours, either being a whole tree of its
+// own.
+func treeRoot(poly Polyhedron, adj *FaceAdjacency, parent []int, want int) (int, error) {
+    n := len(poly.Faces)
+    if len(parent) != n {
+        return -1, &ErrInvalidTree{Face: -1, Reason: fmt.Sprintf("%d entries for %d faces", len(parent), n)}
+    }
+    if n == 0 {
+        return -1, &ErrInvalidTree{Face: -1, Reason: "polyhedron has no faces"}
+    }
+    if adj == nil {
+        var err error
+        if adj, err = BuildFaceAdjacency(poly); err != nil {
+            return -1, fmt.Errorf("error building adjacency: %v", err)
+        }
+    }
+    if len(adj.Neighbors) != n {
+        return -1, &ErrInvalidTree{Face: -1, Reason: fmt.Sprintf("adjacency has %d faces, polyhedron %d", len(adj.Neighbors), n)}
+    }
+
+    // every link is a real edge
+    for f, p := range parent {
+        if p == -1 {
+            continue
+        }
+        if p < 0 || p >= n {
+            return -1, &ErrInvalidTree{Face: f, Reason: fmt.Sprintf("parent %d out of range", p)}
+        }
+        shared := false
+        for _, nbr := range adj.Neighbors[f] {
+            if nbr.FaceIndex == p {
+                shared = true
+                break
+            }
+        }
+        if !shared {
+            return -1, &ErrInvalidTree{Face: f, Reason: fmt.Sprintf("parent %d shares no edge with it", p)}
+        }
+    }
+
+    // walk every face up to its root, each face once: rootOf is -2 while a
+    // face is on the current walk, so meeting one again is a cycle
+    const unknown, walking = -3, -2
+    rootOf := make([]int, n)
+    for f := range rootOf {
+        rootOf[f] = unknown
+    }
+    root := -1
+    for f := range parent {
+        if parent[f] == -1 || rootOf[f] != unknown {
+            continue
+        }
+        var path []int
+        g := f
+        for g != -1 && rootOf[g] == unknown {
+            rootOf[g] = walking
+            path = append(path, g)
+            if parent[g] == -1 {
+                break
+            }
+            g = parent[g]
+        }
+        if rootOf[g] == walking && parent[g] != -1 {
+            return -1, &ErrInvalidTree{Face: g, Reason: "on a cycle"}
+        }
+        r := rootOf[g]
+        if parent[g] == -1 {
+            r = g
+        }
+        for _, h := range path {
+            rootOf[h] = r
+        }
+        if root == -1 {
+            root = r
+        } else if r != root {
+            return -1, &ErrInvalidTree{Face: f, Reason: fmt.Sprintf("hangs off root %d, other faces off root %d", r, root)}
+        }
+    }
+    switch {
+    case root >= 0 && want >= 0 && root != want:
+        return -1, &ErrInvalidTree{Face: want, Reason: fmt.Sprintf("tree is rooted at face %d instead", root)}
+    case root == -1 && want >= 0:
+        if want >= n || parent[want] != -1 {
+            return -1, &ErrInvalidTree{Face: want, Reason: "root out of range or has a parent"}
+        }
+        root = want
+    }
+    if root == -1 {
+        for f := range parent {
+            if len(adj.Neighbors[f]) == 0 {
+                root = f
+                break
+            }
+        }
+        if root == -1 {
+            return -1, &ErrInvalidTree{Face: 0, Reason: "no face has a parent, but every face has neighbours"}
+        }
+    }
+
+    // everything the root reaches is in the tree
+    seen := make([]bool, n)
+    seen[root] = true
+    queue := []int{root}
+    for i := 0; i < len(queue); i++ {
+        for _, nbr := range adj.Neighbors[queue[i]] {
+            g := nbr.FaceIndex
+            if seen[g] {
+                continue
+            }
+            if parent[g] == -1 {
+                return -1, &ErrInvalidTree{Face: g, Reason: fmt.Sprintf("reachable from root %d but not in the tree", root)}
+            }
+            seen[g] = true
+            queue = append(queue, g)
+        }
+    }
+    return root, nil
+}
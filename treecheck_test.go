@@ -0,0 +1,75 @@
+package unfolder
+
+import (
+    "errors"
+    "strings"
+    "testing"
+)
+
+func TestValidateSpanningTree(t *testing.T) {
+    // cube faces: 0 bottom, 1 top, 2 front, 3 right, 4 back, 5 left; the
+    // bottom and top touch the four sides, not each other
+    tests := []struct {
+        name   string
+        poly   Polyhedron
+        parent []int
+        face   int    // face the error names, -2 if no error
+        reason string // part of the reason
+    }{
+        {"bfs from the bottom", testCube(), []int{-1, 2, 0, 0, 0, 0}, -2, ""},
+        {"a path round the sides", testCube(), []int{2, -1, 1, 2, 3, 4}, -2, ""},
+        {"single face", Polyhedron{Vertices: testCube().Vertices, Faces: testCube().Faces[:1]}, []int{-1}, -2, ""},
+        {"too short", testCube(), []int{-1, 2, 0}, -1, "3 entries for 6 faces"},
+        {"no faces", Polyhedron{}, []int{}, -1, "no faces"},
+        {"parent out of range", testCube(), []int{-1, 2, 0, 0, 0, 9}, 5, "out of range"},
+        {"not neighbours", testCube(), []int{-1, 0, 0, 0, 0, 0}, 1, "shares no edge"},
+        {"cycle", testCube(), []int{-1, 2, 3, 2, 0, 0}, 2, "cycle"},
+        {"two roots", testCube(), []int{-1, -1, 1, 0, 0, 0}, 3, "other faces off root"},
+        {"left out", testCube(), []int{-1, -1, 0, 0, 0, 0}, 1, "not in the tree"},
+        {"no parents at all", testCube(), []int{-1, -1, -1, -1, -1, -1}, 0, "every face has neighbours"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := ValidateSpanningTree(tt.poly, nil, tt.parent)
+            if tt.face == -2 {
+                if err != nil {
+                    t.Fatal(err)
+                }
+                return
+            }
+            var invalid *ErrInvalidTree
+            if !errors.As(err, &invalid) {
+                t.Fatalf("err = %v, want an *ErrInvalidTree", err)
+            }
+            if invalid.Face != tt.face || !strings.Contains(invalid.Reason, tt.reason) {
+                t.Errorf("face %d, %q; want face %d, %q", invalid.Face, invalid.Reason, tt.face, tt.reason)
+            }
+        })
+    }
+}
+
+func TestValidateSpanningTreeAdjacency(t *testing.T) {
+    poly := testCube()
+    parent := []int{-1, 2, 0, 0, 0, 0}
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := ValidateSpanningTree(poly, adj, parent); err != nil {
+        t.Errorf("with the cube's adjacency: %v", err)
+    }
+    other, err := BuildFaceAdjacency(testSphere(3, 4))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := ValidateSpanningTree(poly, other, parent); err == nil {
+        t.Error("with another mesh's adjacency: no error")
+    }
+
+    // every tree the package builds passes
+    for root := range poly.Faces {
+        if err := ValidateSpanningTree(poly, adj, BuildFaceSpanningTree(adj, root, len(poly.Faces))); err != nil {
+            t.Errorf("BFS tree from face %d: %v", root, err)
+        }
+    }
+}
@@ -0,0 +1,169 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Triangulation
+// -----------------------------
+
+// Triangulate splits every non-planar or concave face into triangles, so
+// each face the unfolder places is flat and convex. Faces are non-planar when
+// a vertex is further than planarityTolerance (model units) from the face's
+// plane; 0 means 1e-9 of the mesh size. Planar convex faces are kept as they
+// are.
+//
+// The returned slice maps each face of the new mesh to the face of poly it
+// came from; pass it as FaceGroups to the exporters to draw split faces as
+// one outline. Vertices are shared with poly, not copied. Faces with holes
+// are kept as they are too.
+func Triangulate(poly Polyhedron, planarityTolerance float64) (Polyhedron, []int, error) {
+    if planarityTolerance <= 0 {
+        lo, hi := boundingBox(poly.Vertices)
+        planarityTolerance = 1e-9 * length(sub(hi, lo))
+    }
+    out := Polyhedron{Vertices: poly.Vertices, Name: poly.Name, Units: poly.Units, Faces: make([]Face, 0, len(poly.Faces))}
+    origin := make([]int, 0, len(poly.Faces))
+    for fIdx, face := range poly.Faces {
+        for _, v := range face.Vertices {
+            if v < 0 || v >= len(poly.Vertices) {
+                return Polyhedron{}, nil, &ErrDegenerateFace{Face: fIdx, Reason: fmt.Sprintf("vertex %d out of range", v)}
+            }
+        }
+        if len(face.Vertices) <= 3 || len(face.Holes) > 0 || (facePlanar(poly, face, planarityTolerance) && faceConvex(poly, face)) {
+            out.Faces = append(out.Faces, Face{Vertices: append([]int(nil), face.Vertices...), UVs: append([]Point2(nil), face.UVs...), Holes: copyHoles(face.Holes, sameVertex)})
+            origin = append(origin, fIdx)
+            continue
+        }
+        textured := len(face.UVs) == len(face.Vertices)
+        for _, tri := range earClip(poly, face) {
+            t := Face{Vertices: make([]int, 3)}
+            for i, c := range tri {
+                t.Vertices[i] = face.Vertices[c]
+                if textured {
+                    t.UVs = append(t.UVs, face.UVs[c])
+                }
+            }
+            out.Faces = append(out.Faces, t)
+            origin = append(origin, fIdx)
+        }
+    }
+    return out, origin, nil
+}
+
+// facePlanar reports whether every vertex of the face is within tol of the
+// plane through its centroid with its Newell normal.
+func facePlanar(poly Polyhedron, face Face, tol float64) bool {
+    return faceWarp(poly, face) <= tol
+}
+
+// faceWarp is how far the face's furthest vertex is from the plane through
+// its centroid with its Newell normal; 0 for a degenerate face, which
+// there's nothing to gain from splitting.
+func faceWarp(poly Polyhedron, face Face) float64 {
+    n := faceNormal(poly, face)
+    if length(n) == 0 {
+        return 0
+    }
+    n = normalize(n)
+    var c Vector3
+    for _, v := range face.Vertices {
+        c = add(c, poly.Vertices[v])
+    }
+    c = scale(c, 1/float64(len(face.Vertices)))
+    warp := 0.0
+    for _, v := range face.Vertices {
+        warp = math.Max(warp, math.Abs(dot(sub(poly.Vertices[v], c), n)))
+    }
+    return warp
+}
+
+// faceConvex reports whether the face turns the same way at every vertex.
+func faceConvex(poly Polyhedron, face Face) bool {
+    pts := projectFace(poly, face.Vertices)
+    n := len(pts)
+    for i := range pts {
+        if cross2(pts[i], pts[(i+1)%n], pts[(i+2)%n]) < 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// projectFace projects the vertices onto the face's Newell plane, so a CCW
+// face gives a CCW 2D polygon.
+func projectFace(poly Polyhedron, vs []int) []Point2 {
+    n := normalize(faceNormal(poly, Face{Vertices: vs}))
+    // any axis not parallel to n gives a basis
+    ref := Vector3{1, 0, 0}
+    if math.Abs(n.X) > 0.9 {
+        ref = Vector3{0, 1, 0}
+    }
+    u := normalize(cross(ref, n))
+    w := cross(n, u)
+    pts := make([]Point2, len(vs))
+    for i, v := range vs {
+        p := poly.Vertices[v]
+        pts[i] = Point2{dot(p, u), dot(p, w)}
+    }
+    return pts
+}
+
+// earClip triangulates the face by ear clipping in its projected plane,
+// always cutting the ear with the shortest (3D) diagonal so non-planar faces
+// fold along their short diagonals. If no ear is found (self-intersecting
+// outline) the rest is fanned. Triangles are returned as corner indices
+// into face.Vertices.
+func earClip(poly Polyhedron, face Face) [][]int {
+    pts := projectFace(poly, face.Vertices)
+    idx := make([]int, len(pts)) // remaining polygon, as positions in face
+    for i := range idx {
+        idx[i] = i
+    }
+    var tris [][]int
+    for len(idx) > 3 {
+        n := len(idx)
+        best, bestLen := -1, math.Inf(1)
+        for k := 0; k < n; k++ {
+            a, b, c := idx[(k+n-1)%n], idx[k], idx[(k+1)%n]
+            if cross2(pts[a], pts[b], pts[c]) <= 0 {
+                continue // reflex or flat corner
+            }
+            ear := true
+            for _, o := range idx {
+                if o != a && o != b && o != c && pointInTriangle(pts[o], pts[a], pts[b], pts[c]) {
+                    ear = false
+                    break
+                }
+            }
+            if !ear {
+                continue
+            }
+            if d := length(sub(poly.Vertices[face.Vertices[a]], poly.Vertices[face.Vertices[c]])); d < bestLen {
+                best, bestLen = k, d
+            }
+        }
+        if best < 0 {
+            break
+        }
+        a, b, c := idx[(best+n-1)%n], idx[best], idx[(best+1)%n]
+        tris = append(tris, []int{a, b, c})
+        idx = append(idx[:best], idx[best+1:]...)
+    }
+    for k := 1; k+1 < len(idx); k++ {
+        tris = append(tris, []int{idx[0], idx[k], idx[k+1]})
+    }
+    return tris
+}
+
+// cross2 is the z component of (b-a) x (c-b): positive for a left turn.
+func cross2(a, b, c Point2) float64 {
+    return (b.X-a.X)*(c.Y-b.Y) - (b.Y-a.Y)*(c.X-b.X)
+}
+
+// pointInTriangle reports whether p is inside or on the CCW triangle abc.
+func pointInTriangle(p, a, b, c Point2) bool {
+    return cross2(a, b, p) >= 0 && cross2(b, c, p) >= 0 && cross2(c, a, p) >= 0
+}
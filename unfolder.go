@@ -23,6 +23,13 @@ type Point2 struct {
 // Face holds indices to vertices in the Polyhedron (in CCW order).
 type Face struct {
     Vertices []int
+    // UVs are texture coordinates, one per vertex (0..1, V up as in OBJ
+    // files), or nil if the face isn't textured.
+    UVs []Point2
+    // Holes are cutouts in the face, each a loop of vertex indices wound
+    // clockwise (the other way to Vertices); nil for a plain face. Their
+    // edges are always cut, see holes.go.
+    Holes [][]int
 }
 
 // Polyhedron holds the 3D model data: a set of vertices and faces.
@@ -30,6 +37,7 @@ type Polyhedron struct {
     Vertices []Vector3
     Faces    []Face
     Name     string
+    Units    string // length unit of the vertices: "mm", "cm", "m", "in", or "" if unknown
 }
 
 // Adjacency info: for each face, which other faces are adjacent and by which edge?
@@ -39,9 +47,11 @@ type FaceNeighbor struct {
     ThisFaceEdge  [2]int  // which edge in "this" face corresponds to SharedEdge
 }
 
-// FaceAdjacency stores adjacency for each face: a list of neighbors
+// FaceAdjacency stores adjacency for each face: a list of neighbors.
+// Neighbors is indexed by face and each list is in the face's edge order, so
+// everything walking it (and every net built from it) is deterministic.
 type FaceAdjacency struct {
-    Neighbors map[int][]FaceNeighbor
+    Neighbors [][]FaceNeighbor
 }
 
 // -----------------------------
@@ -49,11 +59,12 @@ type FaceAdjacency struct {
 // -----------------------------
 
 // BuildFaceAdjacency finds which faces share edges. We assume manifold geometry:
-// each edge belongs to either 1 or 2 faces.
+// each edge belongs to either 1 or 2 faces. Only face outlines count: the
+// edges of Face.Holes never join two faces.
 func BuildFaceAdjacency(poly Polyhedron) (*FaceAdjacency, error) {
     nFaces := len(poly.Faces)
     adj := FaceAdjacency{
-        Neighbors: make(map[int][]FaceNeighbor, nFaces),
+        Neighbors: make([][]FaceNeighbor, nFaces),
     }
     
     // Edge map: key = (minVertex, maxVertex), value = []faceIndex
@@ -71,31 +82,23 @@ func BuildFaceAdjacency(poly Polyhedron) (*FaceAdjacency, error) {
         }
     }
 
-    // Now, each entry in edgeMap with 2 faces means those faces share that edge
-    for edge, faceList := range edgeMap {
-        if len(faceList) == 2 {
-            f0 := faceList[0]
-            f1 := faceList[1]
-
-            // figure out the local edge indices in each face
-            face0Edge, err0 := findEdgeInFace(poly.Faces[f0], edge)
-            face1Edge, err1 := findEdgeInFace(poly.Faces[f1], edge)
-            if err0 != nil || err1 != nil {
-                // Should not happen if geometry is consistent
+    // Now walk every face's edges in order: an edge with 2 faces links them
+    for fIdx, face := range poly.Faces {
+        vCount := len(face.Vertices)
+        for i := 0; i < vCount; i++ {
+            edge := sortPair(face.Vertices[i], face.Vertices[(i+1)%vCount])
+            faceList := edgeMap[edge]
+            if len(faceList) != 2 {
                 continue
             }
-
-            // add adjacency entry for face0
-            adj.Neighbors[f0] = append(adj.Neighbors[f0], FaceNeighbor{
-                FaceIndex:    f1,
-                SharedEdge:   edge,
-                ThisFaceEdge: face0Edge,
-            })
-            // add adjacency entry for face1
-            adj.Neighbors[f1] = append(adj.Neighbors[f1], FaceNeighbor{
-                FaceIndex:    f0,
+            other := faceList[0]
+            if other == fIdx {
+                other = faceList[1]
+            }
+            adj.Neighbors[fIdx] = append(adj.Neighbors[fIdx], FaceNeighbor{
+                FaceIndex:    other,
                 SharedEdge:   edge,
-                ThisFaceEdge: face1Edge,
+                ThisFaceEdge: [2]int{i, (i + 1) % vCount},
             })
         }
     }
@@ -169,35 +172,156 @@ func BuildFaceSpanningTree(adj *FaceAdjacency, rootFace int, nFaces int) []int {
 // the 2D coordinates of that face's vertices. We'll do the latter for simplicity.
 
 type Face2D struct {
-    Vertices []Point2 // 2D coordinates of each vertex of this face
+    Vertices []Point2   // 2D coordinates of each vertex of this face
+    UVs      []Point2   // texture coordinates of each vertex, from the mesh face (nil if untextured)
+    Holes    [][]Point2 // 2D coordinates of each hole loop of the mesh face (nil if it has none)
 }
 
-// UnfoldResult holds the final 2D positions for each vertex in the mesh
+// UnfoldResult holds the final 2D position of every face corner in the mesh
 // plus face-level info. You can also store "cuts" if needed.
 type UnfoldResult struct {
-    Vertex2D  []Point2
     Face2D    []Face2D
+    VertexInstances []VertexInstance // every placed face corner, see Instances
     SpanningTree []int // parent array from BFS
+    FoldEdges []NetEdge // edges the net folds along (one per spanning tree edge)
+    CutEdges  []NetEdge // every other edge: cut lines, including the mesh boundary
+    Overlaps  []OverlapPair // colliding faces, only set if UnfoldOptions.DetectOverlaps
+    DoubleWalls []DoubleWall // fold-over face copies, only set if UnfoldOptions.DoubleWalled
+    Tabs      []TabPolygon // glue tabs, see GenerateTabs
+    Memory    *MemoryReport // per-stage memory usage, only set if UnfoldOptions.ReportMemory
+    Validation *ValidationReport // only set if UnfoldOptions.ValidateOnly
+    Side      NetSide // surface the net shows, from UnfoldOptions.Side
+    // Mesh is set if the unfold split faces (NonPlanarTriangulate): the net
+    // is of this mesh, so pass it rather than the original wherever a mesh
+    // goes with the result. FaceOrigin maps its faces to the original ones,
+    // ready for the exporters' FaceGroups.
+    Mesh       *Polyhedron
+    FaceOrigin []int
 }
 
 // UnfoldMesh flattens the polyhedron into a single connected net, ignoring overlaps.
 // - rootFace is the index of the face we place first in 2D
 func UnfoldMesh(poly Polyhedron, rootFace int) (*UnfoldResult, error) {
+    return UnfoldMeshWithOptions(poly, rootFace, UnfoldOptions{})
+}
+
+// UnfoldMeshWithOptions is UnfoldMesh with extra knobs (see UnfoldOptions).
+func UnfoldMeshWithOptions(poly Polyhedron, rootFace int, opts UnfoldOptions) (result *UnfoldResult, err error) {
+    at := &progress{stage: "planarity", face: -1}
+    defer at.recover(&err)
+    if opts.ValidateOnly {
+        report := Preflight(poly, rootFace, opts)
+        return &UnfoldResult{Validation: report}, report.Err()
+    }
     if len(poly.Faces) == 0 {
         return nil, errors.New("polyhedron has no faces")
     }
 
-    // 1) Build adjacency
+    // 0) memory, planarity, 1) adjacency and the mesh checks opts asks for
+    m, err := prepareMesh(poly, opts, at)
+    if err != nil {
+        return nil, err
+    }
+    poly, adjacency, mem := m.poly, m.adjacency, m.mem
+
+    // 2) Spanning tree (which edges are "cuts"), BFS unless a strategy is set
+    at.stage = "spanning-tree"
+    var parent []int
+    if opts.Strategy != nil {
+        parent, rootFace, err = opts.Strategy.SpanningTree(poly, adjacency, rootFace)
+        if err != nil {
+            return nil, fmt.Errorf("error building spanning tree: %v", err)
+        }
+        // strategies can be the caller's own, check before laying out
+        if _, err := treeRoot(poly, adjacency, parent, rootFace); err != nil {
+            return nil, err
+        }
+    } else {
+        if err := checkRoot(poly, rootFace); err != nil {
+            return nil, err
+        }
+        parent = BuildFaceSpanningTree(adjacency, rootFace, len(poly.Faces))
+    }
+    if !opts.Constraints.empty() {
+        if parent, err = constrainTree(poly, adjacency, rootFace, parent, opts.Constraints); err != nil {
+            return nil, err
+        }
+    }
+    mem.mark("spanning-tree")
+
+    result, err = unfoldAlongTree(poly, adjacency, rootFace, parent, opts, mem)
+    if err == nil && m.split != nil {
+        result.Mesh, result.FaceOrigin = m.split, m.origin
+    }
+    return result, err
+}
+
+// preparedMesh is a mesh that passed prepareMesh: poly is the one to unfold,
+// the split one (also in split, with origin) if warped faces were split. mem
+// is set if opts.ReportMemory, with the adjacency stage marked.
+type preparedMesh struct {
+    poly      Polyhedron
+    adjacency *FaceAdjacency
+    split     *Polyhedron
+    origin    []int
+    mem       *memoryTracker
+}
+
+// prepareMesh runs the checks every way of unfolding starts with: the memory
+// estimate (before and after splitting warped faces, which copies the mesh),
+// planarity, adjacency, and opts.RequireManifold and opts.Components. at
+// follows along for the panic report.
+func prepareMesh(poly Polyhedron, opts UnfoldOptions, at *progress) (*preparedMesh, error) {
+    // refuse up front if the estimate is over budget, before allocating
+    // anything big
+    estimate := EstimateMemory(poly)
+    if opts.MemoryLimit > 0 && estimate > opts.MemoryLimit {
+        return nil, fmt.Errorf("%w: estimated %d bytes, limit %d", ErrMemoryLimit, estimate, opts.MemoryLimit)
+    }
+    at.stage = "planarity"
+    split, origin, err := checkPlanarity(poly, opts)
+    if err != nil {
+        return nil, err
+    }
+    if split != nil {
+        poly = *split
+        estimate = EstimateMemory(poly)
+        if opts.MemoryLimit > 0 && estimate > opts.MemoryLimit {
+            return nil, fmt.Errorf("%w: estimated %d bytes with warped faces split, limit %d", ErrMemoryLimit, estimate, opts.MemoryLimit)
+        }
+    }
+    var mem *memoryTracker
+    if opts.ReportMemory {
+        mem = newMemoryTracker(estimate)
+    }
+
+    at.stage = "adjacency"
     adjacency, err := BuildFaceAdjacency(poly)
     if err != nil {
         return nil, fmt.Errorf("error building adjacency: %v", err)
     }
+    mem.mark("adjacency")
+    if opts.RequireManifold {
+        if err := nonManifoldError(poly); err != nil {
+            return nil, err
+        }
+    }
+    if opts.Components != ComponentsRootOnly {
+        if comps := faceComponents(adjacency, len(poly.Faces)); len(comps) > 1 {
+            return nil, disconnectedError(comps)
+        }
+    }
+    return &preparedMesh{poly: poly, adjacency: adjacency, split: split, origin: origin, mem: mem}, nil
+}
 
+// unfoldAlongTree lays out the faces along any spanning tree (parent array)
+// and runs the optional post-processing passes. It's shared by every way of
+// picking the tree. A panic comes back as an ErrInternal with the face being
+// placed, whoever the caller is.
+func unfoldAlongTree(poly Polyhedron, adjacency *FaceAdjacency, rootFace int, parent []int, opts UnfoldOptions, mem *memoryTracker) (result *UnfoldResult, err error) {
+    at := &progress{stage: "placement", face: rootFace}
+    defer at.recover(&err)
     nFaces := len(poly.Faces)
-    nVerts := len(poly.Vertices)
-
-    // 2) BFS spanning tree (which edges are "cuts")
-    parent := BuildFaceSpanningTree(adjacency, rootFace, nFaces)
 
     // We'll keep track of whether each face is "placed" in 2D
     placed := make([]bool, nFaces)
@@ -205,20 +329,19 @@ func UnfoldMesh(poly Polyhedron, rootFace int) (*UnfoldResult, error) {
 
     // Face2D array
     face2Ds := make([]Face2D, nFaces)
-    // We'll also store a global 2D coordinate for each vertex
-    // (This is simplistic: if a vertex belongs to multiple faces,
-    // they might not coincide if there's overlap or non-developable geometry,
-    // but let's ignore that for now.)
-    vertex2D := make([]Point2, nVerts)
 
     // 3) Place the root face in 2D
-    err = placeRootFace(poly, rootFace, &face2Ds[rootFace], vertex2D)
+    err = placeRootFace(poly, rootFace, &face2Ds[rootFace])
+    if err == nil && !finitePoints(face2Ds[rootFace].Vertices) {
+        err = &ErrDegenerateFace{Face: rootFace, Reason: "placed at non-finite coordinates"}
+    }
     if err != nil {
-        return nil, fmt.Errorf("failed to place root face: %v", err)
+        return nil, fmt.Errorf("failed to place root face: %w", err)
     }
 
     // BFS queue
     queue := []int{rootFace}
+    var folds []NetEdge
 
     for len(queue) > 0 {
         fIdx := queue[0]
@@ -230,32 +353,88 @@ func UnfoldMesh(poly Polyhedron, rootFace int) (*UnfoldResult, error) {
             // If that face's parent is the current face => this is the BFS tree edge
             if parent[nfIdx] == fIdx && !placed[nfIdx] {
                 // place neighbor face in 2D
-                err = placeAdjacentFace(poly, fIdx, nfIdx, &face2Ds[nfIdx], vertex2D, &nbr)
+                at.face = nfIdx
+                err = placeAdjacentFace(poly, fIdx, nfIdx, &face2Ds[fIdx], &face2Ds[nfIdx], &nbr, opts.Anchoring)
+                if err == nil && !finitePoints(face2Ds[nfIdx].Vertices) {
+                    // NaN from near-degenerate geometry would only blow up
+                    // later, in the overlap grid or an exporter
+                    err = &ErrDegenerateFace{Face: nfIdx, Reason: "placed at non-finite coordinates"}
+                }
                 if err != nil {
-                    return nil, fmt.Errorf("failed to place face %d adjacent to %d: %v", nfIdx, fIdx, err)
+                    return nil, fmt.Errorf("failed to place face %d adjacent to %d: %w", nfIdx, fIdx, err)
                 }
                 placed[nfIdx] = true
                 queue = append(queue, nfIdx)
+
+                childEdge, _ := findEdgeInFace(poly.Faces[nfIdx], nbr.SharedEdge)
+                folds = append(folds, NetEdge{
+                    Vertices: nbr.SharedEdge,
+                    FaceA:    fIdx,
+                    EdgeA:    nbr.ThisFaceEdge[0],
+                    FaceB:    nfIdx,
+                    EdgeB:    childEdge[0],
+                })
             }
         }
     }
+    at.stage, at.face = "post-processing", -1
+    if err := applyMaterial(poly, face2Ds, folds, opts.Material); err != nil {
+        return nil, err
+    }
+    return finishResult(poly, face2Ds, parent, folds, opts, mem), nil
+}
+
+// finitePoints reports whether every point has finite coordinates.
+func finitePoints(pts []Point2) bool {
+    for _, p := range pts {
+        if math.IsNaN(p.X) || math.IsNaN(p.Y) || math.IsInf(p.X, 0) || math.IsInf(p.Y, 0) {
+            return false
+        }
+    }
+    return true
+}
+
+// finishResult turns a finished placement into an UnfoldResult: cut edges,
+// vertex instances and the optional post-processing passes.
+func finishResult(poly Polyhedron, face2Ds []Face2D, parent []int, folds []NetEdge, opts UnfoldOptions, mem *memoryTracker) *UnfoldResult {
+    sortNetEdges(folds)
+    cuts := classifyEdges(poly, folds)
+    mem.mark("placement")
 
-    return &UnfoldResult{
-        Vertex2D:     vertex2D,
-        Face2D:       face2Ds,
+    attachHoles(poly, face2Ds)
+    if opts.Side == SideInterior {
+        mirrorFaces(face2Ds)
+    }
+    attachUVs(poly, face2Ds)
+    result := &UnfoldResult{
+        Face2D:          face2Ds,
+        VertexInstances: vertexInstances(poly, face2Ds),
         SpanningTree: parent,
-    }, nil
+        FoldEdges:    folds,
+        CutEdges:     cuts,
+        Side:         opts.Side,
+    }
+    if opts.DetectOverlaps {
+        result.Overlaps = DetectOverlaps(result)
+        mem.mark("overlaps")
+    }
+    if opts.DoubleWalled {
+        AddDoubleWalls(result)
+        mem.mark("double-walls")
+    }
+    result.Memory = mem.report()
+    return result
 }
 
 // placeRootFace simply puts the root face in the plane so that:
 // - The first vertex is at (0,0)
 // - The second vertex is at (edgeLength, 0)
 // - The rest of the vertices are placed accordingly in the plane of the face
-func placeRootFace(poly Polyhedron, faceIdx int, face2D *Face2D, vertex2D []Point2) error {
+func placeRootFace(poly Polyhedron, faceIdx int, face2D *Face2D) error {
     face := poly.Faces[faceIdx]
     vCount := len(face.Vertices)
     if vCount < 3 {
-        return errors.New("face has fewer than 3 vertices")
+        return &ErrDegenerateFace{Face: faceIdx, Reason: "fewer than 3 vertices"}
     }
 
     // Let's define a local 3D coordinate system for this face:
@@ -277,4 +456,137 @@ func placeRootFace(poly Polyhedron, faceIdx int, face2D *Face2D, vertex2D []Poin
     // yAxis is in the plane: cross the face normal with xAxis or something similar
     normal := cross(e01, e02)
     normal = normalize(normal)
-    yAxis := cros
+    yAxis := cross(normal, xAxis)
+
+    // 3) project every vertex of the face into (xAxis, yAxis)
+    face2D.Vertices = make([]Point2, vCount)
+    for i, vIdx := range face.Vertices {
+        d := sub(poly.Vertices[vIdx], p0)
+        p := Point2{X: dot(d, xAxis), Y: dot(d, yAxis)}
+        face2D.Vertices[i] = p
+    }
+
+    return nil
+}
+
+// placeAdjacentFace lays out faceIdx next to the already placed parentIdx, hinged
+// on the shared edge. The face is first flattened in its own plane (like the root),
+// then rotated + translated so the shared edge lands on the parent's copy of it.
+// The hinge is read from the parent's own Face2D (via nbr.ThisFaceEdge): a vertex
+// on a cut has a different position in every branch of the tree.
+// With AnchorBestFit the hinged face is then refitted over all shared vertices.
+func placeAdjacentFace(poly Polyhedron, parentIdx, faceIdx int, parent2D, face2D *Face2D, nbr *FaceNeighbor, anchor AnchorMode) error {
+    // shared edge endpoints (global vertex indices) and where the parent put them
+    pFace := poly.Faces[parentIdx]
+    i0, i1 := nbr.ThisFaceEdge[0], nbr.ThisFaceEdge[1]
+    vA, vB := pFace.Vertices[i0], pFace.Vertices[i1]
+    a2, b2 := parent2D.Vertices[i0], parent2D.Vertices[i1]
+
+    pts, err := hingeFace(poly, faceIdx, vA, vB, a2, b2)
+    if err != nil {
+        return fmt.Errorf("%w (parent face %d)", err, parentIdx)
+    }
+    if anchor == AnchorBestFit {
+        pts = bestFitAnchor(poly, parentIdx, faceIdx, parent2D.Vertices, pts)
+    }
+    face2D.Vertices = pts
+    return nil
+}
+
+// hingeFace flattens faceIdx in its own plane and moves it so that its vertices
+// vA and vB land on a2 and b2. Returns the 2D position of each face vertex.
+func hingeFace(poly Polyhedron, faceIdx, vA, vB int, a2, b2 Point2) ([]Point2, error) {
+    face := poly.Faces[faceIdx]
+    vCount := len(face.Vertices)
+    if vCount < 3 {
+        return nil, &ErrDegenerateFace{Face: faceIdx, Reason: "fewer than 3 vertices"}
+    }
+
+    // local frame for this face: origin at A, x-axis along A->B
+    pA := poly.Vertices[vA]
+    pB := poly.Vertices[vB]
+    eAB := sub(pB, pA)
+    if length(eAB) == 0 {
+        return nil, &ErrDegenerateFace{Face: faceIdx, Reason: "shared edge has zero length"}
+    }
+    xAxis := normalize(eAB)
+    normal := normalize(faceNormal(poly, face))
+    yAxis := cross(normal, xAxis)
+
+    dx := b2.X - a2.X
+    dy := b2.Y - a2.Y
+    l2 := math.Hypot(dx, dy)
+    if l2 == 0 {
+        return nil, errors.New("shared edge collapsed in 2D")
+    }
+    cosT := dx / l2
+    sinT := dy / l2
+
+    pts := make([]Point2, vCount)
+    for i, vIdx := range face.Vertices {
+        d := sub(poly.Vertices[vIdx], pA)
+        lx := dot(d, xAxis)
+        ly := dot(d, yAxis)
+        pts[i] = Point2{
+            X: a2.X + lx*cosT - ly*sinT,
+            Y: a2.Y + lx*sinT + ly*cosT,
+        }
+    }
+    return pts, nil
+}
+
+// faceNormal returns the (unnormalized) Newell normal of a face, which is robust
+// for polygons whose first three vertices happen to be collinear.
+func faceNormal(poly Polyhedron, face Face) Vector3 {
+    var n Vector3
+    vCount := len(face.Vertices)
+    for i := 0; i < vCount; i++ {
+        a := poly.Vertices[face.Vertices[i]]
+        b := poly.Vertices[face.Vertices[(i+1)%vCount]]
+        n.X += (a.Y - b.Y) * (a.Z + b.Z)
+        n.Y += (a.Z - b.Z) * (a.X + b.X)
+        n.Z += (a.X - b.X) * (a.Y + b.Y)
+    }
+    return n
+}
+
+// -----------------------------
+//   Vector helpers
+// -----------------------------
+
+func sub(a, b Vector3) Vector3 {
+    return Vector3{a.X - b.X, a.Y - b.Y, a.Z - b.Z}
+}
+
+func add(a, b Vector3) Vector3 {
+    return Vector3{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
+}
+
+func scale(a Vector3, s float64) Vector3 {
+    return Vector3{a.X * s, a.Y * s, a.Z * s}
+}
+
+func dot(a, b Vector3) float64 {
+    return a.X*b.X + a.Y*b.Y + a.Z*b.Z
+}
+
+func cross(a, b Vector3) Vector3 {
+    return Vector3{
+        a.Y*b.Z - a.Z*b.Y,
+        a.Z*b.X - a.X*b.Z,
+        a.X*b.Y - a.Y*b.X,
+    }
+}
+
+func length(a Vector3) float64 {
+    return math.Sqrt(dot(a, a))
+}
+
+// normalize returns a unit vector in the direction of a (or a itself if it's zero)
+func normalize(a Vector3) Vector3 {
+    l := length(a)
+    if l == 0 {
+        return a
+    }
+    return Vector3{a.X / l, a.Y / l, a.Z / l}
+}
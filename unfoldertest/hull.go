@@ -0,0 +1,82 @@
+package unfoldertest
+
+import (
+    "github.com/yourusername/unfolder"
+)
+
+// hullEps is how far (on the unit sphere) a point must be in front of a face
+// to see it.
+const hullEps = 1e-9
+
+// convexHull returns the triangulated convex hull of pts (incremental
+// algorithm, O(n²)), with outward CCW faces and only the hull vertices. ok is
+// false if the points are too close to coplanar to start a hull.
+func convexHull(pts []unfolder.Vector3) (unfolder.Polyhedron, bool) {
+    if len(pts) < 4 {
+        return unfolder.Polyhedron{}, false
+    }
+    // start from the first 4 points; flip the tetrahedron to face outward
+    a, b, c, d := 0, 1, 2, 3
+    vol := orient(pts[a], pts[b], pts[c], pts[d])
+    if vol > -hullEps && vol < hullEps {
+        return unfolder.Polyhedron{}, false
+    }
+    if vol > 0 {
+        b, c = c, b
+    }
+    faces := [][3]int{{a, b, c}, {a, c, d}, {a, d, b}, {b, d, c}}
+
+    for p := 4; p < len(pts); p++ {
+        visible := make(map[[2]int]bool)
+        kept := faces[:0:0]
+        for _, f := range faces {
+            if orient(pts[f[0]], pts[f[1]], pts[f[2]], pts[p]) > hullEps {
+                visible[[2]int{f[0], f[1]}] = true
+                visible[[2]int{f[1], f[2]}] = true
+                visible[[2]int{f[2], f[0]}] = true
+            } else {
+                kept = append(kept, f)
+            }
+        }
+        if len(visible) == 0 {
+            continue // inside
+        }
+        // horizon: visible edges whose twin belongs to a hidden face
+        for e := range visible {
+            if !visible[[2]int{e[1], e[0]}] {
+                kept = append(kept, [3]int{e[0], e[1], p})
+            }
+        }
+        faces = kept
+    }
+
+    // keep only hull vertices, in input order
+    remap := make([]int, len(pts))
+    for i := range remap {
+        remap[i] = -1
+    }
+    for _, f := range faces {
+        for _, v := range f {
+            remap[v] = 0
+        }
+    }
+    var poly unfolder.Polyhedron
+    for i, m := range remap {
+        if m == 0 {
+            remap[i] = len(poly.Vertices)
+            poly.Vertices = append(poly.Vertices, pts[i])
+        }
+    }
+    for _, f := range faces {
+        poly.Faces = append(poly.Faces, unfolder.Face{Vertices: []int{remap[f[0]], remap[f[1]], remap[f[2]]}})
+    }
+    return poly, true
+}
+
+// orient is positive when p is in front of (outside) the CCW triangle abc.
+func orient(a, b, c, p unfolder.Vector3) float64 {
+    ux, uy, uz := b.X-a.X, b.Y-a.Y, b.Z-a.Z
+    vx, vy, vz := c.X-a.X, c.Y-a.Y, c.Z-a.Z
+    wx, wy, wz := p.X-a.X, p.Y-a.Y, p.Z-a.Z
+    return wx*(uy*vz-uz*vy) + wy*(uz*vx-ux*vz) + wz*(ux*vy-uy*vx)
+}
@@ -0,0 +1,250 @@
+// Package unfoldertest has random generators for property-based testing of
+// unfolder code, new spanning strategies in particular. The generators
+// implement testing/quick's Generator interface, so they can be used directly
+// as arguments of quick.Check properties:
+//
+//	f := func(t unfoldertest.SpanningTree) bool {
+//	    res, err := unfolder.UnfoldMeshWithOptions(t.Poly, t.Root,
+//	        unfolder.UnfoldOptions{Strategy: t.Strategy()})
+//	    return err == nil && unfoldertest.CheckRigid(t.Poly, res, 1e-9) == nil
+//	}
+//	quick.Check(f, nil)
+//
+// CheckTree and CheckRigid test the invariants every unfold should keep.
+package unfoldertest
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "math/rand"
+    "reflect"
+
+    "github.com/yourusername/unfolder"
+)
+
+// maxHullPoints caps the vertex count of generated polyhedra; quick's default
+// size is 50, which keeps properties fast.
+const maxHullPoints = 200
+
+// -----------------------------
+//   Generators
+// -----------------------------
+
+// ConvexPolyhedron is a random convex polyhedron: the convex hull of 4 to
+// size+4 random points on the unit sphere, with triangular faces wound CCW
+// seen from outside.
+type ConvexPolyhedron struct {
+    unfolder.Polyhedron
+}
+
+// Generate implements quick.Generator.
+func (ConvexPolyhedron) Generate(r *rand.Rand, size int) reflect.Value {
+    return reflect.ValueOf(ConvexPolyhedron{RandomConvex(r, size)})
+}
+
+// SpanningTree is a random convex polyhedron together with a spanning tree of
+// its faces drawn uniformly from all spanning trees, and a random root.
+type SpanningTree struct {
+    Poly   unfolder.Polyhedron
+    Root   int
+    Parent []int
+}
+
+// Generate implements quick.Generator.
+func (SpanningTree) Generate(r *rand.Rand, size int) reflect.Value {
+    poly := RandomConvex(r, size)
+    adj, err := unfolder.BuildFaceAdjacency(poly)
+    if err != nil {
+        panic(err) // BuildFaceAdjacency doesn't fail on hull output
+    }
+    root := r.Intn(len(poly.Faces))
+    return reflect.ValueOf(SpanningTree{Poly: poly, Root: root, Parent: RandomTree(r, adj, len(poly.Faces), root)})
+}
+
+// Strategy returns a SpanningStrategy that always answers with this tree, so
+// it can be unfolded with UnfoldMeshWithOptions.
+func (t SpanningTree) Strategy() unfolder.SpanningStrategy {
+    return fixedTree{parent: t.Parent, root: t.Root}
+}
+
+type fixedTree struct {
+    parent []int
+    root   int
+}
+
+func (f fixedTree) SpanningTree(poly unfolder.Polyhedron, _ *unfolder.FaceAdjacency, _ int) ([]int, int, error) {
+    if len(f.parent) != len(poly.Faces) {
+        return nil, 0, fmt.Errorf("tree has %d faces, polyhedron %d", len(f.parent), len(poly.Faces))
+    }
+    return append([]int(nil), f.parent...), f.root, nil
+}
+
+// RandomStrategy is a SpanningStrategy that picks a uniformly random spanning
+// tree of the faces reachable from the root. Handy as a baseline: a strategy
+// under test should do at least as well on average.
+type RandomStrategy struct {
+    Rand *rand.Rand // nil uses a fixed seed
+}
+
+// SpanningTree implements unfolder.SpanningStrategy.
+func (s RandomStrategy) SpanningTree(poly unfolder.Polyhedron, adj *unfolder.FaceAdjacency, rootFace int) ([]int, int, error) {
+    if rootFace < 0 || rootFace >= len(poly.Faces) {
+        return nil, 0, fmt.Errorf("root face %d out of range", rootFace)
+    }
+    r := s.Rand
+    if r == nil {
+        r = rand.New(rand.NewSource(1))
+    }
+    return RandomTree(r, adj, len(poly.Faces), rootFace), rootFace, nil
+}
+
+// RandomConvex returns the convex hull of between 4 and size+4 random points
+// on the unit sphere.
+func RandomConvex(r *rand.Rand, size int) unfolder.Polyhedron {
+    n := 4
+    if size > 0 {
+        n += r.Intn(size + 1)
+    }
+    if n > maxHullPoints {
+        n = maxHullPoints
+    }
+    for {
+        pts := make([]unfolder.Vector3, n)
+        for i := 0; i < n; {
+            // normal deviates give a uniform direction
+            v := unfolder.Vector3{X: r.NormFloat64(), Y: r.NormFloat64(), Z: r.NormFloat64()}
+            if l := math.Sqrt(v.X*v.X + v.Y*v.Y + v.Z*v.Z); l > 1e-6 {
+                pts[i] = unfolder.Vector3{X: v.X / l, Y: v.Y / l, Z: v.Z / l}
+                i++
+            }
+        }
+        if poly, ok := convexHull(pts); ok {
+            poly.Name = fmt.Sprintf("random-hull-%d", len(poly.Vertices))
+            return poly
+        }
+        // (nearly) coplanar start, try new points
+    }
+}
+
+// RandomTree returns the parent array of a uniformly random spanning tree of
+// the faces reachable from root (Wilson's algorithm). Unreachable faces get
+// parent -1, like the root.
+func RandomTree(r *rand.Rand, adj *unfolder.FaceAdjacency, nFaces, root int) []int {
+    return unfolder.RandomSpanningTree(r, adj, nFaces, root)
+}
+
+// -----------------------------
+//   Invariants
+// -----------------------------
+
+// CheckTree reports whether parent is a spanning tree of the face graph rooted
+// at root: unfolder.ValidateSpanningTree's checks (every parent link is a
+// shared edge, there are no cycles, and every face reachable from the root is
+// in the tree), with the root being root.
+func CheckTree(poly unfolder.Polyhedron, parent []int, root int) error {
+    n := len(poly.Faces)
+    if len(parent) != n {
+        return fmt.Errorf("parent array has %d entries, want %d", len(parent), n)
+    }
+    if root < 0 || root >= n {
+        return fmt.Errorf("root %d out of range", root)
+    }
+    if parent[root] != -1 {
+        return fmt.Errorf("root %d has parent %d", root, parent[root])
+    }
+    adj, err := unfolder.BuildFaceAdjacency(poly)
+    if err != nil {
+        return err
+    }
+    if err := unfolder.ValidateSpanningTree(poly, adj, parent); err != nil {
+        return err
+    }
+    for f, p := range parent {
+        if p == -1 {
+            continue
+        }
+        g := f
+        for parent[g] != -1 {
+            g = parent[g]
+        }
+        if g != root {
+            return fmt.Errorf("tree is rooted at face %d, not %d", g, root)
+        }
+        return nil
+    }
+    // a tree of one face
+    if len(adj.Neighbors[root]) > 0 {
+        return fmt.Errorf("face %d is reachable from the root but not in the tree", adj.Neighbors[root][0].FaceIndex)
+    }
+    return nil
+}
+
+// CheckRigid reports whether the net is an isometric unfolding: every placed
+// face keeps its edge lengths and is not mirrored, and both faces of every fold
+// edge put its endpoints at the same place. tol is relative to the mesh size.
+func CheckRigid(poly unfolder.Polyhedron, res *unfolder.UnfoldResult, tol float64) error {
+    if res == nil {
+        return errors.New("nil unfold result")
+    }
+    if len(res.Face2D) != len(poly.Faces) {
+        return fmt.Errorf("result has %d faces, polyhedron %d", len(res.Face2D), len(poly.Faces))
+    }
+    scale := 0.0
+    for _, v := range poly.Vertices {
+        scale = math.Max(scale, math.Max(math.Abs(v.X), math.Max(math.Abs(v.Y), math.Abs(v.Z))))
+    }
+    eps := tol * math.Max(scale, 1)
+
+    for f, face := range poly.Faces {
+        pts := res.Face2D[f].Vertices
+        if len(pts) == 0 {
+            continue
+        }
+        if len(pts) != len(face.Vertices) {
+            return fmt.Errorf("face %d: %d points for %d vertices", f, len(pts), len(face.Vertices))
+        }
+        area := 0.0
+        for i := range pts {
+            j := (i + 1) % len(pts)
+            a, b := poly.Vertices[face.Vertices[i]], poly.Vertices[face.Vertices[j]]
+            want := math.Sqrt((a.X-b.X)*(a.X-b.X) + (a.Y-b.Y)*(a.Y-b.Y) + (a.Z-b.Z)*(a.Z-b.Z))
+            got := math.Hypot(pts[i].X-pts[j].X, pts[i].Y-pts[j].Y)
+            if math.Abs(got-want) > eps {
+                return fmt.Errorf("face %d: edge %d-%d is %g long in the net, %g in 3D", f, face.Vertices[i], face.Vertices[j], got, want)
+            }
+            area += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
+        }
+        if area < 0 {
+            return fmt.Errorf("face %d is mirrored in the net", f)
+        }
+    }
+
+    for _, e := range res.FoldEdges {
+        for _, v := range e.Vertices {
+            pa, okA := facePoint(poly, res, e.FaceA, v)
+            pb, okB := facePoint(poly, res, e.FaceB, v)
+            if !okA || !okB {
+                return fmt.Errorf("fold edge %v: faces %d and %d are not both placed", e.Vertices, e.FaceA, e.FaceB)
+            }
+            if d := math.Hypot(pa.X-pb.X, pa.Y-pb.Y); d > eps {
+                return fmt.Errorf("fold edge %v: vertex %d is %g apart on faces %d and %d", e.Vertices, v, d, e.FaceA, e.FaceB)
+            }
+        }
+    }
+    return nil
+}
+
+// facePoint returns where face f puts mesh vertex v in the net.
+func facePoint(poly unfolder.Polyhedron, res *unfolder.UnfoldResult, f, v int) (unfolder.Point2, bool) {
+    if f < 0 || f >= len(poly.Faces) {
+        return unfolder.Point2{}, false
+    }
+    pts := res.Face2D[f].Vertices
+    for i, fv := range poly.Faces[f].Vertices {
+        if fv == v && i < len(pts) {
+            return pts[i], true
+        }
+    }
+    return unfolder.Point2{}, false
+}
@@ -0,0 +1,161 @@
+package unfoldertest
+
+import (
+    "errors"
+    "math/rand"
+    "reflect"
+    "testing"
+    "testing/quick"
+
+    "github.com/yourusername/unfolder"
+)
+
+func quickConfig() *quick.Config {
+    return &quick.Config{MaxCount: 50, Rand: rand.New(rand.NewSource(1))}
+}
+
+// Any spanning tree of a convex polyhedron unfolds rigidly along exactly that
+// tree.
+func TestQuickSpanningTreeUnfoldsRigidly(t *testing.T) {
+    f := func(st SpanningTree) bool {
+        res, err := unfolder.UnfoldMeshWithOptions(st.Poly, st.Root, unfolder.UnfoldOptions{Strategy: st.Strategy()})
+        if err != nil {
+            t.Log(err)
+            return false
+        }
+        if !reflect.DeepEqual(res.SpanningTree, st.Parent) {
+            t.Logf("unfolded along %v, not %v", res.SpanningTree, st.Parent)
+            return false
+        }
+        if err := CheckTree(st.Poly, res.SpanningTree, st.Root); err != nil {
+            t.Log(err)
+            return false
+        }
+        if err := CheckRigid(st.Poly, res, 1e-9); err != nil {
+            t.Log(err)
+            return false
+        }
+        return true
+    }
+    if err := quick.Check(f, quickConfig()); err != nil {
+        t.Error(err)
+    }
+}
+
+// The built-in strategies all give trees that unfold rigidly.
+func TestQuickStrategies(t *testing.T) {
+    strategies := []struct {
+        name     string
+        strategy unfolder.SpanningStrategy
+    }{
+        {"breadth first", unfolder.BreadthFirst{}},
+        {"steepest edge", unfolder.SteepestEdge{}},
+        {"dihedral MST", unfolder.DihedralMST{}},
+        {"random", RandomStrategy{}},
+    }
+    for _, s := range strategies {
+        t.Run(s.name, func(t *testing.T) {
+            f := func(p ConvexPolyhedron, root uint) bool {
+                r := int(root % uint(len(p.Faces)))
+                res, err := unfolder.UnfoldMeshWithOptions(p.Polyhedron, r, unfolder.UnfoldOptions{Strategy: s.strategy})
+                if err != nil {
+                    t.Log(err)
+                    return false
+                }
+                if err := CheckTree(p.Polyhedron, res.SpanningTree, r); err != nil {
+                    t.Log(err)
+                    return false
+                }
+                if err := CheckRigid(p.Polyhedron, res, 1e-9); err != nil {
+                    t.Log(err)
+                    return false
+                }
+                return true
+            }
+            if err := quick.Check(f, quickConfig()); err != nil {
+                t.Error(err)
+            }
+        })
+    }
+}
+
+// The overlap-avoiding search either gives a rigid net without overlaps or
+// says why not.
+func TestQuickNonOverlapping(t *testing.T) {
+    f := func(p ConvexPolyhedron) bool {
+        res, err := unfolder.UnfoldMeshNonOverlapping(p.Polyhedron, 0, unfolder.UnfoldOptions{SearchBudget: 20000})
+        if errors.Is(err, unfolder.ErrSearchBudget) || errors.Is(err, unfolder.ErrOverlapUnavoidable) {
+            return true
+        }
+        if err != nil {
+            t.Log(err)
+            return false
+        }
+        if err := CheckRigid(p.Polyhedron, res, 1e-9); err != nil {
+            t.Log(err)
+            return false
+        }
+        if o := unfolder.DetectOverlaps(res); len(o) > 0 {
+            t.Logf("overlaps %v", o)
+            return false
+        }
+        return true
+    }
+    if err := quick.Check(f, quickConfig()); err != nil {
+        t.Error(err)
+    }
+}
+
+// CheckTree and CheckRigid catch broken trees and nets.
+func TestChecksCatchBrokenNets(t *testing.T) {
+    st := SpanningTree{}.Generate(rand.New(rand.NewSource(2)), 10).Interface().(SpanningTree)
+    res, err := unfolder.UnfoldMeshWithOptions(st.Poly, st.Root, unfolder.UnfoldOptions{Strategy: st.Strategy()})
+    if err != nil {
+        t.Fatal(err)
+    }
+    child := -1
+    for f, p := range st.Parent {
+        if p >= 0 {
+            child = f
+            break
+        }
+    }
+
+    tests := []struct {
+        name   string
+        mangle func(parent []int, res *unfolder.UnfoldResult)
+        tree   bool // CheckTree should fail, else CheckRigid
+    }{
+        {"cut off a face", func(parent []int, _ *unfolder.UnfoldResult) { parent[child] = -1 }, true},
+        {"short parent array", nil, true},
+        {"stretched face", func(_ []int, res *unfolder.UnfoldResult) { res.Face2D[child].Vertices[0].X += 0.5 }, false},
+        {"mirrored face", func(_ []int, res *unfolder.UnfoldResult) {
+            for i := range res.Face2D[child].Vertices {
+                res.Face2D[child].Vertices[i].X *= -1
+            }
+        }, false},
+        {"missing face", func(_ []int, res *unfolder.UnfoldResult) { res.Face2D = res.Face2D[1:] }, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            parent := append([]int(nil), st.Parent...)
+            broken := *res
+            broken.Face2D = make([]unfolder.Face2D, len(res.Face2D))
+            for f, fc := range res.Face2D {
+                broken.Face2D[f] = unfolder.Face2D{Vertices: append([]unfolder.Point2(nil), fc.Vertices...)}
+            }
+            if tt.mangle == nil {
+                parent = parent[1:]
+            } else {
+                tt.mangle(parent, &broken)
+            }
+            if tt.tree {
+                if CheckTree(st.Poly, parent, st.Root) == nil {
+                    t.Error("CheckTree passed")
+                }
+            } else if CheckRigid(st.Poly, &broken, 1e-9) == nil {
+                t.Error("CheckRigid passed")
+            }
+        })
+    }
+}
@@ -0,0 +1,211 @@
+package unfolder
+
+import (
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Units and scaling
+// -----------------------------
+
+// unitMM is the length of each supported unit in millimetres.
+var unitMM = map[string]float64{
+    "mm": 1,
+    "cm": 10,
+    "m":  1000,
+    "in": 25.4,
+    "pt": 25.4 / 72,
+    "px": 25.4 / 96,
+}
+
+// UnitFactor returns what a length in from units is multiplied by to get it
+// in to units, e.g. 10 from "cm" to "mm". Units are "mm", "cm", "m", "in",
+// "pt" or "px".
+func UnitFactor(from, to string) (float64, error) {
+    f, ok := unitMM[from]
+    if !ok {
+        return 0, fmt.Errorf("unknown unit %q", from)
+    }
+    t, ok := unitMM[to]
+    if !ok {
+        return 0, fmt.Errorf("unknown unit %q", to)
+    }
+    return f / t, nil
+}
+
+// UnitGuess is what InferUnits makes of a model's units.
+type UnitGuess struct {
+    // Units are the units the model is most likely in: the file's if they
+    // give a sensible size, else "mm", "cm" or "m" from the size alone.
+    Units string `json:"units"`
+    // File is what the file said, "" if nothing.
+    File string `json:"file,omitempty"`
+    // Plausible is whether the model comes out between 2 cm and 3 m across
+    // in Units, the range of paper models.
+    Plausible bool `json:"plausible"`
+    // Warning says what looks wrong, for the user: no units in the file and
+    // the size suggests something other than mm (which the exporters assume),
+    // or units in the file that make the model absurdly big or small. "" if
+    // all is well.
+    Warning string `json:"warning,omitempty"`
+}
+
+// InferUnits guesses the units poly is in, since a model in the wrong units
+// makes a net ten or a thousand times too big or small. A model whose
+// largest side is 20 or more is taken to be in mm, 2 to 20 in cm and below
+// that in m; inches can't be told from cm by size, so only the file (or the
+// user) can say a model is in inches. Units in the file win if they give
+// a size between 2 cm and 3 m.
+//
+// To convert, set poly.Units to the guess: the exporters scale by it.
+func InferUnits(poly Polyhedron) UnitGuess {
+    g := UnitGuess{File: poly.Units}
+    lo, hi := boundingBox(poly.Vertices)
+    d := math.Max(hi.X-lo.X, math.Max(hi.Y-lo.Y, hi.Z-lo.Z))
+    if len(poly.Vertices) == 0 || !(d > 0) {
+        g.Units = poly.Units
+        if g.Units == "" {
+            g.Units = "mm"
+        }
+        g.Warning = "the model has no size"
+        return g
+    }
+    plausible := func(u string) bool {
+        mm := d * unitMM[u]
+        return mm >= 20 && mm <= 3000
+    }
+
+    bySize := "m"
+    switch {
+    case d >= 20:
+        bySize = "mm"
+    case d >= 2:
+        bySize = "cm"
+    }
+    if _, ok := unitMM[poly.Units]; ok {
+        if plausible(poly.Units) {
+            g.Units, g.Plausible = poly.Units, true
+            return g
+        }
+        g.Units, g.Plausible = bySize, plausible(bySize)
+        g.Warning = fmt.Sprintf("the file says %s, making the model %s across; in %s it would be %s",
+            poly.Units, formatLength(d*unitMM[poly.Units]), bySize, formatLength(d*unitMM[bySize]))
+        return g
+    }
+    g.Units, g.Plausible = bySize, plausible(bySize)
+    switch {
+    case poly.Units != "":
+        g.Warning = fmt.Sprintf("unknown units %q in the file; at %.4g across the model is most likely in %s", poly.Units, d, bySize)
+    case !g.Plausible:
+        g.Warning = fmt.Sprintf("no units in the file, and at %.4g across the model is an odd size in any unit", d)
+    case bySize != "mm":
+        g.Warning = fmt.Sprintf("no units in the file; at %.4g across the model is most likely in %s, as mm it would be only %s",
+            d, bySize, formatLength(d))
+    }
+    return g
+}
+
+// formatLength writes a length in mm in the unit that suits it best.
+func formatLength(mm float64) string {
+    switch {
+    case mm >= 1e6:
+        return fmt.Sprintf("%.3g km", mm/1e6)
+    case mm >= 1000:
+        return fmt.Sprintf("%.3g m", mm/1000)
+    case mm >= 10:
+        return fmt.Sprintf("%.3g cm", mm/10)
+    }
+    return fmt.Sprintf("%.3g mm", mm)
+}
+
+// exportScale is the default Scale of the exporters: mesh units to out units
+// if the mesh says what its units are, 1 otherwise.
+func exportScale(mesh *Polyhedron, out string) float64 {
+    if mesh == nil || mesh.Units == "" {
+        return 1
+    }
+    f, err := UnitFactor(mesh.Units, out)
+    if err != nil {
+        return 1
+    }
+    return f
+}
+
+// Scale returns a copy of result with every 2D coordinate multiplied by
+// factor. Topology (tree, folds, cuts, overlaps) is copied unchanged.
+func Scale(result *UnfoldResult, factor float64) *UnfoldResult {
+    if result == nil {
+        return nil
+    }
+    scalePts := func(pts []Point2) []Point2 {
+        if pts == nil {
+            return nil
+        }
+        out := make([]Point2, len(pts))
+        for i, p := range pts {
+            out[i] = Point2{X: p.X * factor, Y: p.Y * factor}
+        }
+        return out
+    }
+    res := &UnfoldResult{
+        Face2D:       make([]Face2D, len(result.Face2D)),
+        SpanningTree: append([]int(nil), result.SpanningTree...),
+        FoldEdges:    append([]NetEdge(nil), result.FoldEdges...),
+        CutEdges:     append([]NetEdge(nil), result.CutEdges...),
+        Overlaps:     append([]OverlapPair(nil), result.Overlaps...),
+        Memory:       result.Memory,
+        Validation:   result.Validation,
+        Side:         result.Side,
+        Mesh:         result.Mesh,
+        FaceOrigin:   append([]int(nil), result.FaceOrigin...),
+    }
+    for i, f := range result.Face2D {
+        res.Face2D[i] = Face2D{Vertices: scalePts(f.Vertices), UVs: f.UVs}
+        if f.Holes != nil {
+            res.Face2D[i].Holes = make([][]Point2, len(f.Holes))
+            for h, loop := range f.Holes {
+                res.Face2D[i].Holes[h] = scalePts(loop)
+            }
+        }
+    }
+    if result.VertexInstances != nil {
+        res.VertexInstances = make([]VertexInstance, len(result.VertexInstances))
+        for i, in := range result.VertexInstances {
+            in.Pos = Point2{X: in.Pos.X * factor, Y: in.Pos.Y * factor}
+            res.VertexInstances[i] = in
+        }
+    }
+    for _, dw := range result.DoubleWalls {
+        res.DoubleWalls = append(res.DoubleWalls, DoubleWall{Face: dw.Face, Edge: dw.Edge, Vertices: scalePts(dw.Vertices)})
+    }
+    for _, t := range result.Tabs {
+        t.Vertices = scalePts(t.Vertices)
+        res.Tabs = append(res.Tabs, t)
+    }
+    return res
+}
+
+// ScaleToFit returns a copy of result scaled (see Scale) as large as
+// it can go inside a pageWidth by pageHeight area, tabs and double walls
+// included, and the factor used. Export the copy with Scale 1: it's no longer
+// in mesh units. The net isn't rotated; an empty or zero-size net is copied
+// as it is, with factor 1.
+func ScaleToFit(result *UnfoldResult, pageWidth, pageHeight float64) (*UnfoldResult, float64) {
+    if result == nil {
+        return nil, 1
+    }
+    factor := math.Inf(1)
+    if lo, hi, ok := sheetBounds(result, nil); ok && pageWidth > 0 && pageHeight > 0 {
+        if w := hi.X - lo.X; w > 0 {
+            factor = pageWidth / w
+        }
+        if h := hi.Y - lo.Y; h > 0 {
+            factor = math.Min(factor, pageHeight/h)
+        }
+    }
+    if math.IsInf(factor, 1) {
+        factor = 1
+    }
+    return Scale(result, factor), factor
+}
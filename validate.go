@@ -0,0 +1,221 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// -----------------------------
+//   Preflight validation
+// -----------------------------
+
+// Severity says whether an Issue stops the unfold or is only worth a look.
+type Severity string
+
+const (
+    SeverityError   Severity = "error"
+    SeverityWarning Severity = "warning"
+)
+
+// Issue is a single problem found by Preflight.
+type Issue struct {
+    Severity Severity `json:"severity"`
+    Code     string   `json:"code"`
+    Message  string   `json:"message"`
+    Face     int      `json:"face"`           // offending face, -1 if not face specific
+    Edge     *[2]int  `json:"edge,omitempty"` // offending edge (vertex indices), if any
+}
+
+// ValidationReport is the result of Preflight.
+type ValidationReport struct {
+    Vertices        int     `json:"vertices"`
+    Faces           int     `json:"faces"`
+    Edges           int     `json:"edges"`
+    EstimatedMemory int64   `json:"estimatedMemory"`
+    Issues          []Issue `json:"issues"`
+}
+
+// OK reports whether the mesh passed (warnings are allowed).
+func (r *ValidationReport) OK() bool {
+    for _, is := range r.Issues {
+        if is.Severity == SeverityError {
+            return false
+        }
+    }
+    return true
+}
+
+// Err returns nil if the report is OK, otherwise an error listing the errors.
+// It wraps the error type of the first one that has one (ErrMemoryLimit,
+// ErrNonManifold, ErrDisconnected, ErrDegenerateFace, ErrNonPlanarFace,
+// ErrInternal).
+func (r *ValidationReport) Err() error {
+    var msgs []string
+    var typed error
+    for _, is := range r.Issues {
+        if is.Severity != SeverityError {
+            continue
+        }
+        msgs = append(msgs, is.Message)
+        if typed == nil {
+            typed = issueError(is)
+        }
+    }
+    if len(msgs) == 0 {
+        return nil
+    }
+    if typed != nil {
+        return fmt.Errorf("%s: %w", strings.Join(msgs, "; "), typed)
+    }
+    return errors.New(strings.Join(msgs, "; "))
+}
+
+// issueError is the error type behind an issue, nil if it has none.
+func issueError(is Issue) error {
+    switch is.Code {
+    case "memory-limit":
+        return ErrMemoryLimit
+    case "non-manifold-edge":
+        return ErrNonManifold
+    case "unreachable-faces":
+        return ErrDisconnected
+    case "too-few-vertices", "bad-vertex-index":
+        return &ErrDegenerateFace{Face: is.Face}
+    case "non-planar-face":
+        return &ErrNonPlanarFace{Face: is.Face}
+    case "internal":
+        return &ErrInternal{Stage: "preflight", Face: is.Face, Value: is.Message}
+    }
+    return nil
+}
+
+func (r *ValidationReport) add(sev Severity, code string, face int, edge *[2]int, format string, args ...interface{}) {
+    r.Issues = append(r.Issues, Issue{
+        Severity: sev,
+        Code:     code,
+        Message:  fmt.Sprintf(format, args...),
+        Face:     face,
+        Edge:     edge,
+    })
+}
+
+// Preflight runs every check UnfoldMeshWithOptions depends on, without
+// allocating or placing a net. It is cheap enough to gate uploads with.
+func Preflight(poly Polyhedron, rootFace int, opts UnfoldOptions) (r *ValidationReport) {
+    // a panic in a check is an "internal" error issue, not a crash
+    var crash error
+    at := &progress{stage: "memory estimate", face: -1}
+    defer func() {
+        if crash == nil {
+            return
+        }
+        if r == nil {
+            r = &ValidationReport{Vertices: len(poly.Vertices), Faces: len(poly.Faces), Issues: []Issue{}}
+        }
+        r.add(SeverityError, "internal", at.face, nil, "%v", crash)
+    }()
+    defer at.recover(&crash)
+
+    r = &ValidationReport{
+        Vertices:        len(poly.Vertices),
+        Faces:           len(poly.Faces),
+        EstimatedMemory: EstimateMemory(poly),
+        Issues:          []Issue{},
+    }
+
+    if len(poly.Faces) == 0 {
+        r.add(SeverityError, "no-faces", -1, nil, "polyhedron has no faces")
+        return r
+    }
+    if rootFace < 0 || rootFace >= len(poly.Faces) {
+        r.add(SeverityError, "bad-root", -1, nil, "root face %d out of range [0, %d)", rootFace, len(poly.Faces))
+    }
+    if opts.MemoryLimit > 0 && r.EstimatedMemory > opts.MemoryLimit {
+        r.add(SeverityError, "memory-limit", -1, nil, "estimated %d bytes, limit %d", r.EstimatedMemory, opts.MemoryLimit)
+    }
+
+    // per-face checks; collect edges as we go
+    at.stage = "face checks"
+    edgeFaces := make(map[[2]int]int)
+    indicesOK := true
+    for fIdx, face := range poly.Faces {
+        at.face = fIdx
+        if len(face.Vertices) < 3 {
+            r.add(SeverityError, "too-few-vertices", fIdx, nil, "face %d has fewer than 3 vertices", fIdx)
+        }
+        for _, v := range face.Vertices {
+            if v < 0 || v >= len(poly.Vertices) {
+                r.add(SeverityError, "bad-vertex-index", fIdx, nil, "face %d references vertex %d out of range", fIdx, v)
+                indicesOK = false
+            }
+        }
+        for h, hole := range face.Holes {
+            if len(hole) < 3 {
+                r.add(SeverityError, "too-few-vertices", fIdx, nil, "hole %d of face %d has fewer than 3 vertices", h, fIdx)
+            }
+            for _, v := range hole {
+                if v < 0 || v >= len(poly.Vertices) {
+                    r.add(SeverityError, "bad-vertex-index", fIdx, nil, "hole %d of face %d references vertex %d out of range", h, fIdx, v)
+                    indicesOK = false
+                }
+            }
+        }
+        for i := range face.Vertices {
+            edgeFaces[sortPair(face.Vertices[i], face.Vertices[(i+1)%len(face.Vertices)])]++
+        }
+    }
+    r.Edges = len(edgeFaces)
+    at.stage, at.face = "edge checks", -1
+    if opts.PlanarityTolerance > 0 && indicesOK {
+        for _, w := range NonPlanarFaces(poly, opts.PlanarityTolerance) {
+            if opts.NonPlanar == NonPlanarTriangulate {
+                r.add(SeverityWarning, "non-planar-face", w.Face, nil, "face %d is %.3g from flat and will be split into triangles", w.Face, w.Deviation)
+            } else {
+                r.add(SeverityError, "non-planar-face", w.Face, nil, "face %d is %.3g from flat, more than %g", w.Face, w.Deviation, opts.PlanarityTolerance)
+            }
+        }
+    }
+    // in vertex order, so the report is the same from run to run
+    var nonManifold [][2]int
+    for e, n := range edgeFaces {
+        if n > 2 {
+            nonManifold = append(nonManifold, e)
+        }
+    }
+    sortPairs(nonManifold)
+    for _, e := range nonManifold {
+        e, n := e, edgeFaces[e]
+        if opts.RequireManifold {
+            r.add(SeverityError, "non-manifold-edge", -1, &e, "edge %d-%d is shared by %d faces", e[0], e[1], n)
+        } else {
+            r.add(SeverityWarning, "non-manifold-edge", -1, &e, "edge %d-%d is shared by %d faces and will always be cut", e[0], e[1], n)
+        }
+    }
+    if !indicesOK || rootFace < 0 || rootFace >= len(poly.Faces) {
+        return r
+    }
+
+    // connectivity: faces the BFS can't reach are silently left unplaced
+    at.stage = "connectivity"
+    adj, err := BuildFaceAdjacency(poly)
+    if err != nil {
+        r.add(SeverityError, "adjacency", -1, nil, "error building adjacency: %v", err)
+        return r
+    }
+    parent := BuildFaceSpanningTree(adj, rootFace, len(poly.Faces))
+    unreached := 0
+    for f, p := range parent {
+        if p < 0 && f != rootFace {
+            unreached++
+        }
+    }
+    switch {
+    case unreached > 0 && opts.Components == ComponentsRootOnly:
+        r.add(SeverityWarning, "unreachable-faces", -1, nil, "%d faces are not connected to root face %d and will not be placed", unreached, rootFace)
+    case unreached > 0 && opts.Components == ComponentsError:
+        r.add(SeverityError, "unreachable-faces", -1, nil, "%d faces are not connected to root face %d", unreached, rootFace)
+    }
+
+    return r
+}
@@ -0,0 +1,183 @@
+package unfolder
+
+import (
+    "math"
+    "runtime"
+    "sort"
+    "sync"
+)
+
+// -----------------------------
+//   Edge visibility (ambient occlusion) for seam hiding
+// -----------------------------
+
+// VisibilityOptions controls EdgeVisibility.
+type VisibilityOptions struct {
+    // Rays cast from each sample point, spread over the hemisphere around the
+    // edge's normal. Default 32.
+    Rays int
+    // Samples is the number of points measured along each edge. Default 3.
+    Samples int
+    // Up, if set, is where the model is seen from: rays towards it count
+    // more than rays away from it, so undersides come out less visible than
+    // tops that are just as open. Zero weighs every direction the same.
+    Up Vector3
+    // Workers shares the edges out over this many goroutines (<= 0 means
+    // GOMAXPROCS).
+    Workers int
+}
+
+// EdgeVisibility estimates how visible every edge between two faces is:
+// from points along the edge, rays go out over the hemisphere around the
+// average of the two face normals (cosine weighted) and the fraction that
+// escape the mesh is the edge's visibility, 0 (deep in a crevice) to 1 (on
+// an open flat). Edges are keyed by their vertex pair, lower index first.
+// The rays go through a BVH, so this is quick even for big meshes.
+func EdgeVisibility(poly Polyhedron, adj *FaceAdjacency, opts VisibilityOptions) (map[[2]int]float64, error) {
+    bvh, err := NewBVH(poly, opts.Workers)
+    if err != nil {
+        return nil, err
+    }
+    rays, samples := opts.Rays, opts.Samples
+    if rays <= 0 {
+        rays = 32
+    }
+    if samples <= 0 {
+        samples = 3
+    }
+    workers := opts.Workers
+    if workers <= 0 {
+        workers = runtime.GOMAXPROCS(0)
+    }
+    up := opts.Up
+    if length(up) > 0 {
+        up = normalize(up)
+    }
+    lo, hi := boundingBox(poly.Vertices)
+    eps := 1e-6 * length(sub(hi, lo))
+
+    // each interior edge once, in a fixed order
+    type edge struct {
+        key  [2]int
+        a, b int
+    }
+    var edges []edge
+    seen := make(map[[2]int]bool)
+    for f := range adj.Neighbors {
+        for _, nbr := range adj.Neighbors[f] {
+            k := nbr.SharedEdge
+            if k[0] > k[1] {
+                k[0], k[1] = k[1], k[0]
+            }
+            if !seen[k] {
+                seen[k] = true
+                edges = append(edges, edge{k, f, nbr.FaceIndex})
+            }
+        }
+    }
+    sort.Slice(edges, func(i, j int) bool {
+        if edges[i].key[0] != edges[j].key[0] {
+            return edges[i].key[0] < edges[j].key[0]
+        }
+        return edges[i].key[1] < edges[j].key[1]
+    })
+
+    dirs := hemisphereDirections(rays)
+    vis := make([]float64, len(edges))
+    var wg sync.WaitGroup
+    jobs := make(chan int)
+    for w := 0; w < workers; w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for i := range jobs {
+                e := edges[i]
+                n := add(normalize(faceNormal(poly, poly.Faces[e.a])), normalize(faceNormal(poly, poly.Faces[e.b])))
+                if length(n) == 0 {
+                    continue // a fin folded flat on itself: nothing gets in
+                }
+                n = normalize(n)
+                u, v := orthoBasis(n)
+                p, q := poly.Vertices[e.key[0]], poly.Vertices[e.key[1]]
+                var open, total float64
+                for s := 0; s < samples; s++ {
+                    t := (float64(s) + 0.5) / float64(samples)
+                    o := add(add(p, scale(sub(q, p), t)), scale(n, eps))
+                    for _, d := range dirs {
+                        dir := add(add(scale(u, d.X), scale(v, d.Y)), scale(n, d.Z))
+                        wt := 1.0
+                        if length(up) > 0 {
+                            wt = (1 + dot(dir, up)) / 2
+                        }
+                        total++
+                        if _, _, hit := bvh.Raycast(o, dir); !hit {
+                            open += wt
+                        }
+                    }
+                }
+                vis[i] = open / total
+            }
+        }()
+    }
+    for i := range edges {
+        jobs <- i
+    }
+    close(jobs)
+    wg.Wait()
+
+    out := make(map[[2]int]float64, len(edges))
+    for i, e := range edges {
+        out[e.key] = vis[i]
+    }
+    return out, nil
+}
+
+// HiddenSeams puts the cuts where they're least seen: a minimum spanning tree
+// of the face graph that folds the most visible edges, so the cuts (and the
+// glued seams they become) land in crevices and on undersides. See
+// EdgeVisibility for the options.
+type HiddenSeams struct {
+    Visibility VisibilityOptions
+}
+
+// SpanningTree implements SpanningStrategy.
+func (h HiddenSeams) SpanningTree(poly Polyhedron, adj *FaceAdjacency, rootFace int) ([]int, int, error) {
+    if err := checkRoot(poly, rootFace); err != nil {
+        return nil, 0, err
+    }
+    vis, err := EdgeVisibility(poly, adj, h.Visibility)
+    if err != nil {
+        return nil, 0, err
+    }
+    weight := func(_ int, nbr FaceNeighbor) float64 {
+        k := nbr.SharedEdge
+        if k[0] > k[1] {
+            k[0], k[1] = k[1], k[0]
+        }
+        return 1 - vis[k]
+    }
+    return minSpanningTree(adj, rootFace, len(poly.Faces), weight), rootFace, nil
+}
+
+// hemisphereDirections spreads n cosine-weighted directions over the
+// hemisphere around +Z (Fibonacci points on the disk lifted up).
+func hemisphereDirections(n int) []Vector3 {
+    golden := math.Pi * (3 - math.Sqrt(5))
+    out := make([]Vector3, n)
+    for i := range out {
+        r := math.Sqrt((float64(i) + 0.5) / float64(n))
+        a := golden * float64(i)
+        out[i] = Vector3{r * math.Cos(a), r * math.Sin(a), math.Sqrt(1 - r*r)}
+    }
+    return out
+}
+
+// orthoBasis returns two unit vectors perpendicular to unit n and each other.
+func orthoBasis(n Vector3) (Vector3, Vector3) {
+    a := Vector3{1, 0, 0}
+    if math.Abs(n.X) > 0.9 {
+        a = Vector3{0, 1, 0}
+    }
+    u := normalize(cross(n, a))
+    return u, cross(n, u)
+}
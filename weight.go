@@ -0,0 +1,105 @@
+package unfolder
+
+import (
+    "errors"
+    "math"
+)
+
+// -----------------------------
+//   Weight and material estimate
+// -----------------------------
+
+// Material describes the sheet a net is cut from. Give either GSM (paper and
+// card are sold by it) or Density and Thickness.
+type Material struct {
+    GSM       float64 // grams per square metre
+    Density   float64 // g/cm³, used with Thickness when GSM is 0
+    Thickness float64 // mm
+    // Scale converts net units to millimetres. Default 1.
+    Scale float64
+}
+
+// gsm returns the sheet's weight per square metre.
+func (m Material) gsm() (float64, error) {
+    if m.GSM > 0 {
+        return m.GSM, nil
+    }
+    if m.Density > 0 && m.Thickness > 0 {
+        // g/cm³ * mm = 1000 g/m²
+        return m.Density * m.Thickness * 1000, nil
+    }
+    return 0, errors.New("material needs a GSM, or a density and a thickness")
+}
+
+// PieceWeight is the material in one piece of a net (see NetGluingGraph for
+// what a piece is). Areas are in mm², weights in grams.
+type PieceWeight struct {
+    Faces    []int   `json:"faces"`
+    FaceArea float64 `json:"faceArea"`
+    TabArea  float64 `json:"tabArea"` // glue tabs and double walls
+    Area     float64 `json:"area"`    // FaceArea + TabArea
+    Weight   float64 `json:"weight"`
+}
+
+// WeightEstimate is the material in a whole net.
+type WeightEstimate struct {
+    Pieces   []PieceWeight `json:"pieces"`
+    FaceArea float64       `json:"faceArea"`
+    TabArea  float64       `json:"tabArea"`
+    Area     float64       `json:"area"`
+    Weight   float64       `json:"weight"` // the finished model, in grams
+    Volume   float64       `json:"volume"` // sheet volume in cm³, 0 without a Thickness
+}
+
+// EstimateWeight works out how much material the pieces of result take and
+// what the finished model weighs, from the areas of the faces plus whatever
+// tabs and double walls the net has. Offcuts and glue aren't counted.
+func EstimateWeight(result *UnfoldResult, m Material) (WeightEstimate, error) {
+    var est WeightEstimate
+    if result == nil {
+        return est, errors.New("nil unfold result")
+    }
+    gsm, err := m.gsm()
+    if err != nil {
+        return est, err
+    }
+    if m.Scale <= 0 {
+        m.Scale = 1
+    }
+    toMM2 := m.Scale * m.Scale
+
+    pieceOf, pieces := netPieces(result)
+    est.Pieces = make([]PieceWeight, len(pieces))
+    for i, faces := range pieces {
+        p := &est.Pieces[i]
+        p.Faces = faces
+        for _, f := range faces {
+            p.FaceArea += math.Abs(polygonArea(result.Face2D[f].Vertices)) * toMM2
+        }
+    }
+    extra := func(face int, pts []Point2) {
+        if face >= 0 && face < len(pieceOf) && pieceOf[face] >= 0 && len(pts) >= 3 {
+            est.Pieces[pieceOf[face]].TabArea += math.Abs(polygonArea(pts)) * toMM2
+        }
+    }
+    for _, t := range result.Tabs {
+        extra(t.Face, t.Vertices)
+    }
+    for _, dw := range result.DoubleWalls {
+        extra(dw.Face, dw.Vertices)
+    }
+
+    for i := range est.Pieces {
+        p := &est.Pieces[i]
+        p.Area = p.FaceArea + p.TabArea
+        p.Weight = p.Area * 1e-6 * gsm
+        est.FaceArea += p.FaceArea
+        est.TabArea += p.TabArea
+        est.Area += p.Area
+        est.Weight += p.Weight
+    }
+    if m.Thickness > 0 {
+        est.Volume = est.Area * m.Thickness / 1000
+    }
+    return est, nil
+}
@@ -0,0 +1,86 @@
+package unfolder
+
+import "math"
+
+// WeldVertices merges vertices closer than eps (eps = 0 merges only exact
+// duplicates), remaps the faces, drops repeated corners that welding creates
+// and removes faces left with fewer than 3 vertices. Formats like STL store
+// every triangle separately; welding restores the shared edges
+// BuildFaceAdjacency needs.
+func WeldVertices(poly Polyhedron, eps float64) Polyhedron {
+    out := Polyhedron{Name: poly.Name, Units: poly.Units}
+    remap := make([]int, len(poly.Vertices))
+
+    if eps <= 0 {
+        index := make(map[Vector3]int, len(poly.Vertices))
+        for i, v := range poly.Vertices {
+            j, ok := index[v]
+            if !ok {
+                j = len(out.Vertices)
+                index[v] = j
+                out.Vertices = append(out.Vertices, v)
+            }
+            remap[i] = j
+        }
+    } else {
+        // hash grid with cell size eps: a match can only be in the 27 cells
+        // around a vertex
+        type cell [3]int64
+        grid := make(map[cell][]int)
+        cellOf := func(v Vector3) cell {
+            return cell{int64(math.Floor(v.X / eps)), int64(math.Floor(v.Y / eps)), int64(math.Floor(v.Z / eps))}
+        }
+        for i, v := range poly.Vertices {
+            c := cellOf(v)
+            found := -1
+        search:
+            for dx := int64(-1); dx <= 1; dx++ {
+                for dy := int64(-1); dy <= 1; dy++ {
+                    for dz := int64(-1); dz <= 1; dz++ {
+                        for _, j := range grid[cell{c[0] + dx, c[1] + dy, c[2] + dz}] {
+                            if length(sub(out.Vertices[j], v)) <= eps {
+                                found = j
+                                break search
+                            }
+                        }
+                    }
+                }
+            }
+            if found < 0 {
+                found = len(out.Vertices)
+                out.Vertices = append(out.Vertices, v)
+                grid[c] = append(grid[c], found)
+            }
+            remap[i] = found
+        }
+    }
+
+    for _, f := range poly.Faces {
+        textured := len(f.UVs) == len(f.Vertices)
+        loop := make([]int, 0, len(f.Vertices))
+        var uvs []Point2
+        for i, v := range f.Vertices {
+            nv := remap[v]
+            if len(loop) > 0 && loop[len(loop)-1] == nv {
+                continue
+            }
+            loop = append(loop, nv)
+            if textured {
+                uvs = append(uvs, f.UVs[i])
+            }
+        }
+        if len(loop) > 1 && loop[0] == loop[len(loop)-1] {
+            loop = loop[:len(loop)-1]
+            if textured {
+                uvs = uvs[:len(uvs)-1]
+            }
+        }
+        if len(loop) >= 3 {
+            nf := f
+            nf.Vertices, nf.UVs = loop, uvs
+            nf.Holes = copyHoles(f.Holes, func(v int) int { return remap[v] })
+            out.Faces = append(out.Faces, nf)
+        }
+    }
+    return out
+}
@@ -0,0 +1,187 @@
+package unfolder
+
+import (
+    "errors"
+    "fmt"
+    "math"
+)
+
+// -----------------------------
+//   Winding repair
+// -----------------------------
+
+// ErrNonOrientable means the surface has no consistent winding (a Möbius
+// strip, Klein bottle, ...), so some faces will always come out mirrored.
+var ErrNonOrientable = errors.New("surface is not orientable")
+
+// RepairWinding makes the faces of every connected part of the mesh wind the
+// same way, flipping (reversing) faces as needed. Parts end up CCW seen from
+// outside, as the rest of the package expects, going by their signed volume;
+// open parts that are too flat to have an outside keep whichever orientation
+// most of their faces already had. Faces are connected through
+// edges shared by exactly two faces, like BuildFaceAdjacency does.
+//
+// If a part is not orientable, poly is left unchanged and the error wraps
+// ErrNonOrientable.
+func RepairWinding(poly *Polyhedron) error {
+    if poly == nil {
+        return errors.New("nil polyhedron")
+    }
+    nFaces := len(poly.Faces)
+    type use struct{ face, from int }
+    edges := make(map[[2]int][]use)
+    for fIdx, face := range poly.Faces {
+        vs := face.Vertices
+        if len(vs) < 3 {
+            continue
+        }
+        for i := range vs {
+            a, b := vs[i], vs[(i+1)%len(vs)]
+            if a < 0 || a >= len(poly.Vertices) || b < 0 || b >= len(poly.Vertices) {
+                return fmt.Errorf("face %d references vertex out of range", fIdx)
+            }
+            e := sortPair(a, b)
+            edges[e] = append(edges[e], use{fIdx, a})
+        }
+    }
+    // neighbours over manifold edges; same is true when both faces run the
+    // edge in the same direction, i.e. one of them is wound the wrong way
+    type link struct {
+        face int
+        same bool
+    }
+    links := make([][]link, nFaces)
+    boundary := make([]bool, nFaces)
+    for _, us := range edges {
+        switch len(us) {
+        case 1:
+            boundary[us[0].face] = true
+        case 2:
+            a, b := us[0], us[1]
+            if a.face == b.face {
+                continue
+            }
+            same := a.from == b.from
+            links[a.face] = append(links[a.face], link{b.face, same})
+            links[b.face] = append(links[b.face], link{a.face, same})
+        default:
+            for _, u := range us {
+                boundary[u.face] = true
+            }
+        }
+    }
+
+    flip := make([]bool, nFaces)
+    seen := make([]bool, nFaces)
+    for start := 0; start < nFaces; start++ {
+        if seen[start] || len(poly.Faces[start].Vertices) < 3 {
+            continue
+        }
+        comp := []int{start}
+        seen[start] = true
+        for i := 0; i < len(comp); i++ {
+            f := comp[i]
+            for _, l := range links[f] {
+                want := flip[f] != l.same
+                if !seen[l.face] {
+                    seen[l.face] = true
+                    flip[l.face] = want
+                    comp = append(comp, l.face)
+                } else if flip[l.face] != want {
+                    return fmt.Errorf("%w: faces %d and %d can't agree", ErrNonOrientable, f, l.face)
+                }
+            }
+        }
+
+        // pick the orientation of the whole part
+        closed := true
+        flipped := 0
+        for _, f := range comp {
+            closed = closed && !boundary[f]
+            if flip[f] {
+                flipped++
+            }
+        }
+        // signed volume (times 6) around the part's centroid, with faces
+        // taken as they'll be after flipping; for open parts it still tells
+        // outside from inside unless the part is nearly flat
+        var c Vector3
+        nc := 0
+        for _, f := range comp {
+            for _, v := range poly.Faces[f].Vertices {
+                c = add(c, poly.Vertices[v])
+                nc++
+            }
+        }
+        c = scale(c, 1/float64(nc))
+        vol, mag := 0.0, 0.0
+        for _, f := range comp {
+            n := faceNormal(*poly, poly.Faces[f])
+            d := dot(n, sub(poly.Vertices[poly.Faces[f].Vertices[0]], c))
+            if flip[f] {
+                d = -d
+            }
+            vol += d
+            mag += math.Abs(d)
+        }
+        var reverse bool
+        switch {
+        case closed:
+            reverse = vol < 0
+        case math.Abs(vol) > 1e-3*mag:
+            reverse = vol < 0
+        default:
+            reverse = 2*flipped > len(comp)
+        }
+        if reverse {
+            for _, f := range comp {
+                flip[f] = !flip[f]
+            }
+        }
+    }
+
+    for f, ok := range flip {
+        if !ok {
+            continue
+        }
+        vs, uvs := poly.Faces[f].Vertices, poly.Faces[f].UVs
+        for i, j := 0, len(vs)-1; i < j; i, j = i+1, j-1 {
+            vs[i], vs[j] = vs[j], vs[i]
+        }
+        for i, j := 0, len(uvs)-1; i < j; i, j = i+1, j-1 {
+            uvs[i], uvs[j] = uvs[j], uvs[i]
+        }
+        // holes keep running against the outline
+        for _, hole := range poly.Faces[f].Holes {
+            for i, j := 0, len(hole)-1; i < j; i, j = i+1, j-1 {
+                hole[i], hole[j] = hole[j], hole[i]
+            }
+        }
+    }
+    return nil
+}
+
+// FaceNormals returns the unit normal of every face, by its winding (CCW faces
+// point outward). Degenerate faces get a zero normal.
+func FaceNormals(poly Polyhedron) []Vector3 {
+    normals := make([]Vector3, len(poly.Faces))
+    for i, f := range poly.Faces {
+        if len(f.Vertices) < 3 {
+            continue
+        }
+        if n := faceNormal(poly, f); length(n) > 0 {
+            normals[i] = normalize(n)
+        }
+    }
+    return normals
+}
+
+// OrientOutward repairs the winding of poly (see RepairWinding) and returns
+// the resulting outward face normals. Run it on imported meshes before
+// unfolding so mountain and valley folds come out right.
+func OrientOutward(poly *Polyhedron) ([]Vector3, error) {
+    if err := RepairWinding(poly); err != nil {
+        return nil, err
+    }
+    return FaceNormals(*poly), nil
+}
@@ -0,0 +1,156 @@
+package unfolder
+
+import (
+    "errors"
+    "testing"
+)
+
+// testCube is the unit cube with its six squares wound CCW from outside.
+func testCube() Polyhedron {
+    return Polyhedron{
+        Vertices: []Vector3{
+            {0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0},
+            {0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1},
+        },
+        Faces: []Face{
+            {Vertices: []int{0, 3, 2, 1}}, // bottom
+            {Vertices: []int{4, 5, 6, 7}}, // top
+            {Vertices: []int{0, 1, 5, 4}}, // front
+            {Vertices: []int{1, 2, 6, 5}}, // right
+            {Vertices: []int{2, 3, 7, 6}}, // back
+            {Vertices: []int{3, 0, 4, 7}}, // left
+        },
+    }
+}
+
+// copyFaces returns a deep copy of the face loops of poly.
+func copyFaces(poly Polyhedron) Polyhedron {
+    out := poly
+    out.Faces = make([]Face, len(poly.Faces))
+    for f, face := range poly.Faces {
+        out.Faces[f] = Face{Vertices: append([]int(nil), face.Vertices...)}
+    }
+    return out
+}
+
+// sameLoop reports whether b is a rotation of a.
+func sameLoop(a, b []int) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    if len(a) == 0 {
+        return true
+    }
+    for s := range b {
+        match := true
+        for i := range a {
+            if a[i] != b[(s+i)%len(b)] {
+                match = false
+                break
+            }
+        }
+        if match {
+            return true
+        }
+    }
+    return false
+}
+
+func reversed(vs []int) []int {
+    out := make([]int, len(vs))
+    for i, v := range vs {
+        out[len(vs)-1-i] = v
+    }
+    return out
+}
+
+// mobius is a strip of four quads joined end to end with a half twist.
+func mobius() Polyhedron {
+    return Polyhedron{
+        Vertices: []Vector3{
+            {1, 0, 1}, {1, 0, -1}, // top and bottom of each rung
+            {0, 1, 1}, {0, 1, -1},
+            {-1, 0, 1}, {-1, 0, -1},
+            {0, -1, 1}, {0, -1, -1},
+        },
+        Faces: []Face{
+            {Vertices: []int{0, 1, 3, 2}},
+            {Vertices: []int{2, 3, 5, 4}},
+            {Vertices: []int{4, 5, 7, 6}},
+            {Vertices: []int{6, 7, 0, 1}}, // rejoins rung 0 upside down
+        },
+    }
+}
+
+func TestRepairWinding(t *testing.T) {
+    cube := testCube()
+    tests := []struct {
+        name  string
+        flip  []int // faces of the cube to wind the wrong way first
+        poly  func() Polyhedron
+        isErr error
+    }{
+        {name: "consistent", poly: testCube},
+        {name: "one face flipped", flip: []int{3}},
+        {name: "two faces flipped", flip: []int{0, 4}},
+        {name: "inside out", flip: []int{0, 1, 2, 3, 4, 5}},
+        {name: "mobius strip", poly: mobius, isErr: ErrNonOrientable},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            poly := testCube()
+            if tt.poly != nil {
+                poly = tt.poly()
+            }
+            for _, f := range tt.flip {
+                poly.Faces[f].Vertices = reversed(poly.Faces[f].Vertices)
+            }
+            before := copyFaces(poly)
+            err := RepairWinding(&poly)
+            if tt.isErr != nil {
+                if !errors.Is(err, tt.isErr) {
+                    t.Fatalf("err = %v, want %v", err, tt.isErr)
+                }
+                for f := range poly.Faces {
+                    if !sameLoop(before.Faces[f].Vertices, poly.Faces[f].Vertices) {
+                        t.Errorf("face %d changed to %v on error", f, poly.Faces[f].Vertices)
+                    }
+                }
+                return
+            }
+            if err != nil {
+                t.Fatal(err)
+            }
+            for f := range poly.Faces {
+                if !sameLoop(cube.Faces[f].Vertices, poly.Faces[f].Vertices) {
+                    t.Errorf("face %d = %v, want a rotation of %v", f, poly.Faces[f].Vertices, cube.Faces[f].Vertices)
+                }
+            }
+        })
+    }
+}
+
+func TestRepairWindingKeepsHolesAgainstOutline(t *testing.T) {
+    poly := testCube()
+    poly.Vertices = append(poly.Vertices, Vector3{0.25, 0.25, 1}, Vector3{0.75, 0.25, 1}, Vector3{0.75, 0.75, 1}, Vector3{0.25, 0.75, 1})
+    poly.Faces[1].Holes = [][]int{{8, 11, 10, 9}}
+    poly.Faces[1].Vertices = reversed(poly.Faces[1].Vertices)
+    poly.Faces[1].Holes[0] = reversed(poly.Faces[1].Holes[0])
+    if err := RepairWinding(&poly); err != nil {
+        t.Fatal(err)
+    }
+    if !sameLoop([]int{4, 5, 6, 7}, poly.Faces[1].Vertices) || !sameLoop([]int{8, 11, 10, 9}, poly.Faces[1].Holes[0]) {
+        t.Errorf("top face = %v with hole %v", poly.Faces[1].Vertices, poly.Faces[1].Holes[0])
+    }
+}
+
+func TestRepairWindingErrors(t *testing.T) {
+    if err := RepairWinding(nil); err == nil {
+        t.Error("nil polyhedron: no error")
+    }
+    poly := testCube()
+    poly.Faces[2].Vertices[1] = 99
+    if err := RepairWinding(&poly); err == nil {
+        t.Error("vertex out of range: no error")
+    }
+}